@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"sistem-06-Backend/internal/config"
+	"sistem-06-Backend/internal/infrastructure/database/repository"
+	"sistem-06-Backend/internal/infrastructure/database/sqlc"
+)
+
+// rotateBatchSize is how many users are read per query by encryption
+// rotate.
+const rotateBatchSize = 500
+
+const usage = `usage: web [command]
+
+Without a command the HTTP server is started.
+
+commands:
+  config check        load config.json and SISTEM06_* overrides, report every invalid key
+  encryption rotate   encrypt personal data stored in the clear, move it to the primary
+                      key and recompute blind indexes; run after adding a key or
+                      changing encryption.primary_key, then drop the old key
+`
+
+// runCommand executes a maintenance command and returns the process exit code.
+func runCommand(args []string) int {
+	switch {
+	case len(args) == 2 && args[0] == "config" && args[1] == "check":
+		return configCheck()
+	case len(args) == 2 && args[0] == "encryption" && args[1] == "rotate":
+		return encryptionRotate()
+	case len(args) == 1 && (args[0] == "help" || args[0] == "-h" || args[0] == "--help"):
+		fmt.Print(usage)
+		return 0
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+}
+
+func configCheck() int {
+	viperConfig, err := config.NewViper()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	if _, err := config.CheckConfig(viperConfig); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	if file := viperConfig.ConfigFileUsed(); file != "" {
+		fmt.Printf("configuration OK (%s)\n", file)
+	} else {
+		fmt.Println("configuration OK (environment only)")
+	}
+	return 0
+}
+
+func encryptionRotate() int {
+	viperConfig, err := config.NewViper()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	appConfig, err := config.NewConfig(viperConfig)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	cipher, err := config.NewFieldCipher(appConfig)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	// without keys emails stay in the clear, but the blind indexes are
+	// still filled in
+	if cipher == nil {
+		fmt.Println("no encryption keys configured, only recomputing blind indexes")
+	}
+
+	log := config.NewLogger(appConfig)
+	pool := config.NewDatabase(appConfig, log)
+	db := config.NewPostgres(pool)
+	defer pool.Close()
+	defer db.Close()
+
+	// rows are updated one by one, so an interrupted run can simply be rerun
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	queries := sqlc.New(db)
+	users := repository.NewUserRepository(queries, log, cipher)
+	rotated, err := users.RotateEmails(ctx, rotateBatchSize)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "rotated %d user emails before failing: %v\n", rotated, err)
+		return 1
+	}
+	fmt.Printf("rotated %d user emails\n", rotated)
+
+	emailChanges := repository.NewEmailChangeRepository(queries, log, cipher)
+	rotated, err = emailChanges.RotateNewEmails(ctx, rotateBatchSize)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "rotated %d pending email changes before failing: %v\n", rotated, err)
+		return 1
+	}
+	fmt.Printf("rotated %d pending email changes\n", rotated)
+	return 0
+}
@@ -1,31 +1,107 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
-	"backend-sistem06.com/internal/config"
+	"sistem-06-Backend/internal/config"
+
+	"github.com/gofiber/fiber/v2"
 )
 
 func main() {
-	viperConfig := config.NewViper()
-	app := config.NewFiber(viperConfig)
-	log := config.NewLogger(viperConfig)
-	db := config.NewPostgres(viperConfig, log)
-	validate := config.NewValidator(viperConfig)
-	sessionStore := config.NewSession(viperConfig, db, log)
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(os.Args[1:]))
+	}
+
+	viperConfig, err := config.NewViper()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	appConfig, err := config.NewConfig(viperConfig)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	app := config.NewFiber(appConfig)
+	log := config.NewLogger(appConfig)
+	lifecycle := config.NewLifecycle(log)
+	tracerProvider := config.NewTracing(appConfig, log)
+	// registered first so buffered spans are flushed after everything else
+	lifecycle.OnShutdown("tracing", tracerProvider.Shutdown)
+	pool := config.NewDatabase(appConfig, log)
+	db := config.NewPostgres(pool)
+	metrics := config.NewMetrics(appConfig, db)
+	validate := config.NewValidator(appConfig)
+	sessionStore := config.NewSession(appConfig, pool, metrics, log)
 
 	config.Bootstrap(&config.BootstrapConfig{
 		App:       app,
-		Config:    viperConfig,
+		Config:    appConfig,
 		Validator: validate,
+		Pool:      pool,
 		DB:        db,
 		Log:       log,
 		Session:   sessionStore,
+		Lifecycle: lifecycle,
+		Metrics:   metrics,
 	})
-	webPort := viperConfig.GetInt("web.port")
-	err := app.Listen(fmt.Sprintf(":%d", webPort))
-	if err != nil {
-		panic(err)
+
+	app.Hooks().OnListen(func(fiber.ListenData) error {
+		lifecycle.MarkReady()
+		return nil
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	listenErr := make(chan error, 1)
+	go func() {
+		listenErr <- app.Listen(fmt.Sprintf(":%d", appConfig.Web.Port))
+	}()
+
+	var serveErr error
+	select {
+	case serveErr = <-listenErr:
+		if serveErr != nil {
+			log.Errorf("Server stopped: %v", serveErr)
+		}
+	case <-ctx.Done():
+		log.Info("Shutdown signal received")
+	}
+	stop()
+
+	// Fail readiness first so the load balancer stops routing new requests
+	// here, then give in-flight requests time to finish. There is nothing to
+	// drain when the listener never came up, e.g. the port was taken.
+	drainDelay := time.Duration(appConfig.Web.DrainDelay) * time.Second
+	if serveErr == nil {
+		lifecycle.MarkNotReady()
+		if drainDelay > 0 {
+			log.Infof("Draining for %s before closing listeners", drainDelay)
+			time.Sleep(drainDelay)
+		}
+	}
+
+	shutdownTimeout := time.Duration(appConfig.Web.ShutdownTimeout) * time.Second
+	if err := app.ShutdownWithTimeout(shutdownTimeout); err != nil {
+		log.Warnf("HTTP server shutdown: %v", err)
 	}
 
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := lifecycle.Shutdown(shutdownCtx); err != nil {
+		log.Errorf("Shutdown finished with errors: %v", err)
+		os.Exit(1)
+	}
+	if serveErr != nil {
+		os.Exit(1)
+	}
+	log.Info("Shutdown complete")
 }
@@ -1,47 +1,183 @@
 package config
 
 import (
+	"context"
 	"database/sql"
+	"time"
+
+	"sistem-06-Backend/internal/delivery/http"
+	"sistem-06-Backend/internal/delivery/http/middleware"
+	"sistem-06-Backend/internal/delivery/http/route"
+	"sistem-06-Backend/internal/infrastructure/database/migrations"
+	"sistem-06-Backend/internal/infrastructure/database/repository"
+	"sistem-06-Backend/internal/infrastructure/database/sqlc"
+	"sistem-06-Backend/internal/infrastructure/metrics"
+	"sistem-06-Backend/internal/infrastructure/notification"
+	"sistem-06-Backend/internal/usecase"
+	"sistem-06-Backend/pkg"
 
-	"backend-sistem06.com/internal/http"
-	"backend-sistem06.com/internal/http/route"
-	"backend-sistem06.com/internal/repository"
-	"backend-sistem06.com/internal/usecase"
-	"backend-sistem06.com/utils"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/session"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/sirupsen/logrus"
-	"github.com/spf13/viper"
 )
 
 type BootstrapConfig struct {
 	App       *fiber.App
-	Config    *viper.Viper
+	Config    *Config
 	Log       *logrus.Logger
+	Pool      *pgxpool.Pool
 	DB        *sql.DB
 	Validator *validator.Validate
 	Session   *session.Store
+	Lifecycle *Lifecycle
+	Metrics   *metrics.Metrics
 }
 
 func Bootstrap(config *BootstrapConfig) {
-	helloContoller := http.NewHelloController()
+	// The session storage is not closed: postgres.Storage.Close closes the
+	// pool it shares with the application, which this hook already does.
+	// Its GC goroutine ends with the process.
+	config.Lifecycle.OnShutdown("database", func(ctx context.Context) error {
+		err := config.DB.Close()
+		config.Pool.Close()
+		return err
+	})
 
-	userRepository := repository.NewUserRepository(config.DB, config.Log)
-	sessionHandler := utils.NewSessionHandler(config.Session, config.Log)
-	// authRepository := repository.NewTokenRepository(config.DB, config.Log)
+	queries := sqlc.New(config.DB)
+	fieldCipher, err := NewFieldCipher(config.Config)
+	if err != nil {
+		config.Log.Fatalf("failed to load encryption keys: %v", err)
+	}
+	if fieldCipher == nil {
+		config.Log.Warn("No encryption keys configured, personal data is stored in the clear")
+	}
 
-	userUseCase := usecase.NewUserUseCase(config.DB, config.Log, config.Validator, userRepository)
-	authUseCase := usecase.NewAuthUseCase(config.DB, config.Log, config.Validator, userRepository)
+	userRepository := repository.NewUserRepository(queries, config.Log, fieldCipher)
+	addressRepository := repository.NewAddressRepository(queries, config.Log)
+	healthRepository := repository.NewHealthRepository(config.DB, config.Log)
+	loginAttemptRepository := repository.NewLoginAttemptRepository(queries, config.Log)
+	rateLimitRepository := repository.NewRateLimitRepository(queries, config.Log)
+	twoFactorRepository := repository.NewTwoFactorRepository(queries, config.Log)
+	tokenRepository := repository.NewTokenRepository(queries, config.Log)
+	emailChangeRepository := repository.NewEmailChangeRepository(queries, config.Log, fieldCipher)
+	auditRepository := repository.NewAuditRepository(queries, config.Log)
+	roleRepository := repository.NewRoleRepository(queries, config.Log)
+	searchRepository := repository.NewSearchRepository(queries, config.Log, fieldCipher)
+	importJobRepository := repository.NewImportJobRepository(queries, config.Log)
+	sessionRepository := repository.NewSessionRepository(queries, config.Log, config.Config.Session.Table)
+	deletionRequestRepository := repository.NewDeletionRequestRepository(queries, config.Log)
+	auditRecorder := usecase.NewAuditRecorder(auditRepository, config.Log, fieldCipher)
+	notifier := notification.NewLogNotifier(config.Log)
+	loginPolicy := NewLoginPolicy(config.Config)
+	sessionHandler := pkg.NewSessionHandler(config.Session, config.Log)
+	passwordPolicy, err := NewPasswordPolicy(config.Config)
+	if err != nil {
+		config.Log.Fatalf("failed to load password policy: %v", err)
+	}
+	passwordHasher := NewPasswordHasher(config.Config)
+
+	userUseCase := usecase.NewUserUseCase(config.DB, config.Log, config.Validator, userRepository, passwordPolicy, passwordHasher, auditRecorder, config.Metrics)
+	authUseCase := usecase.NewAuthUseCase(config.DB, config.Log, config.Validator, userRepository, loginAttemptRepository, twoFactorRepository, notifier, passwordHasher, auditRecorder, loginPolicy, config.Metrics, fieldCipher)
+	twoFactorUseCase := usecase.NewTwoFactorUseCase(config.DB, config.Log, config.Validator, userRepository, twoFactorRepository, passwordHasher, authUseCase, auditRecorder, config.Config.TwoFactor.Issuer)
+	tokenUseCase := usecase.NewTokenUseCase(config.DB, config.Log, config.Validator, userRepository, tokenRepository, auditRecorder,
+		time.Duration(config.Config.Token.DefaultLifetimeDays)*24*time.Hour,
+		time.Duration(config.Config.Token.MaxLifetimeDays)*24*time.Hour)
+	accountUseCase := usecase.NewAccountUseCase(config.DB, config.Log, config.Validator, userRepository, emailChangeRepository, passwordPolicy, passwordHasher, notifier, auditRecorder,
+		time.Duration(config.Config.Account.EmailChangeTTLHours)*time.Hour)
+	rateLimitUseCase := usecase.NewRateLimitUseCase(config.Log, rateLimitRepository)
+	roleUseCase := usecase.NewRoleUseCase(config.DB, config.Log, roleRepository, auditRecorder)
+	retentionUseCase := usecase.NewRetentionUseCase(config.Log, userRepository, addressRepository, roleRepository,
+		time.Duration(config.Config.SoftDelete.RetentionDays)*24*time.Hour)
+	auditUseCase := usecase.NewAuditUseCase(config.Log, config.Validator, auditRepository)
+	addressUseCase := usecase.NewAddressUseCase(config.DB, config.Log, config.Validator, addressRepository, auditRecorder)
+	searchUseCase := usecase.NewSearchUseCase(config.Log, config.Validator, userRepository, searchRepository)
+	importUseCase := usecase.NewImportUseCase(config.DB, config.Log, config.Validator, userRepository, addressRepository, roleRepository, importJobRepository, passwordPolicy, passwordHasher, auditRecorder,
+		config.Lifecycle.Go, config.Config.Import.MaxRows, int64(config.Config.Import.MaxFileMB)<<20)
+	exportUseCase := usecase.NewExportUseCase(config.DB, config.Log, userRepository, addressRepository, auditRecorder)
+	privacyUseCase := usecase.NewPrivacyUseCase(config.DB, config.Log, config.Validator, userRepository, deletionRequestRepository, passwordHasher, auditRecorder,
+		time.Duration(config.Config.Account.DeletionGraceDays)*24*time.Hour)
+	// a new table holding personal data registers its source here
+	privacyUseCase.Register("user", usecase.UserDataSource(userRepository))
+	privacyUseCase.Register("roles", usecase.RoleDataSource(roleRepository))
+	privacyUseCase.Register("addresses", usecase.AddressDataSource(addressRepository))
+	privacyUseCase.Register("sessions", usecase.SessionDataSource(sessionRepository, userRepository))
+	privacyUseCase.Register("access_tokens", usecase.TokenDataSource(tokenRepository))
+	privacyUseCase.Register("two_factor", usecase.TwoFactorDataSource(twoFactorRepository))
+	privacyUseCase.Register("email_change", usecase.EmailChangeDataSource(emailChangeRepository))
+
+	migrationVersion, err := migrations.LatestVersion()
+	if err != nil {
+		config.Log.Fatalf("failed to read embedded migrations: %v", err)
+	}
+	healthUseCase := usecase.NewHealthUseCase(config.DB, config.Log, config.Session.Storage, healthRepository, migrationVersion, config.Lifecycle.IsReady)
 
-	userController := http.NewUserController(userUseCase, config.Log)
+	userController := http.NewUserController(userUseCase, authUseCase, config.Log)
 	authController := http.NewAuthController(authUseCase, config.Log, sessionHandler)
+	accountController := http.NewAccountController(accountUseCase, config.Log, sessionHandler)
+	twoFactorController := http.NewTwoFactorController(twoFactorUseCase, config.Log, sessionHandler)
+	tokenController := http.NewTokenController(tokenUseCase, config.Log)
+	addressController := http.NewAddressController(addressUseCase, authUseCase, config.Log)
+	auditController := http.NewAuditController(auditUseCase, config.Log)
+	roleController := http.NewRoleController(roleUseCase, config.Log)
+	searchController := http.NewSearchController(searchUseCase, config.Log)
+	importController := http.NewImportController(importUseCase, config.Log)
+	exportController := http.NewExportController(exportUseCase, config.Log)
+	privacyController := http.NewPrivacyController(privacyUseCase, config.Log)
+	databaseController := http.NewDatabaseController(config.Pool, config.Log)
+	healthController := http.NewHealthController(healthUseCase, config.Log)
+
+	var metricsController *http.MetricsController
+	if config.Metrics != nil {
+		metricsController = http.NewMetricsController(config.Metrics, config.Log)
+	}
+
+	authMiddleware := middleware.NewAuthMiddleware(sessionHandler, authUseCase, tokenUseCase, config.Log)
+	loggingMiddleware := middleware.NewLoggingMiddleware(config.Log)
+	tracingMiddleware := middleware.NewTracingMiddleware()
+	rateLimitMiddleware := middleware.NewRateLimitMiddleware(rateLimitUseCase, sessionHandler, NewRateLimitRules(config.Config), config.Metrics, config.Log)
+	csrfMiddleware := middleware.NewCSRFMiddleware(NewCSRF(config.Config, config.Session), config.Config.CSRF.Enabled, config.Log)
+	metricsMiddleware := middleware.NewMetricsMiddleware(config.Metrics, config.Config.Metrics.Token, config.Config.Metrics.Allowlist, config.Log)
+
+	config.Lifecycle.Every("login attempt purge", loginPolicy.Window, authUseCase.PurgeLoginAttempts)
+	if config.Config.RateLimit.Enabled {
+		purgeInterval := time.Duration(config.Config.RateLimit.PurgeIntervalMinutes) * time.Minute
+		config.Lifecycle.Every("rate limit purge", purgeInterval, rateLimitUseCase.PurgeExpired)
+	}
+	if config.Config.SoftDelete.RetentionDays > 0 {
+		purgeInterval := time.Duration(config.Config.SoftDelete.PurgeIntervalMinutes) * time.Minute
+		config.Lifecycle.Every("soft delete purge", purgeInterval, retentionUseCase.PurgeDeleted)
+	}
+	deletionInterval := time.Duration(config.Config.Account.DeletionIntervalMinutes) * time.Minute
+	config.Lifecycle.Every("account deletion", deletionInterval, privacyUseCase.ProcessDeletions)
+
+	// Registered ahead of every route so all requests are measured
+	config.App.Use(metricsMiddleware.Instrument())
 
 	routeConfig := route.RouteConfig{
-		App:             config.App,
-		HelloController: helloContoller,
-		UserController:  userController,
-		AuthController:  authController,
+		App:                 config.App,
+		AuthMiddleware:      authMiddleware,
+		LoggingMiddleware:   loggingMiddleware,
+		MetricsMiddleware:   metricsMiddleware,
+		TracingMiddleware:   tracingMiddleware,
+		RateLimitMiddleware: rateLimitMiddleware,
+		CSRFMiddleware:      csrfMiddleware,
+		UserController:      userController,
+		AuthController:      authController,
+		AccountController:   accountController,
+		TwoFactorController: twoFactorController,
+		TokenController:     tokenController,
+		AddressController:   addressController,
+		AuditController:     auditController,
+		RoleController:      roleController,
+		SearchController:    searchController,
+		ImportController:    importController,
+		ExportController:    exportController,
+		PrivacyController:   privacyController,
+		DatabaseController:  databaseController,
+		HealthController:    healthController,
+		MetricsController:   metricsController,
 	}
 	routeConfig.Setup()
 
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"sistem-06-Backend/internal/pkg/password"
+	"sistem-06-Backend/internal/usecase"
+)
+
+func NewLoginPolicy(config *Config) usecase.LoginPolicy {
+	return usecase.LoginPolicy{
+		FreeAttempts:     config.Login.FreeAttempts,
+		BaseDelay:        time.Duration(config.Login.DelayBaseSeconds) * time.Second,
+		MaxDelay:         time.Duration(config.Login.DelayMaxSeconds) * time.Second,
+		AccountThreshold: config.Login.AccountThreshold,
+		IPThreshold:      config.Login.IPThreshold,
+		Window:           time.Duration(config.Login.WindowMinutes) * time.Minute,
+		LockoutDuration:  time.Duration(config.Login.LockoutMinutes) * time.Minute,
+		TwoFactorTimeout: time.Duration(config.Login.TwoFactorTimeoutMinutes) * time.Minute,
+	}
+}
+
+// NewPasswordPolicy loads the breached-password list as well, so a missing
+// breached_list_file stops the start-up.
+func NewPasswordPolicy(config *Config) (*password.Policy, error) {
+	policy := &password.Policy{
+		MinLength:           config.Password.MinLength,
+		MaxLength:           config.Password.MaxLength,
+		MinCharacterClasses: config.Password.MinCharacterClasses,
+		RequireUppercase:    config.Password.RequireUppercase,
+		RequireLowercase:    config.Password.RequireLowercase,
+		RequireDigit:        config.Password.RequireDigit,
+		RequireSymbol:       config.Password.RequireSymbol,
+		RejectPersonalInfo:  config.Password.RejectPersonalInfo,
+	}
+	if config.Password.Hash.Algorithm == "bcrypt" {
+		policy.MaxBytes = password.BcryptMaxBytes
+	}
+	if config.Password.CheckBreached {
+		list, err := password.LoadBreachedListFile(config.Password.BreachedListFile)
+		if err != nil {
+			return nil, fmt.Errorf("breached password list: %w", err)
+		}
+		policy.Breached = list
+	}
+	return policy, nil
+}
+
+// NewPasswordHasher hashes with the configured algorithm and keeps the other
+// one for verifying hashes stored before a switch.
+func NewPasswordHasher(config *Config) password.Hasher {
+	hash := config.Password.Hash
+	bcrypt := &password.Bcrypt{Cost: hash.BcryptCost}
+	argon2id := &password.Argon2id{
+		Memory:      hash.Argon2.MemoryKiB,
+		Iterations:  hash.Argon2.Iterations,
+		Parallelism: hash.Argon2.Parallelism,
+		SaltLength:  hash.Argon2.SaltLength,
+		KeyLength:   hash.Argon2.KeyLength,
+	}
+
+	if hash.Algorithm == "argon2id" {
+		return password.NewHasher(argon2id, bcrypt)
+	}
+	return password.NewHasher(bcrypt, argon2id)
+}
@@ -0,0 +1,423 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/spf13/viper"
+)
+
+// EnvPrefix is prepended to every environment override, e.g.
+// SISTEM06_DATABASE_PASSWORD overrides database.password.
+const EnvPrefix = "SISTEM06"
+
+// Config is the typed view of config.json after defaults, environment
+// overrides and secret files have been applied.
+type Config struct {
+	App        AppConfig        `mapstructure:"app"`
+	Web        WebConfig        `mapstructure:"web"`
+	Log        LogConfig        `mapstructure:"log"`
+	Database   DatabaseConfig   `mapstructure:"database"`
+	Session    SessionConfig    `mapstructure:"session"`
+	CSRF       CSRFConfig       `mapstructure:"csrf"`
+	Metrics    MetricsConfig    `mapstructure:"metrics"`
+	Tracing    TracingConfig    `mapstructure:"tracing"`
+	Login      LoginConfig      `mapstructure:"login"`
+	RateLimit  RateLimitConfig  `mapstructure:"rate_limit"`
+	TwoFactor  TwoFactorConfig  `mapstructure:"two_factor"`
+	Token      TokenConfig      `mapstructure:"token"`
+	Password   PasswordConfig   `mapstructure:"password"`
+	Account    AccountConfig    `mapstructure:"account"`
+	SoftDelete SoftDeleteConfig `mapstructure:"soft_delete"`
+	Import     ImportConfig     `mapstructure:"import"`
+	Encryption EncryptionConfig `mapstructure:"encryption"`
+}
+
+type AppConfig struct {
+	Name string `mapstructure:"name"`
+}
+
+type WebConfig struct {
+	Port            int  `mapstructure:"port"`
+	Prefork         bool `mapstructure:"prefork"`
+	ReadTimeout     int  `mapstructure:"read_timeout"`
+	IdleTimeout     int  `mapstructure:"idle_timeout"`
+	DrainDelay      int  `mapstructure:"drain_delay"`
+	ShutdownTimeout int  `mapstructure:"shutdown_timeout"`
+}
+
+type LogConfig struct {
+	Level  int             `mapstructure:"level"`
+	Redact LogRedactConfig `mapstructure:"redact"`
+}
+
+// LogRedactConfig keeps personal data out of the logs. Values of the
+// listed fields, e.g. "password=..." in a message or a "new_password" log
+// field, are replaced by [REDACTED]; email, nik and phone values are also
+// recognised on their own. Patterns are extra regular expressions to
+// redact wherever they match.
+type LogRedactConfig struct {
+	Enabled  bool     `mapstructure:"enabled"`
+	Fields   []string `mapstructure:"fields"`
+	Patterns []string `mapstructure:"patterns"`
+}
+
+type DatabaseConfig struct {
+	URL                string             `mapstructure:"url"`
+	Host               string             `mapstructure:"host"`
+	Port               int                `mapstructure:"port"`
+	Username           string             `mapstructure:"username"`
+	Password           string             `mapstructure:"password"`
+	Name               string             `mapstructure:"name"`
+	SSLMode            string             `mapstructure:"sslmode"`
+	SSLRootCert        string             `mapstructure:"sslrootcert"`
+	SSLCert            string             `mapstructure:"sslcert"`
+	SSLKey             string             `mapstructure:"sslkey"`
+	ApplicationName    string             `mapstructure:"application_name"`
+	StatementTimeoutMs int                `mapstructure:"statement_timeout_ms"`
+	ConnectTimeout     int                `mapstructure:"connect_timeout"`
+	Pool               DatabasePoolConfig `mapstructure:"pool"`
+}
+
+type DatabasePoolConfig struct {
+	Max               int32 `mapstructure:"max"`
+	Idle              int32 `mapstructure:"idle"`
+	Lifetime          int   `mapstructure:"lifetime"`
+	IdleTimeout       int   `mapstructure:"idle_timeout"`
+	HealthCheckPeriod int   `mapstructure:"health_check_period"`
+}
+
+type SessionConfig struct {
+	Table             string              `mapstructure:"table"`
+	GCIntervalMinutes int                 `mapstructure:"gc_interval_minutes"`
+	ExpirationHours   int                 `mapstructure:"expiration_hours"`
+	Cookie            SessionCookieConfig `mapstructure:"cookie"`
+}
+
+// SessionCookieConfig applies to the session cookie and the CSRF cookie.
+// With host_prefix the names get the __Host- prefix, which makes browsers
+// insist on secure, no domain and path /.
+type SessionCookieConfig struct {
+	Name       string `mapstructure:"name"`
+	Secure     bool   `mapstructure:"secure"`
+	Domain     string `mapstructure:"domain"`
+	Path       string `mapstructure:"path"`
+	SameSite   string `mapstructure:"same_site"`
+	HostPrefix bool   `mapstructure:"host_prefix"`
+}
+
+// CookieName returns name with the __Host- prefix when it is enabled.
+func (c SessionCookieConfig) CookieName(name string) string {
+	if c.HostPrefix {
+		return "__Host-" + name
+	}
+	return name
+}
+
+// CSRFConfig protects state-changing requests authenticated by the session
+// cookie. The token is kept in the session and must be echoed in header_name
+// as well as in the cookie; requests with a bearer token are exempt.
+type CSRFConfig struct {
+	Enabled           bool   `mapstructure:"enabled"`
+	CookieName        string `mapstructure:"cookie_name"`
+	HeaderName        string `mapstructure:"header_name"`
+	ExpirationMinutes int    `mapstructure:"expiration_minutes"`
+}
+
+type MetricsConfig struct {
+	Enabled   bool     `mapstructure:"enabled"`
+	Token     string   `mapstructure:"token"`
+	Allowlist []string `mapstructure:"allowlist"`
+}
+
+type TracingConfig struct {
+	Exporter    string  `mapstructure:"exporter"`
+	Endpoint    string  `mapstructure:"endpoint"`
+	Insecure    bool    `mapstructure:"insecure"`
+	SampleRatio float64 `mapstructure:"sample_ratio"`
+}
+
+// LoginConfig controls brute-force protection. After free_attempts failures
+// every further failure blocks the account or IP for delay_base_seconds,
+// doubling up to delay_max_seconds; reaching a threshold locks it for
+// lockout_minutes. Counters reset window_minutes after the last failure.
+type LoginConfig struct {
+	FreeAttempts     int `mapstructure:"free_attempts"`
+	DelayBaseSeconds int `mapstructure:"delay_base_seconds"`
+	DelayMaxSeconds  int `mapstructure:"delay_max_seconds"`
+	AccountThreshold int `mapstructure:"account_threshold"`
+	IPThreshold      int `mapstructure:"ip_threshold"`
+	WindowMinutes    int `mapstructure:"window_minutes"`
+	LockoutMinutes   int `mapstructure:"lockout_minutes"`
+	// how long a correct password waits for the second factor
+	TwoFactorTimeoutMinutes int `mapstructure:"two_factor_timeout_minutes"`
+}
+
+// RateLimitConfig lists the rate limit rules; the first rule matching a
+// request applies. key is one of ip, user or ip_user.
+type RateLimitConfig struct {
+	Enabled              bool                  `mapstructure:"enabled"`
+	PurgeIntervalMinutes int                   `mapstructure:"purge_interval_minutes"`
+	Rules                []RateLimitRuleConfig `mapstructure:"rules"`
+}
+
+type RateLimitRuleConfig struct {
+	Name          string `mapstructure:"name"`
+	Method        string `mapstructure:"method"`
+	Path          string `mapstructure:"path"`
+	Limit         int    `mapstructure:"limit"`
+	WindowSeconds int    `mapstructure:"window_seconds"`
+	Key           string `mapstructure:"key"`
+}
+
+type TwoFactorConfig struct {
+	// shown next to the account in authenticator apps
+	Issuer string `mapstructure:"issuer"`
+}
+
+// PasswordConfig is the policy for new passwords. breached_list_file points
+// to a file of SHA-1 hashes ("HASH" or "HASH:COUNT" lines) replacing the
+// bundled list of common passwords.
+type PasswordConfig struct {
+	MinLength           int                `mapstructure:"min_length"`
+	MaxLength           int                `mapstructure:"max_length"`
+	MinCharacterClasses int                `mapstructure:"min_character_classes"`
+	RequireUppercase    bool               `mapstructure:"require_uppercase"`
+	RequireLowercase    bool               `mapstructure:"require_lowercase"`
+	RequireDigit        bool               `mapstructure:"require_digit"`
+	RequireSymbol       bool               `mapstructure:"require_symbol"`
+	RejectPersonalInfo  bool               `mapstructure:"reject_personal_info"`
+	CheckBreached       bool               `mapstructure:"check_breached"`
+	BreachedListFile    string             `mapstructure:"breached_list_file"`
+	Hash                PasswordHashConfig `mapstructure:"hash"`
+}
+
+// PasswordHashConfig picks the algorithm new password hashes are made with.
+// Stored hashes of the other algorithm, or with other parameters, are
+// replaced on the next successful login. argon2.memory_kib is in KiB.
+type PasswordHashConfig struct {
+	Algorithm  string               `mapstructure:"algorithm"`
+	BcryptCost int                  `mapstructure:"bcrypt_cost"`
+	Argon2     PasswordArgon2Config `mapstructure:"argon2"`
+}
+
+type PasswordArgon2Config struct {
+	MemoryKiB   uint32 `mapstructure:"memory_kib"`
+	Iterations  uint32 `mapstructure:"iterations"`
+	Parallelism uint8  `mapstructure:"parallelism"`
+	SaltLength  uint32 `mapstructure:"salt_length"`
+	KeyLength   uint32 `mapstructure:"key_length"`
+}
+
+// TokenConfig bounds the lifetime of personal access tokens; a token created
+// without expires_in_days gets the default.
+type TokenConfig struct {
+	DefaultLifetimeDays int `mapstructure:"default_lifetime_days"`
+	MaxLifetimeDays     int `mapstructure:"max_lifetime_days"`
+}
+
+// AccountConfig covers the self-service account endpoints. A requested email
+// change waits email_change_ttl_hours for confirmation. A deletion request
+// can be cancelled for deletion_grace_days; due requests are carried out
+// every deletion_interval_minutes.
+type AccountConfig struct {
+	EmailChangeTTLHours     int `mapstructure:"email_change_ttl_hours"`
+	DeletionGraceDays       int `mapstructure:"deletion_grace_days"`
+	DeletionIntervalMinutes int `mapstructure:"deletion_interval_minutes"`
+}
+
+// SoftDeleteConfig controls the purge job that removes deleted users,
+// addresses and roles for good after retention_days. A retention of 0 keeps
+// them forever.
+type SoftDeleteConfig struct {
+	RetentionDays        int `mapstructure:"retention_days"`
+	PurgeIntervalMinutes int `mapstructure:"purge_interval_minutes"`
+}
+
+// ImportConfig bounds the CSV and XLSX files of the bulk import endpoints.
+// The request body limit of the web server is raised to fit max_file_mb.
+type ImportConfig struct {
+	MaxRows   int `mapstructure:"max_rows"`
+	MaxFileMB int `mapstructure:"max_file_mb"`
+}
+
+// EncryptionConfig turns on encryption of personal data such as
+// users.email. keys maps key ids to base64 encoded 32 byte keys; new values
+// are encrypted under primary_key and the other keys only read older values
+// until `web encryption rotate` has moved them over. index_key keys the
+// blind indexes used for exact lookups and changing it also takes a rotate
+// run. key_file names a JSON file with primary_key, keys and index_key,
+// used instead of the inline keys. Without any keys values are stored in
+// the clear.
+type EncryptionConfig struct {
+	PrimaryKey string            `mapstructure:"primary_key"`
+	Keys       map[string]string `mapstructure:"keys"`
+	IndexKey   string            `mapstructure:"index_key"`
+	KeyFile    string            `mapstructure:"key_file"`
+}
+
+func setDefaults(config *viper.Viper) {
+	config.SetDefault("app.name", "sistem06")
+
+	config.SetDefault("web.port", 3000)
+	config.SetDefault("web.prefork", false)
+	config.SetDefault("web.read_timeout", 30)
+	config.SetDefault("web.idle_timeout", 60)
+	config.SetDefault("web.drain_delay", 5)
+	config.SetDefault("web.shutdown_timeout", 30)
+
+	config.SetDefault("log.level", 4)
+	config.SetDefault("log.redact.enabled", true)
+	config.SetDefault("log.redact.fields", []string{"email", "password", "nik", "phone"})
+	config.SetDefault("log.redact.patterns", []string{})
+
+	config.SetDefault("database.url", "")
+	config.SetDefault("database.host", "127.0.0.1")
+	config.SetDefault("database.port", 5432)
+	config.SetDefault("database.username", "")
+	config.SetDefault("database.password", "")
+	config.SetDefault("database.name", "")
+	config.SetDefault("database.sslmode", "prefer")
+	config.SetDefault("database.sslrootcert", "")
+	config.SetDefault("database.sslcert", "")
+	config.SetDefault("database.sslkey", "")
+	config.SetDefault("database.application_name", "")
+	config.SetDefault("database.statement_timeout_ms", 0)
+	config.SetDefault("database.connect_timeout", 10)
+	config.SetDefault("database.pool.max", 10)
+	config.SetDefault("database.pool.idle", 2)
+	config.SetDefault("database.pool.lifetime", 3600)
+	config.SetDefault("database.pool.idle_timeout", 1800)
+	config.SetDefault("database.pool.health_check_period", 60)
+
+	config.SetDefault("session.table", "sessions")
+	config.SetDefault("session.gc_interval_minutes", 10)
+	config.SetDefault("session.expiration_hours", 24)
+	config.SetDefault("session.cookie.name", "session_id")
+	config.SetDefault("session.cookie.secure", true)
+	config.SetDefault("session.cookie.domain", "")
+	config.SetDefault("session.cookie.path", "/")
+	config.SetDefault("session.cookie.same_site", "Lax")
+	config.SetDefault("session.cookie.host_prefix", false)
+
+	config.SetDefault("csrf.enabled", true)
+	config.SetDefault("csrf.cookie_name", "csrf_token")
+	config.SetDefault("csrf.header_name", "X-CSRF-Token")
+	config.SetDefault("csrf.expiration_minutes", 60)
+
+	config.SetDefault("metrics.enabled", true)
+	config.SetDefault("metrics.token", "")
+	config.SetDefault("metrics.allowlist", []string{"127.0.0.1/32", "::1/128"})
+
+	config.SetDefault("tracing.exporter", "none")
+	config.SetDefault("tracing.endpoint", "")
+	config.SetDefault("tracing.insecure", false)
+	config.SetDefault("tracing.sample_ratio", 1.0)
+
+	config.SetDefault("login.free_attempts", 3)
+	config.SetDefault("login.delay_base_seconds", 1)
+	config.SetDefault("login.delay_max_seconds", 30)
+	config.SetDefault("login.account_threshold", 10)
+	config.SetDefault("login.ip_threshold", 50)
+	config.SetDefault("login.window_minutes", 15)
+	config.SetDefault("login.lockout_minutes", 15)
+	config.SetDefault("login.two_factor_timeout_minutes", 5)
+
+	config.SetDefault("two_factor.issuer", "Sistem06")
+
+	config.SetDefault("password.min_length", 8)
+	config.SetDefault("password.max_length", 72)
+	config.SetDefault("password.min_character_classes", 2)
+	config.SetDefault("password.require_uppercase", false)
+	config.SetDefault("password.require_lowercase", false)
+	config.SetDefault("password.require_digit", false)
+	config.SetDefault("password.require_symbol", false)
+	config.SetDefault("password.reject_personal_info", true)
+	config.SetDefault("password.check_breached", true)
+	config.SetDefault("password.breached_list_file", "")
+	config.SetDefault("password.hash.algorithm", "bcrypt")
+	config.SetDefault("password.hash.bcrypt_cost", 12)
+	config.SetDefault("password.hash.argon2.memory_kib", 19456)
+	config.SetDefault("password.hash.argon2.iterations", 2)
+	config.SetDefault("password.hash.argon2.parallelism", 1)
+	config.SetDefault("password.hash.argon2.salt_length", 16)
+	config.SetDefault("password.hash.argon2.key_length", 32)
+
+	config.SetDefault("token.default_lifetime_days", 90)
+	config.SetDefault("token.max_lifetime_days", 365)
+
+	config.SetDefault("account.email_change_ttl_hours", 24)
+	config.SetDefault("account.deletion_grace_days", 14)
+	config.SetDefault("account.deletion_interval_minutes", 60)
+
+	config.SetDefault("soft_delete.retention_days", 90)
+	config.SetDefault("soft_delete.purge_interval_minutes", 60)
+
+	config.SetDefault("import.max_rows", 5000)
+	config.SetDefault("import.max_file_mb", 10)
+
+	config.SetDefault("encryption.primary_key", "")
+	config.SetDefault("encryption.keys", map[string]string{})
+	config.SetDefault("encryption.index_key", "")
+	config.SetDefault("encryption.key_file", "")
+
+	config.SetDefault("rate_limit.enabled", true)
+	config.SetDefault("rate_limit.purge_interval_minutes", 5)
+	config.SetDefault("rate_limit.rules", []map[string]any{
+		{"name": "login", "method": "POST", "path": "/api/v1/login*", "limit": 10, "window_seconds": 60, "key": "ip"},
+		{"name": "register", "method": "POST", "path": "/api/v1/users", "limit": 5, "window_seconds": 3600, "key": "ip"},
+		{"name": "api", "path": "/api/v1/*", "limit": 300, "window_seconds": 60, "key": "ip_user"},
+	})
+}
+
+// loadSecretFiles applies <ENV>_FILE variables, so container secrets such as
+// SISTEM06_DATABASE_PASSWORD_FILE=/run/secrets/db_password can be mounted
+// instead of being passed as plain environment variables.
+func loadSecretFiles(config *viper.Viper) error {
+	for _, key := range config.AllKeys() {
+		path := os.Getenv(envName(key) + "_FILE")
+		if path == "" {
+			continue
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read secret file for %s: %w", key, err)
+		}
+		config.Set(key, strings.TrimRight(string(content), "\r\n"))
+	}
+	return nil
+}
+
+func envName(key string) string {
+	return EnvPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// NewConfig decodes the viper settings into a Config and validates it. Every
+// invalid key is reported in the returned ValidationErrors.
+func NewConfig(config *viper.Viper) (*Config, error) {
+	return decodeConfig(config)
+}
+
+// CheckConfig is NewConfig for config check: it also rejects keys that no
+// setting reads, so a misspelled key such as databse.host is reported
+// instead of silently falling back to the default.
+func CheckConfig(config *viper.Viper) (*Config, error) {
+	return decodeConfig(config, func(decoder *mapstructure.DecoderConfig) {
+		decoder.ErrorUnused = true
+	})
+}
+
+func decodeConfig(config *viper.Viper, opts ...viper.DecoderConfigOption) (*Config, error) {
+	cfg := new(Config)
+	if err := config.Unmarshal(cfg, opts...); err != nil {
+		return nil, fmt.Errorf("decode configuration: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
@@ -0,0 +1,389 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"sistem-06-Backend/internal/pkg/password"
+)
+
+// ValidationErrors lists every invalid configuration key found at startup.
+type ValidationErrors []string
+
+func (e ValidationErrors) Error() string {
+	return "invalid configuration:\n  " + strings.Join(e, "\n  ")
+}
+
+func (e *ValidationErrors) add(key string, format string, args ...any) {
+	*e = append(*e, fmt.Sprintf("%s: %s", key, fmt.Sprintf(format, args...)))
+}
+
+var validSSLModes = map[string]bool{
+	"disable":     true,
+	"allow":       true,
+	"prefer":      true,
+	"require":     true,
+	"verify-ca":   true,
+	"verify-full": true,
+}
+
+func (c *Config) Validate() error {
+	var errs ValidationErrors
+
+	if c.App.Name == "" {
+		errs.add("app.name", "is required")
+	}
+
+	if c.Web.Port < 1 || c.Web.Port > 65535 {
+		errs.add("web.port", "must be between 1 and 65535, got %d", c.Web.Port)
+	}
+	if c.Web.ReadTimeout < 0 {
+		errs.add("web.read_timeout", "must not be negative")
+	}
+	if c.Web.IdleTimeout < 0 {
+		errs.add("web.idle_timeout", "must not be negative")
+	}
+	if c.Web.DrainDelay < 0 {
+		errs.add("web.drain_delay", "must not be negative")
+	}
+	if c.Web.ShutdownTimeout < 1 {
+		errs.add("web.shutdown_timeout", "must be at least 1 second")
+	}
+
+	if c.Log.Level < 0 || c.Log.Level > 6 {
+		errs.add("log.level", "must be between 0 (panic) and 6 (trace), got %d", c.Log.Level)
+	}
+	for _, pattern := range c.Log.Redact.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			errs.add("log.redact.patterns", "%q is not a valid regular expression: %v", pattern, err)
+		}
+	}
+
+	c.Database.validate(&errs)
+	c.Session.validate(&errs)
+	c.CSRF.validate(&errs)
+	c.Metrics.validate(&errs)
+	c.Tracing.validate(&errs)
+	c.Login.validate(&errs)
+	c.RateLimit.validate(&errs)
+
+	if c.TwoFactor.Issuer == "" {
+		errs.add("two_factor.issuer", "is required")
+	} else if strings.Contains(c.TwoFactor.Issuer, ":") {
+		errs.add("two_factor.issuer", "must not contain a colon")
+	}
+
+	c.Password.validate(&errs)
+
+	if c.Token.DefaultLifetimeDays < 1 {
+		errs.add("token.default_lifetime_days", "must be at least 1")
+	}
+	if c.Token.MaxLifetimeDays < c.Token.DefaultLifetimeDays {
+		errs.add("token.max_lifetime_days", "must not be less than token.default_lifetime_days")
+	}
+
+	if c.Account.EmailChangeTTLHours < 1 {
+		errs.add("account.email_change_ttl_hours", "must be at least 1")
+	}
+	if c.Account.DeletionGraceDays < 0 {
+		errs.add("account.deletion_grace_days", "must not be negative")
+	}
+	if c.Account.DeletionIntervalMinutes < 1 {
+		errs.add("account.deletion_interval_minutes", "must be at least 1")
+	}
+
+	if c.SoftDelete.RetentionDays < 0 {
+		errs.add("soft_delete.retention_days", "must not be negative")
+	}
+	if c.SoftDelete.PurgeIntervalMinutes < 1 {
+		errs.add("soft_delete.purge_interval_minutes", "must be at least 1")
+	}
+
+	if c.Import.MaxRows < 1 {
+		errs.add("import.max_rows", "must be at least 1")
+	}
+	if c.Import.MaxFileMB < 1 {
+		errs.add("import.max_file_mb", "must be at least 1")
+	}
+
+	c.Encryption.validate(&errs)
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (c *DatabaseConfig) validate(errs *ValidationErrors) {
+	// database.url carries host, credentials and TLS settings on its own
+	if c.URL == "" {
+		if c.Host == "" {
+			errs.add("database.host", "is required when database.url is empty")
+		}
+		if c.Port < 1 || c.Port > 65535 {
+			errs.add("database.port", "must be between 1 and 65535, got %d", c.Port)
+		}
+		if c.Username == "" {
+			errs.add("database.username", "is required when database.url is empty")
+		}
+		if c.Name == "" {
+			errs.add("database.name", "is required when database.url is empty")
+		}
+		if !validSSLModes[c.SSLMode] {
+			errs.add("database.sslmode", "unknown mode %q", c.SSLMode)
+		}
+		if (c.SSLMode == "verify-ca" || c.SSLMode == "verify-full") && c.SSLRootCert == "" {
+			errs.add("database.sslrootcert", "is required for sslmode %s", c.SSLMode)
+		}
+		if (c.SSLCert == "") != (c.SSLKey == "") {
+			errs.add("database.sslcert", "sslcert and sslkey must be set together")
+		}
+	} else if !strings.HasPrefix(c.URL, "postgres://") && !strings.HasPrefix(c.URL, "postgresql://") {
+		errs.add("database.url", "must start with postgres:// or postgresql://")
+	}
+
+	if c.StatementTimeoutMs < 0 {
+		errs.add("database.statement_timeout_ms", "must not be negative")
+	}
+	if c.ConnectTimeout < 1 {
+		errs.add("database.connect_timeout", "must be at least 1 second")
+	}
+	if c.Pool.Max < 1 {
+		errs.add("database.pool.max", "must be at least 1, got %d", c.Pool.Max)
+	}
+	if c.Pool.Idle < 0 || c.Pool.Idle > c.Pool.Max {
+		errs.add("database.pool.idle", "must be between 0 and database.pool.max (%d), got %d", c.Pool.Max, c.Pool.Idle)
+	}
+	if c.Pool.Lifetime < 0 {
+		errs.add("database.pool.lifetime", "must not be negative")
+	}
+	if c.Pool.IdleTimeout < 0 {
+		errs.add("database.pool.idle_timeout", "must not be negative")
+	}
+	if c.Pool.HealthCheckPeriod < 0 {
+		errs.add("database.pool.health_check_period", "must not be negative")
+	}
+}
+
+func (c *SessionConfig) validate(errs *ValidationErrors) {
+	if c.Table == "" {
+		errs.add("session.table", "is required")
+	}
+	if c.GCIntervalMinutes < 1 {
+		errs.add("session.gc_interval_minutes", "must be at least 1")
+	}
+	if c.ExpirationHours < 1 {
+		errs.add("session.expiration_hours", "must be at least 1")
+	}
+	c.Cookie.validate(errs)
+}
+
+var validSameSite = map[string]bool{
+	"strict": true,
+	"lax":    true,
+	"none":   true,
+}
+
+func (c *SessionCookieConfig) validate(errs *ValidationErrors) {
+	if c.Name == "" {
+		errs.add("session.cookie.name", "is required")
+	}
+	if !strings.HasPrefix(c.Path, "/") {
+		errs.add("session.cookie.path", "must start with /")
+	}
+	if !validSameSite[strings.ToLower(c.SameSite)] {
+		errs.add("session.cookie.same_site", "must be one of Strict, Lax or None, got %q", c.SameSite)
+	} else if strings.EqualFold(c.SameSite, "none") && !c.Secure {
+		errs.add("session.cookie.same_site", "None requires session.cookie.secure")
+	}
+	if c.HostPrefix {
+		if !c.Secure {
+			errs.add("session.cookie.host_prefix", "requires session.cookie.secure")
+		}
+		if c.Domain != "" {
+			errs.add("session.cookie.host_prefix", "requires an empty session.cookie.domain")
+		}
+		if c.Path != "/" {
+			errs.add("session.cookie.host_prefix", "requires session.cookie.path to be /")
+		}
+	}
+}
+
+func (c *PasswordConfig) validate(errs *ValidationErrors) {
+	if c.MinLength < 1 {
+		errs.add("password.min_length", "must be at least 1")
+	}
+	// bcrypt refuses more than 72 bytes, the policy also checks the bytes
+	maxLength := password.MaxLength
+	if c.Hash.Algorithm == "bcrypt" {
+		maxLength = password.BcryptMaxBytes
+	}
+	if c.MaxLength < c.MinLength || c.MaxLength > maxLength {
+		errs.add("password.max_length", "must be between password.min_length and %d, got %d", maxLength, c.MaxLength)
+	}
+	if c.MinCharacterClasses < 0 || c.MinCharacterClasses > 4 {
+		errs.add("password.min_character_classes", "must be between 0 and 4, got %d", c.MinCharacterClasses)
+	}
+	c.Hash.validate(errs)
+}
+
+func (c *PasswordHashConfig) validate(errs *ValidationErrors) {
+	switch c.Algorithm {
+	case "bcrypt", "argon2id":
+	default:
+		errs.add("password.hash.algorithm", "must be bcrypt or argon2id, got %q", c.Algorithm)
+	}
+	if c.BcryptCost < 10 || c.BcryptCost > 31 {
+		errs.add("password.hash.bcrypt_cost", "must be between 10 and 31, got %d", c.BcryptCost)
+	}
+	if c.Argon2.Iterations < 1 {
+		errs.add("password.hash.argon2.iterations", "must be at least 1")
+	}
+	if c.Argon2.Parallelism < 1 {
+		errs.add("password.hash.argon2.parallelism", "must be at least 1")
+	}
+	if c.Argon2.MemoryKiB < 8*uint32(c.Argon2.Parallelism) {
+		errs.add("password.hash.argon2.memory_kib", "must be at least 8 times password.hash.argon2.parallelism")
+	}
+	if c.Argon2.SaltLength < 8 {
+		errs.add("password.hash.argon2.salt_length", "must be at least 8")
+	}
+	if c.Argon2.KeyLength < 16 {
+		errs.add("password.hash.argon2.key_length", "must be at least 16")
+	}
+}
+
+func (c *CSRFConfig) validate(errs *ValidationErrors) {
+	if !c.Enabled {
+		return
+	}
+	if c.CookieName == "" {
+		errs.add("csrf.cookie_name", "is required")
+	}
+	if c.HeaderName == "" {
+		errs.add("csrf.header_name", "is required")
+	}
+	if c.ExpirationMinutes < 1 {
+		errs.add("csrf.expiration_minutes", "must be at least 1")
+	}
+}
+
+func (c *MetricsConfig) validate(errs *ValidationErrors) {
+	for _, cidr := range c.Allowlist {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			errs.add("metrics.allowlist", "invalid CIDR %q", cidr)
+		}
+	}
+	if c.Enabled && c.Token == "" && len(c.Allowlist) == 0 {
+		errs.add("metrics.token", "metrics.token or metrics.allowlist is required when metrics are enabled")
+	}
+}
+
+func (c *TracingConfig) validate(errs *ValidationErrors) {
+	switch c.Exporter {
+	case "none", "stdout":
+	case "otlp":
+		if c.Endpoint == "" {
+			errs.add("tracing.endpoint", "is required for the otlp exporter")
+		}
+	default:
+		errs.add("tracing.exporter", "must be one of none, stdout, otlp, got %q", c.Exporter)
+	}
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		errs.add("tracing.sample_ratio", "must be between 0 and 1, got %v", c.SampleRatio)
+	}
+}
+
+func (c *LoginConfig) validate(errs *ValidationErrors) {
+	if c.FreeAttempts < 0 {
+		errs.add("login.free_attempts", "must not be negative")
+	}
+	if c.DelayBaseSeconds < 0 {
+		errs.add("login.delay_base_seconds", "must not be negative")
+	}
+	if c.DelayMaxSeconds < c.DelayBaseSeconds {
+		errs.add("login.delay_max_seconds", "must be at least login.delay_base_seconds (%d)", c.DelayBaseSeconds)
+	}
+	if c.AccountThreshold <= c.FreeAttempts {
+		errs.add("login.account_threshold", "must be greater than login.free_attempts (%d)", c.FreeAttempts)
+	}
+	if c.IPThreshold <= c.FreeAttempts {
+		errs.add("login.ip_threshold", "must be greater than login.free_attempts (%d)", c.FreeAttempts)
+	}
+	if c.WindowMinutes < 1 {
+		errs.add("login.window_minutes", "must be at least 1")
+	}
+	if c.LockoutMinutes < 1 {
+		errs.add("login.lockout_minutes", "must be at least 1")
+	}
+	if c.TwoFactorTimeoutMinutes < 1 {
+		errs.add("login.two_factor_timeout_minutes", "must be at least 1")
+	}
+}
+
+var validRateLimitKeys = map[string]bool{
+	"ip":      true,
+	"user":    true,
+	"ip_user": true,
+}
+
+func (c *RateLimitConfig) validate(errs *ValidationErrors) {
+	if c.Enabled && c.PurgeIntervalMinutes < 1 {
+		errs.add("rate_limit.purge_interval_minutes", "must be at least 1")
+	}
+
+	names := make(map[string]bool, len(c.Rules))
+	for i, rule := range c.Rules {
+		key := fmt.Sprintf("rate_limit.rules[%d]", i)
+		if rule.Name == "" {
+			errs.add(key+".name", "is required")
+		} else if names[rule.Name] {
+			errs.add(key+".name", "duplicate rule name %q", rule.Name)
+		}
+		names[rule.Name] = true
+
+		if !strings.HasPrefix(rule.Path, "/") {
+			errs.add(key+".path", "must start with /, got %q", rule.Path)
+		}
+		if rule.Method != strings.ToUpper(rule.Method) {
+			errs.add(key+".method", "must be upper case, got %q", rule.Method)
+		}
+		if rule.Limit < 1 {
+			errs.add(key+".limit", "must be at least 1")
+		}
+		if rule.WindowSeconds < 1 {
+			errs.add(key+".window_seconds", "must be at least 1")
+		}
+		if !validRateLimitKeys[rule.Key] {
+			errs.add(key+".key", "must be one of ip, user, ip_user, got %q", rule.Key)
+		}
+	}
+}
+
+// validate checks the inline keys; a key_file is only read at start-up.
+func (c *EncryptionConfig) validate(errs *ValidationErrors) {
+	inline := c.PrimaryKey != "" || len(c.Keys) > 0 || c.IndexKey != ""
+	if c.KeyFile != "" {
+		if inline {
+			errs.add("encryption.key_file", "cannot be combined with inline keys")
+		}
+		return
+	}
+	if !inline {
+		return
+	}
+
+	if _, ok := c.Keys[c.PrimaryKey]; !ok {
+		errs.add("encryption.primary_key", "must name one of encryption.keys, got %q", c.PrimaryKey)
+	}
+	for id, key := range c.Keys {
+		if _, err := decodeKey(key); err != nil {
+			errs.add("encryption.keys."+id, "%v", err)
+		}
+	}
+	if _, err := decodeKey(c.IndexKey); err != nil {
+		errs.add("encryption.index_key", "%v", err)
+	}
+}
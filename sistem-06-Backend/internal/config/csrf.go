@@ -0,0 +1,26 @@
+package config
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2/middleware/csrf"
+	"github.com/gofiber/fiber/v2/middleware/session"
+)
+
+// NewCSRF keeps the token in the session (synchronizer token) and also
+// compares it with the cookie (double submit). The cookie is readable from
+// JavaScript so single page apps can copy it into the header.
+func NewCSRF(config *Config, store *session.Store) csrf.Config {
+	cookie := config.Session.Cookie
+	return csrf.Config{
+		KeyLookup:      "header:" + config.CSRF.HeaderName,
+		CookieName:     cookie.CookieName(config.CSRF.CookieName),
+		CookieDomain:   cookie.Domain,
+		CookiePath:     cookie.Path,
+		CookieSecure:   cookie.Secure,
+		CookieHTTPOnly: false,
+		CookieSameSite: cookie.SameSite,
+		Expiration:     time.Duration(config.CSRF.ExpirationMinutes) * time.Minute,
+		Session:        store,
+	}
+}
@@ -1,47 +1,105 @@
 package config
 
 import (
+	"context"
 	"database/sql"
-	"fmt"
+	"net"
+	"net/url"
+	"strconv"
 	"time"
 
-	_ "github.com/lib/pq"
+	"sistem-06-Backend/internal/infrastructure/tracing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/sirupsen/logrus"
-	"github.com/spf13/viper"
 )
 
-func NewPostgres(config *viper.Viper, log *logrus.Logger) *sql.DB {
-	// URI := config.GetString("database.url")
-	username := config.GetString("database.username")
-	password := config.GetString("database.password")
-	port := config.GetInt("database.port")
-	host := config.GetString("database.host")
-	dbname := config.GetString("database.name")
-	dsn := fmt.Sprintf(
-		"host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
-		host,
-		port,
-		username,
-		password,
-		dbname,
-	)
-
-	db, err := sql.Open("postgres", dsn)
+// NewDatabase creates the single pgx pool shared by sqlc, the session
+// storage and every other component that talks to postgres.
+func NewDatabase(config *Config, log *logrus.Logger) *pgxpool.Pool {
+	poolConfig, err := pgxpool.ParseConfig(databaseDSN(&config.Database))
 	if err != nil {
-		log.Fatalf("failed to connect database: %v", err)
+		log.Fatalf("invalid database configuration: %v", err)
 	}
 
+	runtimeParams := poolConfig.ConnConfig.RuntimeParams
+	if config.Database.ApplicationName != "" {
+		runtimeParams["application_name"] = config.Database.ApplicationName
+	} else if runtimeParams["application_name"] == "" {
+		runtimeParams["application_name"] = config.App.Name
+	}
+	if timeout := config.Database.StatementTimeoutMs; timeout > 0 {
+		runtimeParams["statement_timeout"] = strconv.Itoa(timeout)
+	}
+
+	// One span per statement, including sqlc queries run through database/sql
+	poolConfig.ConnConfig.Tracer = tracing.NewQueryTracer()
+
 	// Connection pool configuration
-	db.SetMaxIdleConns(config.GetInt("database.pool.idle"))
-	db.SetMaxOpenConns(config.GetInt("database.pool.max"))
-	db.SetConnMaxLifetime(
-		time.Duration(config.GetInt("database.pool.lifetime")) * time.Second,
-	)
+	pool := config.Database.Pool
+	poolConfig.MaxConns = pool.Max
+	poolConfig.MinConns = pool.Idle
+	if pool.Lifetime > 0 {
+		poolConfig.MaxConnLifetime = time.Duration(pool.Lifetime) * time.Second
+	}
+	if pool.IdleTimeout > 0 {
+		poolConfig.MaxConnIdleTime = time.Duration(pool.IdleTimeout) * time.Second
+	}
+	if pool.HealthCheckPeriod > 0 {
+		poolConfig.HealthCheckPeriod = time.Duration(pool.HealthCheckPeriod) * time.Second
+	}
+
+	connectTimeout := time.Duration(config.Database.ConnectTimeout) * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
+	defer cancel()
+
+	db, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		log.Fatalf("failed to connect database: %v", err)
+	}
 
 	// Test koneksi ke DB
-	if err := db.Ping(); err != nil {
+	if err := db.Ping(ctx); err != nil {
 		log.Fatalf("failed to ping database: %v", err)
 	}
 
+	log.Infof("Database pool initialized (host: %s, database: %s, max conns: %d)",
+		poolConfig.ConnConfig.Host, poolConfig.ConnConfig.Database, poolConfig.MaxConns)
 	return db
 }
+
+// NewPostgres exposes the shared pool through database/sql for sqlc and the
+// usecase transactions.
+func NewPostgres(pool *pgxpool.Pool) *sql.DB {
+	return stdlib.OpenDBFromPool(pool)
+}
+
+// databaseDSN returns database.url when set, otherwise it builds a postgres
+// URL from the individual database.* keys.
+func databaseDSN(config *DatabaseConfig) string {
+	if config.URL != "" {
+		return config.URL
+	}
+
+	query := url.Values{}
+	query.Set("sslmode", config.SSLMode)
+	if config.SSLRootCert != "" {
+		query.Set("sslrootcert", config.SSLRootCert)
+	}
+	if config.SSLCert != "" {
+		query.Set("sslcert", config.SSLCert)
+	}
+	if config.SSLKey != "" {
+		query.Set("sslkey", config.SSLKey)
+	}
+
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(config.Username, config.Password),
+		Host:     net.JoinHostPort(config.Host, strconv.Itoa(config.Port)),
+		Path:     "/" + config.Name,
+		RawQuery: query.Encode(),
+	}
+	return dsn.String()
+}
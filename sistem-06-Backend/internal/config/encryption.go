@@ -0,0 +1,65 @@
+package config
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"sistem-06-Backend/internal/pkg/fieldcrypt"
+)
+
+// encryptionKeyFile is the layout of encryption.key_file.
+type encryptionKeyFile struct {
+	PrimaryKey string            `json:"primary_key"`
+	Keys       map[string]string `json:"keys"`
+	IndexKey   string            `json:"index_key"`
+}
+
+// NewFieldCipher builds the keyring from the inline keys or the key file.
+// It returns nil, which stores values in the clear, when no keys are
+// configured.
+func NewFieldCipher(config *Config) (*fieldcrypt.Cipher, error) {
+	keyFile := encryptionKeyFile{
+		PrimaryKey: config.Encryption.PrimaryKey,
+		Keys:       config.Encryption.Keys,
+		IndexKey:   config.Encryption.IndexKey,
+	}
+	if path := config.Encryption.KeyFile; path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read encryption key file: %w", err)
+		}
+		if err := json.Unmarshal(content, &keyFile); err != nil {
+			return nil, fmt.Errorf("parse encryption key file %s: %w", path, err)
+		}
+	}
+	if keyFile.PrimaryKey == "" && len(keyFile.Keys) == 0 && keyFile.IndexKey == "" {
+		return nil, nil
+	}
+
+	keys := make(map[string][]byte, len(keyFile.Keys))
+	for id, encoded := range keyFile.Keys {
+		key, err := decodeKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("encryption key %s: %w", id, err)
+		}
+		keys[id] = key
+	}
+	indexKey, err := decodeKey(keyFile.IndexKey)
+	if err != nil {
+		return nil, fmt.Errorf("encryption index key: %w", err)
+	}
+	return fieldcrypt.New(keyFile.PrimaryKey, keys, indexKey)
+}
+
+func decodeKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("must be base64: %v", err)
+	}
+	if len(key) != fieldcrypt.KeySize {
+		return nil, fmt.Errorf("must be %d bytes, got %d", fieldcrypt.KeySize, len(key))
+	}
+	return key, nil
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type shutdownHook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// Lifecycle owns the readiness flag, the background workers and the shutdown
+// hooks of the process. Hooks run in reverse registration order, so a
+// component registered after its dependencies is closed before them.
+type Lifecycle struct {
+	Log *logrus.Logger
+
+	ready   atomic.Bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+	workers sync.WaitGroup
+
+	mu    sync.Mutex
+	hooks []shutdownHook
+}
+
+func NewLifecycle(log *logrus.Logger) *Lifecycle {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Lifecycle{
+		Log:    log,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// OnShutdown registers a hook that is called once the HTTP server has
+// stopped accepting and draining requests.
+func (l *Lifecycle) OnShutdown(name string, fn func(ctx context.Context) error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hooks = append(l.hooks, shutdownHook{name: name, fn: fn})
+}
+
+// Go starts a background worker. The worker's context is canceled when
+// shutdown begins and Shutdown waits for it to return before running hooks.
+func (l *Lifecycle) Go(name string, fn func(ctx context.Context)) {
+	l.workers.Add(1)
+	go func() {
+		defer l.workers.Done()
+		l.Log.Infof("Background worker %s started", name)
+		fn(l.ctx)
+		l.Log.Infof("Background worker %s stopped", name)
+	}()
+}
+
+// Every runs fn on a background worker each interval until shutdown. Errors
+// are logged and the next run happens as scheduled.
+func (l *Lifecycle) Every(name string, interval time.Duration, fn func(ctx context.Context) error) {
+	l.Go(name, func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := fn(ctx); err != nil {
+					l.Log.Errorf("Background worker %s failed: %v", name, err)
+				}
+			}
+		}
+	})
+}
+
+func (l *Lifecycle) MarkReady() {
+	l.ready.Store(true)
+}
+
+func (l *Lifecycle) MarkNotReady() {
+	l.ready.Store(false)
+}
+
+func (l *Lifecycle) IsReady() bool {
+	return l.ready.Load()
+}
+
+// Shutdown stops the background workers and runs every hook, newest first.
+// All hooks are attempted even when one of them fails.
+func (l *Lifecycle) Shutdown(ctx context.Context) error {
+	l.MarkNotReady()
+	l.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		l.workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		l.Log.Warn("Timed out waiting for background workers to stop")
+	}
+
+	l.mu.Lock()
+	hooks := make([]shutdownHook, len(l.hooks))
+	copy(hooks, l.hooks)
+	l.mu.Unlock()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hook := hooks[i]
+		l.Log.Infof("Shutting down %s", hook.name)
+		if err := hook.fn(ctx); err != nil {
+			l.Log.Errorf("Failed to shut down %s: %v", hook.name, err)
+			errs = append(errs, fmt.Errorf("%s: %w", hook.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
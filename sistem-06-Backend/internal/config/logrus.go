@@ -1,13 +1,23 @@
 package config
 
 import (
+	"sistem-06-Backend/internal/infrastructure/tracing"
+	"sistem-06-Backend/internal/pkg/redact"
+
 	"github.com/sirupsen/logrus"
-	"github.com/spf13/viper"
 )
 
-func NewLogger(viper *viper.Viper) *logrus.Logger {
+func NewLogger(config *Config) *logrus.Logger {
 	log := logrus.New()
-	log.SetLevel(logrus.Level(viper.GetInt32("log.level")))
+	log.SetLevel(logrus.Level(config.Log.Level))
 	log.SetFormatter(&logrus.JSONFormatter{})
+	log.AddHook(tracing.NewLogHook())
+	if config.Log.Redact.Enabled {
+		redactor, err := redact.New(config.Log.Redact.Fields, config.Log.Redact.Patterns)
+		if err != nil {
+			log.Fatalf("Failed to build log redactor: %+v", err)
+		}
+		log.AddHook(redact.NewHook(redactor))
+	}
 	return log
 }
@@ -0,0 +1,16 @@
+package config
+
+import (
+	"database/sql"
+
+	"sistem-06-Backend/internal/infrastructure/metrics"
+)
+
+// NewMetrics returns nil when metrics.enabled is false; every consumer
+// treats a nil *metrics.Metrics as a no-op.
+func NewMetrics(config *Config, db *sql.DB) *metrics.Metrics {
+	if !config.Metrics.Enabled {
+		return nil
+	}
+	return metrics.NewMetrics(db)
+}
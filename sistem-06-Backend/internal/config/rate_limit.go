@@ -0,0 +1,28 @@
+package config
+
+import (
+	"time"
+
+	"sistem-06-Backend/internal/delivery/http/middleware"
+)
+
+// NewRateLimitRules returns no rules when rate limiting is disabled, which
+// turns the middleware into a pass-through.
+func NewRateLimitRules(config *Config) []middleware.RateLimitRule {
+	if !config.RateLimit.Enabled {
+		return nil
+	}
+
+	rules := make([]middleware.RateLimitRule, 0, len(config.RateLimit.Rules))
+	for _, rule := range config.RateLimit.Rules {
+		rules = append(rules, middleware.RateLimitRule{
+			Name:   rule.Name,
+			Method: rule.Method,
+			Path:   rule.Path,
+			Limit:  rule.Limit,
+			Window: time.Duration(rule.WindowSeconds) * time.Second,
+			Key:    rule.Key,
+		})
+	}
+	return rules
+}
@@ -1,54 +1,37 @@
 package config
 
 import (
-	"database/sql"
 	"time"
 
+	"sistem-06-Backend/internal/infrastructure/metrics"
+
 	"github.com/gofiber/fiber/v2/middleware/session"
 	"github.com/gofiber/storage/postgres/v3"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/sirupsen/logrus"
-	"github.com/spf13/viper"
 )
 
-func NewSession(viper *viper.Viper, db *sql.DB, log *logrus.Logger) *session.Store {
-	// connectionURI := viper.GetString("database.url")
-	tableName := viper.GetString("session.table")
-
-	if tableName == "" {
-		tableName = "sessions"
-		log.Warn("session.table not configured, using default: sessions")
-	}
-
-	gcInterval := viper.GetDuration("session.gc_interval_minutes")
-	if gcInterval == 0 {
-		gcInterval = 10
-	}
-
-	expiration := viper.GetDuration("session.expiration_hours")
-	if expiration == 0 {
-		expiration = 24
-	}
-
-	log.Infof("Initializing session storage (table: %s)", tableName)
+func NewSession(config *Config, pool *pgxpool.Pool, metrics *metrics.Metrics, log *logrus.Logger) *session.Store {
+	log.Infof("Initializing session storage (table: %s)", config.Session.Table)
 
+	// The storage shares the application pool instead of opening its own
 	storage := postgres.New(postgres.Config{
-		Host:       viper.GetString("database.host"),
-		Username:   viper.GetString("database.username"),
-		Password:   viper.GetString("database.password"),
-		Port:       viper.GetInt("database.port"),
-		Database:   viper.GetString("database.name"),
+		DB:         pool,
+		Table:      config.Session.Table,
 		Reset:      false,
-		GCInterval: gcInterval * time.Minute,
+		GCInterval: time.Duration(config.Session.GCIntervalMinutes) * time.Minute,
 	})
 
+	cookie := config.Session.Cookie
 	store := session.New(session.Config{
-		Storage:        storage,
-		Expiration:     expiration * time.Hour,
-		KeyLookup:      "cookie:session_id",
-		CookiePath:     "/",
-		CookieSecure:   false,
+		Storage:        metrics.InstrumentStorage(storage),
+		Expiration:     time.Duration(config.Session.ExpirationHours) * time.Hour,
+		KeyLookup:      "cookie:" + cookie.CookieName(cookie.Name),
+		CookieDomain:   cookie.Domain,
+		CookiePath:     cookie.Path,
+		CookieSecure:   cookie.Secure,
 		CookieHTTPOnly: true,
-		CookieSameSite: "Lax",
+		CookieSameSite: cookie.SameSite,
 	})
 
 	log.Info("Session store initialized successfully")
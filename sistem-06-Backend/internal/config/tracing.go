@@ -0,0 +1,28 @@
+package config
+
+import (
+	"context"
+
+	"sistem-06-Backend/internal/infrastructure/tracing"
+	"sistem-06-Backend/internal/pkg/buildinfo"
+
+	"github.com/sirupsen/logrus"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func NewTracing(config *Config, log *logrus.Logger) *sdktrace.TracerProvider {
+	provider, err := tracing.NewTracerProvider(context.Background(), tracing.Options{
+		ServiceName:    config.App.Name,
+		ServiceVersion: buildinfo.Version,
+		Exporter:       config.Tracing.Exporter,
+		Endpoint:       config.Tracing.Endpoint,
+		Insecure:       config.Tracing.Insecure,
+		SampleRatio:    config.Tracing.SampleRatio,
+	})
+	if err != nil {
+		log.Fatalf("failed to initialize tracing: %v", err)
+	}
+
+	log.Infof("Tracing initialized (exporter: %s)", config.Tracing.Exporter)
+	return provider
+}
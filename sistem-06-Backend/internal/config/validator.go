@@ -1,12 +1,12 @@
 package config
 
 import (
-	"backend-sistem06.com/internal/validation"
+	"sistem-06-Backend/internal/pkg/validation"
+
 	"github.com/go-playground/validator/v10"
-	"github.com/spf13/viper"
 )
 
-func NewValidator(viper *viper.Viper) *validator.Validate {
+func NewValidator(config *Config) *validator.Validate {
 	validate := validator.New()
 
 	validate.RegisterValidation("RT_RW", validation.CustomRtRwCodeValidation)
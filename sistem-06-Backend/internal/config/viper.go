@@ -1,23 +1,38 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/spf13/viper"
 )
 
-func NewViper() *viper.Viper {
+// NewViper reads config.json when present and layers defaults, SISTEM06_*
+// environment variables and *_FILE secrets on top of it. The file is
+// optional so containers can be configured from the environment alone.
+func NewViper() (*viper.Viper, error) {
 	config := viper.New()
 
 	config.SetConfigName("config")
 	config.SetConfigType("json")
 	config.AddConfigPath("./../")
 	config.AddConfigPath(".")
+
+	setDefaults(config)
+	config.SetEnvPrefix(EnvPrefix)
+	config.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	config.AutomaticEnv()
+
 	err := config.ReadInConfig()
+	var notFound viper.ConfigFileNotFoundError
+	if err != nil && !errors.As(err, &notFound) {
+		return nil, fmt.Errorf("read config file: %w", err)
+	}
 
-	if err != nil {
-		panic(fmt.Errorf("fatal error config file: %w \n", err))
+	if err := loadSecretFiles(config); err != nil {
+		return nil, err
 	}
 
-	return config
+	return config, nil
 }
@@ -1,16 +1,22 @@
 package config
 
 import (
+	"time"
+
 	"github.com/gofiber/fiber/v2"
-	"github.com/spf13/viper"
 )
 
-func NewFiber(config *viper.Viper) *fiber.App {
+func NewFiber(config *Config) *fiber.App {
 
 	app := fiber.New(
 		fiber.Config{
-			AppName: config.GetString("app.name"),
-			Prefork: config.GetBool("web.prefork"),
+			AppName: config.App.Name,
+			Prefork: config.Web.Prefork,
+			// Timeouts let ShutdownWithTimeout close idle keep-alive connections
+			ReadTimeout: time.Duration(config.Web.ReadTimeout) * time.Second,
+			IdleTimeout: time.Duration(config.Web.IdleTimeout) * time.Second,
+			// room for an import file plus the rest of the multipart form
+			BodyLimit: max(config.Import.MaxFileMB+1, 4) << 20,
 		})
 	return app
 }
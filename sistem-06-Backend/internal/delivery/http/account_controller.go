@@ -0,0 +1,100 @@
+package http
+
+import (
+	"sistem-06-Backend/internal/dto"
+	"sistem-06-Backend/internal/usecase"
+	"sistem-06-Backend/pkg"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type AccountController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.AccountUseCase
+	Session *pkg.SessionHandler
+}
+
+func NewAccountController(useCase *usecase.AccountUseCase, log *logrus.Logger, session *pkg.SessionHandler) *AccountController {
+	return &AccountController{
+		Log:     log,
+		UseCase: useCase,
+		Session: session,
+	}
+}
+
+func (c *AccountController) Get(ctx *fiber.Ctx) error {
+	response, err := c.UseCase.Get(ctx.UserContext(), ctx.Locals("user_id").(int))
+	if err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to get account : %+v", err)
+		return err
+	}
+
+	ctx.Set(fiber.HeaderETag, pkg.ETag(response.Version))
+	return ctx.JSON(pkg.WebResponse[*dto.UserResponse]{Data: response})
+}
+
+func (c *AccountController) Update(ctx *fiber.Ctx) error {
+	version, err := ifMatch(ctx)
+	if err != nil {
+		return err
+	}
+
+	request := new(dto.UpdateAccountRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+
+	response, err := c.UseCase.Update(ctx.UserContext(), ctx.Locals("user_id").(int), version, request)
+	if err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to update account : %+v", err)
+		return preconditionFailed(ctx, err)
+	}
+
+	ctx.Set(fiber.HeaderETag, pkg.ETag(response.Version))
+	return ctx.JSON(pkg.WebResponse[*dto.UserResponse]{Data: response})
+}
+
+// VerifyEmail confirms a pending email change and stores the new address in
+// the session.
+func (c *AccountController) VerifyEmail(ctx *fiber.Ctx) error {
+	request := new(dto.VerifyUserRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+
+	response, err := c.UseCase.VerifyEmail(ctx.UserContext(), ctx.Locals("user_id").(int), request)
+	if err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to verify email : %+v", err)
+		return err
+	}
+
+	if err := c.Session.SetUserSession(ctx, response.ID, response.Email); err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return ctx.JSON(pkg.WebResponse[*dto.UserResponse]{Data: response})
+}
+
+// ChangePassword rotates the session id once the password is changed.
+func (c *AccountController) ChangePassword(ctx *fiber.Ctx) error {
+	request := new(dto.ChangePasswordRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+
+	response, err := c.UseCase.ChangePassword(ctx.UserContext(), ctx.Locals("user_id").(int), request)
+	if err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to change password : %+v", err)
+		return err
+	}
+
+	if err := c.Session.SetUserSession(ctx, response.ID, response.Email); err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return ctx.JSON(pkg.WebResponse[bool]{Data: true})
+}
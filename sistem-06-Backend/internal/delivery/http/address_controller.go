@@ -11,29 +11,118 @@ import (
 )
 
 type AddressController struct {
-	Log     *logrus.Logger
-	UseCase *usecase.AddressUseCase
+	Log         *logrus.Logger
+	UseCase     *usecase.AddressUseCase
+	AuthUseCase *usecase.AuthUseCase
 }
 
-func NewAddressController(usecase *usecase.AddressUseCase, log *logrus.Logger) *AddressController {
+func NewAddressController(usecase *usecase.AddressUseCase, authUseCase *usecase.AuthUseCase, log *logrus.Logger) *AddressController {
 	return &AddressController{
-		Log:     log,
-		UseCase: usecase,
+		Log:         log,
+		UseCase:     usecase,
+		AuthUseCase: authUseCase,
 	}
 }
 
 func (c *AddressController) Create(ctx *fiber.Ctx) error {
-	request := new(*dto.AddressRequest)
+	request := new(dto.AddressRequest)
 	err := ctx.BodyParser(request)
 	if err != nil {
-		c.Log.Warnf("Failed to parse request body : %+v", err)
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to parse request body : %+v", err)
 		return fiber.ErrBadRequest
 	}
 
 	res, err := c.UseCase.Create(ctx.UserContext(), request)
 	if err != nil {
-		c.Log.Warnf("Failed to create address: %+v", err)
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to create address: %+v", err)
 		return err
 	}
 	return ctx.JSON(pkg.WebResponse[*dto.AddressEntity]{Data: res})
 }
+
+func (c *AddressController) List(ctx *fiber.Ctx) error {
+	res, err := c.UseCase.List(ctx.UserContext(), ctx.Queries())
+	if err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to list addresses: %+v", err)
+		return err
+	}
+	if err := maskFor(ctx, c.AuthUseCase, res); err != nil {
+		return err
+	}
+	return ctx.JSON(res)
+}
+
+func (c *AddressController) Get(ctx *fiber.Ctx) error {
+	addressID, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid address id")
+	}
+
+	res, err := c.UseCase.Get(ctx.UserContext(), addressID)
+	if err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to get address: %+v", err)
+		return err
+	}
+	if err := maskFor(ctx, c.AuthUseCase, res); err != nil {
+		return err
+	}
+	ctx.Set(fiber.HeaderETag, pkg.ETag(res.Version))
+	return ctx.JSON(pkg.WebResponse[*dto.AddressEntity]{Data: res})
+}
+
+func (c *AddressController) Update(ctx *fiber.Ctx) error {
+	addressID, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid address id")
+	}
+	version, err := ifMatch(ctx)
+	if err != nil {
+		return err
+	}
+
+	request := new(dto.UpdateAddressRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+
+	res, err := c.UseCase.Update(ctx.UserContext(), addressID, version, request)
+	if err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to update address: %+v", err)
+		return preconditionFailed(ctx, err)
+	}
+	ctx.Set(fiber.HeaderETag, pkg.ETag(res.Version))
+	return ctx.JSON(pkg.WebResponse[*dto.AddressEntity]{Data: res})
+}
+
+func (c *AddressController) Delete(ctx *fiber.Ctx) error {
+	addressID, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid address id")
+	}
+	version, err := ifMatch(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := c.UseCase.Delete(ctx.UserContext(), addressID, version); err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to delete address: %+v", err)
+		return preconditionFailed(ctx, err)
+	}
+	return ctx.JSON(pkg.WebResponse[bool]{Data: true})
+}
+
+func (c *AddressController) Restore(ctx *fiber.Ctx) error {
+	addressID, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid address id")
+	}
+
+	res, err := c.UseCase.Restore(ctx.UserContext(), addressID)
+	if err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to restore address: %+v", err)
+		return err
+	}
+	ctx.Set(fiber.HeaderETag, pkg.ETag(res.Version))
+	return ctx.JSON(pkg.WebResponse[*dto.AddressEntity]{Data: res})
+}
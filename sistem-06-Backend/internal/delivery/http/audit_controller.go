@@ -0,0 +1,47 @@
+package http
+
+import (
+	"sistem-06-Backend/internal/dto"
+	"sistem-06-Backend/internal/usecase"
+	"sistem-06-Backend/pkg"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type AuditController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.AuditUseCase
+}
+
+func NewAuditController(useCase *usecase.AuditUseCase, log *logrus.Logger) *AuditController {
+	return &AuditController{
+		Log:     log,
+		UseCase: useCase,
+	}
+}
+
+func (c *AuditController) Search(ctx *fiber.Ctx) error {
+	request := &dto.SearchAuditLogRequest{Page: 1, Size: 20}
+	if err := ctx.QueryParser(request); err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to parse query : %+v", err)
+		return fiber.ErrBadRequest
+	}
+
+	responses, paging, err := c.UseCase.Search(ctx.UserContext(), request)
+	if err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to search audit log : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(pkg.WebResponse[[]*dto.AuditLogResponse]{Data: responses, Paging: paging})
+}
+
+func (c *AuditController) Verify(ctx *fiber.Ctx) error {
+	response, err := c.UseCase.Verify(ctx.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(pkg.WebResponse[*dto.AuditVerifyResponse]{Data: response})
+}
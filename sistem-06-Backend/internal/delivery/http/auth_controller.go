@@ -1,6 +1,10 @@
 package http
 
 import (
+	"errors"
+	"math"
+	"strconv"
+
 	"sistem-06-Backend/internal/dto"
 	"sistem-06-Backend/internal/usecase"
 	"sistem-06-Backend/pkg"
@@ -27,14 +31,59 @@ func (c *AuthController) Login(ctx *fiber.Ctx) error {
 	request := new(dto.UserLoginRequest)
 	err := ctx.BodyParser(request)
 	if err != nil {
-		c.Log.Warnf("Failed to parse request body : %+v", err)
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to parse request body : %+v", err)
 		return fiber.ErrBadRequest
 	}
 
+	request.IPAddress = ctx.IP()
+
 	response, err := c.UseCase.Login(ctx.UserContext(), request)
 	if err != nil {
-		c.Log.Warnf("Failed to login user : %+v", err)
-		return err
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to login user : %+v", err)
+		return loginError(ctx, err)
+	}
+
+	if response.TwoFactorRequired {
+		expiresAt, err := c.Session.SetPendingTwoFactor(ctx, response.User.ID, c.UseCase.Policy.TwoFactorTimeout)
+		if err != nil {
+			return fiber.ErrInternalServerError
+		}
+		return ctx.Status(fiber.StatusAccepted).JSON(pkg.WebResponse[*dto.TwoFactorChallengeResponse]{
+			Data: &dto.TwoFactorChallengeResponse{TwoFactorRequired: true, ExpiresAt: expiresAt},
+		})
+	}
+
+	if err := c.Session.SetUserSession(ctx, response.User.ID, response.User.Email); err != nil {
+		return fiber.ErrInternalServerError
+	}
+	if response.TwoFactorEnrollmentRequired {
+		if err := c.Session.SetTwoFactorEnrollmentRequired(ctx, true); err != nil {
+			return fiber.ErrInternalServerError
+		}
+	}
+
+	return ctx.JSON(pkg.WebResponse[*dto.UserResponse]{Data: response.User})
+
+}
+
+func (c *AuthController) VerifyTwoFactor(ctx *fiber.Ctx) error {
+	request := new(dto.TwoFactorLoginRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+
+	userID, err := c.Session.GetPendingTwoFactor(ctx)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "no pending two-factor login")
+	}
+	request.UserID = userID
+	request.IPAddress = ctx.IP()
+
+	response, err := c.UseCase.VerifyTwoFactor(ctx.UserContext(), request)
+	if err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to verify second factor : %+v", err)
+		return loginError(ctx, err)
 	}
 
 	if err := c.Session.SetUserSession(ctx, response.ID, response.Email); err != nil {
@@ -42,5 +91,35 @@ func (c *AuthController) Login(ctx *fiber.Ctx) error {
 	}
 
 	return ctx.JSON(pkg.WebResponse[*dto.UserResponse]{Data: response})
+}
+
+// loginError turns a throttled login into 429 with Retry-After.
+func loginError(ctx *fiber.Ctx, err error) error {
+	var throttled *usecase.LoginThrottledError
+	if errors.As(err, &throttled) {
+		ctx.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+		return fiber.NewError(fiber.StatusTooManyRequests, "too many failed login attempts, try again later")
+	}
+	return err
+}
+
+func (c *AuthController) Unlock(ctx *fiber.Ctx) error {
+	userID, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid user id")
+	}
+
+	if err := c.UseCase.Unlock(ctx.UserContext(), userID); err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to unlock user : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(pkg.WebResponse[bool]{Data: true})
+}
 
+// CSRFToken returns the token issued by the CSRF middleware, for clients
+// that cannot read the cookie. It is empty when CSRF protection is off.
+func (c *AuthController) CSRFToken(ctx *fiber.Ctx) error {
+	token, _ := ctx.Locals("csrf_token").(string)
+	return ctx.JSON(pkg.WebResponse[*dto.CSRFTokenResponse]{Data: &dto.CSRFTokenResponse{Token: token}})
 }
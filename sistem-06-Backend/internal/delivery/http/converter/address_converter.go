@@ -1,16 +1,18 @@
-package address
+package converter
 
 import (
-	"backend-sistem06.com/internal/model"
+	"sistem-06-Backend/internal/domain/entity"
+	"sistem-06-Backend/internal/dto"
 )
 
-func AddressToResponse(address *Address) *model.Address {
-	return &model.Address{
+func AddressToResponse(address *entity.Address) *dto.AddressEntity {
+	return &dto.AddressEntity{
 		ID:         address.ID,
 		Jalan:      address.Jalan,
 		RT:         address.RT,
 		RW:         address.RW,
 		Kota:       address.Kota,
 		PostalCode: address.PostalCode,
+		Version:    address.Version,
 	}
 }
@@ -0,0 +1,24 @@
+package converter
+
+import (
+	"encoding/json"
+
+	"sistem-06-Backend/internal/domain/entity"
+	"sistem-06-Backend/internal/dto"
+)
+
+func AuditEntryToResponse(entry *entity.AuditEntry) *dto.AuditLogResponse {
+	return &dto.AuditLogResponse{
+		ID:         entry.ID,
+		ActorID:    entry.ActorID,
+		Action:     entry.Action,
+		EntityType: entry.EntityType,
+		EntityID:   entry.EntityID,
+		Diff:       json.RawMessage(entry.Diff),
+		IP:         entry.IP,
+		RequestID:  entry.RequestID,
+		CreatedAt:  entry.CreatedAt,
+		PrevHash:   entry.PrevHash,
+		Hash:       entry.Hash,
+	}
+}
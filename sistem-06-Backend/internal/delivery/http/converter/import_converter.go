@@ -0,0 +1,25 @@
+package converter
+
+import (
+	"sistem-06-Backend/internal/domain/entity"
+	"sistem-06-Backend/internal/dto"
+)
+
+func ImportJobToResponse(job *entity.ImportJob) *dto.ImportJobResponse {
+	errors := make([]dto.ImportRowError, len(job.Errors))
+	for i, rowError := range job.Errors {
+		errors[i] = dto.ImportRowError(rowError)
+	}
+	return &dto.ImportJobResponse{
+		ID:            job.ID,
+		Kind:          job.Kind,
+		Status:        job.Status,
+		DryRun:        job.DryRun,
+		TotalRows:     job.TotalRows,
+		ProcessedRows: job.ProcessedRows,
+		ImportedRows:  job.ImportedRows,
+		Errors:        errors,
+		CreatedAt:     job.CreatedAt,
+		FinishedAt:    job.FinishedAt,
+	}
+}
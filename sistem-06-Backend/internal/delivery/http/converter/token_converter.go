@@ -0,0 +1,22 @@
+package converter
+
+import (
+	"sistem-06-Backend/internal/domain/entity"
+	"sistem-06-Backend/internal/dto"
+)
+
+func TokenToResponse(token *entity.PersonalAccessToken) *dto.TokenResponse {
+	scopes := make([]string, len(token.Scopes))
+	for i, scope := range token.Scopes {
+		scopes[i] = string(scope)
+	}
+
+	return &dto.TokenResponse{
+		ID:         token.ID,
+		Name:       token.Name,
+		Scopes:     scopes,
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+		CreatedAt:  token.CreatedAt,
+	}
+}
@@ -1,47 +1,63 @@
-package user
+package converter
 
 import (
-	"backend-sistem06.com/internal/entity"
-	"backend-sistem06.com/internal/model"
+	"sistem-06-Backend/internal/domain/entity"
+	"sistem-06-Backend/internal/dto"
 )
 
-func UserToResponse(user *entity.UserEntity) *model.UserResponse {
-	return &model.UserResponse{
+func UserToResponse(user *entity.User) *dto.UserResponse {
+	return &dto.UserResponse{
 		ID:    user.ID,
 		Name:  user.Name,
 		Email: user.Email,
 
 		CreatedAt: user.CreatedAt,
 		UpdatedAt: user.UpdatedAt,
+		Version:   user.Version,
 	}
 }
 
-func UserWithRolesToResponse(user *entity.UserWithRole) *model.UserResponse {
+func UserWithRolesToResponse(user *entity.User) *dto.UserResponse {
 	if user == nil {
 		return nil
 	}
 
 	// Convert roles
-	roles := make([]model.RoleResponse, len(user.Roles))
-	permissionSet := make(map[string]bool)
+	roles := make([]dto.RoleResponse, len(user.Roles))
 
 	for i, role := range user.Roles {
-		roles[i] = model.RoleResponse{
-			ID:          role.ID,
-			Name:        role.Name,
-			Permissions: role.Permissions,
+		permissions := make([]string, len(role.Permission))
+		for j, perm := range role.Permission {
+			permissions[j] = string(perm)
 		}
 
-		// Collect all unique permissions
-		for _, perm := range role.Permissions {
-			permissionSet[perm] = true
+		roles[i] = dto.RoleResponse{
+			ID:          role.ID,
+			Name:        role.Name,
+			Permissions: permissions,
 		}
 	}
 
-	return &model.UserResponse{
+	return &dto.UserResponse{
 		ID:    user.ID,
 		Name:  user.Name,
 		Email: user.Email,
 		Roles: roles,
+
+		Version: user.Version,
+	}
+}
+
+func RoleToResponse(role *entity.Role) *dto.RoleResponse {
+	permissions := make([]string, len(role.Permission))
+	for i, perm := range role.Permission {
+		permissions[i] = string(perm)
+	}
+	return &dto.RoleResponse{
+		ID:          role.ID,
+		Name:        role.Name,
+		Permissions: permissions,
+		DeletedAt:   role.DeletedAt,
+		Version:     role.Version,
 	}
 }
@@ -0,0 +1,44 @@
+package http
+
+import (
+	"sistem-06-Backend/internal/dto"
+	"sistem-06-Backend/pkg"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sirupsen/logrus"
+)
+
+type DatabaseController struct {
+	Log  *logrus.Logger
+	Pool *pgxpool.Pool
+}
+
+func NewDatabaseController(pool *pgxpool.Pool, log *logrus.Logger) *DatabaseController {
+	return &DatabaseController{
+		Log:  log,
+		Pool: pool,
+	}
+}
+
+func (c *DatabaseController) Stats(ctx *fiber.Ctx) error {
+	stat := c.Pool.Stat()
+
+	response := &dto.DatabaseStatsResponse{
+		MaxConns:                stat.MaxConns(),
+		TotalConns:              stat.TotalConns(),
+		AcquiredConns:           stat.AcquiredConns(),
+		IdleConns:               stat.IdleConns(),
+		ConstructingConns:       stat.ConstructingConns(),
+		AcquireCount:            stat.AcquireCount(),
+		EmptyAcquireCount:       stat.EmptyAcquireCount(),
+		CanceledAcquireCount:    stat.CanceledAcquireCount(),
+		AcquireDurationMs:       stat.AcquireDuration().Milliseconds(),
+		NewConnsCount:           stat.NewConnsCount(),
+		MaxLifetimeDestroyCount: stat.MaxLifetimeDestroyCount(),
+		MaxIdleDestroyCount:     stat.MaxIdleDestroyCount(),
+		ApplicationName:         c.Pool.Config().ConnConfig.RuntimeParams["application_name"],
+	}
+
+	return ctx.JSON(pkg.WebResponse[*dto.DatabaseStatsResponse]{Data: response})
+}
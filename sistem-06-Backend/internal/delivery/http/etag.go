@@ -0,0 +1,37 @@
+package http
+
+import (
+	"errors"
+
+	"sistem-06-Backend/pkg"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ifMatch returns the version named by the If-Match header. Writes to
+// versioned resources must send it.
+func ifMatch(ctx *fiber.Ctx) (int, error) {
+	header := ctx.Get(fiber.HeaderIfMatch)
+	if header == "" {
+		return 0, fiber.NewError(fiber.StatusPreconditionRequired, "If-Match header is required")
+	}
+	version, ok := pkg.ParseETag(header)
+	if !ok {
+		return 0, fiber.NewError(fiber.StatusBadRequest, "invalid If-Match header")
+	}
+	return version, nil
+}
+
+// preconditionFailed answers a *pkg.VersionConflict with 412 and the current
+// representation. Other errors are returned as they are.
+func preconditionFailed(ctx *fiber.Ctx, err error) error {
+	var conflict *pkg.VersionConflict
+	if !errors.As(err, &conflict) {
+		return err
+	}
+	ctx.Set(fiber.HeaderETag, pkg.ETag(conflict.Version))
+	return ctx.Status(fiber.StatusPreconditionFailed).JSON(pkg.WebResponse[any]{
+		Data:   conflict.Current,
+		Errors: conflict.Error(),
+	})
+}
@@ -0,0 +1,65 @@
+package http
+
+import (
+	"bufio"
+
+	"sistem-06-Backend/internal/domain/entity"
+	"sistem-06-Backend/internal/dto"
+	"sistem-06-Backend/internal/usecase"
+	"sistem-06-Backend/pkg"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type ExportController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.ExportUseCase
+}
+
+func NewExportController(useCase *usecase.ExportUseCase, log *logrus.Logger) *ExportController {
+	return &ExportController{
+		Log:     log,
+		UseCase: useCase,
+	}
+}
+
+func (c *ExportController) Users(ctx *fiber.Ctx) error {
+	// nil for session logins
+	token, _ := ctx.Locals("token").(*entity.PersonalAccessToken)
+	file, err := c.UseCase.Users(ctx.UserContext(), ctx.Locals("user_id").(int), token, ctx.Queries())
+	if err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to export users : %+v", err)
+		return err
+	}
+	return sendFile(ctx, c.Log, file)
+}
+
+func (c *ExportController) Addresses(ctx *fiber.Ctx) error {
+	token, _ := ctx.Locals("token").(*entity.PersonalAccessToken)
+	file, err := c.UseCase.Addresses(ctx.UserContext(), ctx.Locals("user_id").(int), token, ctx.Queries())
+	if err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to export addresses : %+v", err)
+		return err
+	}
+	return sendFile(ctx, c.Log, file)
+}
+
+// sendFile streams the file with chunked encoding after the handler
+// returns, so only the current row is held in memory.
+func sendFile(ctx *fiber.Ctx, log *logrus.Logger, file *dto.ExportFile) error {
+	// Attachment guesses the type from the extension; keep the charset
+	ctx.Attachment(file.Filename)
+	ctx.Set(fiber.HeaderContentType, file.ContentType)
+	userCtx := ctx.UserContext()
+	ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := file.Write(w); err != nil {
+			pkg.Logger(userCtx, log).Errorf("Failed to stream export %s : %+v", file.Filename, err)
+			return
+		}
+		if err := w.Flush(); err != nil {
+			pkg.Logger(userCtx, log).Warnf("Failed to flush export %s : %+v", file.Filename, err)
+		}
+	})
+	return nil
+}
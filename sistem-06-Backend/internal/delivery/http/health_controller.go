@@ -0,0 +1,40 @@
+package http
+
+import (
+	"sistem-06-Backend/internal/dto"
+	"sistem-06-Backend/internal/usecase"
+	"sistem-06-Backend/pkg"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type HealthController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.HealthUseCase
+}
+
+func NewHealthController(useCase *usecase.HealthUseCase, log *logrus.Logger) *HealthController {
+	return &HealthController{
+		Log:     log,
+		UseCase: useCase,
+	}
+}
+
+// Liveness only reports that the process is able to serve requests.
+func (c *HealthController) Liveness(ctx *fiber.Ctx) error {
+	return ctx.JSON(pkg.WebResponse[*dto.HealthResponse]{Data: &dto.HealthResponse{Status: "ok"}})
+}
+
+func (c *HealthController) Readiness(ctx *fiber.Ctx) error {
+	response, ready := c.UseCase.Readiness(ctx.UserContext())
+	if !ready {
+		ctx.Status(fiber.StatusServiceUnavailable)
+	}
+	return ctx.JSON(pkg.WebResponse[*dto.ReadinessResponse]{Data: response})
+}
+
+func (c *HealthController) Status(ctx *fiber.Ctx) error {
+	response := c.UseCase.Status(ctx.UserContext())
+	return ctx.JSON(pkg.WebResponse[*dto.StatusResponse]{Data: response})
+}
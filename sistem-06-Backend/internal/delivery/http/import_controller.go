@@ -0,0 +1,86 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"sistem-06-Backend/internal/domain/entity"
+	"sistem-06-Backend/internal/dto"
+	"sistem-06-Backend/internal/usecase"
+	"sistem-06-Backend/pkg"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type ImportController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.ImportUseCase
+}
+
+func NewImportController(useCase *usecase.ImportUseCase, log *logrus.Logger) *ImportController {
+	return &ImportController{
+		Log:     log,
+		UseCase: useCase,
+	}
+}
+
+func (c *ImportController) Addresses(ctx *fiber.Ctx) error {
+	return c.start(ctx, entity.ImportKindAddress)
+}
+
+func (c *ImportController) Users(ctx *fiber.Ctx) error {
+	return c.start(ctx, entity.ImportKindUser)
+}
+
+// start takes a multipart form with the file under "file", an optional
+// "dry_run" flag and an optional "mapping" JSON object.
+func (c *ImportController) start(ctx *fiber.Ctx, kind string) error {
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "file is required")
+	}
+	file, err := header.Open()
+	if err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to open uploaded file : %+v", err)
+		return fiber.ErrBadRequest
+	}
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	if err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to read uploaded file : %+v", err)
+		return fiber.ErrBadRequest
+	}
+
+	request := &dto.ImportRequest{Filename: header.Filename, Content: content}
+	if value := ctx.FormValue("dry_run"); value != "" {
+		if request.DryRun, err = strconv.ParseBool(value); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "dry_run must be true or false")
+		}
+	}
+	if value := ctx.FormValue("mapping"); value != "" {
+		if err := json.Unmarshal([]byte(value), &request.Mapping); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "mapping must be a JSON object of column header to field")
+		}
+	}
+
+	response, err := c.UseCase.Start(ctx.UserContext(), ctx.Locals("user_id").(int), kind, request)
+	if err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to start import : %+v", err)
+		return err
+	}
+
+	ctx.Location("/api/v1/imports/" + response.ID)
+	return ctx.Status(fiber.StatusAccepted).JSON(pkg.WebResponse[*dto.ImportJobResponse]{Data: response})
+}
+
+func (c *ImportController) Get(ctx *fiber.Ctx) error {
+	response, err := c.UseCase.Get(ctx.UserContext(), ctx.Locals("user_id").(int), ctx.Params("id"))
+	if err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to get import : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(pkg.WebResponse[*dto.ImportJobResponse]{Data: response})
+}
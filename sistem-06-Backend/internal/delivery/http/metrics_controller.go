@@ -0,0 +1,26 @@
+package http
+
+import (
+	"sistem-06-Backend/internal/infrastructure/metrics"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
+)
+
+type MetricsController struct {
+	Log     *logrus.Logger
+	handler fiber.Handler
+}
+
+func NewMetricsController(metrics *metrics.Metrics, log *logrus.Logger) *MetricsController {
+	return &MetricsController{
+		Log:     log,
+		handler: adaptor.HTTPHandler(promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{})),
+	}
+}
+
+func (c *MetricsController) Metrics(ctx *fiber.Ctx) error {
+	return c.handler(ctx)
+}
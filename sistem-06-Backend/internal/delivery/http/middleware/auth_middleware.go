@@ -1,19 +1,29 @@
 package middleware
 
 import (
-	"backend-sistem06.com/utils"
+	"errors"
+	"strings"
+
+	"sistem-06-Backend/internal/domain/entity"
+	"sistem-06-Backend/internal/usecase"
+	"sistem-06-Backend/pkg"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/sirupsen/logrus"
 )
 
 type AuthMiddleware struct {
-	SessionHandler *utils.SessionHandler
+	SessionHandler *pkg.SessionHandler
+	AuthUseCase    *usecase.AuthUseCase
+	TokenUseCase   *usecase.TokenUseCase
 	Log            *logrus.Logger
 }
 
-func NewAuthMiddleware(sessionHandler *utils.SessionHandler, log *logrus.Logger) *AuthMiddleware {
+func NewAuthMiddleware(sessionHandler *pkg.SessionHandler, authUseCase *usecase.AuthUseCase, tokenUseCase *usecase.TokenUseCase, log *logrus.Logger) *AuthMiddleware {
 	return &AuthMiddleware{
 		SessionHandler: sessionHandler,
+		AuthUseCase:    authUseCase,
+		TokenUseCase:   tokenUseCase,
 		Log:            log,
 	}
 }
@@ -28,39 +38,178 @@ func (m *AuthMiddleware) RequireGuest() fiber.Handler {
 }
 
 func (m *AuthMiddleware) RequiredAuth() fiber.Handler {
+	return m.requireAuth(false)
+}
+
+// RequiredAuthForEnrollment also admits sessions that must set up 2FA before
+// anything else, for the enrollment endpoints themselves. Bearer tokens are
+// not accepted there.
+func (m *AuthMiddleware) RequiredAuthForEnrollment() fiber.Handler {
+	return m.requireAuth(true)
+}
+
+func (m *AuthMiddleware) requireAuth(allowEnrollment bool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		if token, ok := bearerToken(c); ok && !allowEnrollment {
+			return m.authenticateToken(c, token)
+		}
 		if !m.SessionHandler.IsAuthenticated(c) {
-			m.Log.Warn("Unauthorized access attempt")
+			pkg.Logger(c.UserContext(), m.Log).Warn("Unauthorized access attempt")
 			return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
 		}
+		if !allowEnrollment && m.SessionHandler.TwoFactorEnrollmentRequired(c) {
+			return fiber.NewError(fiber.StatusForbidden, "two-factor enrollment required")
+		}
 		userID, err := m.SessionHandler.GetUserID(c)
 		if err != nil {
-			m.Log.Errorf("Failed to get user ID from session: %v", err)
+			pkg.Logger(c.UserContext(), m.Log).Errorf("Failed to get user ID from session: %v", err)
 			return fiber.ErrUnauthorized
 		}
 
 		email, err := m.SessionHandler.GetUserEmail(c)
 		if err != nil {
-			m.Log.Errorf("Failed to get user Email from session: %v", err)
+			pkg.Logger(c.UserContext(), m.Log).Errorf("Failed to get user Email from session: %v", err)
 			return fiber.ErrUnauthorized
 		}
 
+		if err := m.AuthUseCase.CheckSession(c.UserContext(), userID, m.SessionHandler.GetCreatedAt(c)); err != nil {
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) && fiberErr.Code == fiber.StatusUnauthorized {
+				pkg.Logger(c.UserContext(), m.Log).Warn("Revoked session used")
+				if err := m.SessionHandler.DestroySession(c); err != nil {
+					pkg.Logger(c.UserContext(), m.Log).Warnf("Failed to destroy revoked session: %v", err)
+				}
+			}
+			return err
+		}
+
 		c.Locals("user_id", userID)
 		c.Locals("email", email)
 
+		entry := pkg.Logger(c.UserContext(), m.Log).WithField("user_id", userID)
+		c.SetUserContext(pkg.WithActor(pkg.WithLogger(c.UserContext(), entry), userID))
+
 		if err := m.SessionHandler.RefreshSession(c); err != nil {
-			m.Log.Warnf("Failed to refresh session: %v", err)
+			entry.Warnf("Failed to refresh session: %v", err)
 		}
 
 		return c.Next()
 	}
 }
 
-// func (m *AuthMiddleware) RequiredRoles (role string) fiber.Handler {
-// 	return func (c *fiber.Ctx) error  {
-// 		if !m.SessionHandler.IsAuthenticated(c) {
-// 			return fiber.ErrUnauthorized
-// 		}
-// 		if !m.SessionHandler.
-// 	}
-// }
+// ResolveToken looks up the Authorization: Bearer token ahead of the rate
+// limiter, so token requests are counted per user. Nothing is rejected here:
+// RequiredAuth answers for a bad token after the request has been counted.
+func (m *AuthMiddleware) ResolveToken() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		plain, ok := bearerToken(c)
+		if !ok {
+			return c.Next()
+		}
+		token, user, err := m.TokenUseCase.Authenticate(c.UserContext(), plain)
+		if err != nil {
+			c.Locals("token_error", err)
+			return c.Next()
+		}
+		c.Locals("resolved_token", token)
+		c.Locals("token_user", user)
+		return c.Next()
+	}
+}
+
+// authenticateToken handles an Authorization: Bearer request. A token that
+// does not check out is rejected even if a session cookie is present too.
+func (m *AuthMiddleware) authenticateToken(c *fiber.Ctx, plain string) error {
+	token, user, err := m.resolvedToken(c, plain)
+	if err != nil {
+		pkg.Logger(c.UserContext(), m.Log).Warn("Invalid bearer token")
+		return err
+	}
+
+	c.Locals("user_id", user.ID)
+	c.Locals("email", user.Email)
+	c.Locals("token", token)
+
+	entry := pkg.Logger(c.UserContext(), m.Log).WithFields(logrus.Fields{"user_id": user.ID, "token_id": token.ID})
+	c.SetUserContext(pkg.WithActor(pkg.WithLogger(c.UserContext(), entry), user.ID))
+
+	return c.Next()
+}
+
+// resolvedToken reuses the outcome of ResolveToken, so the token is looked
+// up once per request, and authenticates it when ResolveToken did not run.
+func (m *AuthMiddleware) resolvedToken(c *fiber.Ctx, plain string) (*entity.PersonalAccessToken, *entity.User, error) {
+	if err, ok := c.Locals("token_error").(error); ok {
+		return nil, nil, err
+	}
+	token, ok := c.Locals("resolved_token").(*entity.PersonalAccessToken)
+	user, userOK := c.Locals("token_user").(*entity.User)
+	if ok && userOK {
+		return token, user, nil
+	}
+	return m.TokenUseCase.Authenticate(c.UserContext(), plain)
+}
+
+// bearerToken returns the token of an "Authorization: Bearer <token>" header.
+func bearerToken(c *fiber.Ctx) (string, bool) {
+	scheme, token, found := strings.Cut(c.Get(fiber.HeaderAuthorization), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// RequireSession must run after RequiredAuth. It rejects requests made with a
+// personal access token, so a token cannot be used to mint or revoke tokens.
+func (m *AuthMiddleware) RequireSession() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if _, ok := c.Locals("token").(*entity.PersonalAccessToken); ok {
+			return fiber.NewError(fiber.StatusForbidden, "not available with a personal access token")
+		}
+		return c.Next()
+	}
+}
+
+// RequirePermission must run after RequiredAuth, which stores the user id.
+// Requests made with a token also need the permission among its scopes.
+func (m *AuthMiddleware) RequirePermission(permission entity.Permissions) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, ok := c.Locals("user_id").(int)
+		if !ok {
+			return fiber.ErrUnauthorized
+		}
+		if token, ok := c.Locals("token").(*entity.PersonalAccessToken); ok && !token.HasScope(permission) {
+			pkg.Logger(c.UserContext(), m.Log).Warnf("Token is missing scope %s", permission)
+			return fiber.ErrForbidden
+		}
+		if err := m.AuthUseCase.Authorize(c.UserContext(), userID, permission); err != nil {
+			return err
+		}
+		return c.Next()
+	}
+}
+
+// RequireAnyPermission is RequirePermission for routes several permissions
+// open, e.g. an export of users for users.manage or data.export. The use
+// case still decides what each of them gets to see.
+func (m *AuthMiddleware) RequireAnyPermission(permissions ...entity.Permissions) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, ok := c.Locals("user_id").(int)
+		if !ok {
+			return fiber.ErrUnauthorized
+		}
+		token, _ := c.Locals("token").(*entity.PersonalAccessToken)
+		viewer, err := m.AuthUseCase.Viewer(c.UserContext(), userID, token)
+		if err != nil {
+			return err
+		}
+		for _, permission := range permissions {
+			if viewer.Allowed(string(permission)) {
+				return c.Next()
+			}
+		}
+		pkg.Logger(c.UserContext(), m.Log).Warnf("Missing any of the permissions %v", permissions)
+		return fiber.ErrForbidden
+	}
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"errors"
+	"regexp"
+	"time"
+
+	"sistem-06-Backend/pkg"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// incoming ids are only propagated when they are safe to log and echo back
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+type LoggingMiddleware struct {
+	Log *logrus.Logger
+}
+
+func NewLoggingMiddleware(log *logrus.Logger) *LoggingMiddleware {
+	return &LoggingMiddleware{
+		Log: log,
+	}
+}
+
+// RequestID assigns or propagates X-Request-ID and stores a logrus entry
+// carrying it in the request's user context.
+func (m *LoggingMiddleware) RequestID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		requestID := c.Get(RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+		c.Set(RequestIDHeader, requestID)
+		c.Locals("request_id", requestID)
+
+		entry := m.Log.WithFields(logrus.Fields{
+			"request_id": requestID,
+			"method":     c.Method(),
+			"path":       c.Path(),
+		})
+
+		ctx := pkg.WithRequestID(c.UserContext(), requestID)
+		ctx = pkg.WithClientIP(ctx, c.IP())
+		c.SetUserContext(pkg.WithLogger(ctx, entry))
+
+		return c.Next()
+	}
+}
+
+// AccessLog writes one structured line per request once the handler chain
+// has finished. Server errors are logged at error level, client errors at
+// warn level, everything else at info level.
+func (m *LoggingMiddleware) AccessLog() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			// the error handler has not written the response yet
+			status = fiber.StatusInternalServerError
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				status = fiberErr.Code
+			}
+		}
+
+		entry := pkg.Logger(c.UserContext(), m.Log).WithFields(logrus.Fields{
+			"route":      c.Route().Path,
+			"status":     status,
+			"latency_ms": float64(time.Since(start).Microseconds()) / 1000,
+			"ip":         c.IP(),
+			"user_agent": c.Get(fiber.HeaderUserAgent),
+			"bytes_out":  len(c.Response().Body()),
+		})
+
+		switch {
+		case status >= fiber.StatusInternalServerError:
+			entry.Error("request completed")
+		case status >= fiber.StatusBadRequest:
+			entry.Warn("request completed")
+		default:
+			entry.Info("request completed")
+		}
+
+		return err
+	}
+}
@@ -0,0 +1,151 @@
+package middleware
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"sistem-06-Backend/internal/domain/entity"
+	"sistem-06-Backend/internal/infrastructure/metrics"
+	"sistem-06-Backend/internal/usecase"
+	"sistem-06-Backend/pkg"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	RateLimitKeyIP     = "ip"
+	RateLimitKeyUser   = "user"
+	RateLimitKeyIPUser = "ip_user"
+)
+
+// RateLimitRule limits requests matching Method and Path. Path is either an
+// exact path or a prefix ending in "*"; an empty Method matches any method.
+// Paths are compared the way the router matches them, ignoring case and a
+// trailing "/".
+type RateLimitRule struct {
+	Name   string
+	Method string
+	Path   string
+	Limit  int
+	Window time.Duration
+	Key    string
+}
+
+func (r *RateLimitRule) matches(method string, path string) bool {
+	if r.Method != "" && r.Method != method {
+		return false
+	}
+	if prefix, ok := strings.CutSuffix(r.Path, "*"); ok {
+		return strings.HasPrefix(path, strings.ToLower(prefix))
+	}
+	return normalizePath(r.Path) == path
+}
+
+// normalizePath folds the variants fiber routes to the same handler, as
+// CaseSensitive and StrictRouting are off: "/API/V1/Login/" is "/api/v1/login".
+func normalizePath(path string) string {
+	path = strings.ToLower(path)
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	return path
+}
+
+type RateLimitMiddleware struct {
+	Log            *logrus.Logger
+	UseCase        *usecase.RateLimitUseCase
+	SessionHandler *pkg.SessionHandler
+	Metrics        *metrics.Metrics
+	Rules          []RateLimitRule
+}
+
+func NewRateLimitMiddleware(useCase *usecase.RateLimitUseCase, sessionHandler *pkg.SessionHandler, rules []RateLimitRule, metrics *metrics.Metrics, log *logrus.Logger) *RateLimitMiddleware {
+	return &RateLimitMiddleware{
+		Log:            log,
+		UseCase:        useCase,
+		SessionHandler: sessionHandler,
+		Metrics:        metrics,
+		Rules:          rules,
+	}
+}
+
+// Limit applies the first rule matching the request and answers 429 once
+// its window is used up. Counters live in postgres so every prefork worker
+// shares them; if the storage fails the request is let through.
+func (m *RateLimitMiddleware) Limit() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		rule := m.match(c.Method(), normalizePath(c.Path()))
+		if rule == nil {
+			return c.Next()
+		}
+
+		bucket := rule.Name + ":" + m.key(c, rule.Key)
+		counter, err := m.UseCase.Hit(c.UserContext(), bucket, rule.Window)
+		if err != nil {
+			pkg.Logger(c.UserContext(), m.Log).Errorf("Rate limit storage failed, allowing request: %v", err)
+			return c.Next()
+		}
+
+		reset := max(counter.ResetAt-time.Now().Unix(), 0)
+		remaining := max(rule.Limit-counter.Hits, 0)
+		c.Set("RateLimit-Limit", strconv.Itoa(rule.Limit))
+		c.Set("RateLimit-Remaining", strconv.Itoa(remaining))
+		c.Set("RateLimit-Reset", strconv.FormatInt(reset, 10))
+		c.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", rule.Limit, int(rule.Window.Seconds())))
+
+		if counter.Hits > rule.Limit {
+			m.Metrics.RateLimited(rule.Name)
+			pkg.Logger(c.UserContext(), m.Log).WithField("rule", rule.Name).Warn("Rate limit exceeded")
+			c.Set(fiber.HeaderRetryAfter, strconv.FormatInt(reset, 10))
+			return fiber.NewError(fiber.StatusTooManyRequests, "rate limit exceeded, try again later")
+		}
+		return c.Next()
+	}
+}
+
+func (m *RateLimitMiddleware) match(method string, path string) *RateLimitRule {
+	for i := range m.Rules {
+		if m.Rules[i].matches(method, path) {
+			return &m.Rules[i]
+		}
+	}
+	return nil
+}
+
+// key identifies the client for a rule. User keyed rules fall back to the
+// IP for anonymous requests.
+func (m *RateLimitMiddleware) key(c *fiber.Ctx, keyType string) string {
+	ip := "ip=" + c.IP()
+	if keyType == RateLimitKeyIP {
+		return ip
+	}
+
+	userID, ok := m.userID(c)
+	if !ok {
+		return ip
+	}
+	user := "user=" + strconv.Itoa(userID)
+	if keyType == RateLimitKeyUser {
+		return user
+	}
+	return ip + "," + user
+}
+
+// userID is the owner of a bearer token resolved by
+// AuthMiddleware.ResolveToken, or else the user of the session.
+func (m *RateLimitMiddleware) userID(c *fiber.Ctx) (int, bool) {
+	if user, ok := c.Locals("token_user").(*entity.User); ok {
+		return user.ID, true
+	}
+	if !m.SessionHandler.IsAuthenticated(c) {
+		return 0, false
+	}
+	userID, err := m.SessionHandler.GetUserID(c)
+	if err != nil {
+		return 0, false
+	}
+	return userID, true
+}
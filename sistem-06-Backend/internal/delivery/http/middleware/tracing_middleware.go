@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+
+	"sistem-06-Backend/internal/infrastructure/tracing"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type TracingMiddleware struct {
+	tracer trace.Tracer
+}
+
+func NewTracingMiddleware() *TracingMiddleware {
+	return &TracingMiddleware{
+		tracer: otel.Tracer("sistem-06-Backend/internal/delivery/http"),
+	}
+}
+
+// Trace continues the W3C trace context of the caller, or starts a new
+// trace, and opens the server span every usecase and query span nests under.
+func (m *TracingMiddleware) Trace() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		carrier := tracing.HeaderCarrier{Ctx: c}
+		ctx := otel.GetTextMapPropagator().Extract(c.UserContext(), carrier)
+
+		ctx, span := m.tracer.Start(ctx, c.Method(),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(c.Method()),
+				semconv.URLPath(c.Path()),
+			),
+		)
+		defer span.End()
+
+		c.SetUserContext(ctx)
+		otel.GetTextMapPropagator().Inject(ctx, carrier)
+
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				status = fiberErr.Code
+			}
+		}
+
+		// the route pattern is only known once routing has happened
+		route := c.Route().Path
+		span.SetName(fmt.Sprintf("%s %s", c.Method(), route))
+		span.SetAttributes(
+			semconv.HTTPRoute(route),
+			semconv.HTTPResponseStatusCode(status),
+		)
+		if status >= fiber.StatusInternalServerError {
+			if err != nil {
+				span.RecordError(err)
+			}
+			span.SetStatus(codes.Error, fmt.Sprintf("HTTP %d", status))
+		}
+
+		return err
+	}
+}
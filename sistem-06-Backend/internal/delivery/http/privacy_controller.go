@@ -0,0 +1,68 @@
+package http
+
+import (
+	"sistem-06-Backend/internal/dto"
+	"sistem-06-Backend/internal/usecase"
+	"sistem-06-Backend/pkg"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type PrivacyController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.PrivacyUseCase
+}
+
+func NewPrivacyController(useCase *usecase.PrivacyUseCase, log *logrus.Logger) *PrivacyController {
+	return &PrivacyController{
+		Log:     log,
+		UseCase: useCase,
+	}
+}
+
+// Export downloads everything stored about the logged in user, as JSON or,
+// with ?format=zip, as a ZIP of one JSON file per table.
+func (c *PrivacyController) Export(ctx *fiber.Ctx) error {
+	file, err := c.UseCase.Export(ctx.UserContext(), ctx.Locals("user_id").(int), ctx.Query("format"))
+	if err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to export personal data : %+v", err)
+		return err
+	}
+	return sendFile(ctx, c.Log, file)
+}
+
+func (c *PrivacyController) RequestDeletion(ctx *fiber.Ctx) error {
+	request := new(dto.DeleteAccountRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+
+	response, err := c.UseCase.RequestDeletion(ctx.UserContext(), ctx.Locals("user_id").(int), request)
+	if err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to request account deletion : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusAccepted).JSON(pkg.WebResponse[*dto.DeletionRequestResponse]{Data: response})
+}
+
+func (c *PrivacyController) GetDeletion(ctx *fiber.Ctx) error {
+	response, err := c.UseCase.GetDeletion(ctx.UserContext(), ctx.Locals("user_id").(int))
+	if err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to get account deletion : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(pkg.WebResponse[*dto.DeletionRequestResponse]{Data: response})
+}
+
+func (c *PrivacyController) CancelDeletion(ctx *fiber.Ctx) error {
+	if err := c.UseCase.CancelDeletion(ctx.UserContext(), ctx.Locals("user_id").(int)); err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to cancel account deletion : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(pkg.WebResponse[bool]{Data: true})
+}
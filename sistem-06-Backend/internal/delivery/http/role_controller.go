@@ -0,0 +1,70 @@
+package http
+
+import (
+	"sistem-06-Backend/internal/dto"
+	"sistem-06-Backend/internal/usecase"
+	"sistem-06-Backend/pkg"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type RoleController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.RoleUseCase
+}
+
+func NewRoleController(useCase *usecase.RoleUseCase, log *logrus.Logger) *RoleController {
+	return &RoleController{
+		Log:     log,
+		UseCase: useCase,
+	}
+}
+
+func (c *RoleController) Get(ctx *fiber.Ctx) error {
+	roleID, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid role id")
+	}
+
+	response, err := c.UseCase.Get(ctx.UserContext(), roleID)
+	if err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to get role : %+v", err)
+		return err
+	}
+
+	ctx.Set(fiber.HeaderETag, pkg.ETag(response.Version))
+	return ctx.JSON(pkg.WebResponse[*dto.RoleResponse]{Data: response})
+}
+
+func (c *RoleController) Delete(ctx *fiber.Ctx) error {
+	roleID, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid role id")
+	}
+	version, err := ifMatch(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := c.UseCase.Delete(ctx.UserContext(), roleID, version); err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to delete role : %+v", err)
+		return preconditionFailed(ctx, err)
+	}
+
+	return ctx.JSON(pkg.WebResponse[bool]{Data: true})
+}
+
+func (c *RoleController) Restore(ctx *fiber.Ctx) error {
+	roleID, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid role id")
+	}
+
+	if err := c.UseCase.Restore(ctx.UserContext(), roleID); err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to restore role : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(pkg.WebResponse[bool]{Data: true})
+}
@@ -0,0 +1,137 @@
+package route
+
+import (
+	"sistem-06-Backend/internal/delivery/http"
+	"sistem-06-Backend/internal/delivery/http/middleware"
+	"sistem-06-Backend/internal/domain/entity"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type RouteConfig struct {
+	App                 *fiber.App
+	AuthMiddleware      *middleware.AuthMiddleware
+	LoggingMiddleware   *middleware.LoggingMiddleware
+	MetricsMiddleware   *middleware.MetricsMiddleware
+	TracingMiddleware   *middleware.TracingMiddleware
+	RateLimitMiddleware *middleware.RateLimitMiddleware
+	CSRFMiddleware      *middleware.CSRFMiddleware
+	UserController      *http.UserController
+	AuthController      *http.AuthController
+	AccountController   *http.AccountController
+	TwoFactorController *http.TwoFactorController
+	TokenController     *http.TokenController
+	AddressController   *http.AddressController
+	AuditController     *http.AuditController
+	RoleController      *http.RoleController
+	SearchController    *http.SearchController
+	ImportController    *http.ImportController
+	ExportController    *http.ExportController
+	PrivacyController   *http.PrivacyController
+	DatabaseController  *http.DatabaseController
+	HealthController    *http.HealthController
+	MetricsController   *http.MetricsController
+}
+
+func (c *RouteConfig) Setup() {
+	// tracing runs first so the request logger can pick up the trace id
+	c.App.Use(c.TracingMiddleware.Trace(), c.LoggingMiddleware.RequestID(), c.LoggingMiddleware.AccessLog())
+	// ahead of the route handlers, so RequireGuest and RequiredAuth are limited
+	// too; bearer tokens are resolved first so they are limited per user
+	c.App.Use(c.AuthMiddleware.ResolveToken(), c.RateLimitMiddleware.Limit())
+	// every state-changing API request needs the token from GET /api/v1/csrf
+	c.App.Use("/api/v1", c.CSRFMiddleware.Protect())
+
+	c.SetupHealthRoute()
+	c.SetupGuestRoute()
+	c.SetupTwoFactorRoute()
+	c.SetupAuthRoute()
+}
+
+// SetupHealthRoute registers the probe endpoints outside of /api/v1 so no
+// session or auth middleware runs in front of them.
+func (c *RouteConfig) SetupHealthRoute() {
+	c.App.Get("/healthz", c.HealthController.Liveness)
+	c.App.Get("/readyz", c.HealthController.Readiness)
+	c.App.Get("/status", c.AuthMiddleware.RequiredAuth(), c.HealthController.Status)
+
+	// nil when metrics.enabled is false
+	if c.MetricsController != nil {
+		c.App.Get("/metrics", c.MetricsMiddleware.Protect(), c.MetricsController.Metrics)
+	}
+}
+
+func (c *RouteConfig) SetupGuestRoute() {
+	api := c.App.Group("/api/v1")
+
+	api.Get("/csrf", c.AuthController.CSRFToken)
+	api.Post("/users", c.AuthMiddleware.RequireGuest(), c.UserController.Register)
+	api.Post("/login", c.AuthMiddleware.RequireGuest(), c.AuthController.Login)
+	api.Post("/login/2fa", c.AuthMiddleware.RequireGuest(), c.AuthController.VerifyTwoFactor)
+}
+
+// SetupTwoFactorRoute is registered ahead of SetupAuthRoute, whose group
+// middleware rejects sessions that still have to enroll in 2FA.
+func (c *RouteConfig) SetupTwoFactorRoute() {
+	twoFactor := c.App.Group("/api/v1/2fa", c.AuthMiddleware.RequiredAuthForEnrollment())
+
+	twoFactor.Post("/enroll", c.TwoFactorController.Enroll)
+	twoFactor.Post("/enable", c.TwoFactorController.Enable)
+	twoFactor.Post("/disable", c.TwoFactorController.Disable)
+	twoFactor.Post("/recovery-codes", c.TwoFactorController.RegenerateRecoveryCodes)
+}
+
+func (c *RouteConfig) SetupAuthRoute() {
+	api := c.App.Group("/api/v1", c.AuthMiddleware.RequiredAuth())
+
+	// changing the account needs the session; a token may only read it
+	api.Get("/me", c.AccountController.Get)
+	api.Patch("/me", c.AuthMiddleware.RequireSession(), c.AccountController.Update)
+	api.Post("/me/email/verify", c.AuthMiddleware.RequireSession(), c.AccountController.VerifyEmail)
+	api.Post("/me/password", c.AuthMiddleware.RequireSession(), c.AccountController.ChangePassword)
+	// the export holds everything about the user, so it needs the session too
+	api.Get("/me/export", c.AuthMiddleware.RequireSession(), c.PrivacyController.Export)
+	api.Get("/me/deletion", c.PrivacyController.GetDeletion)
+	api.Post("/me/deletion", c.AuthMiddleware.RequireSession(), c.PrivacyController.RequestDeletion)
+	api.Delete("/me/deletion", c.AuthMiddleware.RequireSession(), c.PrivacyController.CancelDeletion)
+
+	// addresses belong to nobody, so every route needs the permission
+	api.Post("/addresses", c.AuthMiddleware.RequirePermission(entity.PermissionManageAddresses), c.AddressController.Create)
+	api.Get("/addresses", c.AuthMiddleware.RequirePermission(entity.PermissionManageAddresses), c.AddressController.List)
+	api.Get("/addresses/:id", c.AuthMiddleware.RequirePermission(entity.PermissionManageAddresses), c.AddressController.Get)
+	api.Patch("/addresses/:id", c.AuthMiddleware.RequirePermission(entity.PermissionManageAddresses), c.AddressController.Update)
+	api.Delete("/addresses/:id", c.AuthMiddleware.RequirePermission(entity.PermissionManageAddresses), c.AddressController.Delete)
+	api.Post("/addresses/:id/restore", c.AuthMiddleware.RequirePermission(entity.PermissionManageAddresses), c.AddressController.Restore)
+
+	api.Get("/users", c.AuthMiddleware.RequirePermission(entity.PermissionManageUsers), c.UserController.List)
+	api.Get("/users/:id", c.AuthMiddleware.RequirePermission(entity.PermissionManageUsers), c.UserController.Get)
+	api.Post("/users/:id/unlock", c.AuthMiddleware.RequirePermission(entity.PermissionManageUsers), c.AuthController.Unlock)
+	api.Delete("/users/:id", c.AuthMiddleware.RequirePermission(entity.PermissionManageUsers), c.UserController.Delete)
+	api.Post("/users/:id/restore", c.AuthMiddleware.RequirePermission(entity.PermissionManageUsers), c.UserController.Restore)
+
+	api.Get("/roles/:id", c.AuthMiddleware.RequirePermission(entity.PermissionManageRoles), c.RoleController.Get)
+	api.Delete("/roles/:id", c.AuthMiddleware.RequirePermission(entity.PermissionManageRoles), c.RoleController.Delete)
+	api.Post("/roles/:id/restore", c.AuthMiddleware.RequirePermission(entity.PermissionManageRoles), c.RoleController.Restore)
+
+	// the use case narrows the results further to what the viewer may see
+	api.Get("/search", c.AuthMiddleware.RequireAnyPermission(entity.PermissionManageUsers, entity.PermissionManageAddresses), c.SearchController.Search)
+
+	api.Post("/imports/addresses", c.AuthMiddleware.RequirePermission(entity.PermissionManageAddresses), c.ImportController.Addresses)
+	api.Post("/imports/users", c.AuthMiddleware.RequirePermission(entity.PermissionManageUsers), c.ImportController.Users)
+	// only the job's creator may read it, as checked by the use case
+	api.Get("/imports/:id", c.AuthMiddleware.RequireAnyPermission(entity.PermissionManageUsers, entity.PermissionManageAddresses), c.ImportController.Get)
+
+	// the use case masks the columns the viewer lacks the permission for
+	api.Get("/exports/users", c.AuthMiddleware.RequireAnyPermission(entity.PermissionManageUsers, entity.PermissionExportData), c.ExportController.Users)
+	api.Get("/exports/addresses", c.AuthMiddleware.RequireAnyPermission(entity.PermissionManageAddresses, entity.PermissionExportData), c.ExportController.Addresses)
+
+	tokens := api.Group("/tokens", c.AuthMiddleware.RequireSession())
+	tokens.Get("/", c.TokenController.List)
+	tokens.Post("/", c.TokenController.Create)
+	tokens.Delete("/:id", c.TokenController.Revoke)
+
+	api.Get("/audit-logs", c.AuthMiddleware.RequirePermission(entity.PermissionViewAuditLog), c.AuditController.Search)
+	api.Get("/audit-logs/verify", c.AuthMiddleware.RequirePermission(entity.PermissionViewAuditLog), c.AuditController.Verify)
+
+	api.Get("/system/database", c.AuthMiddleware.RequirePermission(entity.PermissionViewSystem), c.DatabaseController.Stats)
+}
@@ -0,0 +1,41 @@
+package http
+
+import (
+	"sistem-06-Backend/internal/domain/entity"
+	"sistem-06-Backend/internal/dto"
+	"sistem-06-Backend/internal/usecase"
+	"sistem-06-Backend/pkg"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type SearchController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.SearchUseCase
+}
+
+func NewSearchController(useCase *usecase.SearchUseCase, log *logrus.Logger) *SearchController {
+	return &SearchController{
+		Log:     log,
+		UseCase: useCase,
+	}
+}
+
+func (c *SearchController) Search(ctx *fiber.Ctx) error {
+	request := &dto.SearchRequest{Limit: 20}
+	if err := ctx.QueryParser(request); err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to parse query : %+v", err)
+		return fiber.ErrBadRequest
+	}
+
+	// nil for session logins
+	token, _ := ctx.Locals("token").(*entity.PersonalAccessToken)
+	responses, err := c.UseCase.Search(ctx.UserContext(), ctx.Locals("user_id").(int), token, request)
+	if err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to search : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(pkg.WebResponse[[]*dto.SearchResult]{Data: responses})
+}
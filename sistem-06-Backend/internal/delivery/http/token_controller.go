@@ -0,0 +1,62 @@
+package http
+
+import (
+	"sistem-06-Backend/internal/dto"
+	"sistem-06-Backend/internal/usecase"
+	"sistem-06-Backend/pkg"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type TokenController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.TokenUseCase
+}
+
+func NewTokenController(useCase *usecase.TokenUseCase, log *logrus.Logger) *TokenController {
+	return &TokenController{
+		Log:     log,
+		UseCase: useCase,
+	}
+}
+
+func (c *TokenController) Create(ctx *fiber.Ctx) error {
+	request := new(dto.CreateTokenRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+
+	response, err := c.UseCase.Create(ctx.UserContext(), ctx.Locals("user_id").(int), request)
+	if err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to create token : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(pkg.WebResponse[*dto.CreateTokenResponse]{Data: response})
+}
+
+func (c *TokenController) List(ctx *fiber.Ctx) error {
+	response, err := c.UseCase.List(ctx.UserContext(), ctx.Locals("user_id").(int))
+	if err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to list tokens : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(pkg.WebResponse[[]*dto.TokenResponse]{Data: response})
+}
+
+func (c *TokenController) Revoke(ctx *fiber.Ctx) error {
+	tokenID, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid token id")
+	}
+
+	if err := c.UseCase.Revoke(ctx.UserContext(), ctx.Locals("user_id").(int), tokenID); err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to revoke token : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(pkg.WebResponse[bool]{Data: true})
+}
@@ -0,0 +1,91 @@
+package http
+
+import (
+	"sistem-06-Backend/internal/dto"
+	"sistem-06-Backend/internal/usecase"
+	"sistem-06-Backend/pkg"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type TwoFactorController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.TwoFactorUseCase
+	Session *pkg.SessionHandler
+}
+
+func NewTwoFactorController(useCase *usecase.TwoFactorUseCase, log *logrus.Logger, session *pkg.SessionHandler) *TwoFactorController {
+	return &TwoFactorController{
+		Log:     log,
+		UseCase: useCase,
+		Session: session,
+	}
+}
+
+func (c *TwoFactorController) Enroll(ctx *fiber.Ctx) error {
+	response, err := c.UseCase.Enroll(ctx.UserContext(), ctx.Locals("user_id").(int))
+	if err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to start two-factor enrollment : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(pkg.WebResponse[*dto.TwoFactorEnrollResponse]{Data: response})
+}
+
+func (c *TwoFactorController) Enable(ctx *fiber.Ctx) error {
+	request := new(dto.TwoFactorCodeRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+
+	request.IPAddress = ctx.IP()
+
+	response, err := c.UseCase.Enable(ctx.UserContext(), ctx.Locals("user_id").(int), request)
+	if err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to enable two-factor authentication : %+v", err)
+		return loginError(ctx, err)
+	}
+
+	if err := c.Session.SetTwoFactorEnrollmentRequired(ctx, false); err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return ctx.JSON(pkg.WebResponse[*dto.RecoveryCodesResponse]{Data: response})
+}
+
+func (c *TwoFactorController) Disable(ctx *fiber.Ctx) error {
+	request := new(dto.TwoFactorDisableRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+
+	request.IPAddress = ctx.IP()
+
+	if err := c.UseCase.Disable(ctx.UserContext(), ctx.Locals("user_id").(int), request); err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to disable two-factor authentication : %+v", err)
+		return loginError(ctx, err)
+	}
+
+	return ctx.JSON(pkg.WebResponse[bool]{Data: true})
+}
+
+func (c *TwoFactorController) RegenerateRecoveryCodes(ctx *fiber.Ctx) error {
+	request := new(dto.TwoFactorCodeRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+
+	request.IPAddress = ctx.IP()
+
+	response, err := c.UseCase.RegenerateRecoveryCodes(ctx.UserContext(), ctx.Locals("user_id").(int), request)
+	if err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to regenerate recovery codes : %+v", err)
+		return loginError(ctx, err)
+	}
+
+	return ctx.JSON(pkg.WebResponse[*dto.RecoveryCodesResponse]{Data: response})
+}
@@ -10,30 +10,99 @@ import (
 )
 
 type UserController struct {
-	Log     *logrus.Logger
-	UseCase *usecase.UserUseCase
+	Log         *logrus.Logger
+	UseCase     *usecase.UserUseCase
+	AuthUseCase *usecase.AuthUseCase
 }
 
-func NewUserController(useCase *usecase.UserUseCase, logger *logrus.Logger) *UserController {
+func NewUserController(useCase *usecase.UserUseCase, authUseCase *usecase.AuthUseCase, logger *logrus.Logger) *UserController {
 	return &UserController{
-		Log:     logger,
-		UseCase: useCase,
+		Log:         logger,
+		UseCase:     useCase,
+		AuthUseCase: authUseCase,
 	}
 }
 
 func (c *UserController) Register(ctx *fiber.Ctx) error {
-	request := new(*dto.RegisterUserRequest)
+	request := new(dto.RegisterUserRequest)
 	err := ctx.BodyParser(request)
 	if err != nil {
-		c.Log.Warnf("Failed to parse request body : %+v", err)
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to parse request body : %+v", err)
 		return fiber.ErrBadRequest
 	}
 
 	response, err := c.UseCase.Create(ctx.UserContext(), request)
 	if err != nil {
-		c.Log.Warnf("Failed to register user : %+v", err)
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to register user : %+v", err)
 		return err
 	}
 
 	return ctx.JSON(pkg.WebResponse[*dto.UserResponse]{Data: response})
 }
+
+func (c *UserController) List(ctx *fiber.Ctx) error {
+	response, err := c.UseCase.List(ctx.UserContext(), ctx.Queries())
+	if err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to list users : %+v", err)
+		return err
+	}
+	if err := maskFor(ctx, c.AuthUseCase, response); err != nil {
+		return err
+	}
+
+	return ctx.JSON(response)
+}
+
+func (c *UserController) Get(ctx *fiber.Ctx) error {
+	userID, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid user id")
+	}
+
+	response, err := c.UseCase.Get(ctx.UserContext(), userID)
+	if err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to get user : %+v", err)
+		return err
+	}
+	if err := maskFor(ctx, c.AuthUseCase, response); err != nil {
+		return err
+	}
+
+	ctx.Set(fiber.HeaderETag, pkg.ETag(response.Version))
+	return ctx.JSON(pkg.WebResponse[*dto.UserResponse]{Data: response})
+}
+
+func (c *UserController) Delete(ctx *fiber.Ctx) error {
+	userID, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid user id")
+	}
+	version, err := ifMatch(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := c.UseCase.Delete(ctx.UserContext(), userID, version); err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to delete user : %+v", err)
+		return preconditionFailed(ctx, err)
+	}
+
+	return ctx.JSON(pkg.WebResponse[bool]{Data: true})
+}
+
+func (c *UserController) Restore(ctx *fiber.Ctx) error {
+	userID, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid user id")
+	}
+
+	response, err := c.UseCase.Restore(ctx.UserContext(), userID)
+	if err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).Warnf("Failed to restore user : %+v", err)
+		return err
+	}
+
+	ctx.Set(fiber.HeaderETag, pkg.ETag(response.Version))
+
+	return ctx.JSON(pkg.WebResponse[*dto.UserResponse]{Data: response})
+}
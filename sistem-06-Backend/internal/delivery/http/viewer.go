@@ -0,0 +1,21 @@
+package http
+
+import (
+	"sistem-06-Backend/internal/domain/entity"
+	"sistem-06-Backend/internal/pkg/mask"
+	"sistem-06-Backend/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// maskFor masks response for the authenticated viewer, see mask.Apply.
+func maskFor(ctx *fiber.Ctx, authUseCase *usecase.AuthUseCase, response any) error {
+	// nil for session logins
+	token, _ := ctx.Locals("token").(*entity.PersonalAccessToken)
+	viewer, err := authUseCase.Viewer(ctx.UserContext(), ctx.Locals("user_id").(int), token)
+	if err != nil {
+		return err
+	}
+	mask.Apply(response, viewer)
+	return nil
+}
@@ -7,4 +7,6 @@ type Address struct {
 	RW         string
 	Kota       string
 	PostalCode string
+	DeletedAt  int64
+	Version    int
 }
@@ -0,0 +1,14 @@
+package entity
+
+// DeletionRequest is a user's request to erase their account. It is carried
+// out at ScheduledAt unless cancelled before; CompletedAt stays 0 until then.
+type DeletionRequest struct {
+	UserID      int
+	RequestedAt int64
+	ScheduledAt int64
+	CompletedAt int64
+}
+
+func (r *DeletionRequest) Pending() bool {
+	return r.CompletedAt == 0
+}
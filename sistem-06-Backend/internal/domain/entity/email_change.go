@@ -0,0 +1,11 @@
+package entity
+
+// EmailChange is a requested new email address that waits for the owner of
+// that address to confirm it with the mailed token.
+type EmailChange struct {
+	UserID    int
+	NewEmail  string
+	TokenHash string
+	ExpiresAt int64
+	CreatedAt int64
+}
@@ -5,4 +5,7 @@ import "errors"
 var (
 	ErrUserNotPermitted = errors.New("User Not Permitted")
 	ErrUnauthorized     = errors.New("User Unathorized")
+	// ErrVersionConflict is returned by repositories when a row changed
+	// after the version the caller read.
+	ErrVersionConflict = errors.New("version conflict")
 )
@@ -0,0 +1,40 @@
+package entity
+
+const (
+	ImportKindAddress = "address"
+	ImportKindUser    = "user"
+)
+
+const (
+	ImportStatusPending   = "pending"
+	ImportStatusRunning   = "running"
+	ImportStatusSucceeded = "succeeded"
+	ImportStatusFailed    = "failed"
+)
+
+// ImportJob tracks one uploaded file while it is validated and, unless
+// DryRun is set, imported. Rows are imported all together or not at all, so
+// ImportedRows stays 0 until the job succeeds.
+type ImportJob struct {
+	ID            string
+	Kind          string
+	Status        string
+	DryRun        bool
+	TotalRows     int
+	ProcessedRows int
+	ImportedRows  int
+	Errors        []ImportRowError
+	CreatedBy     int
+	CreatedAt     int64
+	// 0 while the job is pending or running
+	FinishedAt int64
+}
+
+// ImportRowError is a problem with one row of the file. Row is the line
+// number a spreadsheet program shows; Field is empty for problems with the
+// whole row or, with Row 0, the whole file.
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
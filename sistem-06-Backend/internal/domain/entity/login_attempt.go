@@ -0,0 +1,17 @@
+package entity
+
+// LoginScope is what failed login attempts are counted against.
+type LoginScope string
+
+const (
+	LoginScopeAccount LoginScope = "account"
+	LoginScopeIP      LoginScope = "ip"
+)
+
+type LoginAttempt struct {
+	Scope        LoginScope
+	Identifier   string
+	Failures     int
+	LastFailedAt int64
+	LockedUntil  int64
+}
@@ -1,3 +1,22 @@
 package entity
 
 type Permissions string
+
+const (
+	// PermissionManageUsers allows administrative actions on other accounts,
+	// such as lifting a login lockout.
+	PermissionManageUsers Permissions = "users.manage"
+	// PermissionViewAuditLog allows reading and verifying the audit log.
+	PermissionViewAuditLog Permissions = "audit.view"
+	// PermissionManageAddresses allows creating, reading, changing, deleting
+	// and restoring addresses.
+	PermissionManageAddresses Permissions = "addresses.manage"
+	// PermissionManageRoles allows deleting and restoring roles.
+	PermissionManageRoles Permissions = "roles.manage"
+	// PermissionExportData allows downloading users and addresses in bulk.
+	// Emails are masked unless the viewer also has users.manage.
+	PermissionExportData Permissions = "data.export"
+	// PermissionViewSystem allows reading operational details such as the
+	// database pool statistics.
+	PermissionViewSystem Permissions = "system.view"
+)
@@ -0,0 +1,29 @@
+package entity
+
+// PersonalAccessToken lets scripts call the API with a bearer token instead
+// of a session. Only the hash of the token is stored; ExpiresAt, LastUsedAt
+// and RevokedAt are unix seconds with 0 meaning unset.
+type PersonalAccessToken struct {
+	ID         int
+	UserID     int
+	Name       string
+	TokenHash  string
+	Scopes     []Permissions
+	ExpiresAt  int64
+	LastUsedAt int64
+	RevokedAt  int64
+	CreatedAt  int64
+}
+
+func (t *PersonalAccessToken) Active(now int64) bool {
+	return t.RevokedAt == 0 && now < t.ExpiresAt
+}
+
+func (t *PersonalAccessToken) HasScope(p Permissions) bool {
+	for _, scope := range t.Scopes {
+		if scope == p {
+			return true
+		}
+	}
+	return false
+}
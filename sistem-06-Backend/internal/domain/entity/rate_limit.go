@@ -0,0 +1,8 @@
+package entity
+
+// RateLimitCounter is the state of one fixed window after a hit.
+type RateLimitCounter struct {
+	Bucket  string
+	Hits    int
+	ResetAt int64
+}
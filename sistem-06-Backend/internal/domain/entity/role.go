@@ -1,6 +1,11 @@
 package entity
 
 type Role struct {
+	ID         int
 	Name       string
 	Permission []Permissions
+	// RequireTwoFactor makes 2FA mandatory for every member of the role
+	RequireTwoFactor bool
+	DeletedAt        int64
+	Version          int
 }
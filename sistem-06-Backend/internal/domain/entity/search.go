@@ -0,0 +1,25 @@
+package entity
+
+const (
+	SearchTypeUser    = "user"
+	SearchTypeAddress = "address"
+)
+
+// SearchQuery is free text matched by full-text and trigram similarity, and
+// against user emails only as a whole address.
+// UserID limits user results to that one user; 0 searches everyone.
+type SearchQuery struct {
+	Text   string
+	UserID int
+	Limit  int
+}
+
+type UserMatch struct {
+	User *User
+	Rank float32
+}
+
+type AddressMatch struct {
+	Address *Address
+	Rank    float32
+}
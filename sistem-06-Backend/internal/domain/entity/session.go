@@ -0,0 +1,11 @@
+package entity
+
+// Session is a stored login session. ID is the value of the session cookie,
+// so it must never leave the server.
+type Session struct {
+	ID        string
+	UserID    int
+	CreatedAt int64
+	// 0 when the storage keeps the session until it is deleted
+	ExpiresAt int64
+}
@@ -0,0 +1,12 @@
+package entity
+
+// TwoFactor is a user's TOTP enrollment. It is pending until Enabled is set
+// by confirming a first code.
+type TwoFactor struct {
+	UserID       int
+	Secret       string
+	Enabled      bool
+	LastUsedStep int64
+	CreatedAt    int64
+	EnabledAt    int64
+}
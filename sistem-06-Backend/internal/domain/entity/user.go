@@ -1,8 +1,18 @@
 package entity
 
 type User struct {
-	ID    int
-	Name  string
-	Email string
-	Role  Role
+	ID        int
+	Name      string
+	Email     string
+	Password  string
+	Roles     []Role
+	CreatedAt int64
+	UpdatedAt int64
+	// sessions and personal access tokens created before this unix time are
+	// no longer accepted
+	SessionsRevokedAt int64
+	// 0 while the account is live, otherwise the unix time it was deleted
+	DeletedAt int64
+	// incremented on every write, see ErrVersionConflict
+	Version int
 }
@@ -8,3 +8,21 @@ func (r *Role) HasPermission(p Permissions) bool {
 	}
 	return false
 }
+
+func (u *User) HasPermission(p Permissions) bool {
+	for i := range u.Roles {
+		if u.Roles[i].HasPermission(p) {
+			return true
+		}
+	}
+	return false
+}
+
+func (u *User) RequiresTwoFactor() bool {
+	for i := range u.Roles {
+		if u.Roles[i].RequireTwoFactor {
+			return true
+		}
+	}
+	return false
+}
@@ -1,7 +1,34 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"database/sql"
+	"iter"
+
+	"sistem-06-Backend/internal/domain/entity"
+	"sistem-06-Backend/internal/pkg/listquery"
+	"sistem-06-Backend/pkg"
+)
 
 type AddressRepository interface {
-	CreateAddress(ctx context.Context, address Address) error
+	WithTx(tx *sql.Tx) AddressRepository
+	CreateAddress(ctx context.Context, address *entity.Address) error
+	FindByID(ctx context.Context, id int) (*entity.Address, error)
+	// List returns one page of live addresss. Unknown fields or a bad cursor
+	// fail with *listquery.Error.
+	List(ctx context.Context, request *listquery.Request) ([]*entity.Address, pkg.PageMetadata, error)
+	// Export yields every live address matching request while the sequence
+	// is ranged over. Paging is ignored; request is checked up front like
+	// in List.
+	Export(ctx context.Context, request *listquery.Request) (iter.Seq2[*entity.Address, error], error)
+	// Update stores address if it is still at address.Version and then bumps
+	// address.Version. Update and SoftDelete return entity.ErrVersionConflict
+	// when the row has moved on.
+	Update(ctx context.Context, address *entity.Address) error
+	SoftDelete(ctx context.Context, id int, version int, deletedAt int64) error
+	Restore(ctx context.Context, id int) (bool, error)
+	PurgeDeleted(ctx context.Context, deletedBefore int64) (int64, error)
+	// ListCreatedBy returns the live addresses the audit log says userID
+	// created.
+	ListCreatedBy(ctx context.Context, userID int) ([]*entity.Address, error)
 }
@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"context"
+	"database/sql"
+
+	"sistem-06-Backend/internal/domain/entity"
+)
+
+type AuditRepository interface {
+	WithTx(tx *sql.Tx) AuditRepository
+	// Append links entry to the last entry of the chain, fills in PrevHash,
+	// Hash and ID, and stores it. It must run inside the transaction of the
+	// change being recorded, which holds the chain lock until it ends.
+	Append(ctx context.Context, entry *entity.AuditEntry) error
+	Search(ctx context.Context, filter entity.AuditFilter) ([]*entity.AuditEntry, error)
+	Count(ctx context.Context, filter entity.AuditFilter) (int64, error)
+	// ListAfter returns up to limit entries with an ID above afterID, oldest
+	// first, for walking the chain.
+	ListAfter(ctx context.Context, afterID int64, limit int) ([]*entity.AuditEntry, error)
+}
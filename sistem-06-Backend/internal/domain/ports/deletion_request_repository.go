@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"context"
+	"database/sql"
+
+	"sistem-06-Backend/internal/domain/entity"
+)
+
+type DeletionRequestRepository interface {
+	WithTx(tx *sql.Tx) DeletionRequestRepository
+	Create(ctx context.Context, request *entity.DeletionRequest) error
+	FindByUserID(ctx context.Context, userID int) (*entity.DeletionRequest, error)
+	// Cancel reports false when there is no pending request.
+	Cancel(ctx context.Context, userID int) (bool, error)
+	// ListDue returns up to limit pending requests scheduled at or before now.
+	ListDue(ctx context.Context, now int64, limit int) ([]*entity.DeletionRequest, error)
+	// Complete reports false when the request is no longer pending.
+	Complete(ctx context.Context, userID int, completedAt int64) (bool, error)
+}
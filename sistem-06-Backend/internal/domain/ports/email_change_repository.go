@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"context"
+	"database/sql"
+
+	"sistem-06-Backend/internal/domain/entity"
+)
+
+type EmailChangeRepository interface {
+	WithTx(tx *sql.Tx) EmailChangeRepository
+	Save(ctx context.Context, change *entity.EmailChange) error
+	FindByUserID(ctx context.Context, userID int) (*entity.EmailChange, error)
+	FindByTokenHash(ctx context.Context, hash string) (*entity.EmailChange, error)
+	Delete(ctx context.Context, userID int) error
+}
@@ -0,0 +1,8 @@
+package domain
+
+import "context"
+
+type HealthRepository interface {
+	Ping(ctx context.Context) error
+	MigrationVersion(ctx context.Context) (version int64, dirty bool, err error)
+}
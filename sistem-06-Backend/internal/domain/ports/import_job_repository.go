@@ -0,0 +1,14 @@
+package domain
+
+import (
+	"context"
+
+	"sistem-06-Backend/internal/domain/entity"
+)
+
+type ImportJobRepository interface {
+	Create(ctx context.Context, job *entity.ImportJob) error
+	FindByID(ctx context.Context, id string) (*entity.ImportJob, error)
+	UpdateProgress(ctx context.Context, job *entity.ImportJob) error
+	Finish(ctx context.Context, job *entity.ImportJob) error
+}
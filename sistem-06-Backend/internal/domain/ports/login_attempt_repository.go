@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"context"
+	"database/sql"
+
+	"sistem-06-Backend/internal/domain/entity"
+)
+
+type LoginAttemptRepository interface {
+	WithTx(tx *sql.Tx) LoginAttemptRepository
+	Find(ctx context.Context, scope entity.LoginScope, identifier string) (*entity.LoginAttempt, error)
+	RecordFailure(ctx context.Context, scope entity.LoginScope, identifier string, now int64, windowStart int64) (*entity.LoginAttempt, error)
+	Lock(ctx context.Context, scope entity.LoginScope, identifier string, until int64) error
+	Reset(ctx context.Context, scope entity.LoginScope, identifier string) error
+	DeleteExpired(ctx context.Context, lastFailedBefore int64, now int64) (int64, error)
+}
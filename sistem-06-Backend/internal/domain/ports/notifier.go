@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"sistem-06-Backend/internal/domain/entity"
+)
+
+// Notifier delivers security notices to users.
+type Notifier interface {
+	AccountLocked(ctx context.Context, user *entity.User, until time.Time) error
+	// EmailChangeRequested is sent to the new address with the token that
+	// confirms it.
+	EmailChangeRequested(ctx context.Context, user *entity.User, newEmail string, token string) error
+	PasswordChanged(ctx context.Context, user *entity.User) error
+}
@@ -1,8 +1,12 @@
 package domain
 
-import "context"
+import (
+	"context"
+
+	"sistem-06-Backend/internal/domain/entity"
+)
 
 type PermissionRepository interface {
-	GetPermissionsByRoleID(ctx context.Context, id int) (*UserWithRole, error)
-	GetPermissionsByUserID(ctx context.Context, id int) (*UserWithRole, error)
+	GetPermissionsByRoleID(ctx context.Context, id int) ([]entity.Permissions, error)
+	GetPermissionsByUserID(ctx context.Context, id int) ([]entity.Permissions, error)
 }
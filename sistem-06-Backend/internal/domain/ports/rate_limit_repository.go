@@ -0,0 +1,12 @@
+package domain
+
+import (
+	"context"
+
+	"sistem-06-Backend/internal/domain/entity"
+)
+
+type RateLimitRepository interface {
+	Hit(ctx context.Context, bucket string, now int64, resetAt int64) (*entity.RateLimitCounter, error)
+	DeleteExpired(ctx context.Context, now int64) (int64, error)
+}
@@ -1,10 +1,24 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"database/sql"
+
+	"sistem-06-Backend/internal/domain/entity"
+)
 
 type RolesRepository interface {
-	GetRolesByUserID(ctx context.Context, id int) (*Role, error)
-	GetRolesWithPermissionsByUserID(ctx context.Context, id int) (*UserWithRole, error)
+	WithTx(tx *sql.Tx) RolesRepository
+	FindByID(ctx context.Context, id int) (*entity.Role, error)
+	FindByName(ctx context.Context, name string) (*entity.Role, error)
+	GetRolesByUserID(ctx context.Context, id int) ([]entity.Role, error)
+	GetRolesWithPermissionsByUserID(ctx context.Context, id int) ([]entity.Role, error)
 	AssignRoleToUser(ctx context.Context, userId int, rolesId int) error
 	RemoveRoleFromUser(ctx context.Context, userId int, rolesId int) error
+	RemoveAllRolesFromUser(ctx context.Context, userId int) error
+	// SoftDelete returns entity.ErrVersionConflict when the role is no
+	// longer at version.
+	SoftDelete(ctx context.Context, id int, version int, deletedAt int64) error
+	Restore(ctx context.Context, id int) (bool, error)
+	PurgeDeleted(ctx context.Context, deletedBefore int64) (int64, error)
 }
@@ -0,0 +1,13 @@
+package domain
+
+import (
+	"context"
+
+	"sistem-06-Backend/internal/domain/entity"
+)
+
+// SearchRepository returns live rows matching a query, best match first.
+type SearchRepository interface {
+	Users(ctx context.Context, query entity.SearchQuery) ([]entity.UserMatch, error)
+	Addresses(ctx context.Context, query entity.SearchQuery) ([]entity.AddressMatch, error)
+}
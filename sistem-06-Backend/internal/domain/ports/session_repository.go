@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"context"
+	"database/sql"
+
+	"sistem-06-Backend/internal/domain/entity"
+)
+
+// SessionRepository reads the table of the session storage, which only
+// knows the user of a session by decoding its data.
+type SessionRepository interface {
+	WithTx(tx *sql.Tx) SessionRepository
+	// ListByUserID returns the sessions of the user that have not expired
+	// at now.
+	ListByUserID(ctx context.Context, userID int, now int64) ([]*entity.Session, error)
+	DeleteByUserID(ctx context.Context, userID int) (int64, error)
+}
@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"context"
+	"database/sql"
+
+	"sistem-06-Backend/internal/domain/entity"
+)
+
+type TokenRepository interface {
+	WithTx(tx *sql.Tx) TokenRepository
+	CreateToken(ctx context.Context, token *entity.PersonalAccessToken) error
+	FindTokenByHash(ctx context.Context, hash string) (*entity.PersonalAccessToken, error)
+	ListTokensByUserID(ctx context.Context, userID int) ([]*entity.PersonalAccessToken, error)
+	TouchToken(ctx context.Context, id int, usedAt int64) error
+	RevokeToken(ctx context.Context, id int, userID int, revokedAt int64) (bool, error)
+	DeleteTokensByUserID(ctx context.Context, userID int) error
+}
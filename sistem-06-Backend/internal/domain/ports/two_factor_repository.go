@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"context"
+	"database/sql"
+
+	"sistem-06-Backend/internal/domain/entity"
+)
+
+type TwoFactorRepository interface {
+	WithTx(tx *sql.Tx) TwoFactorRepository
+	Find(ctx context.Context, userID int) (*entity.TwoFactor, error)
+	SavePending(ctx context.Context, twoFactor *entity.TwoFactor) (bool, error)
+	Enable(ctx context.Context, userID int, enabledAt int64) (bool, error)
+	MarkStepUsed(ctx context.Context, userID int, step int64) (bool, error)
+	Delete(ctx context.Context, userID int) error
+	ReplaceRecoveryCodes(ctx context.Context, userID int, hashes []string, createdAt int64) error
+	UseRecoveryCode(ctx context.Context, userID int, hash string, usedAt int64) (bool, error)
+}
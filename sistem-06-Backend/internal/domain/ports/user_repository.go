@@ -1,11 +1,47 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"database/sql"
+	"iter"
+
+	"sistem-06-Backend/internal/domain/entity"
+	"sistem-06-Backend/internal/pkg/listquery"
+	"sistem-06-Backend/pkg"
+)
 
 type UserRepository interface {
-	CreateUser(ctx context.Context, user *UserEntity) error
+	WithTx(tx *sql.Tx) UserRepository
+	CreateUser(ctx context.Context, user *entity.User) error
 	CountById(ctx context.Context, id int) (int, error)
 	CountByName(ctx context.Context, name string) (int, error)
-	FindByEmail(ctx context.Context, email string) (*UserEntity, error)
-	FindByID(ctx context.Context, id int) (*UserEntity, error)
+	FindByEmail(ctx context.Context, email string) (*entity.User, error)
+	FindByID(ctx context.Context, id int) (*entity.User, error)
+	FindWithRoles(ctx context.Context, id int) (*entity.User, error)
+	// List returns one page of live users. Unknown fields or a bad cursor
+	// fail with *listquery.Error.
+	List(ctx context.Context, request *listquery.Request) ([]*entity.User, pkg.PageMetadata, error)
+	// Export yields every live user matching request, with role names,
+	// while the sequence is ranged over. Paging is ignored; request is
+	// checked up front like in List.
+	Export(ctx context.Context, request *listquery.Request) (iter.Seq2[*entity.User, error], error)
+	// UpdateName and SoftDelete only apply while the user is still at
+	// version, otherwise they return entity.ErrVersionConflict.
+	UpdateName(ctx context.Context, id int, version int, name string, updatedAt int64) error
+	UpdateEmail(ctx context.Context, id int, email string, updatedAt int64) error
+	UpdatePassword(ctx context.Context, id int, password string, updatedAt int64) error
+	// RehashPassword replaces the hash of an unchanged password without
+	// touching the version or updated_at.
+	RehashPassword(ctx context.Context, id int, password string) error
+	RevokeSessions(ctx context.Context, id int, revokedAt int64) error
+	SoftDelete(ctx context.Context, id int, version int, deletedAt int64) error
+	// Restore reports false when there is no deleted user with the id.
+	Restore(ctx context.Context, id int, restoredAt int64) (bool, error)
+	// PurgeDeleted removes users deleted before deletedBefore for good.
+	// Anonymized users are kept.
+	PurgeDeleted(ctx context.Context, deletedBefore int64) (int64, error)
+	// Anonymize replaces the name and email with placeholder, clears the
+	// password and deletes the user, who can never be restored. It reports
+	// false when the user was anonymized already.
+	Anonymize(ctx context.Context, id int, placeholder string, anonymizedAt int64) (bool, error)
 }
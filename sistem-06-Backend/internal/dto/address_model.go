@@ -1,12 +1,16 @@
 package dto
 
+// AddressEntity shows the street only to viewers with addresses.manage;
+// anyone else, such as exports by data.export holders, sees the RT, RW and
+// city. See mask.Apply.
 type AddressEntity struct {
 	ID         int    `json:"id"`
-	Jalan      string `json:"jalan"`
+	Jalan      string `json:"jalan" mask:"text,addresses.manage"`
 	RT         string `json:"RT"`
 	RW         string `json:"RW"`
 	Kota       string `json:"Kota"`
 	PostalCode string `json:"PostalCode"`
+	Version    int    `json:"version"`
 }
 
 type AddressRequest struct {
@@ -16,3 +20,12 @@ type AddressRequest struct {
 	Kota       string `json:"Kota" validate:"required"`
 	PostalCode string `json:"PostalCode" validate:"required,postal_code"`
 }
+
+// UpdateAddressRequest changes only the fields that are sent.
+type UpdateAddressRequest struct {
+	Jalan      *string `json:"jalan" validate:"omitnil,min=1"`
+	RT         *string `json:"RT" validate:"omitnil,RT_RW"`
+	RW         *string `json:"RW" validate:"omitnil,RT_RW"`
+	Kota       *string `json:"Kota" validate:"omitnil,min=1"`
+	PostalCode *string `json:"PostalCode" validate:"omitnil,postal_code"`
+}
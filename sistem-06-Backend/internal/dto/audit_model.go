@@ -0,0 +1,40 @@
+package dto
+
+import "encoding/json"
+
+// SearchAuditLogRequest is read from the query string. From and To are unix
+// seconds and inclusive.
+type SearchAuditLogRequest struct {
+	ActorID    int    `query:"actor_id" validate:"min=0"`
+	Action     string `query:"action" validate:"max=50"`
+	EntityType string `query:"entity_type" validate:"max=50"`
+	EntityID   string `query:"entity_id" validate:"max=64"`
+	From       int64  `query:"from" validate:"min=0"`
+	To         int64  `query:"to" validate:"min=0"`
+	Page       int    `query:"page" validate:"min=1"`
+	Size       int    `query:"size" validate:"min=1,max=100"`
+}
+
+type AuditLogResponse struct {
+	ID         int64           `json:"id"`
+	ActorID    int             `json:"actor_id,omitempty"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Diff       json.RawMessage `json:"diff"`
+	IP         string          `json:"ip,omitempty"`
+	RequestID  string          `json:"request_id,omitempty"`
+	CreatedAt  int64           `json:"created_at"`
+	PrevHash   string          `json:"prev_hash"`
+	Hash       string          `json:"hash"`
+}
+
+// AuditVerifyResponse reports the first entry whose link to the chain is
+// broken. LastHash can be kept outside the database: entries removed from
+// the end of the chain are only noticed by comparing it later.
+type AuditVerifyResponse struct {
+	Valid    bool   `json:"valid"`
+	Checked  int64  `json:"checked"`
+	BrokenAt int64  `json:"broken_at,omitempty"`
+	LastHash string `json:"last_hash"`
+}
@@ -1,6 +1,35 @@
 package dto
 
 type UserLoginRequest struct {
-	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required,min=8"`
+	Email string `json:"email" validate:"required,email"`
+	// max is password.MaxLength, no stored password can be longer
+	Password string `json:"password" validate:"required,min=8,max=1024"`
+	// IPAddress is filled in by the controller for brute-force tracking
+	IPAddress string `json:"-"`
+}
+
+// LoginResponse is what AuthUseCase.Login hands to the controller. With
+// TwoFactorRequired set the password was correct but the session must wait
+// for the second factor.
+type LoginResponse struct {
+	User                        *UserResponse
+	TwoFactorRequired           bool
+	TwoFactorEnrollmentRequired bool
+}
+
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool  `json:"two_factor_required"`
+	ExpiresAt         int64 `json:"expires_at"`
+}
+
+type TwoFactorLoginRequest struct {
+	// a 6 digit TOTP code or a recovery code
+	Code string `json:"code" validate:"required,min=6,max=16"`
+	// UserID and IPAddress are filled in by the controller
+	UserID    int    `json:"-"`
+	IPAddress string `json:"-"`
+}
+
+type CSRFTokenResponse struct {
+	Token string `json:"token"`
 }
@@ -0,0 +1,17 @@
+package dto
+
+type DatabaseStatsResponse struct {
+	MaxConns                int32  `json:"max_conns"`
+	TotalConns              int32  `json:"total_conns"`
+	AcquiredConns           int32  `json:"acquired_conns"`
+	IdleConns               int32  `json:"idle_conns"`
+	ConstructingConns       int32  `json:"constructing_conns"`
+	AcquireCount            int64  `json:"acquire_count"`
+	EmptyAcquireCount       int64  `json:"empty_acquire_count"`
+	CanceledAcquireCount    int64  `json:"canceled_acquire_count"`
+	AcquireDurationMs       int64  `json:"acquire_duration_ms"`
+	NewConnsCount           int64  `json:"new_conns_count"`
+	MaxLifetimeDestroyCount int64  `json:"max_lifetime_destroy_count"`
+	MaxIdleDestroyCount     int64  `json:"max_idle_destroy_count"`
+	ApplicationName         string `json:"application_name"`
+}
@@ -0,0 +1,11 @@
+package dto
+
+import "io"
+
+// ExportFile is a download that is produced while it is sent. Write runs
+// the query, so it must only be called once.
+type ExportFile struct {
+	Filename    string
+	ContentType string
+	Write       func(w io.Writer) error
+}
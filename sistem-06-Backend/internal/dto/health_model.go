@@ -0,0 +1,38 @@
+package dto
+
+import "sistem-06-Backend/internal/pkg/buildinfo"
+
+type HealthResponse struct {
+	Status string `json:"status"`
+}
+
+type HealthCheck struct {
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latency_ms"`
+	Detail    string `json:"detail,omitempty"`
+}
+
+type ReadinessResponse struct {
+	Status string                 `json:"status"`
+	Checks map[string]HealthCheck `json:"checks"`
+}
+
+type StatusResponse struct {
+	Build         buildinfo.Info `json:"build"`
+	StartedAt     int64          `json:"started_at"`
+	UptimeSeconds int64          `json:"uptime_seconds"`
+	Ready         bool           `json:"ready"`
+	Database      SQLStats       `json:"database"`
+}
+
+type SQLStats struct {
+	MaxOpenConnections int   `json:"max_open_connections"`
+	OpenConnections    int   `json:"open_connections"`
+	InUse              int   `json:"in_use"`
+	Idle               int   `json:"idle"`
+	WaitCount          int64 `json:"wait_count"`
+	WaitDurationMs     int64 `json:"wait_duration_ms"`
+	MaxIdleClosed      int64 `json:"max_idle_closed"`
+	MaxIdleTimeClosed  int64 `json:"max_idle_time_closed"`
+	MaxLifetimeClosed  int64 `json:"max_lifetime_closed"`
+}
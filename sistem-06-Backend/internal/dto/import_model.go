@@ -0,0 +1,30 @@
+package dto
+
+// ImportRequest is the multipart form of an import. Mapping maps the column
+// headers of the file to import fields, e.g. {"Kode Pos": "postal_code"};
+// without it the headers have to be the field names themselves.
+type ImportRequest struct {
+	Filename string
+	Content  []byte
+	DryRun   bool
+	Mapping  map[string]string
+}
+
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+type ImportJobResponse struct {
+	ID            string           `json:"id"`
+	Kind          string           `json:"kind"`
+	Status        string           `json:"status"`
+	DryRun        bool             `json:"dry_run"`
+	TotalRows     int              `json:"total_rows"`
+	ProcessedRows int              `json:"processed_rows"`
+	ImportedRows  int              `json:"imported_rows"`
+	Errors        []ImportRowError `json:"errors"`
+	CreatedAt     int64            `json:"created_at"`
+	FinishedAt    int64            `json:"finished_at,omitempty"`
+}
@@ -0,0 +1,42 @@
+package dto
+
+// DeleteAccountRequest confirms a deletion request with the password.
+type DeleteAccountRequest struct {
+	Password string `json:"password" validate:"required"`
+}
+
+type DeletionRequestResponse struct {
+	RequestedAt int64 `json:"requested_at"`
+	// the account can be kept by cancelling the request before this time
+	ScheduledAt int64 `json:"scheduled_at"`
+}
+
+// PersonalUserData is the users row in a personal data export.
+type PersonalUserData struct {
+	ID                int    `json:"id"`
+	Name              string `json:"name"`
+	Email             string `json:"email"`
+	CreatedAt         int64  `json:"created_at"`
+	UpdatedAt         int64  `json:"updated_at"`
+	SessionsRevokedAt int64  `json:"sessions_revoked_at,omitempty"`
+}
+
+// PersonalSessionData leaves out the session id, which would let anyone
+// holding the export log in.
+type PersonalSessionData struct {
+	CreatedAt int64 `json:"created_at"`
+	ExpiresAt int64 `json:"expires_at"`
+}
+
+// PersonalTwoFactorData leaves out the secret and the recovery codes.
+type PersonalTwoFactorData struct {
+	Enabled   bool  `json:"enabled"`
+	CreatedAt int64 `json:"created_at"`
+	EnabledAt int64 `json:"enabled_at,omitempty"`
+}
+
+type PersonalEmailChangeData struct {
+	NewEmail  string `json:"new_email"`
+	CreatedAt int64  `json:"created_at"`
+	ExpiresAt int64  `json:"expires_at"`
+}
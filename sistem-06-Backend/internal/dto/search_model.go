@@ -0,0 +1,22 @@
+package dto
+
+// SearchRequest is read from the query string. Type limits the search to one
+// kind of record. Query matches names and addresses by full text and by
+// trigram similarity, so typos still find them. Emails are encrypted, so a
+// user is found by email only when Query is the whole address as it was
+// registered; parts of an email or misspelled ones match nothing.
+type SearchRequest struct {
+	Query string `query:"q" validate:"required,max=200"`
+	Type  string `query:"type" validate:"omitempty,oneof=user address"`
+	Limit int    `query:"limit" validate:"min=1,max=50"`
+}
+
+// SearchResult is one ranked hit. Data is a UserResponse or an AddressEntity
+// depending on Type.
+type SearchResult struct {
+	Type  string  `json:"type"`
+	ID    int     `json:"id"`
+	Label string  `json:"label"`
+	Rank  float32 `json:"rank"`
+	Data  any     `json:"data"`
+}
@@ -0,0 +1,24 @@
+package dto
+
+type CreateTokenRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+	// permission names, e.g. users.manage; each must be held by the user
+	Scopes []string `json:"scopes" validate:"dive,required,max=100"`
+	// 0 means the configured default lifetime
+	ExpiresInDays int `json:"expires_in_days" validate:"min=0"`
+}
+
+type TokenResponse struct {
+	ID         int      `json:"id"`
+	Name       string   `json:"name"`
+	Scopes     []string `json:"scopes"`
+	ExpiresAt  int64    `json:"expires_at"`
+	LastUsedAt int64    `json:"last_used_at"`
+	CreatedAt  int64    `json:"created_at"`
+}
+
+// CreateTokenResponse carries the plain token, which is only shown once.
+type CreateTokenResponse struct {
+	TokenResponse
+	Token string `json:"token"`
+}
@@ -0,0 +1,23 @@
+package dto
+
+type TwoFactorEnrollResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" validate:"required,min=6,max=16"`
+	// IPAddress is filled in by the controller for brute-force tracking
+	IPAddress string `json:"-"`
+}
+
+type TwoFactorDisableRequest struct {
+	Password string `json:"password" validate:"required"`
+	Code     string `json:"code" validate:"required,min=6,max=16"`
+	// IPAddress is filled in by the controller for brute-force tracking
+	IPAddress string `json:"-"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
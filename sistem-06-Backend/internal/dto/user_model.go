@@ -1,17 +1,25 @@
 package dto
 
+// UserResponse shows emails in full only to the user themselves and to
+// viewers with users.manage; see mask.Apply.
 type UserResponse struct {
-	ID        int            `json:"id,omitempty"`
+	ID        int            `json:"id,omitempty" mask:"owner"`
 	Name      string         `json:"name,omitempty"`
-	Email     string         `json:"email,omitempty"`
+	Email     string         `json:"email,omitempty" mask:"email,users.manage"`
 	Roles     []RoleResponse `json:"roles"`
 	CreatedAt int64          `json:"created_at,omitempty"`
 	UpdatedAt int64          `json:"updated_at,omitempty"`
+	// an email change that still waits for confirmation
+	PendingEmail string `json:"pending_email,omitempty" mask:"hide,users.manage"`
+	Version      int    `json:"version,omitempty"`
 }
+
+// RegisterUserRequest only requires a password; its length and strength are
+// checked by the password policy.
 type RegisterUserRequest struct {
 	Name     string `json:"name" validate:"required,max=100"`
 	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required,min=8"`
+	Password string `json:"password" validate:"required"`
 }
 type VerifyUserRequest struct {
 	Token string `validate:"required,max=100"`
@@ -21,4 +29,21 @@ type RoleResponse struct {
 	ID          int      `json:"id"`
 	Name        string   `json:"name"`
 	Permissions []string `json:"permissions"`
+	// only set when the role itself is requested
+	DeletedAt int64 `json:"deleted_at,omitempty"`
+	Version   int   `json:"version,omitempty"`
+}
+
+// UpdateAccountRequest changes only the fields that are sent. A new email is
+// not applied until it is confirmed with the mailed token.
+type UpdateAccountRequest struct {
+	Name  *string `json:"name" validate:"omitnil,min=1,max=100"`
+	Email *string `json:"email" validate:"omitnil,email"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	Password        string `json:"password" validate:"required"`
+	// also revokes every personal access token created before the change
+	RevokeOtherSessions bool `json:"revoke_other_sessions"`
 }
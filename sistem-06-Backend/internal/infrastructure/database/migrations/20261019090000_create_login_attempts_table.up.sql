@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS login_attempts (
+    scope VARCHAR(16) NOT NULL,
+    identifier VARCHAR(255) NOT NULL,
+    failures INT NOT NULL DEFAULT 0,
+    last_failed_at BIGINT NOT NULL,
+    locked_until BIGINT NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (scope, identifier)
+);
+
+CREATE INDEX idx_login_attempts_last_failed_at ON login_attempts(last_failed_at);
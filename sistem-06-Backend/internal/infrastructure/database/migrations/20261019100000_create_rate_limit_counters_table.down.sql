@@ -0,0 +1 @@
+DROP TABLE rate_limit_counters;
\ No newline at end of file
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS rate_limit_counters (
+    bucket VARCHAR(255) PRIMARY KEY,
+    hits INT NOT NULL,
+    reset_at BIGINT NOT NULL
+);
+
+CREATE INDEX idx_rate_limit_counters_reset_at ON rate_limit_counters(reset_at);
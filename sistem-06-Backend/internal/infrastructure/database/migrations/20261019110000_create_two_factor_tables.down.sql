@@ -0,0 +1,3 @@
+ALTER TABLE roles DROP COLUMN require_two_factor;
+DROP TABLE user_recovery_codes;
+DROP TABLE user_two_factor;
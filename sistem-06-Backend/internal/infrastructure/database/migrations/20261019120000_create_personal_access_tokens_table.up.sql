@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS personal_access_tokens (
+    id INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    user_id INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    scopes TEXT NOT NULL DEFAULT '',
+    expires_at BIGINT NOT NULL,
+    last_used_at BIGINT NOT NULL DEFAULT 0,
+    revoked_at BIGINT NOT NULL DEFAULT 0,
+    created_at BIGINT NOT NULL,
+
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id) REFERENCES users(id)
+        ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_personal_access_tokens_token_hash ON personal_access_tokens(token_hash);
+CREATE INDEX idx_personal_access_tokens_user_id ON personal_access_tokens(user_id);
@@ -0,0 +1,2 @@
+DROP TABLE email_changes;
+ALTER TABLE users DROP COLUMN sessions_revoked_at;
\ No newline at end of file
@@ -0,0 +1,16 @@
+ALTER TABLE users
+    ADD COLUMN sessions_revoked_at BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS email_changes (
+    user_id INT PRIMARY KEY,
+    new_email VARCHAR(255) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    expires_at BIGINT NOT NULL,
+    created_at BIGINT NOT NULL,
+
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id) REFERENCES users(id)
+        ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_email_changes_token_hash ON email_changes(token_hash);
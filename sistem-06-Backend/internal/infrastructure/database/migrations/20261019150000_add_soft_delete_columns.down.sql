@@ -0,0 +1,14 @@
+DROP INDEX idx_roles_deleted_at;
+DROP INDEX idx_address_deleted_at;
+DROP INDEX idx_users_deleted_at;
+
+DROP INDEX idx_address_rt;
+ALTER TABLE address ADD CONSTRAINT address_rt_key UNIQUE (rt);
+
+DROP INDEX idx_users_email;
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
+CREATE INDEX idx_users_email ON users(email);
+
+ALTER TABLE roles DROP COLUMN deleted_at;
+ALTER TABLE address DROP COLUMN deleted_at;
+ALTER TABLE users DROP COLUMN deleted_at;
\ No newline at end of file
@@ -0,0 +1,22 @@
+-- 0 while the row is live, otherwise the unix time it was deleted
+ALTER TABLE users
+    ADD COLUMN deleted_at BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE address
+    ADD COLUMN deleted_at BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE roles
+    ADD COLUMN deleted_at BIGINT NOT NULL DEFAULT 0;
+
+-- deleted rows keep their values, so uniqueness only applies to live rows
+ALTER TABLE users DROP CONSTRAINT users_email_key;
+DROP INDEX idx_users_email;
+CREATE UNIQUE INDEX idx_users_email ON users(email) WHERE deleted_at = 0;
+
+ALTER TABLE address DROP CONSTRAINT address_rt_key;
+CREATE UNIQUE INDEX idx_address_rt ON address(rt) WHERE deleted_at = 0;
+
+-- used by the purge job
+CREATE INDEX idx_users_deleted_at ON users(deleted_at) WHERE deleted_at > 0;
+CREATE INDEX idx_address_deleted_at ON address(deleted_at) WHERE deleted_at > 0;
+CREATE INDEX idx_roles_deleted_at ON roles(deleted_at) WHERE deleted_at > 0;
@@ -0,0 +1,3 @@
+ALTER TABLE roles DROP COLUMN version;
+ALTER TABLE address DROP COLUMN version;
+ALTER TABLE users DROP COLUMN version;
\ No newline at end of file
@@ -0,0 +1,10 @@
+-- bumped on every write; clients send it back in If-Match to detect lost
+-- updates
+ALTER TABLE users
+    ADD COLUMN version INT NOT NULL DEFAULT 1;
+
+ALTER TABLE address
+    ADD COLUMN version INT NOT NULL DEFAULT 1;
+
+ALTER TABLE roles
+    ADD COLUMN version INT NOT NULL DEFAULT 1;
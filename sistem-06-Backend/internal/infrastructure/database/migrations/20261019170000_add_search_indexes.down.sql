@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_address_kota_trgm;
+DROP INDEX IF EXISTS idx_address_jalan_trgm;
+DROP INDEX IF EXISTS idx_users_email_trgm;
+DROP INDEX IF EXISTS idx_users_name_trgm;
+DROP INDEX IF EXISTS idx_address_search_vector;
+DROP INDEX IF EXISTS idx_users_search_vector;
+
+ALTER TABLE address DROP COLUMN search_vector;
+ALTER TABLE users DROP COLUMN search_vector;
+
+DROP EXTENSION IF EXISTS pg_trgm;
\ No newline at end of file
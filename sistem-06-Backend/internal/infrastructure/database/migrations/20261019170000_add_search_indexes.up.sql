@@ -0,0 +1,25 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- names and street names go through the indonesian stemmer; emails and RT/RW
+-- numbers are kept as written
+ALTER TABLE users
+    ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('indonesian', name), 'A') ||
+        setweight(to_tsvector('simple', replace(email, '@', ' ')), 'B')
+    ) STORED;
+
+ALTER TABLE address
+    ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('indonesian', jalan), 'A') ||
+        setweight(to_tsvector('indonesian', kota), 'B') ||
+        setweight(to_tsvector('simple', 'rt ' || rt || ' rw ' || rw), 'C')
+    ) STORED;
+
+CREATE INDEX idx_users_search_vector ON users USING GIN (search_vector);
+CREATE INDEX idx_address_search_vector ON address USING GIN (search_vector);
+
+-- trigram indexes catch typos the full-text match misses
+CREATE INDEX idx_users_name_trgm ON users USING GIN (name gin_trgm_ops);
+CREATE INDEX idx_users_email_trgm ON users USING GIN (email gin_trgm_ops);
+CREATE INDEX idx_address_jalan_trgm ON address USING GIN (jalan gin_trgm_ops);
+CREATE INDEX idx_address_kota_trgm ON address USING GIN (kota gin_trgm_ops);
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS import_jobs (
+    id VARCHAR(36) PRIMARY KEY,
+    kind VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    dry_run BOOLEAN NOT NULL DEFAULT FALSE,
+    total_rows INT NOT NULL DEFAULT 0,
+    processed_rows INT NOT NULL DEFAULT 0,
+    imported_rows INT NOT NULL DEFAULT 0,
+    errors TEXT NOT NULL DEFAULT '[]',
+    created_by INT NOT NULL,
+    created_at BIGINT NOT NULL,
+    finished_at BIGINT NOT NULL DEFAULT 0,
+
+    CONSTRAINT fk_user
+        FOREIGN KEY (created_by) REFERENCES users(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_import_jobs_created_by ON import_jobs(created_by);
@@ -0,0 +1,19 @@
+-- Encrypted emails cannot be decrypted here; rolling back is only safe
+-- while encryption is turned off and no row has been encrypted.
+DROP INDEX IF EXISTS idx_users_search_vector;
+ALTER TABLE users DROP COLUMN search_vector;
+ALTER TABLE users
+    ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('indonesian', name), 'A') ||
+        setweight(to_tsvector('simple', replace(email, '@', ' ')), 'B')
+    ) STORED;
+CREATE INDEX idx_users_search_vector ON users USING GIN (search_vector);
+CREATE INDEX idx_users_email_trgm ON users USING GIN (email gin_trgm_ops);
+
+DROP INDEX IF EXISTS idx_users_email;
+DROP INDEX IF EXISTS idx_users_email_hash;
+CREATE UNIQUE INDEX idx_users_email ON users(email) WHERE deleted_at = 0;
+
+ALTER TABLE users DROP COLUMN email_hash;
+ALTER TABLE users ALTER COLUMN email TYPE VARCHAR(255);
+ALTER TABLE email_changes ALTER COLUMN new_email TYPE VARCHAR(255);
\ No newline at end of file
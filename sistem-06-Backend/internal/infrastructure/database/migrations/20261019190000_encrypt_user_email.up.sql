@@ -0,0 +1,29 @@
+-- users.email holds ciphertext from now on, which is longer than the plain
+-- address and random, so lookups and uniqueness go through email_hash, a
+-- keyed hash of the address. Existing rows keep their plain email until
+-- `web encryption rotate` encrypts them.
+ALTER TABLE users ALTER COLUMN email TYPE TEXT;
+ALTER TABLE users ADD COLUMN email_hash VARCHAR(64);
+-- pending email changes are encrypted the same way
+ALTER TABLE email_changes ALTER COLUMN new_email TYPE TEXT;
+
+-- the unkeyed hash fieldcrypt uses without encryption keys, i.e.
+-- SHA-256 of "users.email\0<email>"; with an index key configured, rotate
+-- replaces it with the keyed one
+UPDATE users
+SET email_hash = encode(sha256(convert_to('users.email', 'UTF8') || '\x00'::bytea || convert_to(email, 'UTF8')), 'hex');
+
+DROP INDEX idx_users_email;
+CREATE UNIQUE INDEX idx_users_email_hash ON users(email_hash) WHERE deleted_at = 0;
+-- keeps plain emails unique for any row written without a hash
+CREATE UNIQUE INDEX idx_users_email ON users(email) WHERE email_hash IS NULL AND deleted_at = 0;
+
+-- ciphertext cannot be searched, so the search vector keeps only the name
+DROP INDEX idx_users_email_trgm;
+DROP INDEX idx_users_search_vector;
+ALTER TABLE users DROP COLUMN search_vector;
+ALTER TABLE users
+    ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('indonesian', name), 'A')
+    ) STORED;
+CREATE INDEX idx_users_search_vector ON users USING GIN (search_vector);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS deletion_requests;
+ALTER TABLE users DROP COLUMN anonymized_at;
\ No newline at end of file
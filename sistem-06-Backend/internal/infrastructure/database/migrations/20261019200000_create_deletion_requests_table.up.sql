@@ -0,0 +1,17 @@
+-- A carried out deletion request anonymizes the user instead of deleting
+-- the row, so audit entries that name the id keep pointing at a row.
+-- anonymized_at marks those rows; they are never restored or purged.
+ALTER TABLE users ADD COLUMN anonymized_at BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS deletion_requests (
+    user_id INT PRIMARY KEY,
+    requested_at BIGINT NOT NULL,
+    scheduled_at BIGINT NOT NULL,
+    completed_at BIGINT NOT NULL DEFAULT 0,
+
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id) REFERENCES users(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_deletion_requests_due ON deletion_requests(scheduled_at) WHERE completed_at = 0;
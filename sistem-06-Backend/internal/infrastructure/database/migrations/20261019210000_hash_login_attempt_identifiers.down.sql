@@ -0,0 +1,3 @@
+-- Blind indexes cannot be turned back into emails; the account counters are
+-- dropped again.
+DELETE FROM login_attempts WHERE scope = 'account';
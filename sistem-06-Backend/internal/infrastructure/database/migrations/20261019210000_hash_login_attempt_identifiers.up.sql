@@ -0,0 +1,5 @@
+-- Account counters are now kept under a blind index of the email instead of
+-- the address. The keyed index cannot be computed here, so the counters
+-- stored under plain emails are dropped, which also lifts their lockouts;
+-- IP counters are kept.
+DELETE FROM login_attempts WHERE scope = 'account';
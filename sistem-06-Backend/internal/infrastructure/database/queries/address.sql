@@ -5,14 +5,39 @@ VALUES ($1, $2, $3, $4, $5)
 RETURNING id;
 
 -- name: FindAdressByID :one
-SELECT id, jalan, rt, rw, kota, postal_code FROM address WHERE id = $1;
+SELECT id, jalan, rt, rw, kota, postal_code, deleted_at, version FROM address WHERE id = $1 AND deleted_at = 0;
 
--- name: UpdateAddress :exec
+-- The address table has no owner, so the addresses of a user are the ones
+-- the audit log says they created.
+
+-- name: ListAddressesCreatedBy :many
+SELECT a.id, a.jalan, a.rt, a.rw, a.kota, a.postal_code, a.deleted_at, a.version
+FROM address a
+JOIN audit_logs l ON l.entity_type = 'address' AND l.action = 'create' AND l.entity_id = a.id::text
+WHERE l.actor_id = $1 AND a.deleted_at = 0
+ORDER BY a.id;
+
+-- name: UpdateAddress :execrows
 UPDATE address 
 SET
 jalan = COALESCE(sqlc.narg('jalan'), jalan),
 rt = COALESCE(sqlc.narg('rt'), rt),
 rw = COALESCE(sqlc.narg('rw'), rw),
 kota = COALESCE(sqlc.narg('kota'), kota),
-postal_code = COALESCE(sqlc.narg('postal_code'), postal_code)
-WHERE id = $1 ;
+postal_code = COALESCE(sqlc.narg('postal_code'), postal_code),
+version = version + 1
+WHERE id = $1 AND version = sqlc.arg('version') AND deleted_at = 0;
+
+-- name: SoftDeleteAddress :execrows
+UPDATE address
+SET deleted_at = $2, version = version + 1
+WHERE id = $1 AND version = $3 AND deleted_at = 0;
+
+-- name: RestoreAddress :execrows
+UPDATE address
+SET deleted_at = 0, version = version + 1
+WHERE id = $1 AND deleted_at > 0;
+
+-- name: PurgeDeletedAddresses :execrows
+DELETE FROM address
+WHERE deleted_at > 0 AND deleted_at < $1;
@@ -0,0 +1,42 @@
+-- name: LockAuditChain :exec
+SELECT pg_advisory_xact_lock($1);
+
+-- name: LastAuditLogHash :one
+SELECT hash
+FROM audit_logs
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: CreateAuditLog :one
+INSERT INTO audit_logs (actor_id, action, entity_type, entity_id, diff, ip, request_id, created_at, prev_hash, hash)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING id;
+
+-- name: ListAuditLogs :many
+SELECT id, actor_id, action, entity_type, entity_id, diff, ip, request_id, created_at, prev_hash, hash
+FROM audit_logs
+WHERE (sqlc.narg(actor_id)::int IS NULL OR actor_id = sqlc.narg(actor_id))
+  AND (sqlc.narg(action)::text IS NULL OR action = sqlc.narg(action))
+  AND (sqlc.narg(entity_type)::text IS NULL OR entity_type = sqlc.narg(entity_type))
+  AND (sqlc.narg(entity_id)::text IS NULL OR entity_id = sqlc.narg(entity_id))
+  AND (sqlc.narg(created_from)::bigint IS NULL OR created_at >= sqlc.narg(created_from))
+  AND (sqlc.narg(created_to)::bigint IS NULL OR created_at <= sqlc.narg(created_to))
+ORDER BY id DESC
+LIMIT sqlc.arg(limit_count) OFFSET sqlc.arg(offset_count);
+
+-- name: CountAuditLogs :one
+SELECT COUNT(*)
+FROM audit_logs
+WHERE (sqlc.narg(actor_id)::int IS NULL OR actor_id = sqlc.narg(actor_id))
+  AND (sqlc.narg(action)::text IS NULL OR action = sqlc.narg(action))
+  AND (sqlc.narg(entity_type)::text IS NULL OR entity_type = sqlc.narg(entity_type))
+  AND (sqlc.narg(entity_id)::text IS NULL OR entity_id = sqlc.narg(entity_id))
+  AND (sqlc.narg(created_from)::bigint IS NULL OR created_at >= sqlc.narg(created_from))
+  AND (sqlc.narg(created_to)::bigint IS NULL OR created_at <= sqlc.narg(created_to));
+
+-- name: ListAuditLogsAfter :many
+SELECT id, actor_id, action, entity_type, entity_id, diff, ip, request_id, created_at, prev_hash, hash
+FROM audit_logs
+WHERE id > $1
+ORDER BY id
+LIMIT $2;
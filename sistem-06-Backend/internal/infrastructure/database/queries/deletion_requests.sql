@@ -0,0 +1,24 @@
+-- name: CreateDeletionRequest :exec
+INSERT INTO deletion_requests (user_id, requested_at, scheduled_at)
+VALUES ($1, $2, $3);
+
+-- name: FindDeletionRequest :one
+SELECT user_id, requested_at, scheduled_at, completed_at
+FROM deletion_requests
+WHERE user_id = $1;
+
+-- name: CancelDeletionRequest :execrows
+DELETE FROM deletion_requests
+WHERE user_id = $1 AND completed_at = 0;
+
+-- name: ListDueDeletionRequests :many
+SELECT user_id, requested_at, scheduled_at, completed_at
+FROM deletion_requests
+WHERE completed_at = 0 AND scheduled_at <= $1
+ORDER BY scheduled_at
+LIMIT $2;
+
+-- name: CompleteDeletionRequest :execrows
+UPDATE deletion_requests
+SET completed_at = $2
+WHERE user_id = $1 AND completed_at = 0;
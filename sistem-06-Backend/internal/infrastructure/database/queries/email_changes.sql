@@ -0,0 +1,37 @@
+-- name: UpsertEmailChange :exec
+INSERT INTO email_changes (user_id, new_email, token_hash, expires_at, created_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (user_id) DO UPDATE
+SET new_email = EXCLUDED.new_email,
+    token_hash = EXCLUDED.token_hash,
+    expires_at = EXCLUDED.expires_at,
+    created_at = EXCLUDED.created_at;
+
+-- name: FindEmailChangeByUserID :one
+SELECT user_id, new_email, token_hash, expires_at, created_at
+FROM email_changes
+WHERE user_id = $1;
+
+-- name: FindEmailChangeByTokenHash :one
+SELECT user_id, new_email, token_hash, expires_at, created_at
+FROM email_changes
+WHERE token_hash = $1;
+
+-- name: DeleteEmailChange :exec
+DELETE FROM email_changes
+WHERE user_id = $1;
+
+-- name: ListEmailChanges :many
+SELECT user_id, new_email
+FROM email_changes
+WHERE user_id > $1
+ORDER BY user_id
+LIMIT $2;
+
+-- ReencryptEmailChange skips the row when the change was replaced since it
+-- was read.
+
+-- name: ReencryptEmailChange :execrows
+UPDATE email_changes
+SET new_email = sqlc.arg(new_email)
+WHERE user_id = sqlc.arg(user_id) AND new_email = sqlc.arg(old_email);
@@ -0,0 +1,22 @@
+-- name: CreateImportJob :exec
+INSERT INTO import_jobs (id, kind, status, dry_run, total_rows, created_by, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: FindImportJobByID :one
+SELECT id, kind, status, dry_run, total_rows, processed_rows, imported_rows, errors, created_by, created_at, finished_at
+FROM import_jobs
+WHERE id = $1;
+
+-- name: UpdateImportJobProgress :exec
+UPDATE import_jobs
+SET status = $2, processed_rows = $3
+WHERE id = $1;
+
+-- name: FinishImportJob :exec
+UPDATE import_jobs
+SET status = $2,
+    processed_rows = $3,
+    imported_rows = $4,
+    errors = $5,
+    finished_at = $6
+WHERE id = $1;
@@ -0,0 +1,28 @@
+-- name: FindLoginAttempt :one
+SELECT scope, identifier, failures, last_failed_at, locked_until
+FROM login_attempts
+WHERE scope = $1 AND identifier = $2;
+
+-- name: RecordLoginFailure :one
+INSERT INTO login_attempts (scope, identifier, failures, last_failed_at)
+VALUES ($1, $2, 1, $3)
+ON CONFLICT (scope, identifier) DO UPDATE
+SET failures = CASE
+        WHEN login_attempts.last_failed_at < sqlc.arg(window_start) THEN 1
+        ELSE login_attempts.failures + 1
+    END,
+    last_failed_at = EXCLUDED.last_failed_at
+RETURNING scope, identifier, failures, last_failed_at, locked_until;
+
+-- name: LockLoginAttempt :exec
+UPDATE login_attempts
+SET locked_until = GREATEST(locked_until, $3)
+WHERE scope = $1 AND identifier = $2;
+
+-- name: DeleteLoginAttempt :exec
+DELETE FROM login_attempts
+WHERE scope = $1 AND identifier = $2;
+
+-- name: DeleteExpiredLoginAttempts :execrows
+DELETE FROM login_attempts
+WHERE last_failed_at < $1 AND locked_until < $2;
@@ -3,11 +3,13 @@
 SELECT p.id, p.name
 FROM permissions p
 JOIN roles_permissions rp ON rp.permission_id = p.id
-WHERE rp.role_id = $1;
+JOIN roles r ON r.id = rp.role_id
+WHERE rp.role_id = $1 AND r.deleted_at = 0;
 
 -- name: GetPermissionsByUserID :many
 SELECT DISTINCT p.id, p.name
 FROM permissions p
 JOIN roles_permissions rp ON rp.permission_id = p.id
 JOIN user_roles ur ON ur.roles_id = rp.role_id
-WHERE ur.user_id = $1;
\ No newline at end of file
+JOIN roles r ON r.id = rp.role_id
+WHERE ur.user_id = $1 AND r.deleted_at = 0;
\ No newline at end of file
@@ -0,0 +1,29 @@
+-- name: CreatePersonalAccessToken :one
+INSERT INTO personal_access_tokens (user_id, name, token_hash, scopes, expires_at, created_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id;
+
+-- name: FindPersonalAccessTokenByHash :one
+SELECT id, user_id, name, token_hash, scopes, expires_at, last_used_at, revoked_at, created_at
+FROM personal_access_tokens
+WHERE token_hash = $1;
+
+-- name: ListPersonalAccessTokensByUserID :many
+SELECT id, user_id, name, token_hash, scopes, expires_at, last_used_at, revoked_at, created_at
+FROM personal_access_tokens
+WHERE user_id = $1 AND revoked_at = 0
+ORDER BY id;
+
+-- name: TouchPersonalAccessToken :exec
+UPDATE personal_access_tokens
+SET last_used_at = $2
+WHERE id = $1;
+
+-- name: RevokePersonalAccessToken :execrows
+UPDATE personal_access_tokens
+SET revoked_at = $3
+WHERE id = $1 AND user_id = $2 AND revoked_at = 0;
+
+-- name: DeletePersonalAccessTokensByUserID :exec
+DELETE FROM personal_access_tokens
+WHERE user_id = $1;
@@ -0,0 +1,17 @@
+-- name: HitRateLimit :one
+INSERT INTO rate_limit_counters (bucket, hits, reset_at)
+VALUES (sqlc.arg(bucket), 1, sqlc.arg(reset_at))
+ON CONFLICT (bucket) DO UPDATE
+SET hits = CASE
+        WHEN rate_limit_counters.reset_at <= sqlc.arg(now) THEN 1
+        ELSE rate_limit_counters.hits + 1
+    END,
+    reset_at = CASE
+        WHEN rate_limit_counters.reset_at <= sqlc.arg(now) THEN EXCLUDED.reset_at
+        ELSE rate_limit_counters.reset_at
+    END
+RETURNING bucket, hits, reset_at;
+
+-- name: DeleteExpiredRateLimits :execrows
+DELETE FROM rate_limit_counters
+WHERE reset_at <= $1;
@@ -1,20 +1,31 @@
+-- name: FindRoleByID :one
+SELECT id, name, require_two_factor, deleted_at, version
+FROM roles
+WHERE id = $1 AND deleted_at = 0;
+
+-- name: FindRoleByName :one
+SELECT id, name, require_two_factor, deleted_at, version
+FROM roles
+WHERE name = $1 AND deleted_at = 0;
+
 -- name: GetRolesByUserID :many
-SELECT r.id, r.name
+SELECT r.id, r.name, r.require_two_factor, r.deleted_at, r.version
 FROM roles r
 JOIN user_roles ur ON ur.roles_id = r.id
-WHERE ur.user_id = $1;
+WHERE ur.user_id = $1 AND r.deleted_at = 0;
 
 -- name: GetRolesWithPermissionsByUserID :many
 SELECT 
     r.id AS role_id,
     r.name AS role_name,
+    r.require_two_factor,
     p.id AS permission_id,
     p.name AS permission_name
 FROM user_roles ur
 JOIN roles r ON r.id = ur.roles_id
 LEFT JOIN roles_permissions rp ON rp.role_id = r.id
 LEFT JOIN permissions p ON p.id = rp.permission_id
-WHERE ur.user_id = $1
+WHERE ur.user_id = $1 AND r.deleted_at = 0
 ORDER BY r.id, p.name;
 
 -- name: AssignRoleToUser :exec
@@ -24,3 +35,21 @@ VALUES ($1, $2);
 -- name: RemoveRoleFromUser :exec
 DELETE FROM user_roles
 WHERE user_id = $1 AND roles_id = $2;
+
+-- name: RemoveAllRolesFromUser :exec
+DELETE FROM user_roles
+WHERE user_id = $1;
+
+-- name: SoftDeleteRole :execrows
+UPDATE roles
+SET deleted_at = $2, version = version + 1
+WHERE id = $1 AND version = $3 AND deleted_at = 0;
+
+-- name: RestoreRole :execrows
+UPDATE roles
+SET deleted_at = 0, version = version + 1
+WHERE id = $1 AND deleted_at > 0;
+
+-- name: PurgeDeletedRoles :execrows
+DELETE FROM roles
+WHERE deleted_at > 0 AND deleted_at < $1;
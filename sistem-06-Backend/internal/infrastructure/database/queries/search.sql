@@ -0,0 +1,29 @@
+-- terms is an OR of prefix lexemes, e.g. 'budi:* | melati:*'; query is the
+-- raw input matched by trigram similarity. Emails are encrypted, so a user
+-- is only found by email through the blind index of the whole address.
+
+-- name: SearchUsers :many
+SELECT id, name, email, created_at, updated_at, version,
+       (ts_rank(search_vector, to_tsquery('indonesian', sqlc.arg(terms)::text))
+        + word_similarity(name, sqlc.arg(query)::text)
+        + CASE WHEN email_hash = sqlc.arg(email_hash)::text THEN 1 ELSE 0 END)::real AS rank
+FROM users
+WHERE deleted_at = 0
+  AND (sqlc.narg(user_id)::int IS NULL OR id = sqlc.narg(user_id))
+  AND (search_vector @@ to_tsquery('indonesian', sqlc.arg(terms)::text)
+       OR name <% sqlc.arg(query)::text
+       OR email_hash = sqlc.arg(email_hash)::text)
+ORDER BY rank DESC, id
+LIMIT sqlc.arg(limit_count);
+
+-- name: SearchAddresses :many
+SELECT id, jalan, rt, rw, kota, postal_code, version,
+       (ts_rank(search_vector, to_tsquery('indonesian', sqlc.arg(terms)::text))
+        + greatest(word_similarity(jalan, sqlc.arg(query)::text), word_similarity(kota, sqlc.arg(query)::text)))::real AS rank
+FROM address
+WHERE deleted_at = 0
+  AND (search_vector @@ to_tsquery('indonesian', sqlc.arg(terms)::text)
+       OR jalan <% sqlc.arg(query)::text
+       OR kota <% sqlc.arg(query)::text)
+ORDER BY rank DESC, id
+LIMIT sqlc.arg(limit_count);
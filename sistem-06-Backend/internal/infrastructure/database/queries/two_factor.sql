@@ -0,0 +1,40 @@
+-- name: FindUserTwoFactor :one
+SELECT user_id, secret, enabled, last_used_step, created_at, enabled_at
+FROM user_two_factor
+WHERE user_id = $1;
+
+-- name: UpsertPendingTwoFactor :execrows
+INSERT INTO user_two_factor (user_id, secret, created_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id) DO UPDATE
+SET secret = EXCLUDED.secret,
+    last_used_step = 0,
+    created_at = EXCLUDED.created_at
+WHERE user_two_factor.enabled = FALSE;
+
+-- name: EnableUserTwoFactor :execrows
+UPDATE user_two_factor
+SET enabled = TRUE, enabled_at = $2
+WHERE user_id = $1 AND enabled = FALSE;
+
+-- name: MarkTwoFactorStepUsed :execrows
+UPDATE user_two_factor
+SET last_used_step = $2
+WHERE user_id = $1 AND last_used_step < $2;
+
+-- name: DeleteUserTwoFactor :exec
+DELETE FROM user_two_factor
+WHERE user_id = $1;
+
+-- name: CreateRecoveryCode :exec
+INSERT INTO user_recovery_codes (user_id, code_hash, created_at)
+VALUES ($1, $2, $3);
+
+-- name: DeleteRecoveryCodes :exec
+DELETE FROM user_recovery_codes
+WHERE user_id = $1;
+
+-- name: UseRecoveryCode :execrows
+UPDATE user_recovery_codes
+SET used_at = $3
+WHERE user_id = $1 AND code_hash = $2 AND used_at = 0;
@@ -1,23 +1,96 @@
 
 
 -- name: CreateUser :one
-INSERT INTO users (name, email, password, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5)
+INSERT INTO users (name, email, email_hash, password, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6)
 RETURNING id;
 
 
 -- name: CountUserByID :one
-SELECT COUNT(*) FROM users WHERE id = $1;
+SELECT COUNT(*) FROM users WHERE id = $1 AND deleted_at = 0;
 
 -- name: CountUserByName :one
-SELECT COUNT(*) FROM users WHERE name = $1;
+SELECT COUNT(*) FROM users WHERE name = $1 AND deleted_at = 0;
 
 
+-- email only matches rows that `web encryption rotate` has not reached yet,
+-- whose hash may still be the unkeyed one from the migration; ciphertext
+-- never equals an address
+
 -- name: FindUserByEmail :one
-SELECT id, name, email, password, created_at, updated_at 
-FROM users WHERE email = $1;
+SELECT id, name, email, password, created_at, updated_at, sessions_revoked_at, deleted_at, version
+FROM users
+WHERE (email_hash = sqlc.arg(email_hash)::text OR email = sqlc.arg(email))
+  AND deleted_at = 0;
 
 -- name: FindUserByID :one
-SELECT id, name, email, password, created_at, updated_at
+SELECT id, name, email, password, created_at, updated_at, sessions_revoked_at, deleted_at, version
+FROM users
+WHERE id = $1 AND deleted_at = 0;
+
+-- name: UpdateUserName :execrows
+UPDATE users
+SET name = $2, updated_at = $3, version = version + 1
+WHERE id = $1 AND version = $4 AND deleted_at = 0;
+
+-- name: UpdateUserEmail :exec
+UPDATE users
+SET email = $2, email_hash = $3, updated_at = $4, version = version + 1
+WHERE id = $1 AND deleted_at = 0;
+
+-- name: UpdateUserPassword :exec
+UPDATE users
+SET password = $2, updated_at = $3, version = version + 1
+WHERE id = $1 AND deleted_at = 0;
+
+-- RehashUserPassword swaps in a new hash of the same password, so the
+-- user's version and updated_at stay as they are.
+-- name: RehashUserPassword :exec
+UPDATE users
+SET password = $2
+WHERE id = $1 AND deleted_at = 0;
+
+-- RevokeUserSessions leaves the version alone, sessions_revoked_at is not
+-- part of the user clients edit.
+-- name: RevokeUserSessions :exec
+UPDATE users
+SET sessions_revoked_at = $2
+WHERE id = $1 AND deleted_at = 0;
+
+-- name: SoftDeleteUser :execrows
+UPDATE users
+SET deleted_at = $2, version = version + 1
+WHERE id = $1 AND version = $3 AND deleted_at = 0;
+
+-- name: RestoreUser :execrows
+UPDATE users
+SET deleted_at = 0, updated_at = $2, version = version + 1
+WHERE id = $1 AND deleted_at > 0 AND anonymized_at = 0;
+
+-- AnonymizeUser keeps the row for the audit trail. The placeholder goes
+-- into both name and email, which are unique.
+
+-- name: AnonymizeUser :execrows
+UPDATE users
+SET name = $2, email = $2, email_hash = NULL, password = '', updated_at = $3,
+    sessions_revoked_at = $3, deleted_at = $3, anonymized_at = $3, version = version + 1
+WHERE id = $1 AND anonymized_at = 0;
+
+-- name: ListUserEmails :many
+SELECT id, email, email_hash
 FROM users
-WHERE id = $1;
+WHERE id > $1
+ORDER BY id
+LIMIT $2;
+
+-- ReencryptUserEmail leaves version alone: the address itself is unchanged.
+-- It skips the row when the email changed since it was read.
+
+-- name: ReencryptUserEmail :execrows
+UPDATE users
+SET email = sqlc.arg(email), email_hash = sqlc.arg(email_hash)
+WHERE id = sqlc.arg(id) AND email = sqlc.arg(old_email);
+
+-- name: PurgeDeletedUsers :execrows
+DELETE FROM users
+WHERE deleted_at > 0 AND deleted_at < $1 AND anonymized_at = 0;
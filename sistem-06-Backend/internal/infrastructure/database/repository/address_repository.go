@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"iter"
+
+	"sistem-06-Backend/internal/domain/entity"
+	domain "sistem-06-Backend/internal/domain/ports"
+	"sistem-06-Backend/internal/infrastructure/database/sqlc"
+	"sistem-06-Backend/internal/pkg/listquery"
+	"sistem-06-Backend/pkg"
+
+	"github.com/sirupsen/logrus"
+)
+
+// addressListSchema is what GET /addresses may filter and sort on.
+var addressListSchema = &listquery.Schema[*entity.Address]{
+	Table:   "address",
+	Columns: "id, jalan, rt, rw, kota, postal_code, deleted_at, version",
+	Where:   "deleted_at = 0",
+	Fields: map[string]listquery.Field[*entity.Address]{
+		"id":          {Column: "id", Type: listquery.Integer, Sortable: true, Value: func(a *entity.Address) any { return a.ID }},
+		"jalan":       {Column: "jalan", Type: listquery.Text, Sortable: true, Value: func(a *entity.Address) any { return a.Jalan }},
+		"rt":          {Column: "rt", Type: listquery.Text, Sortable: true, Value: func(a *entity.Address) any { return a.RT }},
+		"rw":          {Column: "rw", Type: listquery.Text, Sortable: true, Value: func(a *entity.Address) any { return a.RW }},
+		"kota":        {Column: "kota", Type: listquery.Text, Sortable: true, Value: func(a *entity.Address) any { return a.Kota }},
+		"postal_code": {Column: "postal_code", Type: listquery.Text, Sortable: true, Value: func(a *entity.Address) any { return a.PostalCode }},
+	},
+	Key:         "id",
+	DefaultSort: []listquery.Sort{{Field: "id"}},
+	DefaultSize: 20,
+	MaxSize:     100,
+	CountTotal:  true,
+}
+
+type AddressRepositoryImpl struct {
+	q   *sqlc.Queries
+	log *logrus.Logger
+}
+
+func NewAddressRepository(q *sqlc.Queries, log *logrus.Logger) *AddressRepositoryImpl {
+	return &AddressRepositoryImpl{
+		q:   q,
+		log: log,
+	}
+}
+
+func (r *AddressRepositoryImpl) WithTx(tx *sql.Tx) domain.AddressRepository {
+	return &AddressRepositoryImpl{
+		q:   r.q.WithTx(tx),
+		log: r.log,
+	}
+}
+
+func (r *AddressRepositoryImpl) CreateAddress(ctx context.Context, address *entity.Address) error {
+	id, err := r.q.CreateAddress(ctx, sqlc.CreateAddressParams{
+		Jalan:      address.Jalan,
+		Rt:         address.RT,
+		Rw:         address.RW,
+		Kota:       address.Kota,
+		PostalCode: address.PostalCode,
+	})
+	if err != nil {
+		return err
+	}
+	address.ID = int(id)
+	return nil
+}
+
+func (r *AddressRepositoryImpl) FindByID(ctx context.Context, id int) (*entity.Address, error) {
+	row, err := r.q.FindAdressByID(ctx, int32(id))
+	if err != nil {
+		return nil, err
+	}
+	return toAddressEntity(row), nil
+}
+
+func (r *AddressRepositoryImpl) List(ctx context.Context, request *listquery.Request) ([]*entity.Address, pkg.PageMetadata, error) {
+	return list(ctx, r.q, addressListSchema, request, scanAddress)
+}
+
+func (r *AddressRepositoryImpl) Export(ctx context.Context, request *listquery.Request) (iter.Seq2[*entity.Address, error], error) {
+	return export(ctx, r.q, addressListSchema, request, scanAddress)
+}
+
+func (r *AddressRepositoryImpl) Update(ctx context.Context, address *entity.Address) error {
+	affected, err := r.q.UpdateAddress(ctx, sqlc.UpdateAddressParams{
+		ID:         int32(address.ID),
+		Jalan:      sql.NullString{String: address.Jalan, Valid: true},
+		Rt:         sql.NullString{String: address.RT, Valid: true},
+		Rw:         sql.NullString{String: address.RW, Valid: true},
+		Kota:       sql.NullString{String: address.Kota, Valid: true},
+		PostalCode: sql.NullString{String: address.PostalCode, Valid: true},
+		Version:    int32(address.Version),
+	})
+	if err := checkVersion(affected, err, r.exists(ctx, address.ID)); err != nil {
+		return err
+	}
+	address.Version++
+	return nil
+}
+
+func (r *AddressRepositoryImpl) SoftDelete(ctx context.Context, id int, version int, deletedAt int64) error {
+	affected, err := r.q.SoftDeleteAddress(ctx, sqlc.SoftDeleteAddressParams{
+		ID:        int32(id),
+		DeletedAt: deletedAt,
+		Version:   int32(version),
+	})
+	return checkVersion(affected, err, r.exists(ctx, id))
+}
+
+func (r *AddressRepositoryImpl) Restore(ctx context.Context, id int) (bool, error) {
+	affected, err := r.q.RestoreAddress(ctx, int32(id))
+	return affected > 0, err
+}
+
+func (r *AddressRepositoryImpl) PurgeDeleted(ctx context.Context, deletedBefore int64) (int64, error) {
+	return r.q.PurgeDeletedAddresses(ctx, deletedBefore)
+}
+
+func (r *AddressRepositoryImpl) ListCreatedBy(ctx context.Context, userID int) ([]*entity.Address, error) {
+	rows, err := r.q.ListAddressesCreatedBy(ctx, sql.NullInt32{Int32: int32(userID), Valid: true})
+	if err != nil {
+		return nil, err
+	}
+	addresses := make([]*entity.Address, 0, len(rows))
+	for _, row := range rows {
+		addresses = append(addresses, toAddressEntity(row))
+	}
+	return addresses, nil
+}
+
+func (r *AddressRepositoryImpl) exists(ctx context.Context, id int) func() (bool, error) {
+	return func() (bool, error) {
+		_, err := r.q.FindAdressByID(ctx, int32(id))
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+}
+
+// scanAddress reads a row of addressListSchema.Columns.
+func scanAddress(rows *sql.Rows) (*entity.Address, error) {
+	var row sqlc.Address
+	err := rows.Scan(
+		&row.ID,
+		&row.Jalan,
+		&row.Rt,
+		&row.Rw,
+		&row.Kota,
+		&row.PostalCode,
+		&row.DeletedAt,
+		&row.Version,
+	)
+	return toAddressEntity(&row), err
+}
+
+func toAddressEntity(row *sqlc.Address) *entity.Address {
+	return &entity.Address{
+		ID:         int(row.ID),
+		Jalan:      row.Jalan,
+		RT:         row.Rt,
+		RW:         row.Rw,
+		Kota:       row.Kota,
+		PostalCode: row.PostalCode,
+		DeletedAt:  row.DeletedAt,
+		Version:    int(row.Version),
+	}
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"sistem-06-Backend/internal/domain/entity"
+	domain "sistem-06-Backend/internal/domain/ports"
+	"sistem-06-Backend/internal/infrastructure/database/sqlc"
+
+	"github.com/sirupsen/logrus"
+)
+
+// auditChainLock is the pg_advisory_xact_lock key serializing appends, so
+// two transactions never link to the same previous entry.
+const auditChainLock int64 = 0x61756469745f6c67
+
+type AuditRepositoryImpl struct {
+	q   *sqlc.Queries
+	log *logrus.Logger
+}
+
+func NewAuditRepository(q *sqlc.Queries, log *logrus.Logger) *AuditRepositoryImpl {
+	return &AuditRepositoryImpl{
+		q:   q,
+		log: log,
+	}
+}
+
+func (r *AuditRepositoryImpl) WithTx(tx *sql.Tx) domain.AuditRepository {
+	return &AuditRepositoryImpl{
+		q:   r.q.WithTx(tx),
+		log: r.log,
+	}
+}
+
+func (r *AuditRepositoryImpl) Append(ctx context.Context, entry *entity.AuditEntry) error {
+	if err := r.q.LockAuditChain(ctx, auditChainLock); err != nil {
+		return err
+	}
+
+	prevHash, err := r.q.LastAuditLogHash(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		prevHash = entity.AuditGenesisHash
+	} else if err != nil {
+		return err
+	}
+
+	entry.PrevHash = prevHash
+	entry.Hash = entry.ComputeHash()
+	id, err := r.q.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ActorID:    sql.NullInt32{Int32: int32(entry.ActorID), Valid: entry.ActorID != 0},
+		Action:     entry.Action,
+		EntityType: entry.EntityType,
+		EntityID:   entry.EntityID,
+		Diff:       entry.Diff,
+		Ip:         entry.IP,
+		RequestID:  entry.RequestID,
+		CreatedAt:  entry.CreatedAt,
+		PrevHash:   entry.PrevHash,
+		Hash:       entry.Hash,
+	})
+	if err != nil {
+		return err
+	}
+	entry.ID = id
+	return nil
+}
+
+func (r *AuditRepositoryImpl) Search(ctx context.Context, filter entity.AuditFilter) ([]*entity.AuditEntry, error) {
+	rows, err := r.q.ListAuditLogs(ctx, sqlc.ListAuditLogsParams{
+		ActorID:     sql.NullInt32{Int32: int32(filter.ActorID), Valid: filter.ActorID != 0},
+		Action:      nullString(filter.Action),
+		EntityType:  nullString(filter.EntityType),
+		EntityID:    nullString(filter.EntityID),
+		CreatedFrom: sql.NullInt64{Int64: filter.From, Valid: filter.From != 0},
+		CreatedTo:   sql.NullInt64{Int64: filter.To, Valid: filter.To != 0},
+		LimitCount:  int32(filter.Limit),
+		OffsetCount: int32(filter.Offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toAuditEntities(rows), nil
+}
+
+func (r *AuditRepositoryImpl) Count(ctx context.Context, filter entity.AuditFilter) (int64, error) {
+	return r.q.CountAuditLogs(ctx, sqlc.CountAuditLogsParams{
+		ActorID:     sql.NullInt32{Int32: int32(filter.ActorID), Valid: filter.ActorID != 0},
+		Action:      nullString(filter.Action),
+		EntityType:  nullString(filter.EntityType),
+		EntityID:    nullString(filter.EntityID),
+		CreatedFrom: sql.NullInt64{Int64: filter.From, Valid: filter.From != 0},
+		CreatedTo:   sql.NullInt64{Int64: filter.To, Valid: filter.To != 0},
+	})
+}
+
+func (r *AuditRepositoryImpl) ListAfter(ctx context.Context, afterID int64, limit int) ([]*entity.AuditEntry, error) {
+	rows, err := r.q.ListAuditLogsAfter(ctx, sqlc.ListAuditLogsAfterParams{ID: afterID, Limit: int32(limit)})
+	if err != nil {
+		return nil, err
+	}
+	return toAuditEntities(rows), nil
+}
+
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+func toAuditEntities(rows []*sqlc.AuditLog) []*entity.AuditEntry {
+	entries := make([]*entity.AuditEntry, len(rows))
+	for i, row := range rows {
+		entries[i] = &entity.AuditEntry{
+			ID:         row.ID,
+			ActorID:    int(row.ActorID.Int32),
+			Action:     row.Action,
+			EntityType: row.EntityType,
+			EntityID:   row.EntityID,
+			Diff:       row.Diff,
+			IP:         row.Ip,
+			RequestID:  row.RequestID,
+			CreatedAt:  row.CreatedAt,
+			PrevHash:   row.PrevHash,
+			Hash:       row.Hash,
+		}
+	}
+	return entries
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"sistem-06-Backend/internal/domain/entity"
+	domain "sistem-06-Backend/internal/domain/ports"
+	"sistem-06-Backend/internal/infrastructure/database/sqlc"
+
+	"github.com/sirupsen/logrus"
+)
+
+type DeletionRequestRepositoryImpl struct {
+	q   *sqlc.Queries
+	log *logrus.Logger
+}
+
+func NewDeletionRequestRepository(q *sqlc.Queries, log *logrus.Logger) *DeletionRequestRepositoryImpl {
+	return &DeletionRequestRepositoryImpl{
+		q:   q,
+		log: log,
+	}
+}
+
+func (r *DeletionRequestRepositoryImpl) WithTx(tx *sql.Tx) domain.DeletionRequestRepository {
+	return &DeletionRequestRepositoryImpl{
+		q:   r.q.WithTx(tx),
+		log: r.log,
+	}
+}
+
+func (r *DeletionRequestRepositoryImpl) Create(ctx context.Context, request *entity.DeletionRequest) error {
+	return r.q.CreateDeletionRequest(ctx, sqlc.CreateDeletionRequestParams{
+		UserID:      int32(request.UserID),
+		RequestedAt: request.RequestedAt,
+		ScheduledAt: request.ScheduledAt,
+	})
+}
+
+func (r *DeletionRequestRepositoryImpl) FindByUserID(ctx context.Context, userID int) (*entity.DeletionRequest, error) {
+	row, err := r.q.FindDeletionRequest(ctx, int32(userID))
+	if err != nil {
+		return nil, err
+	}
+	return toDeletionRequestEntity(row), nil
+}
+
+func (r *DeletionRequestRepositoryImpl) Cancel(ctx context.Context, userID int) (bool, error) {
+	affected, err := r.q.CancelDeletionRequest(ctx, int32(userID))
+	return affected > 0, err
+}
+
+func (r *DeletionRequestRepositoryImpl) ListDue(ctx context.Context, now int64, limit int) ([]*entity.DeletionRequest, error) {
+	rows, err := r.q.ListDueDeletionRequests(ctx, sqlc.ListDueDeletionRequestsParams{
+		ScheduledAt: now,
+		Limit:       int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	requests := make([]*entity.DeletionRequest, len(rows))
+	for i, row := range rows {
+		requests[i] = toDeletionRequestEntity(row)
+	}
+	return requests, nil
+}
+
+func (r *DeletionRequestRepositoryImpl) Complete(ctx context.Context, userID int, completedAt int64) (bool, error) {
+	affected, err := r.q.CompleteDeletionRequest(ctx, sqlc.CompleteDeletionRequestParams{
+		UserID:      int32(userID),
+		CompletedAt: completedAt,
+	})
+	return affected > 0, err
+}
+
+func toDeletionRequestEntity(row *sqlc.DeletionRequest) *entity.DeletionRequest {
+	return &entity.DeletionRequest{
+		UserID:      int(row.UserID),
+		RequestedAt: row.RequestedAt,
+		ScheduledAt: row.ScheduledAt,
+		CompletedAt: row.CompletedAt,
+	}
+}
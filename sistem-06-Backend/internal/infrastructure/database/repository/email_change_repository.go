@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"sistem-06-Backend/internal/domain/entity"
+	domain "sistem-06-Backend/internal/domain/ports"
+	"sistem-06-Backend/internal/infrastructure/database/sqlc"
+	"sistem-06-Backend/internal/pkg/fieldcrypt"
+
+	"github.com/sirupsen/logrus"
+)
+
+const emailChangeNewEmailColumn = "email_changes.new_email"
+
+// EmailChangeRepositoryImpl encrypts the new email of a pending change like
+// UserRepositoryImpl does the email of a user.
+type EmailChangeRepositoryImpl struct {
+	q      *sqlc.Queries
+	log    *logrus.Logger
+	cipher *fieldcrypt.Cipher
+}
+
+// NewEmailChangeRepository stores new emails in the clear when cipher is nil.
+func NewEmailChangeRepository(q *sqlc.Queries, log *logrus.Logger, cipher *fieldcrypt.Cipher) *EmailChangeRepositoryImpl {
+	return &EmailChangeRepositoryImpl{
+		q:      q,
+		log:    log,
+		cipher: cipher,
+	}
+}
+
+func (r *EmailChangeRepositoryImpl) WithTx(tx *sql.Tx) domain.EmailChangeRepository {
+	return &EmailChangeRepositoryImpl{
+		q:      r.q.WithTx(tx),
+		log:    r.log,
+		cipher: r.cipher,
+	}
+}
+
+// Save replaces any earlier pending change of the user.
+func (r *EmailChangeRepositoryImpl) Save(ctx context.Context, change *entity.EmailChange) error {
+	newEmail, err := r.cipher.Encrypt(emailChangeNewEmailColumn, change.NewEmail)
+	if err != nil {
+		return err
+	}
+	return r.q.UpsertEmailChange(ctx, sqlc.UpsertEmailChangeParams{
+		UserID:    int32(change.UserID),
+		NewEmail:  newEmail,
+		TokenHash: change.TokenHash,
+		ExpiresAt: change.ExpiresAt,
+		CreatedAt: change.CreatedAt,
+	})
+}
+
+func (r *EmailChangeRepositoryImpl) FindByUserID(ctx context.Context, userID int) (*entity.EmailChange, error) {
+	row, err := r.q.FindEmailChangeByUserID(ctx, int32(userID))
+	if err != nil {
+		return nil, err
+	}
+	return r.toEntity(row)
+}
+
+func (r *EmailChangeRepositoryImpl) FindByTokenHash(ctx context.Context, hash string) (*entity.EmailChange, error) {
+	row, err := r.q.FindEmailChangeByTokenHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	return r.toEntity(row)
+}
+
+func (r *EmailChangeRepositoryImpl) Delete(ctx context.Context, userID int) error {
+	return r.q.DeleteEmailChange(ctx, int32(userID))
+}
+
+// RotateNewEmails does for pending email changes what
+// UserRepositoryImpl.RotateEmails does for users.
+func (r *EmailChangeRepositoryImpl) RotateNewEmails(ctx context.Context, batchSize int) (int, error) {
+	rotated := 0
+	var after int32
+	for {
+		rows, err := r.q.ListEmailChanges(ctx, sqlc.ListEmailChangesParams{UserID: after, Limit: int32(batchSize)})
+		if err != nil {
+			return rotated, err
+		}
+		for _, row := range rows {
+			after = row.UserID
+			newEmail, changed, err := r.cipher.Rotate(emailChangeNewEmailColumn, row.NewEmail)
+			if err != nil {
+				return rotated, fmt.Errorf("rotate email change of user %d: %w", row.UserID, err)
+			}
+			if !changed {
+				continue
+			}
+			affected, err := r.q.ReencryptEmailChange(ctx, sqlc.ReencryptEmailChangeParams{
+				NewEmail: newEmail,
+				UserID:   row.UserID,
+				OldEmail: row.NewEmail,
+			})
+			if err != nil {
+				return rotated, err
+			}
+			rotated += int(affected)
+		}
+		if len(rows) < batchSize {
+			return rotated, nil
+		}
+	}
+}
+
+func (r *EmailChangeRepositoryImpl) toEntity(row *sqlc.EmailChange) (*entity.EmailChange, error) {
+	newEmail, err := r.cipher.Decrypt(emailChangeNewEmailColumn, row.NewEmail)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt email change of user %d: %w", row.UserID, err)
+	}
+	return &entity.EmailChange{
+		UserID:    int(row.UserID),
+		NewEmail:  newEmail,
+		TokenHash: row.TokenHash,
+		ExpiresAt: row.ExpiresAt,
+		CreatedAt: row.CreatedAt,
+	}, nil
+}
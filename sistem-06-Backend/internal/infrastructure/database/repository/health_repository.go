@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/sirupsen/logrus"
+)
+
+type HealthRepositoryImpl struct {
+	db  *sql.DB
+	log *logrus.Logger
+}
+
+func NewHealthRepository(db *sql.DB, log *logrus.Logger) *HealthRepositoryImpl {
+	return &HealthRepositoryImpl{
+		db:  db,
+		log: log,
+	}
+}
+
+func (r *HealthRepositoryImpl) Ping(ctx context.Context) error {
+	return r.db.PingContext(ctx)
+}
+
+// MigrationVersion reads the schema_migrations table maintained by
+// golang-migrate. It is not part of the sqlc schema, hence the raw query.
+func (r *HealthRepositoryImpl) MigrationVersion(ctx context.Context) (int64, bool, error) {
+	var version int64
+	var dirty bool
+	err := r.db.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	return version, dirty, err
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+
+	"sistem-06-Backend/internal/domain/entity"
+	"sistem-06-Backend/internal/infrastructure/database/sqlc"
+
+	"github.com/sirupsen/logrus"
+)
+
+type ImportJobRepositoryImpl struct {
+	q   *sqlc.Queries
+	log *logrus.Logger
+}
+
+func NewImportJobRepository(q *sqlc.Queries, log *logrus.Logger) *ImportJobRepositoryImpl {
+	return &ImportJobRepositoryImpl{
+		q:   q,
+		log: log,
+	}
+}
+
+func (r *ImportJobRepositoryImpl) Create(ctx context.Context, job *entity.ImportJob) error {
+	return r.q.CreateImportJob(ctx, sqlc.CreateImportJobParams{
+		ID:        job.ID,
+		Kind:      job.Kind,
+		Status:    job.Status,
+		DryRun:    job.DryRun,
+		TotalRows: int32(job.TotalRows),
+		CreatedBy: int32(job.CreatedBy),
+		CreatedAt: job.CreatedAt,
+	})
+}
+
+func (r *ImportJobRepositoryImpl) FindByID(ctx context.Context, id string) (*entity.ImportJob, error) {
+	row, err := r.q.FindImportJobByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	job := &entity.ImportJob{
+		ID:            row.ID,
+		Kind:          row.Kind,
+		Status:        row.Status,
+		DryRun:        row.DryRun,
+		TotalRows:     int(row.TotalRows),
+		ProcessedRows: int(row.ProcessedRows),
+		ImportedRows:  int(row.ImportedRows),
+		CreatedBy:     int(row.CreatedBy),
+		CreatedAt:     row.CreatedAt,
+		FinishedAt:    row.FinishedAt,
+	}
+	if err := json.Unmarshal([]byte(row.Errors), &job.Errors); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (r *ImportJobRepositoryImpl) UpdateProgress(ctx context.Context, job *entity.ImportJob) error {
+	return r.q.UpdateImportJobProgress(ctx, sqlc.UpdateImportJobProgressParams{
+		ID:            job.ID,
+		Status:        job.Status,
+		ProcessedRows: int32(job.ProcessedRows),
+	})
+}
+
+// Finish stores the outcome of the job, row errors included.
+func (r *ImportJobRepositoryImpl) Finish(ctx context.Context, job *entity.ImportJob) error {
+	errors := job.Errors
+	if errors == nil {
+		errors = []entity.ImportRowError{}
+	}
+	encoded, err := json.Marshal(errors)
+	if err != nil {
+		return err
+	}
+	return r.q.FinishImportJob(ctx, sqlc.FinishImportJobParams{
+		ID:            job.ID,
+		Status:        job.Status,
+		ProcessedRows: int32(job.ProcessedRows),
+		ImportedRows:  int32(job.ImportedRows),
+		Errors:        string(encoded),
+		FinishedAt:    job.FinishedAt,
+	})
+}
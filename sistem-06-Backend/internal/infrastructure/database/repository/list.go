@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"iter"
+
+	"sistem-06-Backend/internal/infrastructure/database/sqlc"
+	"sistem-06-Backend/internal/pkg/listquery"
+	"sistem-06-Backend/pkg"
+)
+
+// list runs request against schema. A *listquery.Error is returned as it is
+// so the caller can answer it with 400.
+func list[T any](ctx context.Context, q *sqlc.Queries, schema *listquery.Schema[T], request *listquery.Request, scan func(rows *sql.Rows) (T, error)) ([]T, pkg.PageMetadata, error) {
+	plan, err := schema.Compile(request)
+	if err != nil {
+		return nil, pkg.PageMetadata{}, err
+	}
+
+	rows, err := q.Query(ctx, plan.List.SQL, plan.List.Args...)
+	if err != nil {
+		return nil, pkg.PageMetadata{}, err
+	}
+	defer rows.Close()
+	var items []T
+	for rows.Next() {
+		item, err := scan(rows)
+		if err != nil {
+			return nil, pkg.PageMetadata{}, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, pkg.PageMetadata{}, err
+	}
+
+	var total int64
+	if plan.Count != nil {
+		if err := q.QueryRow(ctx, plan.Count.SQL, plan.Count.Args...).Scan(&total); err != nil {
+			return nil, pkg.PageMetadata{}, err
+		}
+	}
+	items, metadata := schema.Page(plan, items, total)
+	return items, metadata, nil
+}
+
+// export compiles request against schema right away, so a bad filter fails
+// before anything is sent, and runs the query over every matching row once
+// the returned sequence is ranged over. Rows are scanned one at a time as
+// the database sends them. The sequence ends after the first error.
+func export[T any](ctx context.Context, q *sqlc.Queries, schema *listquery.Schema[T], request *listquery.Request, scan func(rows *sql.Rows) (T, error)) (iter.Seq2[T, error], error) {
+	query, err := schema.CompileAll(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(yield func(T, error) bool) {
+		var zero T
+		rows, err := q.Query(ctx, query.SQL, query.Args...)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		defer rows.Close()
+		for rows.Next() {
+			item, err := scan(rows)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			if !yield(item, nil) {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			yield(zero, err)
+		}
+	}, nil
+}
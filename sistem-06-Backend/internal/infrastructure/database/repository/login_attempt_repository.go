@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"sistem-06-Backend/internal/domain/entity"
+	domain "sistem-06-Backend/internal/domain/ports"
+	"sistem-06-Backend/internal/infrastructure/database/sqlc"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LoginAttemptRepositoryImpl keeps the failure counters in postgres so every
+// prefork worker sees the same counts.
+type LoginAttemptRepositoryImpl struct {
+	q   *sqlc.Queries
+	log *logrus.Logger
+}
+
+func NewLoginAttemptRepository(q *sqlc.Queries, log *logrus.Logger) *LoginAttemptRepositoryImpl {
+	return &LoginAttemptRepositoryImpl{
+		q:   q,
+		log: log,
+	}
+}
+
+func (r *LoginAttemptRepositoryImpl) WithTx(tx *sql.Tx) domain.LoginAttemptRepository {
+	return &LoginAttemptRepositoryImpl{
+		q:   r.q.WithTx(tx),
+		log: r.log,
+	}
+}
+
+func (r *LoginAttemptRepositoryImpl) Find(ctx context.Context, scope entity.LoginScope, identifier string) (*entity.LoginAttempt, error) {
+	row, err := r.q.FindLoginAttempt(ctx, sqlc.FindLoginAttemptParams{
+		Scope:      string(scope),
+		Identifier: identifier,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toLoginAttemptEntity(row), nil
+}
+
+// RecordFailure increments the counter atomically. A counter whose last
+// failure is older than windowStart restarts at one.
+func (r *LoginAttemptRepositoryImpl) RecordFailure(ctx context.Context, scope entity.LoginScope, identifier string, now int64, windowStart int64) (*entity.LoginAttempt, error) {
+	row, err := r.q.RecordLoginFailure(ctx, sqlc.RecordLoginFailureParams{
+		Scope:        string(scope),
+		Identifier:   identifier,
+		LastFailedAt: now,
+		WindowStart:  windowStart,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toLoginAttemptEntity(row), nil
+}
+
+// Lock never shortens an existing lock.
+func (r *LoginAttemptRepositoryImpl) Lock(ctx context.Context, scope entity.LoginScope, identifier string, until int64) error {
+	return r.q.LockLoginAttempt(ctx, sqlc.LockLoginAttemptParams{
+		Scope:       string(scope),
+		Identifier:  identifier,
+		LockedUntil: until,
+	})
+}
+
+func (r *LoginAttemptRepositoryImpl) Reset(ctx context.Context, scope entity.LoginScope, identifier string) error {
+	return r.q.DeleteLoginAttempt(ctx, sqlc.DeleteLoginAttemptParams{
+		Scope:      string(scope),
+		Identifier: identifier,
+	})
+}
+
+func (r *LoginAttemptRepositoryImpl) DeleteExpired(ctx context.Context, lastFailedBefore int64, now int64) (int64, error) {
+	return r.q.DeleteExpiredLoginAttempts(ctx, sqlc.DeleteExpiredLoginAttemptsParams{
+		LastFailedAt: lastFailedBefore,
+		LockedUntil:  now,
+	})
+}
+
+func toLoginAttemptEntity(row *sqlc.LoginAttempt) *entity.LoginAttempt {
+	return &entity.LoginAttempt{
+		Scope:        entity.LoginScope(row.Scope),
+		Identifier:   row.Identifier,
+		Failures:     int(row.Failures),
+		LastFailedAt: row.LastFailedAt,
+		LockedUntil:  row.LockedUntil,
+	}
+}
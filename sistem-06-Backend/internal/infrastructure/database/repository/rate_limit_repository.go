@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+
+	"sistem-06-Backend/internal/domain/entity"
+	"sistem-06-Backend/internal/infrastructure/database/sqlc"
+
+	"github.com/sirupsen/logrus"
+)
+
+type RateLimitRepositoryImpl struct {
+	q   *sqlc.Queries
+	log *logrus.Logger
+}
+
+func NewRateLimitRepository(q *sqlc.Queries, log *logrus.Logger) *RateLimitRepositoryImpl {
+	return &RateLimitRepositoryImpl{
+		q:   q,
+		log: log,
+	}
+}
+
+// Hit counts one request in bucket. A window that ended before now starts
+// over with a new reset time.
+func (r *RateLimitRepositoryImpl) Hit(ctx context.Context, bucket string, now int64, resetAt int64) (*entity.RateLimitCounter, error) {
+	row, err := r.q.HitRateLimit(ctx, sqlc.HitRateLimitParams{
+		Bucket:  bucket,
+		ResetAt: resetAt,
+		Now:     now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &entity.RateLimitCounter{
+		Bucket:  row.Bucket,
+		Hits:    int(row.Hits),
+		ResetAt: row.ResetAt,
+	}, nil
+}
+
+func (r *RateLimitRepositoryImpl) DeleteExpired(ctx context.Context, now int64) (int64, error) {
+	return r.q.DeleteExpiredRateLimits(ctx, now)
+}
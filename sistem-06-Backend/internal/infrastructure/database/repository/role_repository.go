@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"sistem-06-Backend/internal/domain/entity"
+	domain "sistem-06-Backend/internal/domain/ports"
+	"sistem-06-Backend/internal/infrastructure/database/sqlc"
+
+	"github.com/sirupsen/logrus"
+)
+
+type RoleRepositoryImpl struct {
+	q   *sqlc.Queries
+	log *logrus.Logger
+}
+
+func NewRoleRepository(q *sqlc.Queries, log *logrus.Logger) *RoleRepositoryImpl {
+	return &RoleRepositoryImpl{
+		q:   q,
+		log: log,
+	}
+}
+
+func (r *RoleRepositoryImpl) WithTx(tx *sql.Tx) domain.RolesRepository {
+	return &RoleRepositoryImpl{
+		q:   r.q.WithTx(tx),
+		log: r.log,
+	}
+}
+
+func (r *RoleRepositoryImpl) FindByID(ctx context.Context, id int) (*entity.Role, error) {
+	row, err := r.q.FindRoleByID(ctx, int32(id))
+	if err != nil {
+		return nil, err
+	}
+	return toRoleEntity(row), nil
+}
+
+func (r *RoleRepositoryImpl) FindByName(ctx context.Context, name string) (*entity.Role, error) {
+	row, err := r.q.FindRoleByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return toRoleEntity(row), nil
+}
+
+func (r *RoleRepositoryImpl) GetRolesByUserID(ctx context.Context, id int) ([]entity.Role, error) {
+	rows, err := r.q.GetRolesByUserID(ctx, int64(id))
+	if err != nil {
+		return nil, err
+	}
+	roles := make([]entity.Role, len(rows))
+	for i, row := range rows {
+		roles[i] = *toRoleEntity(row)
+	}
+	return roles, nil
+}
+
+func (r *RoleRepositoryImpl) GetRolesWithPermissionsByUserID(ctx context.Context, id int) ([]entity.Role, error) {
+	rows, err := r.q.GetRolesWithPermissionsByUserID(ctx, int64(id))
+	if err != nil {
+		return nil, err
+	}
+
+	// rows are ordered by role id, so permissions of the same role are adjacent
+	var roles []entity.Role
+	for _, row := range rows {
+		if len(roles) == 0 || roles[len(roles)-1].ID != int(row.RoleID) {
+			roles = append(roles, entity.Role{ID: int(row.RoleID), Name: row.RoleName, RequireTwoFactor: row.RequireTwoFactor})
+		}
+		if row.PermissionName.Valid {
+			role := &roles[len(roles)-1]
+			role.Permission = append(role.Permission, entity.Permissions(row.PermissionName.String))
+		}
+	}
+	return roles, nil
+}
+
+func (r *RoleRepositoryImpl) AssignRoleToUser(ctx context.Context, userId int, rolesId int) error {
+	return r.q.AssignRoleToUser(ctx, sqlc.AssignRoleToUserParams{
+		UserID:  int64(userId),
+		RolesID: int64(rolesId),
+	})
+}
+
+func (r *RoleRepositoryImpl) RemoveRoleFromUser(ctx context.Context, userId int, rolesId int) error {
+	return r.q.RemoveRoleFromUser(ctx, sqlc.RemoveRoleFromUserParams{
+		UserID:  int64(userId),
+		RolesID: int64(rolesId),
+	})
+}
+
+func (r *RoleRepositoryImpl) RemoveAllRolesFromUser(ctx context.Context, userId int) error {
+	return r.q.RemoveAllRolesFromUser(ctx, int64(userId))
+}
+
+func (r *RoleRepositoryImpl) SoftDelete(ctx context.Context, id int, version int, deletedAt int64) error {
+	affected, err := r.q.SoftDeleteRole(ctx, sqlc.SoftDeleteRoleParams{
+		ID:        int32(id),
+		DeletedAt: deletedAt,
+		Version:   int32(version),
+	})
+	return checkVersion(affected, err, func() (bool, error) {
+		_, err := r.q.FindRoleByID(ctx, int32(id))
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return err == nil, err
+	})
+}
+
+func (r *RoleRepositoryImpl) Restore(ctx context.Context, id int) (bool, error) {
+	affected, err := r.q.RestoreRole(ctx, int32(id))
+	return affected > 0, err
+}
+
+func (r *RoleRepositoryImpl) PurgeDeleted(ctx context.Context, deletedBefore int64) (int64, error) {
+	return r.q.PurgeDeletedRoles(ctx, deletedBefore)
+}
+
+func toRoleEntity(row *sqlc.Role) *entity.Role {
+	return &entity.Role{
+		ID:               int(row.ID),
+		Name:             row.Name,
+		RequireTwoFactor: row.RequireTwoFactor,
+		DeletedAt:        row.DeletedAt,
+		Version:          int(row.Version),
+	}
+}
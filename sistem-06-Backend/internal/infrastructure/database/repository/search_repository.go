@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"sistem-06-Backend/internal/domain/entity"
+	"sistem-06-Backend/internal/infrastructure/database/sqlc"
+	"sistem-06-Backend/internal/pkg/fieldcrypt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxSearchTerms bounds the size of the tsquery built from one input.
+const maxSearchTerms = 10
+
+var searchWord = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+type SearchRepositoryImpl struct {
+	q      *sqlc.Queries
+	log    *logrus.Logger
+	cipher *fieldcrypt.Cipher
+}
+
+func NewSearchRepository(q *sqlc.Queries, log *logrus.Logger, cipher *fieldcrypt.Cipher) *SearchRepositoryImpl {
+	return &SearchRepositoryImpl{
+		q:      q,
+		log:    log,
+		cipher: cipher,
+	}
+}
+
+func (r *SearchRepositoryImpl) Users(ctx context.Context, query entity.SearchQuery) ([]entity.UserMatch, error) {
+	rows, err := r.q.SearchUsers(ctx, sqlc.SearchUsersParams{
+		Terms:      SearchTerms(query.Text),
+		Query:      query.Text,
+		EmailHash:  r.cipher.BlindIndex(userEmailColumn, strings.TrimSpace(query.Text)),
+		UserID:     sql.NullInt32{Int32: int32(query.UserID), Valid: query.UserID != 0},
+		LimitCount: int32(query.Limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	matches := make([]entity.UserMatch, len(rows))
+	for i, row := range rows {
+		email, err := r.cipher.Decrypt(userEmailColumn, row.Email)
+		if err != nil {
+			return nil, fmt.Errorf("decrypt email of user %d: %w", row.ID, err)
+		}
+		matches[i] = entity.UserMatch{
+			User: &entity.User{
+				ID:        int(row.ID),
+				Name:      row.Name,
+				Email:     email,
+				CreatedAt: row.CreatedAt,
+				UpdatedAt: row.UpdatedAt,
+				Version:   int(row.Version),
+			},
+			Rank: row.Rank,
+		}
+	}
+	return matches, nil
+}
+
+func (r *SearchRepositoryImpl) Addresses(ctx context.Context, query entity.SearchQuery) ([]entity.AddressMatch, error) {
+	rows, err := r.q.SearchAddresses(ctx, sqlc.SearchAddressesParams{
+		Terms:      SearchTerms(query.Text),
+		Query:      query.Text,
+		LimitCount: int32(query.Limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	matches := make([]entity.AddressMatch, len(rows))
+	for i, row := range rows {
+		matches[i] = entity.AddressMatch{
+			Address: &entity.Address{
+				ID:         int(row.ID),
+				Jalan:      row.Jalan,
+				RT:         row.Rt,
+				RW:         row.Rw,
+				Kota:       row.Kota,
+				PostalCode: row.PostalCode,
+				Version:    int(row.Version),
+			},
+			Rank: row.Rank,
+		}
+	}
+	return matches, nil
+}
+
+// SearchTerms turns free text into a to_tsquery expression matching any of
+// its words as a prefix: "Budi, Gg. Melati" becomes "budi:* | gg:* | melati:*".
+// Only letters and digits survive, so the input cannot inject tsquery
+// operators.
+func SearchTerms(text string) string {
+	words := searchWord.FindAllString(strings.ToLower(text), maxSearchTerms)
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+	return strings.Join(words, " | ")
+}
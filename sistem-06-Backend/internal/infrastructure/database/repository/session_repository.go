@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/gob"
+
+	"sistem-06-Backend/internal/domain/entity"
+	domain "sistem-06-Backend/internal/domain/ports"
+	"sistem-06-Backend/internal/infrastructure/database/sqlc"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SessionRepositoryImpl reads the table of the fiber postgres storage: k is
+// the session id, v the gob encoded session data and e the expiry in unix
+// seconds, 0 for none. The data holds the keys set by pkg.SessionHandler.
+type SessionRepositoryImpl struct {
+	q     *sqlc.Queries
+	log   *logrus.Logger
+	table string
+}
+
+func NewSessionRepository(q *sqlc.Queries, log *logrus.Logger, table string) *SessionRepositoryImpl {
+	return &SessionRepositoryImpl{
+		q:     q,
+		log:   log,
+		table: table,
+	}
+}
+
+func (r *SessionRepositoryImpl) WithTx(tx *sql.Tx) domain.SessionRepository {
+	return &SessionRepositoryImpl{
+		q:     r.q.WithTx(tx),
+		log:   r.log,
+		table: r.table,
+	}
+}
+
+// ListByUserID decodes every live session, as the storage has no index by
+// user.
+func (r *SessionRepositoryImpl) ListByUserID(ctx context.Context, userID int, now int64) ([]*entity.Session, error) {
+	rows, err := r.q.Query(ctx, "SELECT k, v, e FROM "+r.table+" WHERE e = 0 OR e > $1", now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []*entity.Session{}
+	for rows.Next() {
+		var id string
+		var value []byte
+		var expiresAt int64
+		if err := rows.Scan(&id, &value, &expiresAt); err != nil {
+			return nil, err
+		}
+
+		var data map[string]any
+		if err := gob.NewDecoder(bytes.NewReader(value)).Decode(&data); err != nil {
+			r.log.WithContext(ctx).Debugf("Skipping undecodable session: %v", err)
+			continue
+		}
+		if owner, _ := data["user_id"].(int); owner != userID {
+			continue
+		}
+		createdAt, _ := data["created_at"].(int64)
+		sessions = append(sessions, &entity.Session{
+			ID:        id,
+			UserID:    userID,
+			CreatedAt: createdAt,
+			ExpiresAt: expiresAt,
+		})
+	}
+	return sessions, rows.Err()
+}
+
+// DeleteByUserID removes the sessions of the user, expired ones included.
+func (r *SessionRepositoryImpl) DeleteByUserID(ctx context.Context, userID int) (int64, error) {
+	sessions, err := r.ListByUserID(ctx, userID, 0)
+	if err != nil {
+		return 0, err
+	}
+	var deleted int64
+	for _, session := range sessions {
+		result, err := r.q.Exec(ctx, "DELETE FROM "+r.table+" WHERE k = $1", session.ID)
+		if err != nil {
+			return deleted, err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return deleted, err
+		}
+		deleted += affected
+	}
+	return deleted, nil
+}
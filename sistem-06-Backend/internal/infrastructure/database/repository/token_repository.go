@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"sistem-06-Backend/internal/domain/entity"
+	domain "sistem-06-Backend/internal/domain/ports"
+	"sistem-06-Backend/internal/infrastructure/database/sqlc"
+
+	"github.com/sirupsen/logrus"
+)
+
+type TokenRepositoryImpl struct {
+	q   *sqlc.Queries
+	log *logrus.Logger
+}
+
+func NewTokenRepository(q *sqlc.Queries, log *logrus.Logger) *TokenRepositoryImpl {
+	return &TokenRepositoryImpl{
+		q:   q,
+		log: log,
+	}
+}
+
+func (r *TokenRepositoryImpl) WithTx(tx *sql.Tx) domain.TokenRepository {
+	return &TokenRepositoryImpl{
+		q:   r.q.WithTx(tx),
+		log: r.log,
+	}
+}
+
+func (r *TokenRepositoryImpl) CreateToken(ctx context.Context, token *entity.PersonalAccessToken) error {
+	id, err := r.q.CreatePersonalAccessToken(ctx, sqlc.CreatePersonalAccessTokenParams{
+		UserID:    int32(token.UserID),
+		Name:      token.Name,
+		TokenHash: token.TokenHash,
+		Scopes:    joinScopes(token.Scopes),
+		ExpiresAt: token.ExpiresAt,
+		CreatedAt: token.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+	token.ID = int(id)
+	return nil
+}
+
+func (r *TokenRepositoryImpl) FindTokenByHash(ctx context.Context, hash string) (*entity.PersonalAccessToken, error) {
+	row, err := r.q.FindPersonalAccessTokenByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	return tokenFromRow(row), nil
+}
+
+// ListTokensByUserID returns the tokens that are not revoked, expired ones
+// included.
+func (r *TokenRepositoryImpl) ListTokensByUserID(ctx context.Context, userID int) ([]*entity.PersonalAccessToken, error) {
+	rows, err := r.q.ListPersonalAccessTokensByUserID(ctx, int32(userID))
+	if err != nil {
+		return nil, err
+	}
+	tokens := make([]*entity.PersonalAccessToken, len(rows))
+	for i, row := range rows {
+		tokens[i] = tokenFromRow(row)
+	}
+	return tokens, nil
+}
+
+func (r *TokenRepositoryImpl) TouchToken(ctx context.Context, id int, usedAt int64) error {
+	return r.q.TouchPersonalAccessToken(ctx, sqlc.TouchPersonalAccessTokenParams{
+		ID:         int32(id),
+		LastUsedAt: usedAt,
+	})
+}
+
+// RevokeToken reports false when the token does not exist, belongs to
+// another user or is already revoked.
+func (r *TokenRepositoryImpl) RevokeToken(ctx context.Context, id int, userID int, revokedAt int64) (bool, error) {
+	rows, err := r.q.RevokePersonalAccessToken(ctx, sqlc.RevokePersonalAccessTokenParams{
+		ID:        int32(id),
+		UserID:    int32(userID),
+		RevokedAt: revokedAt,
+	})
+	return rows == 1, err
+}
+
+// DeleteTokensByUserID removes every token of the user, revoked ones
+// included.
+func (r *TokenRepositoryImpl) DeleteTokensByUserID(ctx context.Context, userID int) error {
+	return r.q.DeletePersonalAccessTokensByUserID(ctx, int32(userID))
+}
+
+func tokenFromRow(row *sqlc.PersonalAccessToken) *entity.PersonalAccessToken {
+	return &entity.PersonalAccessToken{
+		ID:         int(row.ID),
+		UserID:     int(row.UserID),
+		Name:       row.Name,
+		TokenHash:  row.TokenHash,
+		Scopes:     splitScopes(row.Scopes),
+		ExpiresAt:  row.ExpiresAt,
+		LastUsedAt: row.LastUsedAt,
+		RevokedAt:  row.RevokedAt,
+		CreatedAt:  row.CreatedAt,
+	}
+}
+
+// Scopes are stored space separated, like an OAuth scope string.
+func joinScopes(scopes []entity.Permissions) string {
+	parts := make([]string, len(scopes))
+	for i, scope := range scopes {
+		parts[i] = string(scope)
+	}
+	return strings.Join(parts, " ")
+}
+
+func splitScopes(scopes string) []entity.Permissions {
+	fields := strings.Fields(scopes)
+	permissions := make([]entity.Permissions, len(fields))
+	for i, field := range fields {
+		permissions[i] = entity.Permissions(field)
+	}
+	return permissions
+}
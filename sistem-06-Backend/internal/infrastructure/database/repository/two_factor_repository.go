@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"sistem-06-Backend/internal/domain/entity"
+	domain "sistem-06-Backend/internal/domain/ports"
+	"sistem-06-Backend/internal/infrastructure/database/sqlc"
+
+	"github.com/sirupsen/logrus"
+)
+
+type TwoFactorRepositoryImpl struct {
+	q   *sqlc.Queries
+	log *logrus.Logger
+}
+
+func NewTwoFactorRepository(q *sqlc.Queries, log *logrus.Logger) *TwoFactorRepositoryImpl {
+	return &TwoFactorRepositoryImpl{
+		q:   q,
+		log: log,
+	}
+}
+
+func (r *TwoFactorRepositoryImpl) WithTx(tx *sql.Tx) domain.TwoFactorRepository {
+	return &TwoFactorRepositoryImpl{
+		q:   r.q.WithTx(tx),
+		log: r.log,
+	}
+}
+
+func (r *TwoFactorRepositoryImpl) Find(ctx context.Context, userID int) (*entity.TwoFactor, error) {
+	row, err := r.q.FindUserTwoFactor(ctx, int32(userID))
+	if err != nil {
+		return nil, err
+	}
+	return &entity.TwoFactor{
+		UserID:       int(row.UserID),
+		Secret:       row.Secret,
+		Enabled:      row.Enabled,
+		LastUsedStep: row.LastUsedStep,
+		CreatedAt:    row.CreatedAt,
+		EnabledAt:    row.EnabledAt,
+	}, nil
+}
+
+// SavePending stores a new secret unless 2FA is already enabled, which is
+// reported as false.
+func (r *TwoFactorRepositoryImpl) SavePending(ctx context.Context, twoFactor *entity.TwoFactor) (bool, error) {
+	rows, err := r.q.UpsertPendingTwoFactor(ctx, sqlc.UpsertPendingTwoFactorParams{
+		UserID:    int32(twoFactor.UserID),
+		Secret:    twoFactor.Secret,
+		CreatedAt: twoFactor.CreatedAt,
+	})
+	return rows == 1, err
+}
+
+func (r *TwoFactorRepositoryImpl) Enable(ctx context.Context, userID int, enabledAt int64) (bool, error) {
+	rows, err := r.q.EnableUserTwoFactor(ctx, sqlc.EnableUserTwoFactorParams{
+		UserID:    int32(userID),
+		EnabledAt: enabledAt,
+	})
+	return rows == 1, err
+}
+
+// MarkStepUsed records step as consumed and reports false when it, or a
+// later step, was used before, which rejects replayed codes.
+func (r *TwoFactorRepositoryImpl) MarkStepUsed(ctx context.Context, userID int, step int64) (bool, error) {
+	rows, err := r.q.MarkTwoFactorStepUsed(ctx, sqlc.MarkTwoFactorStepUsedParams{
+		UserID:       int32(userID),
+		LastUsedStep: step,
+	})
+	return rows == 1, err
+}
+
+// Delete removes the enrollment together with its recovery codes; call it
+// inside a transaction.
+func (r *TwoFactorRepositoryImpl) Delete(ctx context.Context, userID int) error {
+	if err := r.q.DeleteRecoveryCodes(ctx, int32(userID)); err != nil {
+		return err
+	}
+	return r.q.DeleteUserTwoFactor(ctx, int32(userID))
+}
+
+// ReplaceRecoveryCodes drops every previous code; call it inside a
+// transaction.
+func (r *TwoFactorRepositoryImpl) ReplaceRecoveryCodes(ctx context.Context, userID int, hashes []string, createdAt int64) error {
+	if err := r.q.DeleteRecoveryCodes(ctx, int32(userID)); err != nil {
+		return err
+	}
+	for _, hash := range hashes {
+		if err := r.q.CreateRecoveryCode(ctx, sqlc.CreateRecoveryCodeParams{
+			UserID:    int32(userID),
+			CodeHash:  hash,
+			CreatedAt: createdAt,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *TwoFactorRepositoryImpl) UseRecoveryCode(ctx context.Context, userID int, hash string, usedAt int64) (bool, error) {
+	rows, err := r.q.UseRecoveryCode(ctx, sqlc.UseRecoveryCodeParams{
+		UserID:   int32(userID),
+		CodeHash: hash,
+		UsedAt:   usedAt,
+	})
+	return rows == 1, err
+}
@@ -1,14 +1,369 @@
 package repository
 
 import (
-	"sistem-06-Backend/internal/database/sqlc"
+	"context"
+	"database/sql"
+	"fmt"
+	"iter"
+	"strings"
+
+	"sistem-06-Backend/internal/domain/entity"
+	domain "sistem-06-Backend/internal/domain/ports"
+	"sistem-06-Backend/internal/infrastructure/database/sqlc"
+	"sistem-06-Backend/internal/pkg/fieldcrypt"
+	"sistem-06-Backend/internal/pkg/listquery"
+	"sistem-06-Backend/pkg"
 
 	"github.com/sirupsen/logrus"
 )
 
+// userEmailColumn names users.email to the cipher; it is bound into the
+// ciphertext and the blind index, so it must never change.
+const userEmailColumn = "users.email"
+
+// newUserListSchema builds what GET /users may filter and sort on. The
+// password column is selected for toEntity but never exposed as a field.
+// Emails are encrypted, so they are only matched exactly through
+// email_hash.
+func newUserListSchema(cipher *fieldcrypt.Cipher) *listquery.Schema[*entity.User] {
+	return &listquery.Schema[*entity.User]{
+		Table:   "users",
+		Columns: "id, name, email, password, created_at, updated_at, sessions_revoked_at, deleted_at, version",
+		Where:   "deleted_at = 0",
+		Fields: map[string]listquery.Field[*entity.User]{
+			"id":         {Column: "id", Type: listquery.Integer, Sortable: true, Value: func(u *entity.User) any { return u.ID }},
+			"name":       {Column: "name", Type: listquery.Text, Sortable: true, Value: func(u *entity.User) any { return u.Name }},
+			"email":      {Column: "email_hash", Type: listquery.Text, Hash: func(email string) string { return cipher.BlindIndex(userEmailColumn, email) }},
+			"created_at": {Column: "created_at", Type: listquery.Integer, Sortable: true, Value: func(u *entity.User) any { return u.CreatedAt }},
+			"updated_at": {Column: "updated_at", Type: listquery.Integer, Sortable: true, Value: func(u *entity.User) any { return u.UpdatedAt }},
+		},
+		Key:         "id",
+		DefaultSort: []listquery.Sort{{Field: "created_at", Desc: true}},
+		DefaultSize: 20,
+		MaxSize:     100,
+		CountTotal:  true,
+	}
+}
+
+// newUserExportSchema filters and sorts like the list schema but selects
+// the names of the live roles of each user instead of the password hash.
+func newUserExportSchema(list *listquery.Schema[*entity.User]) *listquery.Schema[*entity.User] {
+	return &listquery.Schema[*entity.User]{
+		Table: "users",
+		Columns: "id, name, email, created_at, updated_at, version, " +
+			"coalesce((SELECT string_agg(r.name, ',' ORDER BY r.name) FROM user_roles ur JOIN roles r ON r.id = ur.roles_id WHERE ur.user_id = users.id AND r.deleted_at = 0), '')",
+		Where:       list.Where,
+		Fields:      list.Fields,
+		Key:         list.Key,
+		DefaultSort: list.DefaultSort,
+	}
+}
+
+// UserRepositoryImpl encrypts emails on the way in and decrypts them on the
+// way out, so the rest of the application only sees plain addresses.
 type UserRepositoryImpl struct {
-	q *sqlc.Queries
-	log *logrus.Logger
+	q            *sqlc.Queries
+	log          *logrus.Logger
+	cipher       *fieldcrypt.Cipher
+	listSchema   *listquery.Schema[*entity.User]
+	exportSchema *listquery.Schema[*entity.User]
+}
+
+// NewUserRepository stores emails in the clear when cipher is nil.
+func NewUserRepository(q *sqlc.Queries, log *logrus.Logger, cipher *fieldcrypt.Cipher) *UserRepositoryImpl {
+	listSchema := newUserListSchema(cipher)
+	return &UserRepositoryImpl{
+		q:            q,
+		log:          log,
+		cipher:       cipher,
+		listSchema:   listSchema,
+		exportSchema: newUserExportSchema(listSchema),
+	}
+}
+
+func (r *UserRepositoryImpl) WithTx(tx *sql.Tx) domain.UserRepository {
+	return &UserRepositoryImpl{
+		q:            r.q.WithTx(tx),
+		log:          r.log,
+		cipher:       r.cipher,
+		listSchema:   r.listSchema,
+		exportSchema: r.exportSchema,
+	}
+}
+
+func (r *UserRepositoryImpl) CreateUser(ctx context.Context, user *entity.User) error {
+	email, err := r.cipher.Encrypt(userEmailColumn, user.Email)
+	if err != nil {
+		return err
+	}
+	id, err := r.q.CreateUser(ctx, sqlc.CreateUserParams{
+		Name:      user.Name,
+		Email:     email,
+		EmailHash: r.emailHash(user.Email),
+		Password:  user.Password,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	})
+	if err != nil {
+		return err
+	}
+	user.ID = int(id)
+	return nil
+}
+
+func (r *UserRepositoryImpl) CountById(ctx context.Context, id int) (int, error) {
+	count, err := r.q.CountUserByID(ctx, int32(id))
+	return int(count), err
+}
+
+func (r *UserRepositoryImpl) CountByName(ctx context.Context, name string) (int, error) {
+	count, err := r.q.CountUserByName(ctx, name)
+	return int(count), err
+}
+
+func (r *UserRepositoryImpl) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
+	row, err := r.q.FindUserByEmail(ctx, sqlc.FindUserByEmailParams{
+		EmailHash: r.emailHash(email).String,
+		Email:     email,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.toEntity(row)
+}
+
+func (r *UserRepositoryImpl) FindByID(ctx context.Context, id int) (*entity.User, error) {
+	row, err := r.q.FindUserByID(ctx, int32(id))
+	if err != nil {
+		return nil, err
+	}
+	return r.toEntity(row)
+}
+
+func (r *UserRepositoryImpl) FindWithRoles(ctx context.Context, id int) (*entity.User, error) {
+	user, err := r.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.q.GetRolesWithPermissionsByUserID(ctx, int64(id))
+	if err != nil {
+		return nil, err
+	}
+
+	// rows are ordered by role id, so permissions of the same role are adjacent
+	for _, row := range rows {
+		if len(user.Roles) == 0 || user.Roles[len(user.Roles)-1].ID != int(row.RoleID) {
+			user.Roles = append(user.Roles, entity.Role{ID: int(row.RoleID), Name: row.RoleName, RequireTwoFactor: row.RequireTwoFactor})
+		}
+		if row.PermissionName.Valid {
+			role := &user.Roles[len(user.Roles)-1]
+			role.Permission = append(role.Permission, entity.Permissions(row.PermissionName.String))
+		}
+	}
+
+	return user, nil
+}
+
+func (r *UserRepositoryImpl) List(ctx context.Context, request *listquery.Request) ([]*entity.User, pkg.PageMetadata, error) {
+	return list(ctx, r.q, r.listSchema, request, func(rows *sql.Rows) (*entity.User, error) {
+		var row sqlc.User
+		err := rows.Scan(
+			&row.ID,
+			&row.Name,
+			&row.Email,
+			&row.Password,
+			&row.CreatedAt,
+			&row.UpdatedAt,
+			&row.SessionsRevokedAt,
+			&row.DeletedAt,
+			&row.Version,
+		)
+		if err != nil {
+			return nil, err
+		}
+		return r.toEntity(&row)
+	})
 }
 
-func NewUserRepository (q *sqlc.Querier, log *logrus.Logger) *
+func (r *UserRepositoryImpl) Export(ctx context.Context, request *listquery.Request) (iter.Seq2[*entity.User, error], error) {
+	return export(ctx, r.q, r.exportSchema, request, func(rows *sql.Rows) (*entity.User, error) {
+		var row sqlc.User
+		var roles string
+		err := rows.Scan(
+			&row.ID,
+			&row.Name,
+			&row.Email,
+			&row.CreatedAt,
+			&row.UpdatedAt,
+			&row.Version,
+			&roles,
+		)
+		if err != nil {
+			return nil, err
+		}
+		user, err := r.toEntity(&row)
+		if err != nil {
+			return nil, err
+		}
+		if roles != "" {
+			for _, name := range strings.Split(roles, ",") {
+				user.Roles = append(user.Roles, entity.Role{Name: name})
+			}
+		}
+		return user, nil
+	})
+}
+
+func (r *UserRepositoryImpl) UpdateName(ctx context.Context, id int, version int, name string, updatedAt int64) error {
+	affected, err := r.q.UpdateUserName(ctx, sqlc.UpdateUserNameParams{
+		ID:        int32(id),
+		Name:      name,
+		UpdatedAt: updatedAt,
+		Version:   int32(version),
+	})
+	return checkVersion(affected, err, r.exists(ctx, id))
+}
+
+func (r *UserRepositoryImpl) UpdateEmail(ctx context.Context, id int, email string, updatedAt int64) error {
+	encrypted, err := r.cipher.Encrypt(userEmailColumn, email)
+	if err != nil {
+		return err
+	}
+	return r.q.UpdateUserEmail(ctx, sqlc.UpdateUserEmailParams{
+		ID:        int32(id),
+		Email:     encrypted,
+		EmailHash: r.emailHash(email),
+		UpdatedAt: updatedAt,
+	})
+}
+
+// UpdatePassword stores an already hashed password.
+func (r *UserRepositoryImpl) UpdatePassword(ctx context.Context, id int, password string, updatedAt int64) error {
+	return r.q.UpdateUserPassword(ctx, sqlc.UpdateUserPasswordParams{
+		ID:        int32(id),
+		Password:  password,
+		UpdatedAt: updatedAt,
+	})
+}
+
+func (r *UserRepositoryImpl) RehashPassword(ctx context.Context, id int, password string) error {
+	return r.q.RehashUserPassword(ctx, sqlc.RehashUserPasswordParams{
+		ID:       int32(id),
+		Password: password,
+	})
+}
+
+// RevokeSessions invalidates every session and personal access token of the
+// user created before revokedAt.
+func (r *UserRepositoryImpl) RevokeSessions(ctx context.Context, id int, revokedAt int64) error {
+	return r.q.RevokeUserSessions(ctx, sqlc.RevokeUserSessionsParams{
+		ID:                int32(id),
+		SessionsRevokedAt: revokedAt,
+	})
+}
+
+func (r *UserRepositoryImpl) SoftDelete(ctx context.Context, id int, version int, deletedAt int64) error {
+	affected, err := r.q.SoftDeleteUser(ctx, sqlc.SoftDeleteUserParams{
+		ID:        int32(id),
+		DeletedAt: deletedAt,
+		Version:   int32(version),
+	})
+	return checkVersion(affected, err, r.exists(ctx, id))
+}
+
+func (r *UserRepositoryImpl) Restore(ctx context.Context, id int, restoredAt int64) (bool, error) {
+	affected, err := r.q.RestoreUser(ctx, sqlc.RestoreUserParams{
+		ID:        int32(id),
+		UpdatedAt: restoredAt,
+	})
+	return affected > 0, err
+}
+
+func (r *UserRepositoryImpl) PurgeDeleted(ctx context.Context, deletedBefore int64) (int64, error) {
+	return r.q.PurgeDeletedUsers(ctx, deletedBefore)
+}
+
+func (r *UserRepositoryImpl) Anonymize(ctx context.Context, id int, placeholder string, anonymizedAt int64) (bool, error) {
+	affected, err := r.q.AnonymizeUser(ctx, sqlc.AnonymizeUserParams{
+		ID:        int32(id),
+		Name:      placeholder,
+		UpdatedAt: anonymizedAt,
+	})
+	return affected > 0, err
+}
+
+func (r *UserRepositoryImpl) exists(ctx context.Context, id int) func() (bool, error) {
+	return func() (bool, error) {
+		count, err := r.q.CountUserByID(ctx, int32(id))
+		return count > 0, err
+	}
+}
+
+// RotateEmails encrypts the emails still stored in the clear, re-wraps the
+// ones encrypted under an older key and recomputes every blind index, in
+// batches of batchSize rows. Deleted users are included. It returns the
+// number of rows rewritten; running it again only touches rows that changed
+// in between.
+func (r *UserRepositoryImpl) RotateEmails(ctx context.Context, batchSize int) (int, error) {
+	rotated := 0
+	var after int32
+	for {
+		rows, err := r.q.ListUserEmails(ctx, sqlc.ListUserEmailsParams{ID: after, Limit: int32(batchSize)})
+		if err != nil {
+			return rotated, err
+		}
+		for _, row := range rows {
+			after = row.ID
+			plain, err := r.cipher.Decrypt(userEmailColumn, row.Email)
+			if err != nil {
+				return rotated, fmt.Errorf("decrypt email of user %d: %w", row.ID, err)
+			}
+			email, changed, err := r.cipher.Rotate(userEmailColumn, row.Email)
+			if err != nil {
+				return rotated, fmt.Errorf("rotate email of user %d: %w", row.ID, err)
+			}
+			hash := r.emailHash(plain)
+			if !changed && row.EmailHash == hash {
+				continue
+			}
+			// 0 rows when the user changed the email meanwhile, which
+			// already stored it under the current key
+			affected, err := r.q.ReencryptUserEmail(ctx, sqlc.ReencryptUserEmailParams{
+				Email:     email,
+				EmailHash: hash,
+				ID:        row.ID,
+				OldEmail:  row.Email,
+			})
+			if err != nil {
+				return rotated, err
+			}
+			rotated += int(affected)
+		}
+		if len(rows) < batchSize {
+			return rotated, nil
+		}
+	}
+}
+
+func (r *UserRepositoryImpl) emailHash(email string) sql.NullString {
+	return sql.NullString{String: r.cipher.BlindIndex(userEmailColumn, email), Valid: true}
+}
+
+func (r *UserRepositoryImpl) toEntity(row *sqlc.User) (*entity.User, error) {
+	email, err := r.cipher.Decrypt(userEmailColumn, row.Email)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt email of user %d: %w", row.ID, err)
+	}
+	return &entity.User{
+		ID:                int(row.ID),
+		Name:              row.Name,
+		Email:             email,
+		Password:          row.Password,
+		CreatedAt:         row.CreatedAt,
+		UpdatedAt:         row.UpdatedAt,
+		SessionsRevokedAt: row.SessionsRevokedAt,
+		DeletedAt:         row.DeletedAt,
+		Version:           int(row.Version),
+	}, nil
+}
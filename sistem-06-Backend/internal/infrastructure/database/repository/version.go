@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"database/sql"
+
+	"sistem-06-Backend/internal/domain/entity"
+)
+
+// checkVersion interprets an update guarded by "version = $n". When no row
+// matched, exists tells a missing row (sql.ErrNoRows) apart from one that
+// was changed by someone else (entity.ErrVersionConflict).
+func checkVersion(affected int64, err error, exists func() (bool, error)) error {
+	if err != nil || affected > 0 {
+		return err
+	}
+	found, err := exists()
+	if err != nil {
+		return err
+	}
+	if !found {
+		return sql.ErrNoRows
+	}
+	return entity.ErrVersionConflict
+}
@@ -38,7 +38,7 @@ func (q *Queries) CreateAddress(ctx context.Context, arg CreateAddressParams) (i
 }
 
 const FindAdressByID = `-- name: FindAdressByID :one
-SELECT id, jalan, rt, rw, kota, postal_code FROM address WHERE id = $1
+SELECT id, jalan, rt, rw, kota, postal_code, deleted_at, version FROM address WHERE id = $1 AND deleted_at = 0
 `
 
 func (q *Queries) FindAdressByID(ctx context.Context, id int32) (*Address, error) {
@@ -51,19 +51,109 @@ func (q *Queries) FindAdressByID(ctx context.Context, id int32) (*Address, error
 		&i.Rw,
 		&i.Kota,
 		&i.PostalCode,
+		&i.DeletedAt,
+		&i.Version,
 	)
 	return &i, err
 }
 
-const UpdateAddress = `-- name: UpdateAddress :exec
+const ListAddressesCreatedBy = `-- name: ListAddressesCreatedBy :many
+SELECT a.id, a.jalan, a.rt, a.rw, a.kota, a.postal_code, a.deleted_at, a.version
+FROM address a
+JOIN audit_logs l ON l.entity_type = 'address' AND l.action = 'create' AND l.entity_id = a.id::text
+WHERE l.actor_id = $1 AND a.deleted_at = 0
+ORDER BY a.id
+`
+
+func (q *Queries) ListAddressesCreatedBy(ctx context.Context, actorID sql.NullInt32) ([]*Address, error) {
+	rows, err := q.db.QueryContext(ctx, ListAddressesCreatedBy, actorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Address{}
+	for rows.Next() {
+		var i Address
+		if err := rows.Scan(
+			&i.ID,
+			&i.Jalan,
+			&i.Rt,
+			&i.Rw,
+			&i.Kota,
+			&i.PostalCode,
+			&i.DeletedAt,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const PurgeDeletedAddresses = `-- name: PurgeDeletedAddresses :execrows
+DELETE FROM address
+WHERE deleted_at > 0 AND deleted_at < $1
+`
+
+func (q *Queries) PurgeDeletedAddresses(ctx context.Context, deletedAt int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, PurgeDeletedAddresses, deletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const RestoreAddress = `-- name: RestoreAddress :execrows
+UPDATE address
+SET deleted_at = 0, version = version + 1
+WHERE id = $1 AND deleted_at > 0
+`
+
+func (q *Queries) RestoreAddress(ctx context.Context, id int32) (int64, error) {
+	result, err := q.db.ExecContext(ctx, RestoreAddress, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const SoftDeleteAddress = `-- name: SoftDeleteAddress :execrows
+UPDATE address
+SET deleted_at = $2, version = version + 1
+WHERE id = $1 AND version = $3 AND deleted_at = 0
+`
+
+type SoftDeleteAddressParams struct {
+	ID        int32 `json:"id"`
+	DeletedAt int64 `json:"deleted_at"`
+	Version   int32 `json:"version"`
+}
+
+func (q *Queries) SoftDeleteAddress(ctx context.Context, arg SoftDeleteAddressParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, SoftDeleteAddress, arg.ID, arg.DeletedAt, arg.Version)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const UpdateAddress = `-- name: UpdateAddress :execrows
 UPDATE address 
 SET
 jalan = COALESCE($2, jalan),
 rt = COALESCE($3, rt),
 rw = COALESCE($4, rw),
 kota = COALESCE($5, kota),
-postal_code = COALESCE($6, postal_code)
-WHERE id = $1
+postal_code = COALESCE($6, postal_code),
+version = version + 1
+WHERE id = $1 AND version = $7 AND deleted_at = 0
 `
 
 type UpdateAddressParams struct {
@@ -73,16 +163,21 @@ type UpdateAddressParams struct {
 	Rw         sql.NullString `json:"rw"`
 	Kota       sql.NullString `json:"kota"`
 	PostalCode sql.NullString `json:"postal_code"`
+	Version    int32          `json:"version"`
 }
 
-func (q *Queries) UpdateAddress(ctx context.Context, arg UpdateAddressParams) error {
-	_, err := q.db.ExecContext(ctx, UpdateAddress,
+func (q *Queries) UpdateAddress(ctx context.Context, arg UpdateAddressParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, UpdateAddress,
 		arg.ID,
 		arg.Jalan,
 		arg.Rt,
 		arg.Rw,
 		arg.Kota,
 		arg.PostalCode,
+		arg.Version,
 	)
-	return err
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
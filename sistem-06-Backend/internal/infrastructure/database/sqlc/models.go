@@ -4,13 +4,71 @@
 
 package sqlc
 
+import (
+	"database/sql"
+)
+
 type Address struct {
-	ID         int32  `json:"id"`
-	Jalan      string `json:"jalan"`
-	Rt         string `json:"rt"`
-	Rw         string `json:"rw"`
-	Kota       string `json:"kota"`
-	PostalCode string `json:"postal_code"`
+	ID           int32       `json:"id"`
+	Jalan        string      `json:"jalan"`
+	Rt           string      `json:"rt"`
+	Rw           string      `json:"rw"`
+	Kota         string      `json:"kota"`
+	PostalCode   string      `json:"postal_code"`
+	DeletedAt    int64       `json:"deleted_at"`
+	Version      int32       `json:"version"`
+	SearchVector interface{} `json:"search_vector"`
+}
+
+type AuditLog struct {
+	ID         int64         `json:"id"`
+	ActorID    sql.NullInt32 `json:"actor_id"`
+	Action     string        `json:"action"`
+	EntityType string        `json:"entity_type"`
+	EntityID   string        `json:"entity_id"`
+	Diff       string        `json:"diff"`
+	Ip         string        `json:"ip"`
+	RequestID  string        `json:"request_id"`
+	CreatedAt  int64         `json:"created_at"`
+	PrevHash   string        `json:"prev_hash"`
+	Hash       string        `json:"hash"`
+}
+
+type DeletionRequest struct {
+	UserID      int32 `json:"user_id"`
+	RequestedAt int64 `json:"requested_at"`
+	ScheduledAt int64 `json:"scheduled_at"`
+	CompletedAt int64 `json:"completed_at"`
+}
+
+type EmailChange struct {
+	UserID    int32  `json:"user_id"`
+	NewEmail  string `json:"new_email"`
+	TokenHash string `json:"token_hash"`
+	ExpiresAt int64  `json:"expires_at"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+type ImportJob struct {
+	ID            string `json:"id"`
+	Kind          string `json:"kind"`
+	Status        string `json:"status"`
+	DryRun        bool   `json:"dry_run"`
+	TotalRows     int32  `json:"total_rows"`
+	ProcessedRows int32  `json:"processed_rows"`
+	ImportedRows  int32  `json:"imported_rows"`
+	Errors        string `json:"errors"`
+	CreatedBy     int32  `json:"created_by"`
+	CreatedAt     int64  `json:"created_at"`
+	FinishedAt    int64  `json:"finished_at"`
+}
+
+type LoginAttempt struct {
+	Scope        string `json:"scope"`
+	Identifier   string `json:"identifier"`
+	Failures     int32  `json:"failures"`
+	LastFailedAt int64  `json:"last_failed_at"`
+	LockedUntil  int64  `json:"locked_until"`
 }
 
 type Permission struct {
@@ -18,9 +76,30 @@ type Permission struct {
 	Name string `json:"name"`
 }
 
+type PersonalAccessToken struct {
+	ID         int32  `json:"id"`
+	UserID     int32  `json:"user_id"`
+	Name       string `json:"name"`
+	TokenHash  string `json:"token_hash"`
+	Scopes     string `json:"scopes"`
+	ExpiresAt  int64  `json:"expires_at"`
+	LastUsedAt int64  `json:"last_used_at"`
+	RevokedAt  int64  `json:"revoked_at"`
+	CreatedAt  int64  `json:"created_at"`
+}
+
+type RateLimitCounter struct {
+	Bucket  string `json:"bucket"`
+	Hits    int32  `json:"hits"`
+	ResetAt int64  `json:"reset_at"`
+}
+
 type Role struct {
-	ID   int32  `json:"id"`
-	Name string `json:"name"`
+	ID               int32  `json:"id"`
+	Name             string `json:"name"`
+	RequireTwoFactor bool   `json:"require_two_factor"`
+	DeletedAt        int64  `json:"deleted_at"`
+	Version          int32  `json:"version"`
 }
 
 type RolesPermission struct {
@@ -30,12 +109,26 @@ type RolesPermission struct {
 }
 
 type User struct {
-	ID        int32  `json:"id"`
-	Name      string `json:"name"`
-	Email     string `json:"email"`
-	Password  string `json:"password"`
+	ID                int32          `json:"id"`
+	Name              string         `json:"name"`
+	Email             string         `json:"email"`
+	Password          string         `json:"password"`
+	CreatedAt         int64          `json:"created_at"`
+	UpdatedAt         int64          `json:"updated_at"`
+	SessionsRevokedAt int64          `json:"sessions_revoked_at"`
+	DeletedAt         int64          `json:"deleted_at"`
+	Version           int32          `json:"version"`
+	EmailHash         sql.NullString `json:"email_hash"`
+	SearchVector      interface{}    `json:"search_vector"`
+	AnonymizedAt      int64          `json:"anonymized_at"`
+}
+
+type UserRecoveryCode struct {
+	ID        int64  `json:"id"`
+	UserID    int32  `json:"user_id"`
+	CodeHash  string `json:"code_hash"`
+	UsedAt    int64  `json:"used_at"`
 	CreatedAt int64  `json:"created_at"`
-	UpdatedAt int64  `json:"updated_at"`
 }
 
 type UserRole struct {
@@ -43,3 +136,12 @@ type UserRole struct {
 	UserID  int64 `json:"user_id"`
 	RolesID int64 `json:"roles_id"`
 }
+
+type UserTwoFactor struct {
+	UserID       int32  `json:"user_id"`
+	Secret       string `json:"secret"`
+	Enabled      bool   `json:"enabled"`
+	LastUsedStep int64  `json:"last_used_step"`
+	CreatedAt    int64  `json:"created_at"`
+	EnabledAt    int64  `json:"enabled_at"`
+}
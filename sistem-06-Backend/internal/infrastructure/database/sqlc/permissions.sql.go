@@ -13,7 +13,8 @@ const GetPermissionsByRoleID = `-- name: GetPermissionsByRoleID :many
 SELECT p.id, p.name
 FROM permissions p
 JOIN roles_permissions rp ON rp.permission_id = p.id
-WHERE rp.role_id = $1
+JOIN roles r ON r.id = rp.role_id
+WHERE rp.role_id = $1 AND r.deleted_at = 0
 `
 
 func (q *Queries) GetPermissionsByRoleID(ctx context.Context, roleID int64) ([]*Permission, error) {
@@ -44,7 +45,8 @@ SELECT DISTINCT p.id, p.name
 FROM permissions p
 JOIN roles_permissions rp ON rp.permission_id = p.id
 JOIN user_roles ur ON ur.roles_id = rp.role_id
-WHERE ur.user_id = $1
+JOIN roles r ON r.id = rp.role_id
+WHERE ur.user_id = $1 AND r.deleted_at = 0
 `
 
 func (q *Queries) GetPermissionsByUserID(ctx context.Context, userID int64) ([]*Permission, error) {
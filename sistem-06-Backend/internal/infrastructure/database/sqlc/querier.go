@@ -6,23 +6,89 @@ package sqlc
 
 import (
 	"context"
+	"database/sql"
 )
 
 type Querier interface {
+	AnonymizeUser(ctx context.Context, arg AnonymizeUserParams) (int64, error)
 	AssignRoleToUser(ctx context.Context, arg AssignRoleToUserParams) error
+	CancelDeletionRequest(ctx context.Context, userID int32) (int64, error)
+	CompleteDeletionRequest(ctx context.Context, arg CompleteDeletionRequestParams) (int64, error)
+	CountAuditLogs(ctx context.Context, arg CountAuditLogsParams) (int64, error)
 	CountUserByID(ctx context.Context, id int32) (int64, error)
 	CountUserByName(ctx context.Context, name string) (int64, error)
 	CreateAddress(ctx context.Context, arg CreateAddressParams) (int32, error)
+	CreateAuditLog(ctx context.Context, arg CreateAuditLogParams) (int64, error)
+	CreateDeletionRequest(ctx context.Context, arg CreateDeletionRequestParams) error
+	CreateImportJob(ctx context.Context, arg CreateImportJobParams) error
+	CreatePersonalAccessToken(ctx context.Context, arg CreatePersonalAccessTokenParams) (int32, error)
+	CreateRecoveryCode(ctx context.Context, arg CreateRecoveryCodeParams) error
 	CreateUser(ctx context.Context, arg CreateUserParams) (int32, error)
+	DeleteEmailChange(ctx context.Context, userID int32) error
+	DeleteExpiredLoginAttempts(ctx context.Context, arg DeleteExpiredLoginAttemptsParams) (int64, error)
+	DeleteExpiredRateLimits(ctx context.Context, resetAt int64) (int64, error)
+	DeleteLoginAttempt(ctx context.Context, arg DeleteLoginAttemptParams) error
+	DeletePersonalAccessTokensByUserID(ctx context.Context, userID int32) error
+	DeleteRecoveryCodes(ctx context.Context, userID int32) error
+	DeleteUserTwoFactor(ctx context.Context, userID int32) error
+	EnableUserTwoFactor(ctx context.Context, arg EnableUserTwoFactorParams) (int64, error)
 	FindAdressByID(ctx context.Context, id int32) (*Address, error)
-	FindUserByEmail(ctx context.Context, email string) (*User, error)
+	FindDeletionRequest(ctx context.Context, userID int32) (*DeletionRequest, error)
+	FindEmailChangeByTokenHash(ctx context.Context, tokenHash string) (*EmailChange, error)
+	FindEmailChangeByUserID(ctx context.Context, userID int32) (*EmailChange, error)
+	FindImportJobByID(ctx context.Context, id string) (*ImportJob, error)
+	FindLoginAttempt(ctx context.Context, arg FindLoginAttemptParams) (*LoginAttempt, error)
+	FindPersonalAccessTokenByHash(ctx context.Context, tokenHash string) (*PersonalAccessToken, error)
+	FindRoleByID(ctx context.Context, id int32) (*Role, error)
+	FindRoleByName(ctx context.Context, name string) (*Role, error)
+	FindUserByEmail(ctx context.Context, arg FindUserByEmailParams) (*User, error)
 	FindUserByID(ctx context.Context, id int32) (*User, error)
+	FindUserTwoFactor(ctx context.Context, userID int32) (*UserTwoFactor, error)
+	FinishImportJob(ctx context.Context, arg FinishImportJobParams) error
 	GetPermissionsByRoleID(ctx context.Context, roleID int64) ([]*Permission, error)
 	GetPermissionsByUserID(ctx context.Context, userID int64) ([]*Permission, error)
 	GetRolesByUserID(ctx context.Context, userID int64) ([]*Role, error)
 	GetRolesWithPermissionsByUserID(ctx context.Context, userID int64) ([]*GetRolesWithPermissionsByUserIDRow, error)
+	HitRateLimit(ctx context.Context, arg HitRateLimitParams) (*RateLimitCounter, error)
+	LastAuditLogHash(ctx context.Context) (string, error)
+	ListAddressesCreatedBy(ctx context.Context, actorID sql.NullInt32) ([]*Address, error)
+	ListAuditLogs(ctx context.Context, arg ListAuditLogsParams) ([]*AuditLog, error)
+	ListAuditLogsAfter(ctx context.Context, arg ListAuditLogsAfterParams) ([]*AuditLog, error)
+	ListDueDeletionRequests(ctx context.Context, arg ListDueDeletionRequestsParams) ([]*DeletionRequest, error)
+	ListEmailChanges(ctx context.Context, arg ListEmailChangesParams) ([]*ListEmailChangesRow, error)
+	ListPersonalAccessTokensByUserID(ctx context.Context, userID int32) ([]*PersonalAccessToken, error)
+	ListUserEmails(ctx context.Context, arg ListUserEmailsParams) ([]*ListUserEmailsRow, error)
+	LockAuditChain(ctx context.Context, pgAdvisoryXactLock int64) error
+	LockLoginAttempt(ctx context.Context, arg LockLoginAttemptParams) error
+	MarkTwoFactorStepUsed(ctx context.Context, arg MarkTwoFactorStepUsedParams) (int64, error)
+	PurgeDeletedAddresses(ctx context.Context, deletedAt int64) (int64, error)
+	PurgeDeletedRoles(ctx context.Context, deletedAt int64) (int64, error)
+	PurgeDeletedUsers(ctx context.Context, deletedAt int64) (int64, error)
+	RecordLoginFailure(ctx context.Context, arg RecordLoginFailureParams) (*LoginAttempt, error)
+	ReencryptEmailChange(ctx context.Context, arg ReencryptEmailChangeParams) (int64, error)
+	ReencryptUserEmail(ctx context.Context, arg ReencryptUserEmailParams) (int64, error)
+	RehashUserPassword(ctx context.Context, arg RehashUserPasswordParams) error
+	RemoveAllRolesFromUser(ctx context.Context, userID int64) error
 	RemoveRoleFromUser(ctx context.Context, arg RemoveRoleFromUserParams) error
-	UpdateAddress(ctx context.Context, arg UpdateAddressParams) error
+	RestoreAddress(ctx context.Context, id int32) (int64, error)
+	RestoreRole(ctx context.Context, id int32) (int64, error)
+	RestoreUser(ctx context.Context, arg RestoreUserParams) (int64, error)
+	RevokePersonalAccessToken(ctx context.Context, arg RevokePersonalAccessTokenParams) (int64, error)
+	RevokeUserSessions(ctx context.Context, arg RevokeUserSessionsParams) error
+	SearchAddresses(ctx context.Context, arg SearchAddressesParams) ([]*SearchAddressesRow, error)
+	SearchUsers(ctx context.Context, arg SearchUsersParams) ([]*SearchUsersRow, error)
+	SoftDeleteAddress(ctx context.Context, arg SoftDeleteAddressParams) (int64, error)
+	SoftDeleteRole(ctx context.Context, arg SoftDeleteRoleParams) (int64, error)
+	SoftDeleteUser(ctx context.Context, arg SoftDeleteUserParams) (int64, error)
+	TouchPersonalAccessToken(ctx context.Context, arg TouchPersonalAccessTokenParams) error
+	UpdateAddress(ctx context.Context, arg UpdateAddressParams) (int64, error)
+	UpdateImportJobProgress(ctx context.Context, arg UpdateImportJobProgressParams) error
+	UpdateUserEmail(ctx context.Context, arg UpdateUserEmailParams) error
+	UpdateUserName(ctx context.Context, arg UpdateUserNameParams) (int64, error)
+	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error
+	UpsertEmailChange(ctx context.Context, arg UpsertEmailChangeParams) error
+	UpsertPendingTwoFactor(ctx context.Context, arg UpsertPendingTwoFactorParams) (int64, error)
+	UseRecoveryCode(ctx context.Context, arg UseRecoveryCodeParams) (int64, error)
 }
 
 var _ Querier = (*Queries)(nil)
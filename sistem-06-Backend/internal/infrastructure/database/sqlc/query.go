@@ -0,0 +1,21 @@
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Query runs SQL built at runtime, such as the list queries compiled by
+// listquery, on the same connection or transaction as the generated
+// queries. This file is maintained by hand.
+func (q *Queries) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return q.db.QueryContext(ctx, query, args...)
+}
+
+func (q *Queries) QueryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return q.db.QueryRowContext(ctx, query, args...)
+}
+
+func (q *Queries) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return q.db.ExecContext(ctx, query, args...)
+}
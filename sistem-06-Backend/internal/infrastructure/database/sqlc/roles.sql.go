@@ -25,11 +25,49 @@ func (q *Queries) AssignRoleToUser(ctx context.Context, arg AssignRoleToUserPara
 	return err
 }
 
+const FindRoleByID = `-- name: FindRoleByID :one
+SELECT id, name, require_two_factor, deleted_at, version
+FROM roles
+WHERE id = $1 AND deleted_at = 0
+`
+
+func (q *Queries) FindRoleByID(ctx context.Context, id int32) (*Role, error) {
+	row := q.db.QueryRowContext(ctx, FindRoleByID, id)
+	var i Role
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.RequireTwoFactor,
+		&i.DeletedAt,
+		&i.Version,
+	)
+	return &i, err
+}
+
+const FindRoleByName = `-- name: FindRoleByName :one
+SELECT id, name, require_two_factor, deleted_at, version
+FROM roles
+WHERE name = $1 AND deleted_at = 0
+`
+
+func (q *Queries) FindRoleByName(ctx context.Context, name string) (*Role, error) {
+	row := q.db.QueryRowContext(ctx, FindRoleByName, name)
+	var i Role
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.RequireTwoFactor,
+		&i.DeletedAt,
+		&i.Version,
+	)
+	return &i, err
+}
+
 const GetRolesByUserID = `-- name: GetRolesByUserID :many
-SELECT r.id, r.name
+SELECT r.id, r.name, r.require_two_factor, r.deleted_at, r.version
 FROM roles r
 JOIN user_roles ur ON ur.roles_id = r.id
-WHERE ur.user_id = $1
+WHERE ur.user_id = $1 AND r.deleted_at = 0
 `
 
 func (q *Queries) GetRolesByUserID(ctx context.Context, userID int64) ([]*Role, error) {
@@ -41,7 +79,13 @@ func (q *Queries) GetRolesByUserID(ctx context.Context, userID int64) ([]*Role,
 	items := []*Role{}
 	for rows.Next() {
 		var i Role
-		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.RequireTwoFactor,
+			&i.DeletedAt,
+			&i.Version,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, &i)
@@ -59,21 +103,23 @@ const GetRolesWithPermissionsByUserID = `-- name: GetRolesWithPermissionsByUserI
 SELECT 
     r.id AS role_id,
     r.name AS role_name,
+    r.require_two_factor,
     p.id AS permission_id,
     p.name AS permission_name
 FROM user_roles ur
 JOIN roles r ON r.id = ur.roles_id
 LEFT JOIN roles_permissions rp ON rp.role_id = r.id
 LEFT JOIN permissions p ON p.id = rp.permission_id
-WHERE ur.user_id = $1
+WHERE ur.user_id = $1 AND r.deleted_at = 0
 ORDER BY r.id, p.name
 `
 
 type GetRolesWithPermissionsByUserIDRow struct {
-	RoleID         int32          `json:"role_id"`
-	RoleName       string         `json:"role_name"`
-	PermissionID   sql.NullInt32  `json:"permission_id"`
-	PermissionName sql.NullString `json:"permission_name"`
+	RoleID           int32          `json:"role_id"`
+	RoleName         string         `json:"role_name"`
+	RequireTwoFactor bool           `json:"require_two_factor"`
+	PermissionID     sql.NullInt32  `json:"permission_id"`
+	PermissionName   sql.NullString `json:"permission_name"`
 }
 
 func (q *Queries) GetRolesWithPermissionsByUserID(ctx context.Context, userID int64) ([]*GetRolesWithPermissionsByUserIDRow, error) {
@@ -88,6 +134,7 @@ func (q *Queries) GetRolesWithPermissionsByUserID(ctx context.Context, userID in
 		if err := rows.Scan(
 			&i.RoleID,
 			&i.RoleName,
+			&i.RequireTwoFactor,
 			&i.PermissionID,
 			&i.PermissionName,
 		); err != nil {
@@ -104,6 +151,29 @@ func (q *Queries) GetRolesWithPermissionsByUserID(ctx context.Context, userID in
 	return items, nil
 }
 
+const PurgeDeletedRoles = `-- name: PurgeDeletedRoles :execrows
+DELETE FROM roles
+WHERE deleted_at > 0 AND deleted_at < $1
+`
+
+func (q *Queries) PurgeDeletedRoles(ctx context.Context, deletedAt int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, PurgeDeletedRoles, deletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const RemoveAllRolesFromUser = `-- name: RemoveAllRolesFromUser :exec
+DELETE FROM user_roles
+WHERE user_id = $1
+`
+
+func (q *Queries) RemoveAllRolesFromUser(ctx context.Context, userID int64) error {
+	_, err := q.db.ExecContext(ctx, RemoveAllRolesFromUser, userID)
+	return err
+}
+
 const RemoveRoleFromUser = `-- name: RemoveRoleFromUser :exec
 DELETE FROM user_roles
 WHERE user_id = $1 AND roles_id = $2
@@ -118,3 +188,37 @@ func (q *Queries) RemoveRoleFromUser(ctx context.Context, arg RemoveRoleFromUser
 	_, err := q.db.ExecContext(ctx, RemoveRoleFromUser, arg.UserID, arg.RolesID)
 	return err
 }
+
+const RestoreRole = `-- name: RestoreRole :execrows
+UPDATE roles
+SET deleted_at = 0, version = version + 1
+WHERE id = $1 AND deleted_at > 0
+`
+
+func (q *Queries) RestoreRole(ctx context.Context, id int32) (int64, error) {
+	result, err := q.db.ExecContext(ctx, RestoreRole, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const SoftDeleteRole = `-- name: SoftDeleteRole :execrows
+UPDATE roles
+SET deleted_at = $2, version = version + 1
+WHERE id = $1 AND version = $3 AND deleted_at = 0
+`
+
+type SoftDeleteRoleParams struct {
+	ID        int32 `json:"id"`
+	DeletedAt int64 `json:"deleted_at"`
+	Version   int32 `json:"version"`
+}
+
+func (q *Queries) SoftDeleteRole(ctx context.Context, arg SoftDeleteRoleParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, SoftDeleteRole, arg.ID, arg.DeletedAt, arg.Version)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -7,10 +7,32 @@ package sqlc
 
 import (
 	"context"
+	"database/sql"
 )
 
+const AnonymizeUser = `-- name: AnonymizeUser :execrows
+UPDATE users
+SET name = $2, email = $2, email_hash = NULL, password = '', updated_at = $3,
+    sessions_revoked_at = $3, deleted_at = $3, anonymized_at = $3, version = version + 1
+WHERE id = $1 AND anonymized_at = 0
+`
+
+type AnonymizeUserParams struct {
+	ID        int32  `json:"id"`
+	Name      string `json:"name"`
+	UpdatedAt int64  `json:"updated_at"`
+}
+
+func (q *Queries) AnonymizeUser(ctx context.Context, arg AnonymizeUserParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, AnonymizeUser, arg.ID, arg.Name, arg.UpdatedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const CountUserByID = `-- name: CountUserByID :one
-SELECT COUNT(*) FROM users WHERE id = $1
+SELECT COUNT(*) FROM users WHERE id = $1 AND deleted_at = 0
 `
 
 func (q *Queries) CountUserByID(ctx context.Context, id int32) (int64, error) {
@@ -21,7 +43,7 @@ func (q *Queries) CountUserByID(ctx context.Context, id int32) (int64, error) {
 }
 
 const CountUserByName = `-- name: CountUserByName :one
-SELECT COUNT(*) FROM users WHERE name = $1
+SELECT COUNT(*) FROM users WHERE name = $1 AND deleted_at = 0
 `
 
 func (q *Queries) CountUserByName(ctx context.Context, name string) (int64, error) {
@@ -32,23 +54,25 @@ func (q *Queries) CountUserByName(ctx context.Context, name string) (int64, erro
 }
 
 const CreateUser = `-- name: CreateUser :one
-INSERT INTO users (name, email, password, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5)
+INSERT INTO users (name, email, email_hash, password, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6)
 RETURNING id
 `
 
 type CreateUserParams struct {
-	Name      string `json:"name"`
-	Email     string `json:"email"`
-	Password  string `json:"password"`
-	CreatedAt int64  `json:"created_at"`
-	UpdatedAt int64  `json:"updated_at"`
+	Name      string         `json:"name"`
+	Email     string         `json:"email"`
+	EmailHash sql.NullString `json:"email_hash"`
+	Password  string         `json:"password"`
+	CreatedAt int64          `json:"created_at"`
+	UpdatedAt int64          `json:"updated_at"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (int32, error) {
 	row := q.db.QueryRowContext(ctx, CreateUser,
 		arg.Name,
 		arg.Email,
+		arg.EmailHash,
 		arg.Password,
 		arg.CreatedAt,
 		arg.UpdatedAt,
@@ -59,12 +83,19 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (int32,
 }
 
 const FindUserByEmail = `-- name: FindUserByEmail :one
-SELECT id, name, email, password, created_at, updated_at 
-FROM users WHERE email = $1
+SELECT id, name, email, password, created_at, updated_at, sessions_revoked_at, deleted_at, version
+FROM users
+WHERE (email_hash = $1::text OR email = $2)
+  AND deleted_at = 0
 `
 
-func (q *Queries) FindUserByEmail(ctx context.Context, email string) (*User, error) {
-	row := q.db.QueryRowContext(ctx, FindUserByEmail, email)
+type FindUserByEmailParams struct {
+	EmailHash string `json:"email_hash"`
+	Email     string `json:"email"`
+}
+
+func (q *Queries) FindUserByEmail(ctx context.Context, arg FindUserByEmailParams) (*User, error) {
+	row := q.db.QueryRowContext(ctx, FindUserByEmail, arg.EmailHash, arg.Email)
 	var i User
 	err := row.Scan(
 		&i.ID,
@@ -73,14 +104,17 @@ func (q *Queries) FindUserByEmail(ctx context.Context, email string) (*User, err
 		&i.Password,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SessionsRevokedAt,
+		&i.DeletedAt,
+		&i.Version,
 	)
 	return &i, err
 }
 
 const FindUserByID = `-- name: FindUserByID :one
-SELECT id, name, email, password, created_at, updated_at
+SELECT id, name, email, password, created_at, updated_at, sessions_revoked_at, deleted_at, version
 FROM users
-WHERE id = $1
+WHERE id = $1 AND deleted_at = 0
 `
 
 func (q *Queries) FindUserByID(ctx context.Context, id int32) (*User, error) {
@@ -93,6 +127,227 @@ func (q *Queries) FindUserByID(ctx context.Context, id int32) (*User, error) {
 		&i.Password,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SessionsRevokedAt,
+		&i.DeletedAt,
+		&i.Version,
 	)
 	return &i, err
 }
+
+const ListUserEmails = `-- name: ListUserEmails :many
+SELECT id, email, email_hash
+FROM users
+WHERE id > $1
+ORDER BY id
+LIMIT $2
+`
+
+type ListUserEmailsParams struct {
+	ID    int32 `json:"id"`
+	Limit int32 `json:"limit"`
+}
+
+type ListUserEmailsRow struct {
+	ID        int32          `json:"id"`
+	Email     string         `json:"email"`
+	EmailHash sql.NullString `json:"email_hash"`
+}
+
+func (q *Queries) ListUserEmails(ctx context.Context, arg ListUserEmailsParams) ([]*ListUserEmailsRow, error) {
+	rows, err := q.db.QueryContext(ctx, ListUserEmails, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*ListUserEmailsRow{}
+	for rows.Next() {
+		var i ListUserEmailsRow
+		if err := rows.Scan(&i.ID, &i.Email, &i.EmailHash); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const PurgeDeletedUsers = `-- name: PurgeDeletedUsers :execrows
+DELETE FROM users
+WHERE deleted_at > 0 AND deleted_at < $1 AND anonymized_at = 0
+`
+
+func (q *Queries) PurgeDeletedUsers(ctx context.Context, deletedAt int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, PurgeDeletedUsers, deletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const ReencryptUserEmail = `-- name: ReencryptUserEmail :execrows
+UPDATE users
+SET email = $1, email_hash = $2
+WHERE id = $3 AND email = $4
+`
+
+type ReencryptUserEmailParams struct {
+	Email     string         `json:"email"`
+	EmailHash sql.NullString `json:"email_hash"`
+	ID        int32          `json:"id"`
+	OldEmail  string         `json:"old_email"`
+}
+
+func (q *Queries) ReencryptUserEmail(ctx context.Context, arg ReencryptUserEmailParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, ReencryptUserEmail,
+		arg.Email,
+		arg.EmailHash,
+		arg.ID,
+		arg.OldEmail,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const RehashUserPassword = `-- name: RehashUserPassword :exec
+UPDATE users
+SET password = $2
+WHERE id = $1 AND deleted_at = 0
+`
+
+type RehashUserPasswordParams struct {
+	ID       int32  `json:"id"`
+	Password string `json:"password"`
+}
+
+func (q *Queries) RehashUserPassword(ctx context.Context, arg RehashUserPasswordParams) error {
+	_, err := q.db.ExecContext(ctx, RehashUserPassword, arg.ID, arg.Password)
+	return err
+}
+
+const RestoreUser = `-- name: RestoreUser :execrows
+UPDATE users
+SET deleted_at = 0, updated_at = $2, version = version + 1
+WHERE id = $1 AND deleted_at > 0 AND anonymized_at = 0
+`
+
+type RestoreUserParams struct {
+	ID        int32 `json:"id"`
+	UpdatedAt int64 `json:"updated_at"`
+}
+
+func (q *Queries) RestoreUser(ctx context.Context, arg RestoreUserParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, RestoreUser, arg.ID, arg.UpdatedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const RevokeUserSessions = `-- name: RevokeUserSessions :exec
+UPDATE users
+SET sessions_revoked_at = $2
+WHERE id = $1 AND deleted_at = 0
+`
+
+type RevokeUserSessionsParams struct {
+	ID                int32 `json:"id"`
+	SessionsRevokedAt int64 `json:"sessions_revoked_at"`
+}
+
+func (q *Queries) RevokeUserSessions(ctx context.Context, arg RevokeUserSessionsParams) error {
+	_, err := q.db.ExecContext(ctx, RevokeUserSessions, arg.ID, arg.SessionsRevokedAt)
+	return err
+}
+
+const SoftDeleteUser = `-- name: SoftDeleteUser :execrows
+UPDATE users
+SET deleted_at = $2, version = version + 1
+WHERE id = $1 AND version = $3 AND deleted_at = 0
+`
+
+type SoftDeleteUserParams struct {
+	ID        int32 `json:"id"`
+	DeletedAt int64 `json:"deleted_at"`
+	Version   int32 `json:"version"`
+}
+
+func (q *Queries) SoftDeleteUser(ctx context.Context, arg SoftDeleteUserParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, SoftDeleteUser, arg.ID, arg.DeletedAt, arg.Version)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const UpdateUserEmail = `-- name: UpdateUserEmail :exec
+UPDATE users
+SET email = $2, email_hash = $3, updated_at = $4, version = version + 1
+WHERE id = $1 AND deleted_at = 0
+`
+
+type UpdateUserEmailParams struct {
+	ID        int32          `json:"id"`
+	Email     string         `json:"email"`
+	EmailHash sql.NullString `json:"email_hash"`
+	UpdatedAt int64          `json:"updated_at"`
+}
+
+func (q *Queries) UpdateUserEmail(ctx context.Context, arg UpdateUserEmailParams) error {
+	_, err := q.db.ExecContext(ctx, UpdateUserEmail,
+		arg.ID,
+		arg.Email,
+		arg.EmailHash,
+		arg.UpdatedAt,
+	)
+	return err
+}
+
+const UpdateUserName = `-- name: UpdateUserName :execrows
+UPDATE users
+SET name = $2, updated_at = $3, version = version + 1
+WHERE id = $1 AND version = $4 AND deleted_at = 0
+`
+
+type UpdateUserNameParams struct {
+	ID        int32  `json:"id"`
+	Name      string `json:"name"`
+	UpdatedAt int64  `json:"updated_at"`
+	Version   int32  `json:"version"`
+}
+
+func (q *Queries) UpdateUserName(ctx context.Context, arg UpdateUserNameParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, UpdateUserName,
+		arg.ID,
+		arg.Name,
+		arg.UpdatedAt,
+		arg.Version,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const UpdateUserPassword = `-- name: UpdateUserPassword :exec
+UPDATE users
+SET password = $2, updated_at = $3, version = version + 1
+WHERE id = $1 AND deleted_at = 0
+`
+
+type UpdateUserPasswordParams struct {
+	ID        int32  `json:"id"`
+	Password  string `json:"password"`
+	UpdatedAt int64  `json:"updated_at"`
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error {
+	_, err := q.db.ExecContext(ctx, UpdateUserPassword, arg.ID, arg.Password, arg.UpdatedAt)
+	return err
+}
@@ -0,0 +1,139 @@
+package metrics
+
+import (
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+const namespace = "sistem06"
+
+// Metrics holds the application's prometheus collectors. Every method is
+// safe to call on a nil *Metrics so usecases can be built without it.
+//
+// With web.prefork enabled each child process keeps its own registry and
+// the scraper only sees the process that answered the request.
+type Metrics struct {
+	Registry *prometheus.Registry
+
+	httpRequests  *prometheus.HistogramVec
+	sessionOps    *prometheus.CounterVec
+	logins        *prometheus.CounterVec
+	lockouts      *prometheus.CounterVec
+	rateLimited   *prometheus.CounterVec
+	registrations *prometheus.CounterVec
+}
+
+func NewMetrics(db *sql.DB) *Metrics {
+	registry := prometheus.NewRegistry()
+
+	m := &Metrics{
+		Registry: registry,
+		httpRequests: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request latency by route, method and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		sessionOps: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "session_store",
+			Name:      "operations_total",
+			Help:      "Session storage operations by operation and result.",
+		}, []string{"operation", "result"}),
+		logins: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "auth",
+			Name:      "logins_total",
+			Help:      "Login attempts by result.",
+		}, []string{"result"}),
+		lockouts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "auth",
+			Name:      "lockouts_total",
+			Help:      "Login lockouts by scope (account or ip).",
+		}, []string{"scope"}),
+		rateLimited: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "rate_limited_total",
+			Help:      "Requests rejected by the rate limiter by rule.",
+		}, []string{"rule"}),
+		registrations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "users",
+			Name:      "registrations_total",
+			Help:      "User registrations by result.",
+		}, []string{"result"}),
+	}
+
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.sessionOps,
+		m.logins,
+		m.lockouts,
+		m.rateLimited,
+		m.registrations,
+	)
+	if db != nil {
+		registry.MustRegister(collectors.NewDBStatsCollector(db, namespace))
+	}
+
+	return m
+}
+
+func (m *Metrics) ObserveHTTPRequest(method string, route string, status int, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	m.httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Observe(duration.Seconds())
+}
+
+// LoginAttempt records a login outcome, e.g. "success", "invalid_credentials".
+func (m *Metrics) LoginAttempt(result string) {
+	if m == nil {
+		return
+	}
+	m.logins.WithLabelValues(result).Inc()
+}
+
+// Lockout records a temporary login lockout of an account or an IP.
+func (m *Metrics) Lockout(scope string) {
+	if m == nil {
+		return
+	}
+	m.lockouts.WithLabelValues(scope).Inc()
+}
+
+// RateLimited records a request rejected by a rate limit rule.
+func (m *Metrics) RateLimited(rule string) {
+	if m == nil {
+		return
+	}
+	m.rateLimited.WithLabelValues(rule).Inc()
+}
+
+// Registration records a registration outcome, e.g. "success", "conflict".
+func (m *Metrics) Registration(result string) {
+	if m == nil {
+		return
+	}
+	m.registrations.WithLabelValues(result).Inc()
+}
+
+func (m *Metrics) sessionOperation(operation string, err error) {
+	if m == nil {
+		return
+	}
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	m.sessionOps.WithLabelValues(operation, result).Inc()
+}
@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type instrumentedStorage struct {
+	storage fiber.Storage
+	metrics *Metrics
+}
+
+// InstrumentStorage counts every operation made against storage.
+func (m *Metrics) InstrumentStorage(storage fiber.Storage) fiber.Storage {
+	if m == nil {
+		return storage
+	}
+	return &instrumentedStorage{storage: storage, metrics: m}
+}
+
+func (s *instrumentedStorage) Get(key string) ([]byte, error) {
+	value, err := s.storage.Get(key)
+	s.metrics.sessionOperation("get", err)
+	return value, err
+}
+
+func (s *instrumentedStorage) Set(key string, val []byte, exp time.Duration) error {
+	err := s.storage.Set(key, val, exp)
+	s.metrics.sessionOperation("set", err)
+	return err
+}
+
+func (s *instrumentedStorage) Delete(key string) error {
+	err := s.storage.Delete(key)
+	s.metrics.sessionOperation("delete", err)
+	return err
+}
+
+func (s *instrumentedStorage) Reset() error {
+	err := s.storage.Reset()
+	s.metrics.sessionOperation("reset", err)
+	return err
+}
+
+func (s *instrumentedStorage) Close() error {
+	return s.storage.Close()
+}
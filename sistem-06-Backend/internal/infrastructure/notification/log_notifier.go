@@ -0,0 +1,49 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"sistem-06-Backend/internal/domain/entity"
+	"sistem-06-Backend/pkg"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LogNotifier writes notices to the application log. It stands in until a
+// mail or message transport is configured.
+type LogNotifier struct {
+	Log *logrus.Logger
+}
+
+func NewLogNotifier(log *logrus.Logger) *LogNotifier {
+	return &LogNotifier{
+		Log: log,
+	}
+}
+
+func (n *LogNotifier) AccountLocked(ctx context.Context, user *entity.User, until time.Time) error {
+	pkg.Logger(ctx, n.Log).WithFields(logrus.Fields{
+		"notice":       "account_locked",
+		"user_id":      user.ID,
+		"locked_until": until.UTC().Format(time.RFC3339),
+	}).Infof("Notifying %s about login lockout", user.Email)
+	return nil
+}
+
+func (n *LogNotifier) EmailChangeRequested(ctx context.Context, user *entity.User, newEmail string, token string) error {
+	pkg.Logger(ctx, n.Log).WithFields(logrus.Fields{
+		"notice":  "email_change_requested",
+		"user_id": user.ID,
+		"token":   token,
+	}).Infof("Asking %s to confirm the new email address", newEmail)
+	return nil
+}
+
+func (n *LogNotifier) PasswordChanged(ctx context.Context, user *entity.User) error {
+	pkg.Logger(ctx, n.Log).WithFields(logrus.Fields{
+		"notice":  "password_changed",
+		"user_id": user.ID,
+	}).Infof("Notifying %s about a password change", user.Email)
+	return nil
+}
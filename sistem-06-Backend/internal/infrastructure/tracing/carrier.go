@@ -0,0 +1,27 @@
+package tracing
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// HeaderCarrier adapts fiber request/response headers to
+// propagation.TextMapCarrier. Get reads the request, Set writes the response.
+type HeaderCarrier struct {
+	Ctx *fiber.Ctx
+}
+
+func (c HeaderCarrier) Get(key string) string {
+	return c.Ctx.Get(key)
+}
+
+func (c HeaderCarrier) Set(key string, value string) {
+	c.Ctx.Set(key, value)
+}
+
+func (c HeaderCarrier) Keys() []string {
+	keys := make([]string, 0)
+	c.Ctx.Request().Header.VisitAll(func(key, value []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
@@ -0,0 +1,61 @@
+package tracing
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "sistem-06-Backend/internal/infrastructure/tracing"
+
+// sqlc prefixes every generated statement with "-- name: <Query> :<kind>"
+var sqlcQueryName = regexp.MustCompile(`^-- name: (\w+)`)
+
+// QueryTracer is a pgx.QueryTracer creating one client span per statement.
+// It is installed on the shared pool, so sqlc queries made through
+// database/sql are traced as well.
+type QueryTracer struct {
+	tracer trace.Tracer
+}
+
+func NewQueryTracer() *QueryTracer {
+	return &QueryTracer{
+		tracer: otel.Tracer(instrumentationName),
+	}
+}
+
+func (t *QueryTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	name := "db.query"
+	attributes := []attribute.KeyValue{
+		semconv.DBSystemNamePostgreSQL,
+		semconv.DBQueryText(data.SQL),
+	}
+	if match := sqlcQueryName.FindStringSubmatch(data.SQL); match != nil {
+		name = "sqlc." + match[1]
+		attributes = append(attributes, semconv.DBOperationName(match[1]))
+	}
+
+	ctx, _ = t.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attributes...),
+	)
+	return ctx
+}
+
+func (t *QueryTracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+	defer span.End()
+
+	if data.Err != nil {
+		span.RecordError(data.Err)
+		span.SetStatus(codes.Error, data.Err.Error())
+		return
+	}
+	span.SetAttributes(attribute.Int64("db.rows_affected", data.CommandTag.RowsAffected()))
+}
@@ -0,0 +1,222 @@
+// Package fieldcrypt encrypts single column values with AES-256-GCM. Every
+// value gets its own data key, which is stored next to it wrapped by a key
+// of the keyring, so rotating a keyring key only re-wraps data keys:
+//
+//	enc:v1:<key id>:<wrapped data key>:<nonce and ciphertext>
+//
+// Encrypted values are random, so columns that are looked up by value also
+// store a blind index, an HMAC of the plaintext under a separate key.
+package fieldcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// KeySize is the length of keyring keys, data keys and the index key.
+const KeySize = 32
+
+const prefix = "enc:v1:"
+
+var (
+	ErrUnknownKey = errors.New("fieldcrypt: value is encrypted with an unknown key")
+	ErrMalformed  = errors.New("fieldcrypt: malformed encrypted value")
+)
+
+// Cipher holds the keyring. A nil Cipher stores values in the clear and
+// builds blind indexes with plain SHA-256, which is only fit for
+// development.
+type Cipher struct {
+	primary string
+	keys    map[string]cipher.AEAD
+	index   []byte
+}
+
+// New builds a keyring. New values are encrypted under primary; the other
+// keys only decrypt older values.
+func New(primary string, keys map[string][]byte, indexKey []byte) (*Cipher, error) {
+	if _, ok := keys[primary]; !ok {
+		return nil, fmt.Errorf("fieldcrypt: primary key %q is not in the keyring", primary)
+	}
+	if len(indexKey) != KeySize {
+		return nil, fmt.Errorf("fieldcrypt: index key must be %d bytes", KeySize)
+	}
+
+	c := &Cipher{primary: primary, keys: make(map[string]cipher.AEAD, len(keys)), index: indexKey}
+	for id, key := range keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("fieldcrypt: key id %q must be non-empty and without colons", id)
+		}
+		if len(key) != KeySize {
+			return nil, fmt.Errorf("fieldcrypt: key %q must be %d bytes", id, KeySize)
+		}
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+		c.keys[id] = aead
+	}
+	return c, nil
+}
+
+// Encrypted reports whether value was produced by Encrypt.
+func Encrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+// Encrypt seals plaintext for column, e.g. "users.email". The column is
+// authenticated, so a value copied into another column fails to decrypt.
+func (c *Cipher) Encrypt(column string, plaintext string) (string, error) {
+	if c == nil {
+		return plaintext, nil
+	}
+
+	dataKey := make([]byte, KeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+	data, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	wrapped, err := seal(c.keys[c.primary], dataKey, []byte(c.primary))
+	if err != nil {
+		return "", err
+	}
+	sealed, err := seal(data, []byte(plaintext), []byte(column))
+	if err != nil {
+		return "", err
+	}
+	return prefix + c.primary + ":" + encode(wrapped) + ":" + encode(sealed), nil
+}
+
+// Decrypt opens a value of column. Values that were never encrypted, such
+// as rows written before encryption was turned on, are returned as they
+// are.
+func (c *Cipher) Decrypt(column string, value string) (string, error) {
+	if !Encrypted(value) {
+		return value, nil
+	}
+	id, wrapped, sealed, err := c.parse(value)
+	if err != nil {
+		return "", err
+	}
+	dataKey, err := open(c.keys[id], wrapped, []byte(id))
+	if err != nil {
+		return "", err
+	}
+	data, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(data, sealed, []byte(column))
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// Rotate brings a value of column up to the primary key: plain values are
+// encrypted and data keys wrapped by an older key are re-wrapped, leaving
+// the ciphertext as it is. It reports false when value is already current.
+func (c *Cipher) Rotate(column string, value string) (string, bool, error) {
+	if c == nil {
+		return value, false, nil
+	}
+	if !Encrypted(value) {
+		encrypted, err := c.Encrypt(column, value)
+		return encrypted, err == nil, err
+	}
+
+	id, wrapped, sealed, err := c.parse(value)
+	if err != nil {
+		return "", false, err
+	}
+	if id == c.primary {
+		return value, false, nil
+	}
+	dataKey, err := open(c.keys[id], wrapped, []byte(id))
+	if err != nil {
+		return "", false, err
+	}
+	rewrapped, err := seal(c.keys[c.primary], dataKey, []byte(c.primary))
+	if err != nil {
+		return "", false, err
+	}
+	return prefix + c.primary + ":" + encode(rewrapped) + ":" + encode(sealed), true, nil
+}
+
+// BlindIndex is the lookup hash of value in column. Equal values of one
+// column always get the same index; the index key cannot be rotated
+// without recomputing every index.
+func (c *Cipher) BlindIndex(column string, value string) string {
+	var mac []byte
+	if c == nil {
+		sum := sha256.Sum256([]byte(column + "\x00" + value))
+		mac = sum[:]
+	} else {
+		h := hmac.New(sha256.New, c.index)
+		h.Write([]byte(column + "\x00" + value))
+		mac = h.Sum(nil)
+	}
+	return hex.EncodeToString(mac)
+}
+
+func (c *Cipher) parse(value string) (string, []byte, []byte, error) {
+	parts := strings.Split(strings.TrimPrefix(value, prefix), ":")
+	if len(parts) != 3 {
+		return "", nil, nil, ErrMalformed
+	}
+	if c == nil || c.keys[parts[0]] == nil {
+		return "", nil, nil, ErrUnknownKey
+	}
+	wrapped, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", nil, nil, ErrMalformed
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", nil, nil, ErrMalformed
+	}
+	return parts[0], wrapped, sealed, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal prepends a random nonce to the ciphertext.
+func seal(aead cipher.AEAD, plaintext []byte, additional []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additional), nil
+}
+
+func open(aead cipher.AEAD, sealed []byte, additional []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrMalformed
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], additional)
+	if err != nil {
+		return nil, fmt.Errorf("fieldcrypt: %w", err)
+	}
+	return plaintext, nil
+}
+
+func encode(b []byte) string {
+	return base64.RawStdEncoding.EncodeToString(b)
+}
@@ -0,0 +1,163 @@
+// Package listquery parses the query string of list endpoints and compiles
+// it to parameterized SQL against a whitelist of fields.
+//
+//	?filter[kota]=Bandung&filter[created_at][gte]=1700000000&sort=-created_at,name&page[size]=20&page[cursor]=
+//
+// page[number] selects offset pagination, page[cursor] (empty for the first
+// page) selects keyset pagination. page[total]=false skips the count query.
+package listquery
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Filter operators. A filter without an operator is Eq; In takes a comma
+// separated list and Like matches a case-insensitive substring.
+const (
+	Eq   = "eq"
+	Ne   = "ne"
+	Lt   = "lt"
+	Lte  = "lte"
+	Gt   = "gt"
+	Gte  = "gte"
+	Like = "like"
+	In   = "in"
+)
+
+var operators = map[string]string{
+	Eq:  "=",
+	Ne:  "<>",
+	Lt:  "<",
+	Lte: "<=",
+	Gt:  ">",
+	Gte: ">=",
+}
+
+// maxInValues bounds the placeholders a single In filter expands to.
+const maxInValues = 100
+
+type Request struct {
+	Filters   []Filter
+	Sort      []Sort
+	Size      int
+	Number    int
+	Cursor    string
+	Keyset    bool
+	SkipTotal bool
+}
+
+type Filter struct {
+	Field    string
+	Operator string
+	Value    string
+}
+
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// Error is a problem with one query parameter; callers answer it with 400.
+type Error struct {
+	Param   string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Param + ": " + e.Message
+}
+
+// Parse reads the list parameters out of query and ignores every other key.
+// Field names are only checked against a schema by Compile.
+func Parse(query map[string]string) (*Request, error) {
+	request := &Request{}
+	for key, value := range query {
+		switch {
+		case strings.HasPrefix(key, "filter[") && strings.HasSuffix(key, "]"):
+			filter, err := parseFilter(key, value)
+			if err != nil {
+				return nil, err
+			}
+			request.Filters = append(request.Filters, filter)
+		case key == "sort":
+			request.Sort = ParseSort(value)
+		case key == "page[size]":
+			size, err := strconv.Atoi(value)
+			if err != nil || size < 1 {
+				return nil, &Error{Param: key, Message: "must be a positive number"}
+			}
+			request.Size = size
+		case key == "page[number]":
+			number, err := strconv.Atoi(value)
+			if err != nil || number < 1 {
+				return nil, &Error{Param: key, Message: "must be a positive number"}
+			}
+			request.Number = number
+		case key == "page[cursor]":
+			request.Cursor = value
+			request.Keyset = true
+		case key == "page[total]":
+			total, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, &Error{Param: key, Message: "must be true or false"}
+			}
+			request.SkipTotal = !total
+		}
+	}
+	if request.Keyset && request.Number > 0 {
+		return nil, &Error{Param: "page[cursor]", Message: "cannot be combined with page[number]"}
+	}
+
+	// map order is random; keep the generated SQL stable
+	sort.Slice(request.Filters, func(i, j int) bool {
+		a, b := request.Filters[i], request.Filters[j]
+		if a.Field != b.Field {
+			return a.Field < b.Field
+		}
+		return a.Operator < b.Operator
+	})
+	return request, nil
+}
+
+// ParseSort reads "-created_at,name": a leading minus sorts descending.
+func ParseSort(value string) []Sort {
+	var sorts []Sort
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		desc := strings.HasPrefix(field, "-")
+		sorts = append(sorts, Sort{Field: strings.TrimPrefix(field, "-"), Desc: desc})
+	}
+	return sorts
+}
+
+func parseFilter(key, value string) (Filter, error) {
+	parts := strings.Split(strings.TrimSuffix(strings.TrimPrefix(key, "filter["), "]"), "][")
+	filter := Filter{Field: parts[0], Operator: Eq, Value: value}
+	switch {
+	case len(parts) == 2:
+		filter.Operator = parts[1]
+	case len(parts) > 2:
+		return filter, &Error{Param: key, Message: "malformed filter"}
+	}
+	if _, ok := operators[filter.Operator]; !ok && filter.Operator != Like && filter.Operator != In {
+		return filter, &Error{Param: key, Message: fmt.Sprintf("unknown operator %q", filter.Operator)}
+	}
+	return filter, nil
+}
+
+func encodeSort(sorts []Sort) string {
+	fields := make([]string, len(sorts))
+	for i, s := range sorts {
+		fields[i] = s.Field
+		if s.Desc {
+			fields[i] = "-" + s.Field
+		}
+	}
+	return strings.Join(fields, ",")
+}
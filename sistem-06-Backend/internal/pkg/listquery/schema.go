@@ -0,0 +1,319 @@
+package listquery
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"sistem-06-Backend/pkg"
+)
+
+type Type int
+
+const (
+	Text Type = iota
+	Integer
+)
+
+// Field maps a public field name to its column. Value reads the field from
+// a loaded item to build keyset cursors, so sortable fields need it. Hash
+// is set for columns holding a blind index instead of the value: filter
+// values go through it, and only eq, ne and in apply.
+type Field[T any] struct {
+	Column   string
+	Type     Type
+	Sortable bool
+	Value    func(item T) any
+	Hash     func(value string) string
+}
+
+// Schema describes one listable resource. Only the names in Fields can be
+// filtered or sorted on; Key names the unique field that breaks ties so
+// keyset pagination never skips or repeats rows. CountTotal is left off for
+// tables too large to count on every request.
+type Schema[T any] struct {
+	Table       string
+	Columns     string
+	Where       string
+	Fields      map[string]Field[T]
+	Key         string
+	DefaultSort []Sort
+	DefaultSize int
+	MaxSize     int
+	CountTotal  bool
+}
+
+type Query struct {
+	SQL  string
+	Args []any
+}
+
+// Plan is a compiled Request. Count is nil when no total is wanted.
+type Plan struct {
+	List   Query
+	Count  *Query
+	size   int
+	number int
+	keyset bool
+	sort   []Sort
+}
+
+// cursor is the position after the last row of a keyset page. Sort guards
+// against reusing a cursor with a different ordering.
+type cursor struct {
+	Sort   string `json:"s"`
+	Values []any  `json:"v"`
+}
+
+func (s *Schema[T]) Compile(request *Request) (*Plan, error) {
+	plan := &Plan{size: request.Size, number: request.Number, keyset: request.Keyset, sort: request.Sort}
+	if plan.size == 0 {
+		plan.size = s.DefaultSize
+	}
+	if plan.size > s.MaxSize {
+		return nil, &Error{Param: "page[size]", Message: fmt.Sprintf("must be at most %d", s.MaxSize)}
+	}
+	if plan.number == 0 {
+		plan.number = 1
+	}
+	if len(plan.sort) == 0 {
+		plan.sort = s.DefaultSort
+	}
+
+	conditions, args, order, sorts, err := s.clauses(request.Filters, plan.sort)
+	if err != nil {
+		return nil, err
+	}
+	plan.sort = sorts
+
+	if !request.SkipTotal && s.CountTotal {
+		plan.Count = &Query{
+			SQL:  "SELECT count(*) FROM " + s.Table + where(conditions),
+			Args: append([]any(nil), args...),
+		}
+	}
+
+	if request.Cursor != "" {
+		condition, err := s.after(request.Cursor, plan.sort, &args)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, condition)
+	}
+
+	// one extra row tells whether another page follows
+	sql := "SELECT " + s.Columns + " FROM " + s.Table + where(conditions) +
+		" ORDER BY " + strings.Join(order, ", ") + " LIMIT " + strconv.Itoa(plan.size+1)
+	if !plan.keyset && plan.number > 1 {
+		sql += " OFFSET " + strconv.Itoa((plan.number-1)*plan.size)
+	}
+	plan.List = Query{SQL: sql, Args: args}
+	return plan, nil
+}
+
+// CompileAll compiles the filters and sort of request into a single query
+// over every matching row, for exports. Paging parameters are ignored.
+func (s *Schema[T]) CompileAll(request *Request) (*Query, error) {
+	sorts := request.Sort
+	if len(sorts) == 0 {
+		sorts = s.DefaultSort
+	}
+	conditions, args, order, _, err := s.clauses(request.Filters, sorts)
+	if err != nil {
+		return nil, err
+	}
+	return &Query{
+		SQL:  "SELECT " + s.Columns + " FROM " + s.Table + where(conditions) + " ORDER BY " + strings.Join(order, ", "),
+		Args: args,
+	}, nil
+}
+
+// clauses turns filters into WHERE conditions and sorts into ORDER BY terms.
+// The key is appended to the returned sorts unless they already hold it.
+func (s *Schema[T]) clauses(filters []Filter, sorts []Sort) ([]string, []any, []string, []Sort, error) {
+	var args []any
+	var conditions []string
+	if s.Where != "" {
+		conditions = append(conditions, s.Where)
+	}
+	for _, filter := range filters {
+		condition, err := s.filter(filter, &args)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		conditions = append(conditions, condition)
+	}
+
+	order := make([]string, 0, len(sorts)+1)
+	hasKey := false
+	for _, sort := range sorts {
+		field, ok := s.Fields[sort.Field]
+		if !ok || !field.Sortable {
+			return nil, nil, nil, nil, &Error{Param: "sort", Message: fmt.Sprintf("cannot sort by %q", sort.Field)}
+		}
+		hasKey = hasKey || sort.Field == s.Key
+		order = append(order, orderBy(field.Column, sort.Desc))
+	}
+	if !hasKey {
+		sorts = append(sorts[:len(sorts):len(sorts)], Sort{Field: s.Key})
+		order = append(order, orderBy(s.Fields[s.Key].Column, false))
+	}
+	return conditions, args, order, sorts, nil
+}
+
+// Page trims the extra row loaded by the list query and describes the page.
+// TotalItem and TotalPage are -1 when the total was not counted.
+func (s *Schema[T]) Page(plan *Plan, items []T, total int64) ([]T, pkg.PageMetadata) {
+	metadata := pkg.PageMetadata{Size: plan.size, TotalItem: -1, TotalPage: -1}
+	if plan.Count != nil {
+		metadata.TotalItem = total
+		metadata.TotalPage = (total + int64(plan.size) - 1) / int64(plan.size)
+	}
+	more := len(items) > plan.size
+	if more {
+		items = items[:plan.size]
+	}
+	if !plan.keyset {
+		metadata.Page = plan.number
+		return items, metadata
+	}
+	if more {
+		last := items[len(items)-1]
+		next := cursor{Sort: encodeSort(plan.sort), Values: make([]any, len(plan.sort))}
+		for i, sort := range plan.sort {
+			next.Values[i] = s.Fields[sort.Field].Value(last)
+		}
+		encoded, _ := json.Marshal(next)
+		metadata.NextCursor = base64.RawURLEncoding.EncodeToString(encoded)
+	}
+	return items, metadata
+}
+
+func (s *Schema[T]) filter(filter Filter, args *[]any) (string, error) {
+	param := "filter[" + filter.Field + "]"
+	field, ok := s.Fields[filter.Field]
+	if !ok {
+		return "", &Error{Param: param, Message: "unknown field"}
+	}
+
+	if field.Hash != nil && filter.Operator != Eq && filter.Operator != Ne && filter.Operator != In {
+		return "", &Error{Param: param, Message: "only eq, ne and in apply to this field"}
+	}
+
+	switch filter.Operator {
+	case Like:
+		if field.Type != Text {
+			return "", &Error{Param: param, Message: "like only applies to text fields"}
+		}
+		return field.Column + " ILIKE " + bind(args, "%"+escapeLike(filter.Value)+"%"), nil
+	case In:
+		values := strings.Split(filter.Value, ",")
+		if len(values) > maxInValues {
+			return "", &Error{Param: param, Message: fmt.Sprintf("at most %d values", maxInValues)}
+		}
+		placeholders := make([]string, len(values))
+		for i, value := range values {
+			converted, err := field.convert(strings.TrimSpace(value))
+			if err != nil {
+				return "", &Error{Param: param, Message: err.Error()}
+			}
+			placeholders[i] = bind(args, converted)
+		}
+		return field.Column + " IN (" + strings.Join(placeholders, ", ") + ")", nil
+	}
+
+	value, err := field.convert(filter.Value)
+	if err != nil {
+		return "", &Error{Param: param, Message: err.Error()}
+	}
+	return field.Column + " " + operators[filter.Operator] + " " + bind(args, value), nil
+}
+
+func (f Field[T]) convert(value string) (any, error) {
+	if f.Hash != nil {
+		return f.Hash(value), nil
+	}
+	return convert(f.Type, value)
+}
+
+// after expands the keyset condition for sorts, e.g. for "-created_at,id":
+// created_at < $1 OR (created_at = $1 AND id > $2).
+func (s *Schema[T]) after(encoded string, sorts []Sort, args *[]any) (string, error) {
+	invalid := &Error{Param: "page[cursor]", Message: "invalid cursor"}
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", invalid
+	}
+	// numbers stay json.Number so large ids survive the round trip
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var position cursor
+	if err := decoder.Decode(&position); err != nil || len(position.Values) != len(sorts) {
+		return "", invalid
+	}
+	if position.Sort != encodeSort(sorts) {
+		return "", &Error{Param: "page[cursor]", Message: "cursor belongs to another sort order"}
+	}
+
+	placeholders := make([]string, len(sorts))
+	for i, sort := range sorts {
+		field := s.Fields[sort.Field]
+		value, err := convert(field.Type, fmt.Sprint(position.Values[i]))
+		if err != nil {
+			return "", invalid
+		}
+		placeholders[i] = bind(args, value)
+	}
+
+	alternatives := make([]string, len(sorts))
+	for i, sort := range sorts {
+		terms := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			terms = append(terms, s.Fields[sorts[j].Field].Column+" = "+placeholders[j])
+		}
+		op := " > "
+		if sort.Desc {
+			op = " < "
+		}
+		terms = append(terms, s.Fields[sort.Field].Column+op+placeholders[i])
+		alternatives[i] = "(" + strings.Join(terms, " AND ") + ")"
+	}
+	return "(" + strings.Join(alternatives, " OR ") + ")", nil
+}
+
+func convert(kind Type, value string) (any, error) {
+	if kind == Integer {
+		number, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", value)
+		}
+		return number, nil
+	}
+	return value, nil
+}
+
+func bind(args *[]any, value any) string {
+	*args = append(*args, value)
+	return "$" + strconv.Itoa(len(*args))
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+func orderBy(column string, desc bool) string {
+	if desc {
+		return column + " DESC"
+	}
+	return column + " ASC"
+}
+
+func where(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(conditions, " AND ")
+}
@@ -0,0 +1,102 @@
+// Package mask hides most of a personal identifier while keeping enough of
+// it to be recognized by someone who already knows it.
+package mask
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Email keeps the first character of the local part and the domain:
+// "budi.santoso@example.com" becomes "b***@example.com".
+func Email(email string) string {
+	local, domain, found := strings.Cut(email, "@")
+	if !found {
+		return Text(email)
+	}
+	if local == "" {
+		return "***@" + domain
+	}
+	return string([]rune(local)[0]) + "***@" + domain
+}
+
+// Text hides all but the first character.
+func Text(text string) string {
+	if text == "" {
+		return ""
+	}
+	return string([]rune(text)[0]) + "***"
+}
+
+// Viewer is who a response is masked for. Allowed reports whether the
+// viewer holds a permission.
+type Viewer struct {
+	ID      int
+	Allowed func(permission string) bool
+}
+
+// Apply masks, in place, the string fields of the structs reachable from v
+// that are tagged for a permission the viewer lacks:
+//
+//	Email string `mask:"email,users.manage"`
+//
+// The first part picks how the value is masked: email, text (see Email and
+// Text) or hide, which empties it, as does any other kind. A field tagged
+// `mask:"owner"` holds the id of the person the struct describes; nothing of
+// a struct whose owner is the viewer is masked. Pointers, slices, maps and
+// interfaces are followed.
+func Apply(v any, viewer Viewer) {
+	apply(reflect.ValueOf(v), viewer)
+}
+
+func apply(v reflect.Value, viewer Viewer) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			apply(v.Elem(), viewer)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			apply(v.Index(i), viewer)
+		}
+	case reflect.Map:
+		// map values cannot be set in place, only what they point to
+		for _, key := range v.MapKeys() {
+			apply(v.MapIndex(key), viewer)
+		}
+	case reflect.Struct:
+		applyStruct(v, viewer)
+	}
+}
+
+func applyStruct(v reflect.Value, viewer Viewer) {
+	t := v.Type()
+	for i := range t.NumField() {
+		if t.Field(i).Tag.Get("mask") == "owner" && v.Field(i).CanInt() && int(v.Field(i).Int()) == viewer.ID {
+			return
+		}
+	}
+
+	for i := range t.NumField() {
+		field := v.Field(i)
+		if !t.Field(i).IsExported() {
+			continue
+		}
+		kind, permission, found := strings.Cut(t.Field(i).Tag.Get("mask"), ",")
+		if !found {
+			apply(field, viewer)
+			continue
+		}
+		if field.Kind() != reflect.String || !field.CanSet() || (viewer.Allowed != nil && viewer.Allowed(permission)) {
+			continue
+		}
+		switch kind {
+		case "email":
+			field.SetString(Email(field.String()))
+		case "text":
+			field.SetString(Text(field.String()))
+		default:
+			field.SetString("")
+		}
+	}
+}
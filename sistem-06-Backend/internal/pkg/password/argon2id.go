@@ -0,0 +1,98 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Argon2id hashes with argon2id. Memory is in KiB. Hashes are encoded as
+// $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<hash>
+// with unpadded base64, like the reference implementation.
+type Argon2id struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+type argon2idHash struct {
+	version     int
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	salt        []byte
+	key         []byte
+}
+
+func (a *Argon2id) Hash(password string) (string, error) {
+	salt := make([]byte, a.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, a.Iterations, a.Memory, a.Parallelism, a.KeyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, a.Memory, a.Iterations, a.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// Verify uses the parameters stored in encoded, not the configured ones.
+func (a *Argon2id) Verify(password string, encoded string) (bool, error) {
+	hash, err := decodeArgon2id(encoded)
+	if err != nil {
+		return false, err
+	}
+	key := argon2.IDKey([]byte(password), hash.salt, hash.iterations, hash.memory, hash.parallelism, uint32(len(hash.key)))
+	return subtle.ConstantTimeCompare(key, hash.key) == 1, nil
+}
+
+func (a *Argon2id) Identify(encoded string) bool {
+	return strings.HasPrefix(encoded, "$argon2id$")
+}
+
+func (a *Argon2id) NeedsRehash(encoded string) bool {
+	hash, err := decodeArgon2id(encoded)
+	if err != nil {
+		return true
+	}
+	return hash.version != argon2.Version ||
+		hash.memory != a.Memory ||
+		hash.iterations != a.Iterations ||
+		hash.parallelism != a.Parallelism ||
+		uint32(len(hash.salt)) != a.SaltLength ||
+		uint32(len(hash.key)) != a.KeyLength
+}
+
+func decodeArgon2id(encoded string) (*argon2idHash, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return nil, ErrUnknownHash
+	}
+
+	hash := &argon2idHash{}
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &hash.version); err != nil {
+		return nil, fmt.Errorf("password: argon2id version: %w", err)
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &hash.memory, &hash.iterations, &hash.parallelism); err != nil {
+		return nil, fmt.Errorf("password: argon2id parameters: %w", err)
+	}
+
+	var err error
+	if hash.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return nil, fmt.Errorf("password: argon2id salt: %w", err)
+	}
+	if hash.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil {
+		return nil, fmt.Errorf("password: argon2id hash: %w", err)
+	}
+	if len(hash.key) == 0 || hash.iterations == 0 || hash.parallelism == 0 {
+		return nil, fmt.Errorf("password: argon2id hash is malformed")
+	}
+	return hash, nil
+}
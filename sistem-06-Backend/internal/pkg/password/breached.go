@@ -0,0 +1,97 @@
+package password
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// breached_passwords.txt holds the SHA-1 of common and breached passwords,
+// one upper-case hash per line, so the passwords themselves are not shipped.
+//
+//go:embed breached_passwords.txt
+var bundledList []byte
+
+const prefixLength = 5
+
+// BreachedList answers whether a password appears in a list of known
+// passwords. Hashes are grouped by their first five hex characters, the
+// layout of k-anonymity range lookups, so a bigger list or a remote range
+// service can be swapped in without changing callers.
+type BreachedList struct {
+	ranges map[string]map[string]struct{}
+}
+
+// LoadBreachedList reads "HASH" or "HASH:COUNT" lines of SHA-1 hex, the
+// format of the Have I Been Pwned downloads. Blank lines and lines starting
+// with # are skipped.
+func LoadBreachedList(r io.Reader) (*BreachedList, error) {
+	list := &BreachedList{ranges: map[string]map[string]struct{}{}}
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		hash, _, _ := strings.Cut(text, ":")
+		hash = strings.ToUpper(hash)
+		if len(hash) != sha1.Size*2 {
+			return nil, fmt.Errorf("line %d: not a SHA-1 hash", line)
+		}
+		if _, err := hex.DecodeString(hash); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		list.add(hash)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// LoadBreachedListFile loads path, or the bundled list when path is empty.
+func LoadBreachedListFile(path string) (*BreachedList, error) {
+	if path == "" {
+		return LoadBreachedList(bytes.NewReader(bundledList))
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return LoadBreachedList(file)
+}
+
+func (l *BreachedList) add(hash string) {
+	prefix, suffix := hash[:prefixLength], hash[prefixLength:]
+	suffixes, ok := l.ranges[prefix]
+	if !ok {
+		suffixes = map[string]struct{}{}
+		l.ranges[prefix] = suffixes
+	}
+	suffixes[suffix] = struct{}{}
+}
+
+// Contains reports whether password is on the list.
+func (l *BreachedList) Contains(password string) bool {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	_, found := l.ranges[hash[:prefixLength]][hash[prefixLength:]]
+	return found
+}
+
+// Len returns the number of hashes on the list.
+func (l *BreachedList) Len() int {
+	n := 0
+	for _, suffixes := range l.ranges {
+		n += len(suffixes)
+	}
+	return n
+}
@@ -0,0 +1,71 @@
+package password
+
+import (
+	"errors"
+)
+
+// MaxLength is the longest password any configuration accepts. Logins are
+// capped at it too, so an oversized body never reaches a slow hash.
+const MaxLength = 1024
+
+// ErrUnknownHash is returned for a stored hash no registered Hasher can read.
+var ErrUnknownHash = errors.New("password: unknown hash format")
+
+// Hasher turns passwords into self-describing encoded hashes: the algorithm
+// and its parameters are part of the string, in the PHC string format
+// ($id$params$salt$hash; bcrypt keeps its own $2b$cost$... layout).
+type Hasher interface {
+	Hash(password string) (string, error)
+	// Verify reports whether password matches encoded. A mismatch is not an
+	// error; a malformed hash is.
+	Verify(password string, encoded string) (bool, error)
+	// Identify reports whether encoded was produced by this algorithm.
+	Identify(encoded string) bool
+	// NeedsRehash reports whether encoded should be replaced by a new Hash,
+	// because its algorithm or parameters differ from the configured ones.
+	NeedsRehash(encoded string) bool
+}
+
+// NewHasher hashes new passwords with current and still verifies the hashes
+// of the legacy algorithms, so switching algorithm does not lock anybody
+// out. Those hashes report NeedsRehash until the user logs in again.
+func NewHasher(current Hasher, legacy ...Hasher) Hasher {
+	return &multiHasher{current: current, legacy: legacy}
+}
+
+type multiHasher struct {
+	current Hasher
+	legacy  []Hasher
+}
+
+func (h *multiHasher) Hash(password string) (string, error) {
+	return h.current.Hash(password)
+}
+
+func (h *multiHasher) Verify(password string, encoded string) (bool, error) {
+	hasher := h.find(encoded)
+	if hasher == nil {
+		return false, ErrUnknownHash
+	}
+	return hasher.Verify(password, encoded)
+}
+
+func (h *multiHasher) Identify(encoded string) bool {
+	return h.find(encoded) != nil
+}
+
+func (h *multiHasher) NeedsRehash(encoded string) bool {
+	return !h.current.Identify(encoded) || h.current.NeedsRehash(encoded)
+}
+
+func (h *multiHasher) find(encoded string) Hasher {
+	if h.current.Identify(encoded) {
+		return h.current
+	}
+	for _, hasher := range h.legacy {
+		if hasher.Identify(encoded) {
+			return hasher
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package password
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Policy decides which passwords are accepted. A nil Breached list skips
+// the breached-password check. MinLength and MaxLength count characters,
+// MaxBytes, if set, caps the UTF-8 length for hashers such as bcrypt.
+type Policy struct {
+	MinLength           int
+	MaxLength           int
+	MaxBytes            int
+	MinCharacterClasses int
+	RequireUppercase    bool
+	RequireLowercase    bool
+	RequireDigit        bool
+	RequireSymbol       bool
+	RejectPersonalInfo  bool
+	Breached            *BreachedList
+}
+
+// minPersonalLength keeps very short names, such as "Al", from rejecting
+// half of all passwords.
+const minPersonalLength = 3
+
+// Check returns every rule password breaks, or nil. personal holds values
+// the password must not contain, such as the user's email and name.
+func (p *Policy) Check(password string, personal ...string) []string {
+	var violations []string
+
+	length := len([]rune(password))
+	if length < p.MinLength {
+		violations = append(violations, fmt.Sprintf("must be at least %d characters", p.MinLength))
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		violations = append(violations, fmt.Sprintf("must be at most %d characters", p.MaxLength))
+	} else if p.MaxBytes > 0 && len(password) > p.MaxBytes {
+		violations = append(violations, fmt.Sprintf("must be at most %d bytes", p.MaxBytes))
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+	if p.RequireUppercase && !upper {
+		violations = append(violations, "must contain an uppercase letter")
+	}
+	if p.RequireLowercase && !lower {
+		violations = append(violations, "must contain a lowercase letter")
+	}
+	if p.RequireDigit && !digit {
+		violations = append(violations, "must contain a digit")
+	}
+	if p.RequireSymbol && !symbol {
+		violations = append(violations, "must contain a symbol")
+	}
+	if classes := count(upper, lower, digit, symbol); classes < p.MinCharacterClasses {
+		violations = append(violations, fmt.Sprintf("must mix at least %d of uppercase, lowercase, digits and symbols", p.MinCharacterClasses))
+	}
+
+	if p.RejectPersonalInfo && containsPersonal(password, personal) {
+		violations = append(violations, "must not contain your email or name")
+	}
+
+	if p.Breached != nil && p.Breached.Contains(password) {
+		violations = append(violations, "is too common or has appeared in a data breach")
+	}
+
+	return violations
+}
+
+func count(flags ...bool) int {
+	n := 0
+	for _, flag := range flags {
+		if flag {
+			n++
+		}
+	}
+	return n
+}
+
+// containsPersonal compares case-insensitively against each value, the
+// local part of emails and every word of names.
+func containsPersonal(password string, personal []string) bool {
+	lowered := strings.ToLower(password)
+	for _, value := range personal {
+		value = strings.ToLower(strings.TrimSpace(value))
+		candidates := []string{value, strings.ReplaceAll(value, " ", "")}
+		if local, _, found := strings.Cut(value, "@"); found {
+			candidates = append(candidates, local)
+		}
+		candidates = append(candidates, strings.Fields(value)...)
+
+		for _, candidate := range candidates {
+			if len([]rune(candidate)) >= minPersonalLength && strings.Contains(lowered, candidate) {
+				return true
+			}
+		}
+	}
+	return false
+}
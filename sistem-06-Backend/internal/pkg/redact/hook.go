@@ -0,0 +1,45 @@
+package redact
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Hook redacts the message and fields of every entry before it is
+// formatted.
+type Hook struct {
+	redactor *Redactor
+}
+
+func NewHook(redactor *Redactor) *Hook {
+	return &Hook{redactor: redactor}
+}
+
+func (h *Hook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *Hook) Fire(entry *logrus.Entry) error {
+	entry.Message = h.redactor.String(entry.Message)
+	for key, value := range entry.Data {
+		if h.redactor.Field(key) {
+			entry.Data[key] = Placeholder
+			continue
+		}
+		switch value := value.(type) {
+		case string:
+			entry.Data[key] = h.redactor.String(value)
+		case error:
+			// the JSON formatter would print err.Error() anyway
+			if text := value.Error(); h.redactor.String(text) != text {
+				entry.Data[key] = h.redactor.String(text)
+			}
+		case fmt.Stringer:
+			if text := value.String(); h.redactor.String(text) != text {
+				entry.Data[key] = h.redactor.String(text)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+// Package redact keeps personal data and secrets out of the logs. A
+// Redactor replaces values of sensitive keys, e.g. "password=hunter2" or
+// the Password field of a %+v dump, and anything matching its patterns,
+// e.g. email addresses, with Placeholder.
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const Placeholder = "[REDACTED]"
+
+// builtin holds the patterns of fields whose values are recognisable on
+// their own, so they are redacted wherever they appear, not only next to
+// their key.
+var builtin = map[string]string{
+	"email": `[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`,
+	// a Nomor Induk Kependudukan has 16 digits
+	"nik": `\b\d{16}\b`,
+	// Indonesian mobile numbers: 08..., 628... or +628...
+	"phone": `(?:\+62|\b62|\b0)8\d{7,11}\b`,
+}
+
+type Redactor struct {
+	fields   map[string]bool
+	keyValue *regexp.Regexp
+	patterns []*regexp.Regexp
+}
+
+// New redacts the values of the given field names and every match of
+// patterns. A field also covers keys ending in it, so "password" covers
+// "new_password" and "CurrentPassword"; email, nik and phone values are
+// also found without a key.
+func New(fields []string, patterns []string) (*Redactor, error) {
+	r := &Redactor{fields: make(map[string]bool, len(fields))}
+	quoted := make([]string, 0, len(fields))
+	for _, field := range fields {
+		field = strings.ToLower(field)
+		r.fields[field] = true
+		quoted = append(quoted, regexp.QuoteMeta(field))
+		if pattern, ok := builtin[field]; ok {
+			r.patterns = append(r.patterns, regexp.MustCompile(pattern))
+		}
+	}
+	if len(quoted) > 0 {
+		// key, separator, value: Password:hunter2, "email":"a@b.c", nik=123
+		r.keyValue = regexp.MustCompile(`(?i)\b([A-Za-z_]*(?:` + strings.Join(quoted, "|") + `))("?\s*[:=]\s*"?)([^\s,;&"'}\]]+)`)
+	}
+	for _, pattern := range patterns {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("redact pattern %q: %w", pattern, err)
+		}
+		r.patterns = append(r.patterns, compiled)
+	}
+	return r, nil
+}
+
+// Field reports whether values under key are redacted as a whole.
+func (r *Redactor) Field(key string) bool {
+	key = strings.ToLower(key)
+	for field := range r.fields {
+		if strings.HasSuffix(key, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// String redacts the sensitive parts of free text.
+func (r *Redactor) String(text string) string {
+	if r.keyValue != nil {
+		text = r.keyValue.ReplaceAllString(text, "${1}${2}"+Placeholder)
+	}
+	for _, pattern := range r.patterns {
+		text = pattern.ReplaceAllString(text, Placeholder)
+	}
+	return text
+}
@@ -0,0 +1,256 @@
+package spreadsheet
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Page layout in points: A4 landscape with a title, a bold header row and a
+// page number on every page.
+const (
+	pdfPageWidth  = 842.0
+	pdfPageHeight = 595.0
+	pdfMargin     = 36.0
+	pdfTitleSize  = 14.0
+	pdfFontSize   = 9.0
+	pdfRowHeight  = 14.0
+	pdfCellMargin = 4.0
+)
+
+// Objects 1 to 4 are known up front; pages get the numbers after them.
+const (
+	pdfCatalogObject = 1
+	pdfPagesObject   = 2
+	pdfFontObject    = 3
+	pdfBoldObject    = 4
+)
+
+// helveticaWidths holds the widths of the printable ASCII characters of
+// Helvetica in thousandths of the font size, from its AFM metrics. Other
+// characters but the ellipsis are taken to be as wide as a digit.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // space to /
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556, // 0 to ?
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778, // @ to O
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556, // P to _
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556, // ` to o
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584, // p to ~
+}
+
+// winAnsi maps the characters outside Latin-1 that the standard fonts can
+// still show to their WinAnsiEncoding byte.
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E, '‘': 0x91,
+	'’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98,
+	'™': 0x99, 'š': 0x9A, '›': 0x9B, 'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+type pdfWriter struct {
+	out    *bufio.Writer
+	offset int64
+	err    error
+	title  string
+	widths []float64
+	header []string
+	// file offsets of the objects, indexed by object number
+	offsets []int64
+	pages   []int
+	page    bytes.Buffer
+	y       float64
+}
+
+// NewPDFWriter lays the table out over as many pages as it needs, using the
+// standard Helvetica fonts so nothing has to be embedded. Only text that
+// WinAnsiEncoding covers is shown; other characters print as "?". Each page
+// is written out as soon as it is full.
+func NewPDFWriter(w io.Writer, title string) Writer {
+	writer := &pdfWriter{out: bufio.NewWriter(w), title: title, offsets: make([]int64, pdfBoldObject+1)}
+	// the comment of high bytes marks the file as binary
+	writer.printf("%%PDF-1.4\n%%\xE2\xE3\xCF\xD3\n")
+	writer.object(pdfFontObject)
+	writer.printf("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>\nendobj\n")
+	writer.object(pdfBoldObject)
+	writer.printf("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>\nendobj\n")
+	return writer
+}
+
+// Write takes the header first; its column count sets the column widths,
+// which are equal.
+func (w *pdfWriter) Write(cells []string) error {
+	if w.err != nil {
+		return w.err
+	}
+	if w.header == nil {
+		w.header = append([]string{}, cells...)
+		width := (pdfPageWidth - 2*pdfMargin) / float64(max(len(cells), 1))
+		w.widths = make([]float64, len(cells))
+		for i := range w.widths {
+			w.widths[i] = width
+		}
+		w.startPage()
+		return w.err
+	}
+
+	if w.y < pdfMargin+pdfRowHeight {
+		w.finishPage()
+		w.startPage()
+	}
+	w.row(cells, "F1")
+	return w.err
+}
+
+func (w *pdfWriter) Close() error {
+	if w.header == nil {
+		w.Write(nil)
+	}
+	w.finishPage()
+
+	w.object(pdfPagesObject)
+	w.printf("<< /Type /Pages /Kids [")
+	for _, page := range w.pages {
+		w.printf(" %d 0 R", page)
+	}
+	w.printf(" ] /Count %d >>\nendobj\n", len(w.pages))
+	w.object(pdfCatalogObject)
+	w.printf("<< /Type /Catalog /Pages %d 0 R >>\nendobj\n", pdfPagesObject)
+
+	xref := w.offset
+	w.printf("xref\n0 %d\n0000000000 65535 f \n", len(w.offsets))
+	for _, offset := range w.offsets[1:] {
+		w.printf("%010d 00000 n \n", offset)
+	}
+	w.printf("trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(w.offsets), pdfCatalogObject, xref)
+	if w.err != nil {
+		return w.err
+	}
+	return w.out.Flush()
+}
+
+func (w *pdfWriter) startPage() {
+	w.page.Reset()
+	top := pdfPageHeight - pdfMargin
+	w.text(pdfMargin, top-pdfTitleSize, "F2", pdfTitleSize, w.title)
+	w.y = top - pdfTitleSize - 2*pdfRowHeight
+	w.row(w.header, "F2")
+	// a rule under the header
+	line := w.y + pdfRowHeight - 4
+	fmt.Fprintf(&w.page, "0.5 w %.2f %.2f m %.2f %.2f l S\n", pdfMargin, line, pdfPageWidth-pdfMargin, line)
+}
+
+func (w *pdfWriter) finishPage() {
+	number := len(w.pages) + 1
+	w.text(pdfPageWidth-pdfMargin-60, pdfMargin/2, "F1", pdfFontSize, fmt.Sprintf("Page %d", number))
+
+	content := w.nextObject()
+	w.object(content)
+	w.printf("<< /Length %d >>\nstream\n", w.page.Len())
+	w.write(w.page.Bytes())
+	w.printf("\nendstream\nendobj\n")
+
+	page := w.nextObject()
+	w.object(page)
+	w.printf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 %d 0 R /F2 %d 0 R >> >> /Contents %d 0 R >>\nendobj\n",
+		pdfPagesObject, pdfPageWidth, pdfPageHeight, pdfFontObject, pdfBoldObject, content)
+	w.pages = append(w.pages, page)
+
+	if w.err == nil {
+		w.err = w.out.Flush()
+	}
+}
+
+// row draws cells on the current line, each cut to the width of its column.
+func (w *pdfWriter) row(cells []string, font string) {
+	x := pdfMargin
+	for i, width := range w.widths {
+		if i < len(cells) {
+			w.text(x+pdfCellMargin/2, w.y, font, pdfFontSize, fit(cells[i], width-pdfCellMargin, pdfFontSize))
+		}
+		x += width
+	}
+	w.y -= pdfRowHeight
+}
+
+func (w *pdfWriter) text(x float64, y float64, font string, size float64, text string) {
+	fmt.Fprintf(&w.page, "BT /%s %.0f Tf %.2f %.2f Td (", font, size, x, y)
+	for _, b := range encodeWinAnsi(text) {
+		if b == '(' || b == ')' || b == '\\' {
+			w.page.WriteByte('\\')
+		}
+		w.page.WriteByte(b)
+	}
+	w.page.WriteString(") Tj ET\n")
+}
+
+func (w *pdfWriter) nextObject() int {
+	w.offsets = append(w.offsets, 0)
+	return len(w.offsets) - 1
+}
+
+func (w *pdfWriter) object(number int) {
+	w.offsets[number] = w.offset
+	w.printf("%d 0 obj\n", number)
+}
+
+func (w *pdfWriter) printf(format string, args ...any) {
+	w.write(fmt.Appendf(nil, format, args...))
+}
+
+func (w *pdfWriter) write(p []byte) {
+	if w.err != nil {
+		return
+	}
+	n, err := w.out.Write(p)
+	w.offset += int64(n)
+	w.err = err
+}
+
+// fit shortens text with an ellipsis until it is at most width wide.
+func fit(text string, width float64, size float64) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if textWidth(text, size) <= width {
+		return text
+	}
+	runes := []rune(text)
+	// nothing is narrower than the quote, so longer text cannot fit anyway
+	if longest := int(width/(191*size/1000)) + 1; len(runes) > longest {
+		runes = runes[:longest]
+	}
+	for len(runes) > 0 && textWidth(string(runes)+"…", size) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
+
+func textWidth(text string, size float64) float64 {
+	total := 0
+	for _, r := range text {
+		switch {
+		case r >= ' ' && r <= '~':
+			total += helveticaWidths[r-' ']
+		case r == '…':
+			total += 1000
+		default:
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+func encodeWinAnsi(text string) []byte {
+	encoded := make([]byte, 0, len(text))
+	for _, r := range text {
+		switch {
+		case r >= ' ' && r <= '~', r >= 0xA0 && r <= 0xFF:
+			encoded = append(encoded, byte(r))
+		case winAnsi[r] != 0:
+			encoded = append(encoded, winAnsi[r])
+		default:
+			encoded = append(encoded, '?')
+		}
+	}
+	return encoded
+}
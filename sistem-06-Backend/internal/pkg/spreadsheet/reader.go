@@ -0,0 +1,282 @@
+// Package spreadsheet reads uploaded CSV and XLSX files into rows of text
+// and writes tables as CSV, XLSX or printable PDF. XLSX is handled with
+// archive/zip and encoding/xml; only the first worksheet and the cell values
+// are used.
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	CSV  = "csv"
+	XLSX = "xlsx"
+	PDF  = "pdf"
+)
+
+// maxPartSize bounds every decompressed part of an XLSX file, so a small
+// upload cannot expand into gigabytes.
+const maxPartSize = 64 << 20
+
+var ErrUnsupportedFormat = errors.New("unsupported file format, expected .csv or .xlsx")
+
+// Row is one non-empty line. Number is the line or row number shown by a
+// spreadsheet program, so errors can point at it.
+type Row struct {
+	Number int
+	Cells  []string
+}
+
+// FormatOf picks the format from the file name.
+func FormatOf(filename string) (string, error) {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".csv":
+		return CSV, nil
+	case ".xlsx":
+		return XLSX, nil
+	}
+	return "", ErrUnsupportedFormat
+}
+
+// Read returns the rows of the file, header included. Blank rows are skipped.
+func Read(format string, r io.ReaderAt, size int64) ([]Row, error) {
+	switch format {
+	case CSV:
+		return ReadCSV(io.NewSectionReader(r, 0, size))
+	case XLSX:
+		return ReadXLSX(r, size)
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+// ReadCSV accepts comma or semicolon separated files, the latter being what
+// spreadsheet programs write under the Indonesian locale.
+func ReadCSV(r io.Reader) ([]Row, error) {
+	buffered := bufio.NewReader(r)
+	if bom, err := buffered.Peek(3); err == nil && bytes.Equal(bom, []byte{0xEF, 0xBB, 0xBF}) {
+		buffered.Discard(3)
+	}
+	// the delimiter is guessed from the header line
+	head, _ := buffered.Peek(4096)
+	header, _, _ := bytes.Cut(head, []byte("\n"))
+
+	reader := csv.NewReader(buffered)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if bytes.Count(header, []byte(";")) > bytes.Count(header, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	var rows []Row
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		if !blank(record) {
+			rows = append(rows, Row{Number: line, Cells: record})
+		}
+	}
+}
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		ID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.Text
+	}
+	var text strings.Builder
+	for _, run := range t.Runs {
+		text.WriteString(run.Text)
+	}
+	return text.String()
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+type xlsxSheet struct {
+	Rows []struct {
+		Number int `xml:"r,attr"`
+		Cells  []struct {
+			Ref    string   `xml:"r,attr"`
+			Type   string   `xml:"t,attr"`
+			Value  string   `xml:"v"`
+			Inline xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// ReadXLSX reads the first worksheet of an XLSX file.
+func ReadXLSX(r io.ReaderAt, size int64) ([]Row, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("not an xlsx file: %w", err)
+	}
+	parts := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		parts[file.Name] = file
+	}
+
+	sheetPath, err := firstSheet(parts)
+	if err != nil {
+		return nil, err
+	}
+
+	var shared xlsxSharedStrings
+	if file, ok := parts["xl/sharedStrings.xml"]; ok {
+		if err := decodePart(file, &shared); err != nil {
+			return nil, err
+		}
+	}
+
+	file, ok := parts[sheetPath]
+	if !ok {
+		return nil, fmt.Errorf("xlsx worksheet %s is missing", sheetPath)
+	}
+	var sheet xlsxSheet
+	if err := decodePart(file, &sheet); err != nil {
+		return nil, err
+	}
+
+	rows := make([]Row, 0, len(sheet.Rows))
+	for i, row := range sheet.Rows {
+		number := row.Number
+		if number == 0 {
+			number = i + 1
+		}
+		var cells []string
+		for j, cell := range row.Cells {
+			column := j
+			if cell.Ref != "" {
+				if column, err = columnIndex(cell.Ref); err != nil {
+					return nil, err
+				}
+			}
+			for len(cells) <= column {
+				cells = append(cells, "")
+			}
+			switch cell.Type {
+			case "s":
+				index, err := strconv.Atoi(cell.Value)
+				if err != nil || index < 0 || index >= len(shared.Items) {
+					return nil, fmt.Errorf("xlsx cell %s refers to a missing shared string", cell.Ref)
+				}
+				cells[column] = shared.Items[index].String()
+			case "inlineStr":
+				cells[column] = cell.Inline.String()
+			default:
+				cells[column] = cell.Value
+			}
+		}
+		if !blank(cells) {
+			rows = append(rows, Row{Number: number, Cells: cells})
+		}
+	}
+	return rows, nil
+}
+
+// firstSheet resolves the part name of the first worksheet through the
+// workbook relationships.
+func firstSheet(parts map[string]*zip.File) (string, error) {
+	var workbook xlsxWorkbook
+	var relationships xlsxRelationships
+	workbookFile, ok := parts["xl/workbook.xml"]
+	if !ok {
+		return "", errors.New("xlsx workbook is missing")
+	}
+	if err := decodePart(workbookFile, &workbook); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", errors.New("xlsx workbook has no sheets")
+	}
+	relsFile, ok := parts["xl/_rels/workbook.xml.rels"]
+	if !ok {
+		return "", errors.New("xlsx workbook relationships are missing")
+	}
+	if err := decodePart(relsFile, &relationships); err != nil {
+		return "", err
+	}
+	for _, relationship := range relationships.Relationships {
+		if relationship.ID == workbook.Sheets[0].ID {
+			if strings.HasPrefix(relationship.Target, "/") {
+				return strings.TrimPrefix(relationship.Target, "/"), nil
+			}
+			return path.Join("xl", relationship.Target), nil
+		}
+	}
+	return "", errors.New("xlsx first sheet has no relationship")
+}
+
+func decodePart(file *zip.File, v any) error {
+	reader, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	if err := xml.NewDecoder(io.LimitReader(reader, maxPartSize)).Decode(v); err != nil {
+		return fmt.Errorf("xlsx part %s: %w", file.Name, err)
+	}
+	return nil
+}
+
+// columnIndex turns the letters of a cell reference such as "AB12" into a
+// zero based column index.
+func columnIndex(ref string) (int, error) {
+	index := 0
+	letters := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		index = index*26 + int(r-'A') + 1
+		letters++
+	}
+	if letters == 0 || letters > 3 {
+		return 0, fmt.Errorf("invalid xlsx cell reference %q", ref)
+	}
+	return index - 1, nil
+}
+
+func blank(cells []string) bool {
+	for _, cell := range cells {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,85 @@
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+)
+
+// Writer writes a table one row at a time, so a table of any size is
+// streamed instead of built in memory. The first row is the header. Close
+// finishes the file but does not close the underlying io.Writer.
+type Writer interface {
+	Write(cells []string) error
+	Close() error
+}
+
+var ErrUnsupportedExport = errors.New("unsupported export format, expected csv, xlsx or pdf")
+
+// NewWriter starts a table in format. title names the XLSX sheet and heads
+// every PDF page.
+func NewWriter(format string, w io.Writer, title string) (Writer, error) {
+	switch format {
+	case CSV:
+		return NewCSVWriter(w)
+	case XLSX:
+		return NewXLSXWriter(w, title)
+	case PDF:
+		return NewPDFWriter(w, title), nil
+	}
+	return nil, ErrUnsupportedExport
+}
+
+// ContentType is the media type of files in format.
+func ContentType(format string) string {
+	switch format {
+	case CSV:
+		return "text/csv; charset=utf-8"
+	case XLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case PDF:
+		return "application/pdf"
+	}
+	return "application/octet-stream"
+}
+
+type csvWriter struct {
+	writer *csv.Writer
+}
+
+// NewCSVWriter starts the file with a byte order mark, without which
+// spreadsheet programs misread UTF-8.
+func NewCSVWriter(w io.Writer) (Writer, error) {
+	if _, err := w.Write([]byte{0xEF, 0xBB, 0xBF}); err != nil {
+		return nil, err
+	}
+	return &csvWriter{writer: csv.NewWriter(w)}, nil
+}
+
+func (w *csvWriter) Write(cells []string) error {
+	escaped := make([]string, len(cells))
+	for i, cell := range cells {
+		escaped[i] = escapeFormula(cell)
+	}
+	if err := w.writer.Write(escaped); err != nil {
+		return err
+	}
+	// rows reach the client as they are written
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+func (w *csvWriter) Close() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+// escapeFormula keeps a spreadsheet program from running a cell that starts
+// like a formula, e.g. a user named "=HYPERLINK(...)".
+func escapeFormula(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
@@ -0,0 +1,339 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"sistem-06-Backend/internal/delivery/http/converter"
+	"sistem-06-Backend/internal/domain/entity"
+	domain "sistem-06-Backend/internal/domain/ports"
+	"sistem-06-Backend/internal/dto"
+	customErrors "sistem-06-Backend/internal/pkg/errors"
+	"sistem-06-Backend/internal/pkg/password"
+	"sistem-06-Backend/pkg"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// AccountUseCase lets the logged in user read and edit their own account.
+type AccountUseCase struct {
+	DB                    *sql.DB
+	Log                   *logrus.Logger
+	validate              *validator.Validate
+	UserRepository        domain.UserRepository
+	EmailChangeRepository domain.EmailChangeRepository
+	PasswordPolicy        *password.Policy
+	Hasher                password.Hasher
+	Notifier              domain.Notifier
+	Audit                 *AuditRecorder
+	EmailChangeTTL        time.Duration
+}
+
+func NewAccountUseCase(db *sql.DB, log *logrus.Logger, validate *validator.Validate, userRepository domain.UserRepository, emailChangeRepository domain.EmailChangeRepository, passwordPolicy *password.Policy, hasher password.Hasher, notifier domain.Notifier, audit *AuditRecorder, emailChangeTTL time.Duration) *AccountUseCase {
+	return &AccountUseCase{
+		DB:                    db,
+		Log:                   log,
+		validate:              validate,
+		UserRepository:        userRepository,
+		EmailChangeRepository: emailChangeRepository,
+		PasswordPolicy:        passwordPolicy,
+		Hasher:                hasher,
+		Notifier:              notifier,
+		Audit:                 audit,
+		EmailChangeTTL:        emailChangeTTL,
+	}
+}
+
+func (c *AccountUseCase) Get(ctx context.Context, userID int) (*dto.UserResponse, error) {
+	ctx, span := tracer.Start(ctx, "AccountUseCase.Get")
+	defer span.End()
+
+	user, err := c.UserRepository.FindWithRoles(ctx, userID)
+	if err != nil {
+		return nil, c.userError(ctx, err)
+	}
+
+	response := converter.UserWithRolesToResponse(user)
+	response.CreatedAt = user.CreatedAt
+	response.UpdatedAt = user.UpdatedAt
+
+	change, err := c.EmailChangeRepository.FindByUserID(ctx, userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		pkg.Logger(ctx, c.Log).Errorf("Failed to load pending email change: %v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	if err == nil && time.Now().Unix() <= change.ExpiresAt {
+		response.PendingEmail = change.NewEmail
+	}
+	return response, nil
+}
+
+// Update renames the user right away. A new email only becomes pending: the
+// token that confirms it is sent to the new address. version is the one the
+// client read; a stale version fails with *pkg.VersionConflict.
+func (c *AccountUseCase) Update(ctx context.Context, userID int, version int, request *dto.UpdateAccountRequest) (*dto.UserResponse, error) {
+	ctx, span := tracer.Start(ctx, "AccountUseCase.Update")
+	defer span.End()
+
+	if validationErrors := customErrors.UserValidationError(c.validate.Struct(request)); len(validationErrors) > 0 {
+		pkg.Logger(ctx, c.Log).Warnf("Validation failed: %+v", validationErrors)
+		return nil, fiber.NewError(fiber.StatusBadRequest, pkg.FormatValidationErrors(validationErrors))
+	}
+
+	user, err := c.UserRepository.FindByID(ctx, userID)
+	if err != nil {
+		return nil, c.userError(ctx, err)
+	}
+	if user.Version != version {
+		return nil, c.versionConflict(ctx, userID)
+	}
+
+	renamed := request.Name != nil && *request.Name != user.Name
+	changingEmail := request.Email != nil && !strings.EqualFold(*request.Email, user.Email)
+	if !renamed && !changingEmail {
+		return c.Get(ctx, userID)
+	}
+
+	tx, err := c.DB.BeginTx(ctx, nil)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed to begin transaction: %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	if renamed {
+		if err := c.UserRepository.WithTx(tx).UpdateName(ctx, userID, version, *request.Name, now.Unix()); err != nil {
+			if errors.Is(err, entity.ErrVersionConflict) {
+				tx.Rollback()
+				return nil, c.versionConflict(ctx, userID)
+			}
+			if strings.Contains(err.Error(), "duplicate key") {
+				return nil, fiber.NewError(fiber.StatusConflict, "name already exist")
+			}
+			pkg.Logger(ctx, c.Log).Errorf("Failed to update name: %v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+		before, after := map[string]any{"name": user.Name}, map[string]any{"name": *request.Name}
+		if err := c.Audit.Record(ctx, tx, entity.AuditActionUpdate, entity.AuditEntityUser, userID, before, after); err != nil {
+			return nil, err
+		}
+	}
+
+	var token string
+	if changingEmail {
+		if token, err = c.requestEmailChange(ctx, tx, user, *request.Email, now); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if changingEmail {
+		if err := c.Notifier.EmailChangeRequested(ctx, user, *request.Email, token); err != nil {
+			pkg.Logger(ctx, c.Log).Errorf("Failed to send email change notice: %v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+		pkg.Logger(ctx, c.Log).Info("Email change requested")
+	}
+
+	return c.Get(ctx, userID)
+}
+
+// requestEmailChange stores a pending change to newEmail and returns the
+// token that confirms it.
+func (c *AccountUseCase) requestEmailChange(ctx context.Context, tx *sql.Tx, user *entity.User, newEmail string, now time.Time) (string, error) {
+	_, err := c.UserRepository.FindByEmail(ctx, newEmail)
+	if err == nil {
+		return "", fiber.NewError(fiber.StatusConflict, "email already exist")
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		pkg.Logger(ctx, c.Log).Errorf("Failed to look up email: %v", err)
+		return "", fiber.ErrInternalServerError
+	}
+
+	token := pkg.GenerateToken()
+	change := &entity.EmailChange{
+		UserID:    user.ID,
+		NewEmail:  newEmail,
+		TokenHash: pkg.HashToken(token),
+		ExpiresAt: now.Add(c.EmailChangeTTL).Unix(),
+		CreatedAt: now.Unix(),
+	}
+	if err := c.EmailChangeRepository.WithTx(tx).Save(ctx, change); err != nil {
+		pkg.Logger(ctx, c.Log).Errorf("Failed to save email change: %v", err)
+		return "", fiber.ErrInternalServerError
+	}
+	after := map[string]any{"pending_email": newEmail}
+	if err := c.Audit.Record(ctx, tx, entity.AuditActionRequestEmailChange, entity.AuditEntityUser, user.ID, nil, after); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// VerifyEmail applies the pending email change the token was issued for.
+func (c *AccountUseCase) VerifyEmail(ctx context.Context, userID int, request *dto.VerifyUserRequest) (*dto.UserResponse, error) {
+	ctx, span := tracer.Start(ctx, "AccountUseCase.VerifyEmail")
+	defer span.End()
+
+	if validationErrors := customErrors.UserValidationError(c.validate.Struct(request)); len(validationErrors) > 0 {
+		return nil, fiber.NewError(fiber.StatusBadRequest, pkg.FormatValidationErrors(validationErrors))
+	}
+
+	change, err := c.EmailChangeRepository.FindByTokenHash(ctx, pkg.HashToken(request.Token))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		pkg.Logger(ctx, c.Log).Errorf("Failed to load email change: %v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	now := time.Now().Unix()
+	if err != nil || change.UserID != userID || now > change.ExpiresAt {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "invalid or expired token")
+	}
+
+	user, err := c.UserRepository.FindByID(ctx, userID)
+	if err != nil {
+		return nil, c.userError(ctx, err)
+	}
+
+	tx, err := c.DB.BeginTx(ctx, nil)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed to begin transaction: %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	defer tx.Rollback()
+
+	// taken since the change was requested; the unique index alone misses
+	// accounts whose blind index encryption rotate has not keyed yet
+	if owner, err := c.UserRepository.WithTx(tx).FindByEmail(ctx, change.NewEmail); err == nil && owner.ID != userID {
+		return nil, fiber.NewError(fiber.StatusConflict, "email already exist")
+	} else if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		pkg.Logger(ctx, c.Log).Errorf("Failed to look up email: %v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	if err := c.UserRepository.WithTx(tx).UpdateEmail(ctx, userID, change.NewEmail, now); err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			return nil, fiber.NewError(fiber.StatusConflict, "email already exist")
+		}
+		pkg.Logger(ctx, c.Log).Errorf("Failed to update email: %v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	if err := c.EmailChangeRepository.WithTx(tx).Delete(ctx, userID); err != nil {
+		pkg.Logger(ctx, c.Log).Errorf("Failed to delete email change: %v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	before, after := map[string]any{"email": user.Email}, map[string]any{"email": change.NewEmail}
+	if err := c.Audit.Record(ctx, tx, entity.AuditActionUpdate, entity.AuditEntityUser, userID, before, after); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	pkg.Logger(ctx, c.Log).Info("Email changed")
+	return c.Get(ctx, userID)
+}
+
+// ChangePassword replaces the password after checking the current one. With
+// RevokeOtherSessions every session created before now stops working; the
+// caller rotates its own session afterwards.
+func (c *AccountUseCase) ChangePassword(ctx context.Context, userID int, request *dto.ChangePasswordRequest) (*dto.UserResponse, error) {
+	ctx, span := tracer.Start(ctx, "AccountUseCase.ChangePassword")
+	defer span.End()
+
+	user, err := c.UserRepository.FindByID(ctx, userID)
+	if err != nil {
+		return nil, c.userError(ctx, err)
+	}
+
+	validationErrors := customErrors.UserValidationError(c.validate.Struct(request))
+	if _, invalid := validationErrors["CurrentPassword"]; !invalid {
+		if ok, err := c.Hasher.Verify(request.CurrentPassword, user.Password); !ok {
+			if err != nil {
+				pkg.Logger(ctx, c.Log).Errorf("Failed to verify password hash: %v", err)
+			}
+			if validationErrors == nil {
+				validationErrors = map[string]string{}
+			}
+			validationErrors["CurrentPassword"] = "CurrentPassword is incorrect"
+		} else if request.Password == request.CurrentPassword {
+			if validationErrors == nil {
+				validationErrors = map[string]string{}
+			}
+			validationErrors["Password"] = "Password must differ from the current password"
+		}
+	}
+	validationErrors = checkPassword(c.PasswordPolicy, validationErrors, request.Password, user.Email, user.Name)
+	if len(validationErrors) > 0 {
+		pkg.Logger(ctx, c.Log).Warnf("Validation failed: %+v", validationErrors)
+		return nil, fiber.NewError(fiber.StatusBadRequest, pkg.FormatValidationErrors(validationErrors))
+	}
+
+	hash, err := c.Hasher.Hash(request.Password)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("failed to generate password hash: %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	tx, err := c.DB.BeginTx(ctx, nil)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed to begin transaction: %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	defer tx.Rollback()
+
+	now := time.Now().Unix()
+	userRepository := c.UserRepository.WithTx(tx)
+	if err := userRepository.UpdatePassword(ctx, userID, hash, now); err != nil {
+		pkg.Logger(ctx, c.Log).Errorf("Failed to update password: %v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	if request.RevokeOtherSessions {
+		if err := userRepository.RevokeSessions(ctx, userID, now); err != nil {
+			pkg.Logger(ctx, c.Log).Errorf("Failed to revoke sessions: %v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+	after := map[string]any{"revoke_other_sessions": request.RevokeOtherSessions}
+	if err := c.Audit.Record(ctx, tx, entity.AuditActionChangePassword, entity.AuditEntityUser, userID, nil, after); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	go func(ctx context.Context) {
+		if err := c.Notifier.PasswordChanged(ctx, user); err != nil {
+			pkg.Logger(ctx, c.Log).Errorf("Failed to send password change notice: %v", err)
+		}
+	}(context.WithoutCancel(ctx))
+
+	pkg.Logger(ctx, c.Log).WithField("revoke_other_sessions", request.RevokeOtherSessions).Info("Password changed")
+	return converter.UserToResponse(user), nil
+}
+
+// versionConflict reports the account as it is now.
+func (c *AccountUseCase) versionConflict(ctx context.Context, userID int) error {
+	current, err := c.Get(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return &pkg.VersionConflict{Version: current.Version, Current: current}
+}
+
+func (c *AccountUseCase) userError(ctx context.Context, err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return fiber.ErrUnauthorized
+	}
+	pkg.Logger(ctx, c.Log).Errorf("Failed to load user: %v", err)
+	return fiber.ErrInternalServerError
+}
@@ -3,10 +3,18 @@ package usecase
 import (
 	"context"
 	"database/sql"
+	stdErrors "errors"
+	"strings"
+	"time"
 
-	"sistem-06-Backend/internal/domain"
+	"sistem-06-Backend/internal/delivery/http/converter"
+	"sistem-06-Backend/internal/domain/entity"
+	domain "sistem-06-Backend/internal/domain/ports"
+	"sistem-06-Backend/internal/dto"
+	"sistem-06-Backend/internal/pkg/errors"
+	"sistem-06-Backend/internal/pkg/listquery"
+	"sistem-06-Backend/pkg"
 
-	"backend-sistem06.com/utils"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/sirupsen/logrus"
@@ -17,21 +25,26 @@ type AddressUseCase struct {
 	Log               *logrus.Logger
 	Validate          *validator.Validate
 	AddressRepository domain.AddressRepository
+	Audit             *AuditRecorder
 }
 
-func NewAddressUseCase(db *sql.DB, log *logrus.Logger, validate *validator.Validate, addressRepository domain.AddressRepository) *AddressUseCase {
+func NewAddressUseCase(db *sql.DB, log *logrus.Logger, validate *validator.Validate, addressRepository domain.AddressRepository, audit *AuditRecorder) *AddressUseCase {
 	return &AddressUseCase{
 		DB:                db,
 		Log:               log,
 		Validate:          validate,
 		AddressRepository: addressRepository,
+		Audit:             audit,
 	}
 }
 
-func (c *AddressUseCase) Create(ctx context.Context, request *model.AddressRequest) (*model.Address, error) {
+func (c *AddressUseCase) Create(ctx context.Context, request *dto.AddressRequest) (*dto.AddressEntity, error) {
+	ctx, span := tracer.Start(ctx, "AddressUseCase.Create")
+	defer span.End()
+
 	tx, err := c.DB.BeginTx(ctx, nil)
 	if err != nil {
-		c.Log.Warnf("Failed to begin transaction: %+v", err)
+		pkg.Logger(ctx, c.Log).Warnf("Failed to begin transaction: %+v", err)
 
 		if err == context.Canceled || err == context.DeadlineExceeded {
 			return nil, fiber.NewError(fiber.StatusRequestTimeout, "request timeout or canceled")
@@ -42,11 +55,11 @@ func (c *AddressUseCase) Create(ctx context.Context, request *model.AddressReque
 	defer tx.Rollback()
 
 	if err := c.Validate.Struct(request); err != nil {
-		validationErrors := utils.ValidationError(err)
-		c.Log.Warnf("Validation failed: %+v", validationErrors)
+		validationErrors := errors.ValidationError(err)
+		pkg.Logger(ctx, c.Log).Warnf("Validation failed: %+v", validationErrors)
 
 		// Return response error yang bisa dibaca frontend
-		return nil, fiber.NewError(fiber.StatusBadRequest, utils.FormatValidationErrors(validationErrors))
+		return nil, fiber.NewError(fiber.StatusBadRequest, pkg.FormatValidationErrors(validationErrors))
 	}
 
 	address := &entity.Address{
@@ -57,13 +70,207 @@ func (c *AddressUseCase) Create(ctx context.Context, request *model.AddressReque
 		PostalCode: request.PostalCode,
 	}
 
-	if err := c.AddressRepository.CreateAddress(tx, address); err != nil {
-		c.Log.Warnf("Database insert error: %+v", err)
+	if err := c.AddressRepository.WithTx(tx).CreateAddress(ctx, address); err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Database insert error: %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	response := converter.AddressToResponse(address)
+	if err := c.Audit.Record(ctx, tx, entity.AuditActionCreate, entity.AuditEntityAddress, address.ID, nil, response); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("failed commit transcation: %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	return response, nil
+}
+
+func (c *AddressUseCase) Get(ctx context.Context, addressID int) (*dto.AddressEntity, error) {
+	ctx, span := tracer.Start(ctx, "AddressUseCase.Get")
+	defer span.End()
+
+	address, err := c.AddressRepository.FindByID(ctx, addressID)
+	if err != nil {
+		if stdErrors.Is(err, sql.ErrNoRows) {
+			return nil, fiber.NewError(fiber.StatusNotFound, "address not found")
+		}
+		pkg.Logger(ctx, c.Log).Errorf("Failed to load address: %v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	return converter.AddressToResponse(address), nil
+}
+
+// List pages through live addresses, see listquery for the parameters.
+func (c *AddressUseCase) List(ctx context.Context, query map[string]string) (*pkg.PageResponse[*dto.AddressEntity], error) {
+	ctx, span := tracer.Start(ctx, "AddressUseCase.List")
+	defer span.End()
+
+	request, err := listquery.Parse(query)
+	if err != nil {
+		return nil, listError(ctx, c.Log, "addresses", err)
+	}
+	addresses, paging, err := c.AddressRepository.List(ctx, request)
+	if err != nil {
+		return nil, listError(ctx, c.Log, "addresses", err)
+	}
+
+	responses := make([]*dto.AddressEntity, len(addresses))
+	for i, address := range addresses {
+		responses[i] = converter.AddressToResponse(address)
+	}
+	return &pkg.PageResponse[*dto.AddressEntity]{Data: responses, PageMetadata: paging}, nil
+}
+
+// Update applies the fields of request to the address if it is still at
+// version.
+func (c *AddressUseCase) Update(ctx context.Context, addressID int, version int, request *dto.UpdateAddressRequest) (*dto.AddressEntity, error) {
+	ctx, span := tracer.Start(ctx, "AddressUseCase.Update")
+	defer span.End()
+
+	if err := c.Validate.Struct(request); err != nil {
+		validationErrors := errors.ValidationError(err)
+		pkg.Logger(ctx, c.Log).Warnf("Validation failed: %+v", validationErrors)
+		return nil, fiber.NewError(fiber.StatusBadRequest, pkg.FormatValidationErrors(validationErrors))
+	}
+
+	address, err := c.AddressRepository.FindByID(ctx, addressID)
+	if err != nil {
+		if stdErrors.Is(err, sql.ErrNoRows) {
+			return nil, fiber.NewError(fiber.StatusNotFound, "address not found")
+		}
+		pkg.Logger(ctx, c.Log).Errorf("Failed to load address: %v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	if address.Version != version {
+		return nil, &pkg.VersionConflict{Version: address.Version, Current: converter.AddressToResponse(address)}
+	}
+
+	before := converter.AddressToResponse(address)
+	if request.Jalan != nil {
+		address.Jalan = *request.Jalan
+	}
+	if request.RT != nil {
+		address.RT = *request.RT
+	}
+	if request.RW != nil {
+		address.RW = *request.RW
+	}
+	if request.Kota != nil {
+		address.Kota = *request.Kota
+	}
+	if request.PostalCode != nil {
+		address.PostalCode = *request.PostalCode
+	}
+
+	tx, err := c.DB.BeginTx(ctx, nil)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed to begin transaction: %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	defer tx.Rollback()
+
+	if err := c.AddressRepository.WithTx(tx).Update(ctx, address); err != nil {
+		switch {
+		case stdErrors.Is(err, sql.ErrNoRows):
+			return nil, fiber.NewError(fiber.StatusNotFound, "address not found")
+		case stdErrors.Is(err, entity.ErrVersionConflict):
+			tx.Rollback()
+			return nil, c.versionConflict(ctx, addressID)
+		case strings.Contains(err.Error(), "duplicate key"):
+			return nil, fiber.NewError(fiber.StatusConflict, "RT already used by another address")
+		}
+		pkg.Logger(ctx, c.Log).Errorf("Failed to update address: %v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	response := converter.AddressToResponse(address)
+	if err := c.Audit.Record(ctx, tx, entity.AuditActionUpdate, entity.AuditEntityAddress, addressID, before, response); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("failed commit transcation: %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	return response, nil
+}
+
+// Delete soft deletes the address if it is still at version.
+func (c *AddressUseCase) Delete(ctx context.Context, addressID int, version int) error {
+	ctx, span := tracer.Start(ctx, "AddressUseCase.Delete")
+	defer span.End()
+
+	tx, err := c.DB.BeginTx(ctx, nil)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed to begin transaction: %+v", err)
+		return fiber.ErrInternalServerError
+	}
+	defer tx.Rollback()
+
+	if err := c.AddressRepository.WithTx(tx).SoftDelete(ctx, addressID, version, time.Now().Unix()); err != nil {
+		switch {
+		case stdErrors.Is(err, sql.ErrNoRows):
+			return fiber.NewError(fiber.StatusNotFound, "address not found")
+		case stdErrors.Is(err, entity.ErrVersionConflict):
+			tx.Rollback()
+			return c.versionConflict(ctx, addressID)
+		}
+		pkg.Logger(ctx, c.Log).Errorf("Failed to delete address: %v", err)
+		return fiber.ErrInternalServerError
+	}
+	if err := c.Audit.Record(ctx, tx, entity.AuditActionDelete, entity.AuditEntityAddress, addressID, nil, nil); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("failed commit transcation: %+v", err)
+		return fiber.ErrInternalServerError
+	}
+	return nil
+}
+
+// Restore brings back a deleted address unless a live address has taken its
+// RT in the meantime.
+func (c *AddressUseCase) Restore(ctx context.Context, addressID int) (*dto.AddressEntity, error) {
+	ctx, span := tracer.Start(ctx, "AddressUseCase.Restore")
+	defer span.End()
+
+	tx, err := c.DB.BeginTx(ctx, nil)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed to begin transaction: %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	defer tx.Rollback()
+
+	addressRepository := c.AddressRepository.WithTx(tx)
+	restored, err := addressRepository.Restore(ctx, addressID)
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			return nil, fiber.NewError(fiber.StatusConflict, "RT already used by another address")
+		}
+		pkg.Logger(ctx, c.Log).Errorf("Failed to restore address: %v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	if !restored {
+		return nil, fiber.NewError(fiber.StatusNotFound, "deleted address not found")
+	}
+	address, err := addressRepository.FindByID(ctx, addressID)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Errorf("Failed to load restored address: %v", err)
 		return nil, fiber.ErrInternalServerError
 	}
+	if err := c.Audit.Record(ctx, tx, entity.AuditActionRestore, entity.AuditEntityAddress, addressID, nil, nil); err != nil {
+		return nil, err
+	}
 	if err := tx.Commit(); err != nil {
-		c.Log.Warnf("failed commit transcation: %+v", err)
+		pkg.Logger(ctx, c.Log).Warnf("failed commit transcation: %+v", err)
 		return nil, fiber.ErrInternalServerError
 	}
 	return converter.AddressToResponse(address), nil
 }
+
+// versionConflict reports the address as it is now.
+func (c *AddressUseCase) versionConflict(ctx context.Context, addressID int) error {
+	current, err := c.Get(ctx, addressID)
+	if err != nil {
+		return err
+	}
+	return &pkg.VersionConflict{Version: current.Version, Current: current}
+}
@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"sistem-06-Backend/internal/domain/entity"
+	domain "sistem-06-Backend/internal/domain/ports"
+	"sistem-06-Backend/internal/pkg/fieldcrypt"
+	"sistem-06-Backend/pkg"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// AuditRecorder writes the audit entries of the use cases. A nil recorder
+// records nothing, which keeps tests that do not look at the log short.
+type AuditRecorder struct {
+	Repository domain.AuditRepository
+	Log        *logrus.Logger
+	// Cipher builds the blind indexes stored instead of emails; nil uses
+	// the unkeyed ones, like UserRepositoryImpl
+	Cipher *fieldcrypt.Cipher
+}
+
+func NewAuditRecorder(repository domain.AuditRepository, log *logrus.Logger, cipher *fieldcrypt.Cipher) *AuditRecorder {
+	return &AuditRecorder{
+		Repository: repository,
+		Log:        log,
+		Cipher:     cipher,
+	}
+}
+
+// auditIndexedFields maps the snapshot fields that hold an email to the
+// column whose blind index replaces them. The audit log is append-only, so
+// a plain address could never be encrypted or erased later; the index
+// still equals users.email_hash of the address.
+var auditIndexedFields = map[string]string{
+	"email":         "users.email",
+	"pending_email": "users.email",
+}
+
+type auditChange struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
+// Record appends an entry for a change made in tx, so the entry is only kept
+// if the change commits. The actor, IP and request id come from ctx. before
+// and after are snapshots of the entity, usually its response DTO, and nil
+// where the entity did not exist. Emails are recorded as their blind index,
+// see auditIndexedFields.
+func (r *AuditRecorder) Record(ctx context.Context, tx *sql.Tx, action string, entityType string, entityID any, before any, after any) error {
+	if r == nil {
+		return nil
+	}
+
+	diff, err := auditDiff(before, after, r.Cipher)
+	if err != nil {
+		pkg.Logger(ctx, r.Log).Errorf("Failed to build audit diff: %v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	entry := &entity.AuditEntry{
+		ActorID:    pkg.Actor(ctx),
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   fmt.Sprint(entityID),
+		Diff:       diff,
+		IP:         pkg.ClientIP(ctx),
+		RequestID:  pkg.RequestID(ctx),
+		CreatedAt:  time.Now().Unix(),
+	}
+	if err := r.Repository.WithTx(tx).Append(ctx, entry); err != nil {
+		pkg.Logger(ctx, r.Log).Errorf("Failed to append audit entry: %v", err)
+		return fiber.ErrInternalServerError
+	}
+	return nil
+}
+
+// auditDiff returns the fields that differ between the JSON forms of before
+// and after. Keys are sorted by encoding/json, so the text is stable for
+// hashing.
+func auditDiff(before any, after any, cipher *fieldcrypt.Cipher) (string, error) {
+	oldFields, err := auditFields(before, cipher)
+	if err != nil {
+		return "", err
+	}
+	newFields, err := auditFields(after, cipher)
+	if err != nil {
+		return "", err
+	}
+
+	diff := map[string]auditChange{}
+	for key, value := range newFields {
+		if old, ok := oldFields[key]; !ok || !reflect.DeepEqual(old, value) {
+			diff[key] = auditChange{Old: oldFields[key], New: value}
+		}
+	}
+	for key, old := range oldFields {
+		if _, ok := newFields[key]; !ok {
+			diff[key] = auditChange{Old: old}
+		}
+	}
+
+	encoded, err := json.Marshal(diff)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
+
+func auditFields(snapshot any, cipher *fieldcrypt.Cipher) (map[string]any, error) {
+	if snapshot == nil {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil, err
+	}
+	for key, column := range auditIndexedFields {
+		if value, ok := fields[key].(string); ok && value != "" {
+			fields[key] = cipher.BlindIndex(column, value)
+		}
+	}
+	return fields, nil
+}
@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"context"
+
+	"sistem-06-Backend/internal/delivery/http/converter"
+	"sistem-06-Backend/internal/domain/entity"
+	domain "sistem-06-Backend/internal/domain/ports"
+	"sistem-06-Backend/internal/dto"
+	customErrors "sistem-06-Backend/internal/pkg/errors"
+	"sistem-06-Backend/pkg"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// auditVerifyBatch is how many entries Verify loads at a time.
+const auditVerifyBatch = 500
+
+type AuditUseCase struct {
+	Log             *logrus.Logger
+	Validate        *validator.Validate
+	AuditRepository domain.AuditRepository
+}
+
+func NewAuditUseCase(log *logrus.Logger, validate *validator.Validate, auditRepository domain.AuditRepository) *AuditUseCase {
+	return &AuditUseCase{
+		Log:             log,
+		Validate:        validate,
+		AuditRepository: auditRepository,
+	}
+}
+
+// Search returns the matching entries, newest first.
+func (c *AuditUseCase) Search(ctx context.Context, request *dto.SearchAuditLogRequest) ([]*dto.AuditLogResponse, *pkg.PageMetadata, error) {
+	ctx, span := tracer.Start(ctx, "AuditUseCase.Search")
+	defer span.End()
+
+	if validationErrors := customErrors.UserValidationError(c.Validate.Struct(request)); len(validationErrors) > 0 {
+		return nil, nil, fiber.NewError(fiber.StatusBadRequest, pkg.FormatValidationErrors(validationErrors))
+	}
+
+	filter := entity.AuditFilter{
+		ActorID:    request.ActorID,
+		Action:     request.Action,
+		EntityType: request.EntityType,
+		EntityID:   request.EntityID,
+		From:       request.From,
+		To:         request.To,
+		Limit:      request.Size,
+		Offset:     (request.Page - 1) * request.Size,
+	}
+	entries, err := c.AuditRepository.Search(ctx, filter)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Errorf("Failed to search audit log: %v", err)
+		return nil, nil, fiber.ErrInternalServerError
+	}
+	total, err := c.AuditRepository.Count(ctx, filter)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Errorf("Failed to count audit log: %v", err)
+		return nil, nil, fiber.ErrInternalServerError
+	}
+
+	responses := make([]*dto.AuditLogResponse, len(entries))
+	for i, entry := range entries {
+		responses[i] = converter.AuditEntryToResponse(entry)
+	}
+	paging := &pkg.PageMetadata{
+		Page:      request.Page,
+		Size:      request.Size,
+		TotalItem: total,
+		TotalPage: (total + int64(request.Size) - 1) / int64(request.Size),
+	}
+	return responses, paging, nil
+}
+
+// Verify walks the whole chain from the first entry and stops at the first
+// entry that was altered or whose predecessor is missing.
+func (c *AuditUseCase) Verify(ctx context.Context) (*dto.AuditVerifyResponse, error) {
+	ctx, span := tracer.Start(ctx, "AuditUseCase.Verify")
+	defer span.End()
+
+	response := &dto.AuditVerifyResponse{Valid: true, LastHash: entity.AuditGenesisHash}
+	var lastID int64
+	for {
+		entries, err := c.AuditRepository.ListAfter(ctx, lastID, auditVerifyBatch)
+		if err != nil {
+			pkg.Logger(ctx, c.Log).Errorf("Failed to load audit log: %v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+
+		for _, entry := range entries {
+			if entry.PrevHash != response.LastHash || entry.ComputeHash() != entry.Hash {
+				pkg.Logger(ctx, c.Log).WithField("audit_id", entry.ID).Error("Audit log chain is broken")
+				response.Valid = false
+				response.BrokenAt = entry.ID
+				return response, nil
+			}
+			response.Checked++
+			response.LastHash = entry.Hash
+			lastID = entry.ID
+		}
+
+		if len(entries) < auditVerifyBatch {
+			return response, nil
+		}
+	}
+}
@@ -3,74 +3,414 @@ package usecase
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"sync"
+	"time"
 
-	"sistem-06-Backend/internal/domain"
+	"sistem-06-Backend/internal/delivery/http/converter"
+	"sistem-06-Backend/internal/domain/entity"
+	domain "sistem-06-Backend/internal/domain/ports"
 	"sistem-06-Backend/internal/dto"
+	"sistem-06-Backend/internal/infrastructure/metrics"
+	"sistem-06-Backend/internal/pkg/fieldcrypt"
+	"sistem-06-Backend/internal/pkg/mask"
+	"sistem-06-Backend/internal/pkg/password"
+	"sistem-06-Backend/pkg"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/sirupsen/logrus"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type AuthUseCase struct {
-	DB             *sql.DB
-	Log            *logrus.Logger
-	Validate       *validator.Validate
-	UserRepository domain.UserRepository
+	DB                     *sql.DB
+	Log                    *logrus.Logger
+	Validate               *validator.Validate
+	UserRepository         domain.UserRepository
+	LoginAttemptRepository domain.LoginAttemptRepository
+	TwoFactorRepository    domain.TwoFactorRepository
+	Notifier               domain.Notifier
+	Hasher                 password.Hasher
+	Audit                  *AuditRecorder
+	Policy                 LoginPolicy
+	Metrics                *metrics.Metrics
+	// Cipher builds the blind indexes failures are counted under instead of
+	// emails; nil uses the unkeyed ones, like UserRepositoryImpl
+	Cipher *fieldcrypt.Cipher
+	// dummyHash is compared against when the email is unknown, so that
+	// request takes as long as a wrong password for an existing account.
+	dummyHash func() string
 }
 
-func NewAuthUseCase(db *sql.DB, log *logrus.Logger, validate *validator.Validate, userRepository domain.AddressRepository) *AuthUseCase {
+func NewAuthUseCase(db *sql.DB, log *logrus.Logger, validate *validator.Validate, userRepository domain.UserRepository, loginAttemptRepository domain.LoginAttemptRepository, twoFactorRepository domain.TwoFactorRepository, notifier domain.Notifier, hasher password.Hasher, audit *AuditRecorder, policy LoginPolicy, metrics *metrics.Metrics, cipher *fieldcrypt.Cipher) *AuthUseCase {
 	return &AuthUseCase{
-		DB:             db,
-		Log:            log,
-		Validate:       validate,
-		UserRepository: userRepository,
+		DB:                     db,
+		Log:                    log,
+		Validate:               validate,
+		UserRepository:         userRepository,
+		LoginAttemptRepository: loginAttemptRepository,
+		TwoFactorRepository:    twoFactorRepository,
+		Notifier:               notifier,
+		Hasher:                 hasher,
+		Audit:                  audit,
+		Policy:                 policy,
+		Metrics:                metrics,
+		Cipher:                 cipher,
+		dummyHash: sync.OnceValue(func() string {
+			hash, err := hasher.Hash("sistem06-dummy-password")
+			if err != nil {
+				panic(err)
+			}
+			return hash
+		}),
 	}
 }
 
-func (c *AuthUseCase) Login(ctx context.Context, request *dto.UserLoginRequest) (*model.UserResponse, error) {
+// Login checks the password. For users with 2FA enabled the response only
+// carries TwoFactorRequired and VerifyTwoFactor completes the login.
+func (c *AuthUseCase) Login(ctx context.Context, request *dto.UserLoginRequest) (*dto.LoginResponse, error) {
+	ctx, span := tracer.Start(ctx, "AuthUseCase.Login")
+	defer span.End()
+
 	if err := c.Validate.Struct(request); err != nil {
+		c.Metrics.LoginAttempt("invalid_request")
 		return nil, fiber.NewError(fiber.StatusBadRequest, "invalid request")
 	}
 
+	now := time.Now()
+	identifier := c.loginIdentifier(request.Email)
+
+	// Checked before the email lookup so a blocked unknown email and a
+	// blocked existing account get the same answer.
+	if retryAfter := c.blockedFor(ctx, now, identifier, request.IPAddress); retryAfter > 0 {
+		c.Metrics.LoginAttempt("throttled")
+		return nil, &LoginThrottledError{RetryAfter: retryAfter}
+	}
+
 	user, err := c.UserRepository.FindByEmail(ctx, request.Email)
 	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			pkg.Logger(ctx, c.Log).Errorf("Failed to find user by email: %v", err)
+			c.Metrics.LoginAttempt("error")
+			return nil, fiber.ErrInternalServerError
+		}
+		_, _ = c.Hasher.Verify(request.Password, c.dummyHash())
+		c.recordFailure(ctx, now, identifier, request.IPAddress, nil)
+		c.Metrics.LoginAttempt("invalid_credentials")
 		return nil, fiber.ErrUnauthorized
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(request.Password)); err != nil {
+	if ok, err := c.Hasher.Verify(request.Password, user.Password); !ok {
+		if err != nil {
+			pkg.Logger(ctx, c.Log).Errorf("Failed to verify password hash: %v", err)
+		}
+		c.recordFailure(ctx, now, identifier, request.IPAddress, user)
+		c.Metrics.LoginAttempt("invalid_credentials")
 		return nil, fiber.ErrUnauthorized
 	}
+	c.rehash(ctx, user, request.Password)
 
 	userWithRoles, err := c.UserRepository.FindWithRoles(ctx, user.ID)
 	if err != nil {
-		c.Log.Errorf("Failed to load roles: %v", err)
+		pkg.Logger(ctx, c.Log).Errorf("Failed to load roles: %v", err)
+		c.Metrics.LoginAttempt("error")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	twoFactor, err := c.TwoFactorRepository.Find(ctx, user.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		pkg.Logger(ctx, c.Log).Errorf("Failed to find two-factor enrollment: %v", err)
+		c.Metrics.LoginAttempt("error")
+		return nil, fiber.ErrInternalServerError
+	}
+	if twoFactor != nil && twoFactor.Enabled {
+		// The account counter is kept until the second factor succeeds, so
+		// the password cannot be used to reset it between code guesses.
+		c.Metrics.LoginAttempt("two_factor_required")
+		return &dto.LoginResponse{
+			User:              converter.UserWithRolesToResponse(userWithRoles),
+			TwoFactorRequired: true,
+		}, nil
+	}
+
+	c.resetAccount(ctx, identifier)
+	c.Metrics.LoginAttempt("success")
+
+	return &dto.LoginResponse{
+		User:                        converter.UserWithRolesToResponse(userWithRoles),
+		TwoFactorEnrollmentRequired: userWithRoles.RequiresTwoFactor(),
+	}, nil
+}
+
+// rehash replaces a stored hash made with an outdated algorithm or cost
+// while the plain password is at hand. A failure only costs the upgrade.
+func (c *AuthUseCase) rehash(ctx context.Context, user *entity.User, plain string) {
+	if !c.Hasher.NeedsRehash(user.Password) {
+		return
+	}
+
+	hash, err := c.Hasher.Hash(plain)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed to rehash password: %v", err)
+		return
+	}
+	// the password itself did not change, so neither do the version and ETag
+	if err := c.UserRepository.RehashPassword(ctx, user.ID, hash); err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed to store rehashed password: %v", err)
+		return
+	}
+	user.Password = hash
+	pkg.Logger(ctx, c.Log).Info("Password hash upgraded")
+}
+
+// VerifyTwoFactor completes a login that Login left waiting for the second
+// factor. Wrong codes count against the same lockout as wrong passwords.
+func (c *AuthUseCase) VerifyTwoFactor(ctx context.Context, request *dto.TwoFactorLoginRequest) (*dto.UserResponse, error) {
+	ctx, span := tracer.Start(ctx, "AuthUseCase.VerifyTwoFactor")
+	defer span.End()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Metrics.LoginAttempt("invalid_request")
+		return nil, fiber.NewError(fiber.StatusBadRequest, "invalid request")
+	}
+
+	user, err := c.UserRepository.FindWithRoles(ctx, request.UserID)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed to find pending two-factor user: %v", err)
+		return nil, fiber.ErrUnauthorized
+	}
+
+	now := time.Now()
+	identifier := c.loginIdentifier(user.Email)
+	if retryAfter := c.blockedFor(ctx, now, identifier, request.IPAddress); retryAfter > 0 {
+		c.Metrics.LoginAttempt("throttled")
+		return nil, &LoginThrottledError{RetryAfter: retryAfter}
+	}
+
+	twoFactor, err := c.TwoFactorRepository.Find(ctx, user.ID)
+	if err != nil || !twoFactor.Enabled {
+		pkg.Logger(ctx, c.Log).Warnf("No active two-factor enrollment: %v", err)
+		return nil, fiber.ErrUnauthorized
+	}
+
+	ok, err := verifySecondFactor(ctx, c.TwoFactorRepository, twoFactor, request.Code, now)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Errorf("Failed to verify second factor: %v", err)
+		c.Metrics.LoginAttempt("error")
 		return nil, fiber.ErrInternalServerError
 	}
+	if !ok {
+		c.recordFailure(ctx, now, identifier, request.IPAddress, user)
+		c.Metrics.LoginAttempt("invalid_second_factor")
+		return nil, fiber.ErrUnauthorized
+	}
 
-	return converter.UserWithRolesToResponse(userWithRoles), nil
+	c.resetAccount(ctx, identifier)
+	c.Metrics.LoginAttempt("success")
+
+	return converter.UserWithRolesToResponse(user), nil
 }
 
-// func (c *AuthUseCase) Verify(ctx context.Context, tokenID int) (*model.Auth, error) {
-// 	token, err := c.TokenRepository.FindTokenById(tokenID)
-// 	if err != nil {
-// 		c.Log.Warnf("Failed to find token: %+v", err)
-// 		return nil, fiber.ErrUnauthorized
-// 	}
+// resetAccount clears only the account counter: a valid login of one
+// account must not reset the budget of an IP that is guessing others.
+func (c *AuthUseCase) resetAccount(ctx context.Context, identifier string) {
+	if err := c.LoginAttemptRepository.Reset(ctx, entity.LoginScopeAccount, identifier); err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed to reset login attempts: %v", err)
+	}
+}
+
+// blockedFor returns how long the account or the IP is still blocked. Lookup
+// errors are logged and fail open, so a database hiccup does not lock
+// everybody out.
+func (c *AuthUseCase) blockedFor(ctx context.Context, now time.Time, identifier string, ip string) time.Duration {
+	var retryAfter time.Duration
+	for scope, key := range c.loginKeys(identifier, ip) {
+		attempt, err := c.LoginAttemptRepository.Find(ctx, scope, key)
+		if err != nil {
+			if !errors.Is(err, sql.ErrNoRows) {
+				pkg.Logger(ctx, c.Log).Warnf("Failed to read login attempts: %v", err)
+			}
+			continue
+		}
+		if remaining := time.Unix(attempt.LockedUntil, 0).Sub(now); remaining > retryAfter {
+			retryAfter = remaining
+		}
+	}
+	return retryAfter
+}
+
+// recordFailure counts the failure against the account and the IP and blocks
+// them according to the policy. user is nil for unknown emails; they are
+// tracked all the same but nobody is notified.
+func (c *AuthUseCase) recordFailure(ctx context.Context, now time.Time, identifier string, ip string, user *entity.User) {
+	windowStart := now.Add(-c.Policy.Window).Unix()
+
+	for scope, key := range c.loginKeys(identifier, ip) {
+		attempt, err := c.LoginAttemptRepository.RecordFailure(ctx, scope, key, now.Unix(), windowStart)
+		if err != nil {
+			pkg.Logger(ctx, c.Log).Warnf("Failed to record login failure: %v", err)
+			continue
+		}
 
-// 	if time.Now().Unix() > token.ExpiredAt {
-// 		c.Log.Warn("Token expired")
-// 		return nil, fiber.ErrUnauthorized
-// 	}
+		delay := c.Policy.Delay(scope, attempt.Failures)
+		if delay == 0 {
+			continue
+		}
+		until := now.Add(delay)
+		if err := c.LoginAttemptRepository.Lock(ctx, scope, key, until.Unix()); err != nil {
+			pkg.Logger(ctx, c.Log).Warnf("Failed to lock %s: %v", scope, err)
+			continue
+		}
 
-// 	user, err := c.UserRepository.FindByID(token.UserID)
-// 	if err != nil {
-// 		c.Log.Warnf("Failed to find user by token: %+v", err)
-// 		return nil, fiber.ErrUnauthorized
-// 	}
+		if attempt.Failures != c.Policy.threshold(scope) {
+			continue
+		}
+		c.Metrics.Lockout(string(scope))
+		pkg.Logger(ctx, c.Log).WithField("scope", scope).Warnf("Login locked until %s after %d failures", until.UTC().Format(time.RFC3339), attempt.Failures)
 
-// 	return &model.Auth{
-// 		ID: user.ID,
-// 	}, nil
-// }
+		if scope == entity.LoginScopeAccount && user != nil {
+			// Sent in the background so the response time does not reveal
+			// that the account exists.
+			go func(ctx context.Context) {
+				if err := c.Notifier.AccountLocked(ctx, user, until); err != nil {
+					pkg.Logger(ctx, c.Log).Errorf("Failed to send lockout notice: %v", err)
+				}
+			}(context.WithoutCancel(ctx))
+		}
+	}
+}
+
+// ReauthenticationBlockedFor is blockedFor for a signed-in user who has to
+// confirm the password or a code again, e.g. to disable 2FA.
+func (c *AuthUseCase) ReauthenticationBlockedFor(ctx context.Context, user *entity.User, ip string) time.Duration {
+	return c.blockedFor(ctx, time.Now(), c.loginIdentifier(user.Email), ip)
+}
+
+// RecordReauthenticationFailure counts a wrong password or code of a
+// signed-in user against the same lockout as a failed login, so such
+// endpoints cannot be used to guess them instead.
+func (c *AuthUseCase) RecordReauthenticationFailure(ctx context.Context, user *entity.User, ip string) {
+	c.recordFailure(ctx, time.Now(), c.loginIdentifier(user.Email), ip, user)
+}
+
+func (c *AuthUseCase) loginKeys(identifier string, ip string) map[entity.LoginScope]string {
+	keys := map[entity.LoginScope]string{entity.LoginScopeAccount: identifier}
+	if ip != "" {
+		keys[entity.LoginScopeIP] = ip
+	}
+	return keys
+}
+
+// Unlock lifts the login lockout and delay of a user's account.
+func (c *AuthUseCase) Unlock(ctx context.Context, userID int) error {
+	ctx, span := tracer.Start(ctx, "AuthUseCase.Unlock")
+	defer span.End()
+
+	user, err := c.UserRepository.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fiber.NewError(fiber.StatusNotFound, "user not found")
+		}
+		pkg.Logger(ctx, c.Log).Errorf("Failed to find user: %v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	tx, err := c.DB.BeginTx(ctx, nil)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed to begin transaction: %+v", err)
+		return fiber.ErrInternalServerError
+	}
+	defer tx.Rollback()
+
+	if err := c.LoginAttemptRepository.WithTx(tx).Reset(ctx, entity.LoginScopeAccount, c.loginIdentifier(user.Email)); err != nil {
+		pkg.Logger(ctx, c.Log).Errorf("Failed to reset login attempts: %v", err)
+		return fiber.ErrInternalServerError
+	}
+	if err := c.Audit.Record(ctx, tx, entity.AuditActionUnlock, entity.AuditEntityUser, userID, nil, nil); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed commit transaction : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	pkg.Logger(ctx, c.Log).WithField("target_user_id", userID).Info("Login lockout lifted")
+	return nil
+}
+
+// Authorize reports fiber.ErrForbidden unless one of the user's roles grants
+// permission.
+func (c *AuthUseCase) Authorize(ctx context.Context, userID int, permission entity.Permissions) error {
+	ctx, span := tracer.Start(ctx, "AuthUseCase.Authorize")
+	defer span.End()
+
+	user, err := c.UserRepository.FindWithRoles(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fiber.ErrUnauthorized
+		}
+		pkg.Logger(ctx, c.Log).Errorf("Failed to load roles: %v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if !user.HasPermission(permission) {
+		pkg.Logger(ctx, c.Log).Warnf("Missing permission %s", permission)
+		return fiber.ErrForbidden
+	}
+	return nil
+}
+
+// Viewer loads the permissions responses are masked by. A token only
+// unmasks what its scopes cover.
+func (c *AuthUseCase) Viewer(ctx context.Context, userID int, token *entity.PersonalAccessToken) (mask.Viewer, error) {
+	ctx, span := tracer.Start(ctx, "AuthUseCase.Viewer")
+	defer span.End()
+
+	user, err := c.UserRepository.FindWithRoles(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return mask.Viewer{}, fiber.ErrUnauthorized
+		}
+		pkg.Logger(ctx, c.Log).Errorf("Failed to load roles: %v", err)
+		return mask.Viewer{}, fiber.ErrInternalServerError
+	}
+
+	return mask.Viewer{
+		ID: userID,
+		Allowed: func(permission string) bool {
+			p := entity.Permissions(permission)
+			return user.HasPermission(p) && (token == nil || token.HasScope(p))
+		},
+	}, nil
+}
+
+// CheckSession rejects a session created before the user revoked their
+// sessions, e.g. when changing the password.
+func (c *AuthUseCase) CheckSession(ctx context.Context, userID int, createdAt int64) error {
+	user, err := c.UserRepository.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fiber.ErrUnauthorized
+		}
+		pkg.Logger(ctx, c.Log).Errorf("Failed to load user: %v", err)
+		return fiber.ErrInternalServerError
+	}
+	if createdAt < user.SessionsRevokedAt {
+		return fiber.NewError(fiber.StatusUnauthorized, "session revoked")
+	}
+	return nil
+}
+
+// PurgeLoginAttempts removes counters that are outside the window and no
+// longer locked.
+func (c *AuthUseCase) PurgeLoginAttempts(ctx context.Context) error {
+	now := time.Now()
+	deleted, err := c.LoginAttemptRepository.DeleteExpired(ctx, now.Add(-c.Policy.Window).Unix(), now.Unix())
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		pkg.Logger(ctx, c.Log).Debugf("Purged %d expired login attempt counters", deleted)
+	}
+	return nil
+}
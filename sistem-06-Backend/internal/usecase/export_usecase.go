@@ -0,0 +1,259 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	stdErrors "errors"
+	"fmt"
+	"io"
+	"iter"
+	"strconv"
+	"strings"
+	"time"
+
+	"sistem-06-Backend/internal/delivery/http/converter"
+	"sistem-06-Backend/internal/domain/entity"
+	domain "sistem-06-Backend/internal/domain/ports"
+	"sistem-06-Backend/internal/dto"
+	"sistem-06-Backend/internal/pkg/listquery"
+	"sistem-06-Backend/internal/pkg/mask"
+	"sistem-06-Backend/internal/pkg/spreadsheet"
+	"sistem-06-Backend/pkg"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// exportColumn is one column of an export. Value renders it for a row.
+type exportColumn[T any] struct {
+	Header string
+	Value  func(T) string
+}
+
+type ExportUseCase struct {
+	DB                *sql.DB
+	Log               *logrus.Logger
+	UserRepository    domain.UserRepository
+	AddressRepository domain.AddressRepository
+	Audit             *AuditRecorder
+}
+
+func NewExportUseCase(db *sql.DB, log *logrus.Logger, userRepository domain.UserRepository, addressRepository domain.AddressRepository, audit *AuditRecorder) *ExportUseCase {
+	return &ExportUseCase{
+		DB:                db,
+		Log:               log,
+		UserRepository:    userRepository,
+		AddressRepository: addressRepository,
+		Audit:             audit,
+	}
+}
+
+// Users exports the users matching the filters of GET /users, with their
+// roles. It needs users.manage or data.export; emails are masked like in
+// the responses, see dto.UserResponse. query also carries "format", one of
+// csv (the default), xlsx or pdf.
+func (c *ExportUseCase) Users(ctx context.Context, viewerID int, token *entity.PersonalAccessToken, query map[string]string) (*dto.ExportFile, error) {
+	ctx, span := tracer.Start(ctx, "ExportUseCase.Users")
+	defer span.End()
+
+	allowed, err := c.allowed(ctx, viewerID, token)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed(entity.PermissionManageUsers) && !allowed(entity.PermissionExportData) {
+		return nil, fiber.ErrForbidden
+	}
+
+	format, request, err := c.request(ctx, "users", query)
+	if err != nil {
+		return nil, err
+	}
+	users, err := c.UserRepository.Export(ctx, request)
+	if err != nil {
+		return nil, listError(ctx, c.Log, "users", err)
+	}
+	if err := c.record(ctx, entity.AuditEntityUser, format, query); err != nil {
+		return nil, err
+	}
+
+	rows := maskedRows(users, func(u *entity.User) *dto.UserResponse {
+		response := converter.UserWithRolesToResponse(u)
+		response.CreatedAt = u.CreatedAt
+		return response
+	}, maskViewer(viewerID, allowed))
+	return exportFile(ctx, c.Log, "users", format, rows, []exportColumn[*dto.UserResponse]{
+		{Header: "ID", Value: func(u *dto.UserResponse) string { return strconv.Itoa(u.ID) }},
+		{Header: "Name", Value: func(u *dto.UserResponse) string { return u.Name }},
+		{Header: "Email", Value: func(u *dto.UserResponse) string { return u.Email }},
+		{Header: "Roles", Value: func(u *dto.UserResponse) string {
+			names := make([]string, len(u.Roles))
+			for i, role := range u.Roles {
+				names[i] = role.Name
+			}
+			return strings.Join(names, ", ")
+		}},
+		{Header: "Created At", Value: func(u *dto.UserResponse) string { return exportTime(u.CreatedAt) }},
+	}), nil
+}
+
+// Addresses exports the addresses matching the filters of GET /addresses.
+// It needs addresses.manage or data.export; streets are masked like in the
+// responses, see dto.AddressEntity.
+func (c *ExportUseCase) Addresses(ctx context.Context, viewerID int, token *entity.PersonalAccessToken, query map[string]string) (*dto.ExportFile, error) {
+	ctx, span := tracer.Start(ctx, "ExportUseCase.Addresses")
+	defer span.End()
+
+	allowed, err := c.allowed(ctx, viewerID, token)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed(entity.PermissionManageAddresses) && !allowed(entity.PermissionExportData) {
+		return nil, fiber.ErrForbidden
+	}
+
+	format, request, err := c.request(ctx, "addresses", query)
+	if err != nil {
+		return nil, err
+	}
+	addresses, err := c.AddressRepository.Export(ctx, request)
+	if err != nil {
+		return nil, listError(ctx, c.Log, "addresses", err)
+	}
+	if err := c.record(ctx, entity.AuditEntityAddress, format, query); err != nil {
+		return nil, err
+	}
+
+	rows := maskedRows(addresses, converter.AddressToResponse, maskViewer(viewerID, allowed))
+	return exportFile(ctx, c.Log, "addresses", format, rows, []exportColumn[*dto.AddressEntity]{
+		{Header: "ID", Value: func(a *dto.AddressEntity) string { return strconv.Itoa(a.ID) }},
+		{Header: "Jalan", Value: func(a *dto.AddressEntity) string { return a.Jalan }},
+		{Header: "RT", Value: func(a *dto.AddressEntity) string { return a.RT }},
+		{Header: "RW", Value: func(a *dto.AddressEntity) string { return a.RW }},
+		{Header: "Kota", Value: func(a *dto.AddressEntity) string { return a.Kota }},
+		{Header: "Postal Code", Value: func(a *dto.AddressEntity) string { return a.PostalCode }},
+	}), nil
+}
+
+// allowed loads the roles of the viewer once. A token also needs the
+// permission among its scopes.
+func (c *ExportUseCase) allowed(ctx context.Context, viewerID int, token *entity.PersonalAccessToken) (func(entity.Permissions) bool, error) {
+	viewer, err := c.UserRepository.FindWithRoles(ctx, viewerID)
+	if err != nil {
+		if stdErrors.Is(err, sql.ErrNoRows) {
+			return nil, fiber.ErrUnauthorized
+		}
+		pkg.Logger(ctx, c.Log).Errorf("Failed to load roles: %v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	return func(permission entity.Permissions) bool {
+		return viewer.HasPermission(permission) && (token == nil || token.HasScope(permission))
+	}, nil
+}
+
+// maskViewer masks for the viewer with the permissions checked by allowed.
+func maskViewer(viewerID int, allowed func(entity.Permissions) bool) mask.Viewer {
+	return mask.Viewer{
+		ID:      viewerID,
+		Allowed: func(permission string) bool { return allowed(entity.Permissions(permission)) },
+	}
+}
+
+// maskedRows converts rows to their responses as they come and masks each
+// one for viewer, see mask.Apply.
+func maskedRows[E any, R any](rows iter.Seq2[E, error], convert func(E) R, viewer mask.Viewer) iter.Seq2[R, error] {
+	return func(yield func(R, error) bool) {
+		for row, err := range rows {
+			var response R
+			if err == nil {
+				response = convert(row)
+				mask.Apply(response, viewer)
+			}
+			if !yield(response, err) {
+				return
+			}
+		}
+	}
+}
+
+func (c *ExportUseCase) request(ctx context.Context, resource string, query map[string]string) (string, *listquery.Request, error) {
+	format := query["format"]
+	if format == "" {
+		format = spreadsheet.CSV
+	}
+	if format != spreadsheet.CSV && format != spreadsheet.XLSX && format != spreadsheet.PDF {
+		return "", nil, fiber.NewError(fiber.StatusBadRequest, spreadsheet.ErrUnsupportedExport.Error())
+	}
+	request, err := listquery.Parse(query)
+	if err != nil {
+		return "", nil, listError(ctx, c.Log, resource, err)
+	}
+	return format, request, nil
+}
+
+// record notes who exported what before anything is sent.
+func (c *ExportUseCase) record(ctx context.Context, entityType string, format string, query map[string]string) error {
+	tx, err := c.DB.BeginTx(ctx, nil)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed to begin transaction: %+v", err)
+		return fiber.ErrInternalServerError
+	}
+	defer tx.Rollback()
+
+	if err := c.Audit.Record(ctx, tx, entity.AuditActionExport, entityType, "*", nil, map[string]any{"format": format, "query": query}); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed commit transaction : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	pkg.Logger(ctx, c.Log).WithFields(logrus.Fields{"entity_type": entityType, "format": format}).Info("Export started")
+	return nil
+}
+
+// exportFile writes rows as they come from the database. The status line is
+// already sent by then, so a failure can only cut the file short.
+func exportFile[T any](ctx context.Context, log *logrus.Logger, name string, format string, rows iter.Seq2[T, error], columns []exportColumn[T]) *dto.ExportFile {
+	return &dto.ExportFile{
+		Filename:    fmt.Sprintf("%s-%s.%s", name, time.Now().Format("20060102-150405"), format),
+		ContentType: spreadsheet.ContentType(format),
+		Write: func(w io.Writer) error {
+			writer, err := spreadsheet.NewWriter(format, w, name)
+			if err != nil {
+				return err
+			}
+			cells := make([]string, len(columns))
+			for i, column := range columns {
+				cells[i] = column.Header
+			}
+			if err := writer.Write(cells); err != nil {
+				return err
+			}
+
+			count := 0
+			for row, err := range rows {
+				if err != nil {
+					pkg.Logger(ctx, log).Errorf("Failed to export %s: %v", name, err)
+					return err
+				}
+				for i, column := range columns {
+					cells[i] = column.Value(row)
+				}
+				if err := writer.Write(cells); err != nil {
+					return err
+				}
+				count++
+			}
+			if err := writer.Close(); err != nil {
+				return err
+			}
+
+			pkg.Logger(ctx, log).WithFields(logrus.Fields{"export": name, "rows": count}).Info("Export finished")
+			return nil
+		},
+	}
+}
+
+func exportTime(unix int64) string {
+	return time.Unix(unix, 0).UTC().Format(time.RFC3339)
+}
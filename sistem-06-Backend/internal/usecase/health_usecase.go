@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	domain "sistem-06-Backend/internal/domain/ports"
+	"sistem-06-Backend/internal/dto"
+	"sistem-06-Backend/internal/pkg/buildinfo"
+	"sistem-06-Backend/pkg"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	healthStatusOK   = "ok"
+	healthStatusFail = "fail"
+
+	// storage probe key, only read, never written
+	healthStorageProbeKey = "__readyz"
+
+	healthCheckTimeout = 2 * time.Second
+)
+
+type HealthUseCase struct {
+	DB                       *sql.DB
+	Log                      *logrus.Logger
+	Storage                  fiber.Storage
+	HealthRepository         domain.HealthRepository
+	ExpectedMigrationVersion int64
+	IsReady                  func() bool
+	StartedAt                time.Time
+}
+
+func NewHealthUseCase(db *sql.DB, log *logrus.Logger, storage fiber.Storage, healthRepository domain.HealthRepository, expectedMigrationVersion int64, isReady func() bool) *HealthUseCase {
+	return &HealthUseCase{
+		DB:                       db,
+		Log:                      log,
+		Storage:                  storage,
+		HealthRepository:         healthRepository,
+		ExpectedMigrationVersion: expectedMigrationVersion,
+		IsReady:                  isReady,
+		StartedAt:                time.Now(),
+	}
+}
+
+// Readiness runs every dependency check and reports whether the instance
+// should receive traffic.
+func (c *HealthUseCase) Readiness(ctx context.Context) (*dto.ReadinessResponse, bool) {
+	ctx, span := tracer.Start(ctx, "HealthUseCase.Readiness")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	response := &dto.ReadinessResponse{
+		Status: healthStatusOK,
+		Checks: map[string]dto.HealthCheck{},
+	}
+
+	response.Checks["lifecycle"] = runCheck(func() error {
+		if !c.IsReady() {
+			return fmt.Errorf("not accepting traffic")
+		}
+		return nil
+	})
+	response.Checks["database"] = runCheck(func() error {
+		return c.HealthRepository.Ping(ctx)
+	})
+	response.Checks["session_storage"] = runCheck(func() error {
+		_, err := c.Storage.Get(healthStorageProbeKey)
+		return err
+	})
+	response.Checks["migrations"] = runCheck(func() error {
+		version, dirty, err := c.HealthRepository.MigrationVersion(ctx)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return fmt.Errorf("migration %d is dirty", version)
+		}
+		if version < c.ExpectedMigrationVersion {
+			return fmt.Errorf("schema version %d is behind expected %d", version, c.ExpectedMigrationVersion)
+		}
+		return nil
+	})
+
+	ready := true
+	for name, check := range response.Checks {
+		if check.Status != healthStatusOK {
+			pkg.Logger(ctx, c.Log).Warnf("Readiness check %s failed: %s", name, check.Detail)
+			ready = false
+		}
+	}
+	if !ready {
+		response.Status = healthStatusFail
+	}
+	return response, ready
+}
+
+func (c *HealthUseCase) Status(ctx context.Context) *dto.StatusResponse {
+	_, span := tracer.Start(ctx, "HealthUseCase.Status")
+	defer span.End()
+
+	stats := c.DB.Stats()
+
+	return &dto.StatusResponse{
+		Build:         buildinfo.Get(),
+		StartedAt:     c.StartedAt.Unix(),
+		UptimeSeconds: int64(time.Since(c.StartedAt).Seconds()),
+		Ready:         c.IsReady(),
+		Database: dto.SQLStats{
+			MaxOpenConnections: stats.MaxOpenConnections,
+			OpenConnections:    stats.OpenConnections,
+			InUse:              stats.InUse,
+			Idle:               stats.Idle,
+			WaitCount:          stats.WaitCount,
+			WaitDurationMs:     stats.WaitDuration.Milliseconds(),
+			MaxIdleClosed:      stats.MaxIdleClosed,
+			MaxIdleTimeClosed:  stats.MaxIdleTimeClosed,
+			MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+		},
+	}
+}
+
+func runCheck(check func() error) dto.HealthCheck {
+	start := time.Now()
+	err := check()
+	result := dto.HealthCheck{
+		Status:    healthStatusOK,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		result.Status = healthStatusFail
+		result.Detail = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,534 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	stdErrors "errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"sistem-06-Backend/internal/delivery/http/converter"
+	"sistem-06-Backend/internal/domain/entity"
+	domain "sistem-06-Backend/internal/domain/ports"
+	"sistem-06-Backend/internal/dto"
+	"sistem-06-Backend/internal/pkg/errors"
+	"sistem-06-Backend/internal/pkg/password"
+	"sistem-06-Backend/internal/pkg/spreadsheet"
+	"sistem-06-Backend/pkg"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// importProgressEvery is how many rows are checked between two progress
+// writes.
+const importProgressEvery = 100
+
+// maxImportErrors bounds the row errors kept for one job.
+const maxImportErrors = 1000
+
+var (
+	errImportInterrupted = stdErrors.New("import interrupted by shutdown, nothing was imported")
+	errImportFailed      = stdErrors.New("import failed, nothing was imported")
+)
+
+// importField is a column an import understands. Struct is the request
+// field the validator reports the column under.
+type importField struct {
+	Name     string
+	Struct   string
+	Optional bool
+}
+
+var addressImportFields = []importField{
+	{Name: "jalan", Struct: "Jalan"},
+	{Name: "rt", Struct: "RT"},
+	{Name: "rw", Struct: "RW"},
+	{Name: "kota", Struct: "Kota"},
+	{Name: "postal_code", Struct: "PostalCode"},
+}
+
+// roles holds role names separated by commas or semicolons.
+var userImportFields = []importField{
+	{Name: "name", Struct: "Name"},
+	{Name: "email", Struct: "Email"},
+	{Name: "password", Struct: "Password"},
+	{Name: "roles", Struct: "Roles", Optional: true},
+}
+
+type importedUser struct {
+	row   int
+	user  *entity.User
+	roles []entity.Role
+}
+
+type ImportUseCase struct {
+	DB                  *sql.DB
+	Log                 *logrus.Logger
+	Validate            *validator.Validate
+	UserRepository      domain.UserRepository
+	AddressRepository   domain.AddressRepository
+	RolesRepository     domain.RolesRepository
+	ImportJobRepository domain.ImportJobRepository
+	PasswordPolicy      *password.Policy
+	Hasher              password.Hasher
+	Audit               *AuditRecorder
+	// Run starts a job in the background, see config.Lifecycle.Go
+	Run         func(name string, fn func(ctx context.Context))
+	MaxRows     int
+	MaxFileSize int64
+}
+
+func NewImportUseCase(db *sql.DB, log *logrus.Logger, validate *validator.Validate, userRepository domain.UserRepository, addressRepository domain.AddressRepository, rolesRepository domain.RolesRepository, importJobRepository domain.ImportJobRepository, passwordPolicy *password.Policy, hasher password.Hasher, audit *AuditRecorder, run func(name string, fn func(ctx context.Context)), maxRows int, maxFileSize int64) *ImportUseCase {
+	return &ImportUseCase{
+		DB:                  db,
+		Log:                 log,
+		Validate:            validate,
+		UserRepository:      userRepository,
+		AddressRepository:   addressRepository,
+		RolesRepository:     rolesRepository,
+		ImportJobRepository: importJobRepository,
+		PasswordPolicy:      passwordPolicy,
+		Hasher:              hasher,
+		Audit:               audit,
+		Run:                 run,
+		MaxRows:             maxRows,
+		MaxFileSize:         maxFileSize,
+	}
+}
+
+// Start reads the uploaded file and hands its rows to a background job,
+// which checks every row like the single create endpoints do. Unless the
+// job is a dry run, the rows are then imported in one transaction, and only
+// if none of them has an error. Problems with the file as a whole, such as
+// an unreadable format or a missing column, are reported right away.
+func (c *ImportUseCase) Start(ctx context.Context, userID int, kind string, request *dto.ImportRequest) (*dto.ImportJobResponse, error) {
+	ctx, span := tracer.Start(ctx, "ImportUseCase.Start")
+	defer span.End()
+
+	fields := addressImportFields
+	if kind == entity.ImportKindUser {
+		fields = userImportFields
+	}
+
+	if int64(len(request.Content)) > c.MaxFileSize {
+		return nil, fiber.NewError(fiber.StatusRequestEntityTooLarge, fmt.Sprintf("file must not be larger than %d bytes", c.MaxFileSize))
+	}
+	format, err := spreadsheet.FormatOf(request.Filename)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	rows, err := spreadsheet.Read(format, bytes.NewReader(request.Content), int64(len(request.Content)))
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed to read import file: %v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, "failed to read file: "+err.Error())
+	}
+	if len(rows) < 2 {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "file has no rows below the header")
+	}
+	columns, err := importColumns(rows[0].Cells, fields, request.Mapping)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	rows = rows[1:]
+	if len(rows) > c.MaxRows {
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("file has %d rows, at most %d are allowed", len(rows), c.MaxRows))
+	}
+
+	job := &entity.ImportJob{
+		ID:        uuid.NewString(),
+		Kind:      kind,
+		Status:    entity.ImportStatusPending,
+		DryRun:    request.DryRun,
+		TotalRows: len(rows),
+		CreatedBy: userID,
+		CreatedAt: time.Now().Unix(),
+	}
+	if err := c.ImportJobRepository.Create(ctx, job); err != nil {
+		pkg.Logger(ctx, c.Log).Errorf("Failed to create import job: %v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	// taken before the job runs, which is right away in tests
+	response := converter.ImportJobToResponse(job)
+
+	pkg.Logger(ctx, c.Log).WithFields(logrus.Fields{"import_id": job.ID, "kind": kind, "rows": len(rows), "dry_run": job.DryRun}).Info("Import started")
+	requestCtx := ctx
+	c.Run("import "+job.ID, func(ctx context.Context) {
+		c.process(jobContext(ctx, requestCtx, c.Log), job, rows, columns)
+	})
+	return response, nil
+}
+
+// Get reports the progress of a job. Jobs are only visible to whoever
+// started them.
+func (c *ImportUseCase) Get(ctx context.Context, viewerID int, id string) (*dto.ImportJobResponse, error) {
+	ctx, span := tracer.Start(ctx, "ImportUseCase.Get")
+	defer span.End()
+
+	job, err := c.ImportJobRepository.FindByID(ctx, id)
+	if err != nil {
+		if stdErrors.Is(err, sql.ErrNoRows) {
+			return nil, fiber.NewError(fiber.StatusNotFound, "import not found")
+		}
+		pkg.Logger(ctx, c.Log).Errorf("Failed to load import job: %v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	if job.CreatedBy != viewerID {
+		return nil, fiber.NewError(fiber.StatusNotFound, "import not found")
+	}
+	return converter.ImportJobToResponse(job), nil
+}
+
+func (c *ImportUseCase) process(ctx context.Context, job *entity.ImportJob, rows []spreadsheet.Row, columns map[string]int) {
+	ctx, span := tracer.Start(ctx, "ImportUseCase.process")
+	defer span.End()
+
+	job.Status = entity.ImportStatusRunning
+	c.progress(ctx, job)
+
+	var err error
+	switch job.Kind {
+	case entity.ImportKindAddress:
+		err = c.importAddresses(ctx, job, rows, columns)
+	case entity.ImportKindUser:
+		err = c.importUsers(ctx, job, rows, columns)
+	}
+
+	job.Status = entity.ImportStatusSucceeded
+	if err != nil {
+		job.ImportedRows = 0
+		job.Errors = append(job.Errors, entity.ImportRowError{Message: err.Error()})
+	}
+	if len(job.Errors) > 0 {
+		job.Status = entity.ImportStatusFailed
+	}
+	job.FinishedAt = time.Now().Unix()
+
+	// the worker context is canceled on shutdown, the outcome is still stored
+	if err := c.ImportJobRepository.Finish(context.WithoutCancel(ctx), job); err != nil {
+		pkg.Logger(ctx, c.Log).Errorf("Failed to finish import job %s: %v", job.ID, err)
+		return
+	}
+	pkg.Logger(ctx, c.Log).WithFields(logrus.Fields{
+		"import_id": job.ID,
+		"status":    job.Status,
+		"imported":  job.ImportedRows,
+		"errors":    len(job.Errors),
+	}).Info("Import finished")
+}
+
+func (c *ImportUseCase) importAddresses(ctx context.Context, job *entity.ImportJob, rows []spreadsheet.Row, columns map[string]int) error {
+	check := func(ctx context.Context, row spreadsheet.Row) (*entity.Address, bool, error) {
+		request := &dto.AddressRequest{
+			Jalan:      importCell(row, columns, "jalan"),
+			RT:         importCell(row, columns, "rt"),
+			RW:         importCell(row, columns, "rw"),
+			Kota:       importCell(row, columns, "kota"),
+			PostalCode: importCell(row, columns, "postal_code"),
+		}
+		if validationErrors := errors.ValidationError(c.Validate.Struct(request)); len(validationErrors) > 0 {
+			addImportFieldErrors(job, row.Number, addressImportFields, validationErrors)
+			return nil, false, nil
+		}
+		return &entity.Address{
+			Jalan:      request.Jalan,
+			RT:         request.RT,
+			RW:         request.RW,
+			Kota:       request.Kota,
+			PostalCode: request.PostalCode,
+		}, true, nil
+	}
+
+	write := func(ctx context.Context, tx *sql.Tx, addresses []*entity.Address) error {
+		addressRepository := c.AddressRepository.WithTx(tx)
+		for _, address := range addresses {
+			if err := addressRepository.CreateAddress(ctx, address); err != nil {
+				pkg.Logger(ctx, c.Log).Errorf("Failed to import address: %v", err)
+				return errImportFailed
+			}
+			if err := c.Audit.Record(ctx, tx, entity.AuditActionCreate, entity.AuditEntityAddress, address.ID, nil, converter.AddressToResponse(address)); err != nil {
+				return errImportFailed
+			}
+		}
+		return nil
+	}
+
+	return importRows(ctx, c, job, rows, check, write)
+}
+
+func (c *ImportUseCase) importUsers(ctx context.Context, job *entity.ImportJob, rows []spreadsheet.Row, columns map[string]int) error {
+	// rows of the file by lower case email and by name, to catch duplicates
+	// that the database has not seen yet
+	emails := map[string]int{}
+	names := map[string]int{}
+	// nil for names that are not a live role
+	roles := map[string]*entity.Role{}
+
+	check := func(ctx context.Context, row spreadsheet.Row) (*importedUser, bool, error) {
+		request := &dto.RegisterUserRequest{
+			Name:     importCell(row, columns, "name"),
+			Email:    importCell(row, columns, "email"),
+			Password: importCell(row, columns, "password"),
+		}
+		validationErrors := errors.UserValidationError(c.Validate.Struct(request))
+		validationErrors = checkPassword(c.PasswordPolicy, validationErrors, request.Password, request.Email, request.Name)
+		if validationErrors == nil {
+			validationErrors = map[string]string{}
+		}
+
+		if _, invalid := validationErrors["Email"]; !invalid {
+			key := strings.ToLower(request.Email)
+			if first, seen := emails[key]; seen {
+				validationErrors["Email"] = fmt.Sprintf("Email is already used in row %d", first)
+			} else if _, err := c.UserRepository.FindByEmail(ctx, request.Email); err == nil {
+				validationErrors["Email"] = "Email is already used"
+			} else if !stdErrors.Is(err, sql.ErrNoRows) {
+				pkg.Logger(ctx, c.Log).Errorf("Failed to look up email: %v", err)
+				return nil, false, errImportFailed
+			} else {
+				emails[key] = row.Number
+			}
+		}
+		if _, invalid := validationErrors["Name"]; !invalid {
+			if first, seen := names[request.Name]; seen {
+				validationErrors["Name"] = fmt.Sprintf("Name is already used in row %d", first)
+			} else if count, err := c.UserRepository.CountByName(ctx, request.Name); err != nil {
+				pkg.Logger(ctx, c.Log).Errorf("Failed to look up name: %v", err)
+				return nil, false, errImportFailed
+			} else if count > 0 {
+				validationErrors["Name"] = "Name is already used"
+			} else {
+				names[request.Name] = row.Number
+			}
+		}
+
+		var userRoles []entity.Role
+		var unknown []string
+		for _, name := range strings.FieldsFunc(importCell(row, columns, "roles"), func(r rune) bool { return r == ',' || r == ';' }) {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			role, cached := roles[name]
+			if !cached {
+				found, err := c.RolesRepository.FindByName(ctx, name)
+				if err != nil && !stdErrors.Is(err, sql.ErrNoRows) {
+					pkg.Logger(ctx, c.Log).Errorf("Failed to look up role: %v", err)
+					return nil, false, errImportFailed
+				}
+				role = found
+				roles[name] = role
+			}
+			if role == nil {
+				unknown = append(unknown, name)
+				continue
+			}
+			userRoles = append(userRoles, *role)
+		}
+		if len(unknown) > 0 {
+			validationErrors["Roles"] = "Roles has unknown roles: " + strings.Join(unknown, ", ")
+		}
+
+		if len(validationErrors) > 0 {
+			addImportFieldErrors(job, row.Number, userImportFields, validationErrors)
+			return nil, false, nil
+		}
+
+		// hashing is slow, so it is skipped once the import can no longer
+		// be written
+		var hash string
+		if !job.DryRun && len(job.Errors) == 0 {
+			var err error
+			if hash, err = c.Hasher.Hash(request.Password); err != nil {
+				pkg.Logger(ctx, c.Log).Errorf("Failed to hash password: %v", err)
+				return nil, false, errImportFailed
+			}
+		}
+		now := time.Now().Unix()
+		return &importedUser{
+			row: row.Number,
+			user: &entity.User{
+				Name:      request.Name,
+				Email:     request.Email,
+				Password:  hash,
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+			roles: userRoles,
+		}, true, nil
+	}
+
+	write := func(ctx context.Context, tx *sql.Tx, users []*importedUser) error {
+		userRepository := c.UserRepository.WithTx(tx)
+		rolesRepository := c.RolesRepository.WithTx(tx)
+		for _, imported := range users {
+			if err := userRepository.CreateUser(ctx, imported.user); err != nil {
+				if strings.Contains(err.Error(), "duplicate key") {
+					addImportError(job, imported.row, "", "email or name already exist")
+					return errImportFailed
+				}
+				pkg.Logger(ctx, c.Log).Errorf("Failed to import user: %v", err)
+				return errImportFailed
+			}
+			for _, role := range imported.roles {
+				if err := rolesRepository.AssignRoleToUser(ctx, imported.user.ID, role.ID); err != nil {
+					pkg.Logger(ctx, c.Log).Errorf("Failed to assign imported role: %v", err)
+					return errImportFailed
+				}
+			}
+			imported.user.Roles = imported.roles
+			if err := c.Audit.Record(ctx, tx, entity.AuditActionCreate, entity.AuditEntityUser, imported.user.ID, nil, converter.UserWithRolesToResponse(imported.user)); err != nil {
+				return errImportFailed
+			}
+		}
+		return nil
+	}
+
+	return importRows(ctx, c, job, rows, check, write)
+}
+
+// importRows runs check over every row, recording progress as it goes. check
+// adds the errors of a bad row to the job itself and reports false; an error
+// it returns ends the job. Only when every row passed and the job is not a
+// dry run are the checked records handed to write, all in one transaction.
+func importRows[T any](ctx context.Context, c *ImportUseCase, job *entity.ImportJob, rows []spreadsheet.Row, check func(ctx context.Context, row spreadsheet.Row) (T, bool, error), write func(ctx context.Context, tx *sql.Tx, records []T) error) error {
+	records := make([]T, 0, len(rows))
+	for i, row := range rows {
+		if ctx.Err() != nil {
+			return errImportInterrupted
+		}
+		record, ok, err := check(ctx, row)
+		if err != nil {
+			return err
+		}
+		if ok {
+			records = append(records, record)
+		}
+		job.ProcessedRows = i + 1
+		if job.ProcessedRows%importProgressEvery == 0 {
+			c.progress(ctx, job)
+		}
+	}
+	if job.DryRun || len(job.Errors) > 0 {
+		return nil
+	}
+
+	tx, err := c.DB.BeginTx(ctx, nil)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed to begin transaction: %+v", err)
+		if ctx.Err() != nil {
+			return errImportInterrupted
+		}
+		return errImportFailed
+	}
+	defer tx.Rollback()
+
+	if err := write(ctx, tx, records); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed commit transaction : %+v", err)
+		return errImportFailed
+	}
+	job.ImportedRows = len(records)
+	return nil
+}
+
+// progress stores how far the job got. A failed write only delays what
+// pollers see, so it does not stop the job.
+func (c *ImportUseCase) progress(ctx context.Context, job *entity.ImportJob) {
+	if err := c.ImportJobRepository.UpdateProgress(ctx, job); err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed to store import progress: %v", err)
+	}
+}
+
+// importColumns finds the column of every field from the header row. A
+// header listed in mapping is read as the field it maps to, any other header
+// as the field of the same name. Columns of no field are ignored.
+func importColumns(header []string, fields []importField, mapping map[string]string) (map[string]int, error) {
+	known := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		known[field.Name] = true
+	}
+	for source, target := range mapping {
+		if !known[target] {
+			return nil, fmt.Errorf("mapping of %q: unknown field %q", source, target)
+		}
+	}
+
+	columns := make(map[string]int, len(fields))
+	mapped := make(map[string]bool, len(mapping))
+	for i, cell := range header {
+		cell = strings.TrimSpace(cell)
+		name, ok := mapping[cell]
+		if ok {
+			mapped[cell] = true
+		} else {
+			name = strings.ToLower(cell)
+		}
+		if !known[name] {
+			continue
+		}
+		if _, duplicate := columns[name]; duplicate {
+			return nil, fmt.Errorf("more than one column holds %s", name)
+		}
+		columns[name] = i
+	}
+
+	for source := range mapping {
+		if !mapped[source] {
+			return nil, fmt.Errorf("mapped column %q is not in the header", source)
+		}
+	}
+	var missing []string
+	for _, field := range fields {
+		if _, found := columns[field.Name]; !found && !field.Optional {
+			missing = append(missing, field.Name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing columns: %s", strings.Join(missing, ", "))
+	}
+	return columns, nil
+}
+
+func importCell(row spreadsheet.Row, columns map[string]int, field string) string {
+	column, ok := columns[field]
+	if !ok || column >= len(row.Cells) {
+		return ""
+	}
+	return strings.TrimSpace(row.Cells[column])
+}
+
+// addImportFieldErrors adds the validation errors of a row in column order,
+// under the import field names.
+func addImportFieldErrors(job *entity.ImportJob, row int, fields []importField, validationErrors map[string]string) {
+	for _, field := range fields {
+		if message, ok := validationErrors[field.Struct]; ok {
+			addImportError(job, row, field.Name, message)
+		}
+	}
+}
+
+func addImportError(job *entity.ImportJob, row int, field string, message string) {
+	switch {
+	case len(job.Errors) < maxImportErrors:
+		job.Errors = append(job.Errors, entity.ImportRowError{Row: row, Field: field, Message: message})
+	case len(job.Errors) == maxImportErrors:
+		job.Errors = append(job.Errors, entity.ImportRowError{Message: "too many errors, the rest are not listed"})
+	}
+}
+
+// jobContext gives a background job the actor, client IP, request id and
+// logger of the request that started it, so its audit entries and log lines
+// point back at that request. Cancellation still comes from worker.
+func jobContext(worker context.Context, request context.Context, log *logrus.Logger) context.Context {
+	ctx := pkg.WithActor(worker, pkg.Actor(request))
+	ctx = pkg.WithClientIP(ctx, pkg.ClientIP(request))
+	ctx = pkg.WithRequestID(ctx, pkg.RequestID(request))
+	return pkg.WithLogger(ctx, pkg.Logger(request, log))
+}
@@ -0,0 +1,22 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"sistem-06-Backend/internal/pkg/listquery"
+	"sistem-06-Backend/pkg"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// listError answers a bad list parameter with 400 and logs anything else.
+func listError(ctx context.Context, log *logrus.Logger, resource string, err error) error {
+	var invalid *listquery.Error
+	if errors.As(err, &invalid) {
+		return fiber.NewError(fiber.StatusBadRequest, invalid.Error())
+	}
+	pkg.Logger(ctx, log).Errorf("Failed to list %s: %v", resource, err)
+	return fiber.ErrInternalServerError
+}
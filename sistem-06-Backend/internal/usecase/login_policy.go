@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"sistem-06-Backend/internal/domain/entity"
+)
+
+// LoginPolicy decides how long an account or IP is blocked after a failed
+// login.
+type LoginPolicy struct {
+	FreeAttempts     int
+	BaseDelay        time.Duration
+	MaxDelay         time.Duration
+	AccountThreshold int
+	IPThreshold      int
+	Window           time.Duration
+	LockoutDuration  time.Duration
+	// TwoFactorTimeout is how long a correct password waits for the code
+	TwoFactorTimeout time.Duration
+}
+
+func (p LoginPolicy) threshold(scope entity.LoginScope) int {
+	if scope == entity.LoginScopeIP {
+		return p.IPThreshold
+	}
+	return p.AccountThreshold
+}
+
+// Delay returns how long scope is blocked after its n-th failure within the
+// window: nothing for the free attempts, then BaseDelay doubling up to
+// MaxDelay, and LockoutDuration once the threshold is reached.
+func (p LoginPolicy) Delay(scope entity.LoginScope, failures int) time.Duration {
+	if failures >= p.threshold(scope) {
+		return p.LockoutDuration
+	}
+	if failures <= p.FreeAttempts {
+		return 0
+	}
+
+	shift := failures - p.FreeAttempts - 1
+	if shift > 30 {
+		return p.MaxDelay
+	}
+	delay := p.BaseDelay << shift
+	if delay > p.MaxDelay {
+		return p.MaxDelay
+	}
+	return delay
+}
+
+// LoginThrottledError is returned by Login while the account or the client
+// IP is blocked. It is the same for known and unknown emails.
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return fmt.Sprintf("too many failed login attempts, retry after %s", e.RetryAfter)
+}
+
+// loginIdentifierColumn keys the blind indexes of login_attempts apart from
+// those of users.email.
+const loginIdentifierColumn = "login_attempts.identifier"
+
+// loginIdentifier is the account key failures are counted under: a blind
+// index of the email, so that differently cased spellings share one counter
+// and login_attempts holds no addresses.
+func (c *AuthUseCase) loginIdentifier(email string) string {
+	return c.Cipher.BlindIndex(loginIdentifierColumn, strings.ToLower(strings.TrimSpace(email)))
+}
@@ -0,0 +1,27 @@
+package usecase
+
+import (
+	"strings"
+
+	"sistem-06-Backend/internal/pkg/password"
+)
+
+// checkPassword adds the policy violations of a new password to the field
+// errors of the request, under "Password" like the validator would. A
+// password the validator already rejected, e.g. because it is empty, is not
+// checked again.
+func checkPassword(policy *password.Policy, validationErrors map[string]string, newPassword string, personal ...string) map[string]string {
+	if _, invalid := validationErrors["Password"]; invalid {
+		return validationErrors
+	}
+
+	violations := policy.Check(newPassword, personal...)
+	if len(violations) == 0 {
+		return validationErrors
+	}
+	if validationErrors == nil {
+		validationErrors = map[string]string{}
+	}
+	validationErrors["Password"] = "Password " + strings.Join(violations, ", ")
+	return validationErrors
+}
@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	stdErrors "errors"
+	"fmt"
+	"time"
+
+	"sistem-06-Backend/internal/delivery/http/converter"
+	domain "sistem-06-Backend/internal/domain/ports"
+	"sistem-06-Backend/internal/dto"
+)
+
+// The sources below cover the tables of this repository; see
+// PrivacyUseCase.Register.
+
+// UserDataSource exports the users row. Erasing anonymizes it instead of
+// deleting it, as audit entries keep referring to the id.
+func UserDataSource(users domain.UserRepository) PersonalDataSource {
+	return PersonalDataSource{
+		Export: func(ctx context.Context, userID int) (any, error) {
+			user, err := users.FindByID(ctx, userID)
+			if err != nil {
+				return nil, err
+			}
+			return &dto.PersonalUserData{
+				ID:                user.ID,
+				Name:              user.Name,
+				Email:             user.Email,
+				CreatedAt:         user.CreatedAt,
+				UpdatedAt:         user.UpdatedAt,
+				SessionsRevokedAt: user.SessionsRevokedAt,
+			}, nil
+		},
+		Erase: func(ctx context.Context, tx *sql.Tx, userID int) error {
+			_, err := users.WithTx(tx).Anonymize(ctx, userID, fmt.Sprintf("deleted-user-%d", userID), time.Now().Unix())
+			return err
+		},
+	}
+}
+
+// RoleDataSource exports the role assignments from user_roles and removes
+// them on erasure; the roles themselves are shared.
+func RoleDataSource(roles domain.RolesRepository) PersonalDataSource {
+	return PersonalDataSource{
+		Export: func(ctx context.Context, userID int) (any, error) {
+			assigned, err := roles.GetRolesWithPermissionsByUserID(ctx, userID)
+			if err != nil {
+				return nil, err
+			}
+			response := make([]*dto.RoleResponse, len(assigned))
+			for i := range assigned {
+				response[i] = converter.RoleToResponse(&assigned[i])
+			}
+			return response, nil
+		},
+		Erase: func(ctx context.Context, tx *sql.Tx, userID int) error {
+			return roles.WithTx(tx).RemoveAllRolesFromUser(ctx, userID)
+		},
+	}
+}
+
+// AddressDataSource exports the addresses the user created. They are
+// records of the neighbourhood rather than of the user, so they are kept.
+func AddressDataSource(addresses domain.AddressRepository) PersonalDataSource {
+	return PersonalDataSource{
+		Export: func(ctx context.Context, userID int) (any, error) {
+			created, err := addresses.ListCreatedBy(ctx, userID)
+			if err != nil {
+				return nil, err
+			}
+			response := make([]*dto.AddressEntity, len(created))
+			for i, address := range created {
+				response[i] = converter.AddressToResponse(address)
+			}
+			return response, nil
+		},
+	}
+}
+
+// SessionDataSource exports the active sessions, those neither expired nor
+// revoked, and deletes every session on erasure.
+func SessionDataSource(sessions domain.SessionRepository, users domain.UserRepository) PersonalDataSource {
+	return PersonalDataSource{
+		Export: func(ctx context.Context, userID int) (any, error) {
+			user, err := users.FindByID(ctx, userID)
+			if err != nil {
+				return nil, err
+			}
+			stored, err := sessions.ListByUserID(ctx, userID, time.Now().Unix())
+			if err != nil {
+				return nil, err
+			}
+			response := []*dto.PersonalSessionData{}
+			for _, session := range stored {
+				// see AuthUseCase.CheckSession
+				if session.CreatedAt < user.SessionsRevokedAt {
+					continue
+				}
+				response = append(response, &dto.PersonalSessionData{CreatedAt: session.CreatedAt, ExpiresAt: session.ExpiresAt})
+			}
+			return response, nil
+		},
+		Erase: func(ctx context.Context, tx *sql.Tx, userID int) error {
+			_, err := sessions.WithTx(tx).DeleteByUserID(ctx, userID)
+			return err
+		},
+	}
+}
+
+// TokenDataSource exports the personal access tokens that are not revoked,
+// without their hashes, and deletes all of them on erasure.
+func TokenDataSource(tokens domain.TokenRepository) PersonalDataSource {
+	return PersonalDataSource{
+		Export: func(ctx context.Context, userID int) (any, error) {
+			listed, err := tokens.ListTokensByUserID(ctx, userID)
+			if err != nil {
+				return nil, err
+			}
+			response := make([]*dto.TokenResponse, len(listed))
+			for i, token := range listed {
+				response[i] = converter.TokenToResponse(token)
+			}
+			return response, nil
+		},
+		Erase: func(ctx context.Context, tx *sql.Tx, userID int) error {
+			return tokens.WithTx(tx).DeleteTokensByUserID(ctx, userID)
+		},
+	}
+}
+
+// TwoFactorDataSource exports whether 2FA is set up, never the secret, and
+// deletes the enrollment and recovery codes on erasure.
+func TwoFactorDataSource(twoFactor domain.TwoFactorRepository) PersonalDataSource {
+	return PersonalDataSource{
+		Export: func(ctx context.Context, userID int) (any, error) {
+			enrollment, err := twoFactor.Find(ctx, userID)
+			if stdErrors.Is(err, sql.ErrNoRows) {
+				return nil, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			return &dto.PersonalTwoFactorData{
+				Enabled:   enrollment.Enabled,
+				CreatedAt: enrollment.CreatedAt,
+				EnabledAt: enrollment.EnabledAt,
+			}, nil
+		},
+		Erase: func(ctx context.Context, tx *sql.Tx, userID int) error {
+			return twoFactor.WithTx(tx).Delete(ctx, userID)
+		},
+	}
+}
+
+// EmailChangeDataSource exports a pending email change and drops it on
+// erasure.
+func EmailChangeDataSource(changes domain.EmailChangeRepository) PersonalDataSource {
+	return PersonalDataSource{
+		Export: func(ctx context.Context, userID int) (any, error) {
+			change, err := changes.FindByUserID(ctx, userID)
+			if stdErrors.Is(err, sql.ErrNoRows) {
+				return nil, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			return &dto.PersonalEmailChangeData{
+				NewEmail:  change.NewEmail,
+				CreatedAt: change.CreatedAt,
+				ExpiresAt: change.ExpiresAt,
+			}, nil
+		},
+		Erase: func(ctx context.Context, tx *sql.Tx, userID int) error {
+			return changes.WithTx(tx).Delete(ctx, userID)
+		},
+	}
+}
@@ -0,0 +1,399 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	stdErrors "errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"sistem-06-Backend/internal/domain/entity"
+	domain "sistem-06-Backend/internal/domain/ports"
+	"sistem-06-Backend/internal/dto"
+	customErrors "sistem-06-Backend/internal/pkg/errors"
+	"sistem-06-Backend/internal/pkg/password"
+	"sistem-06-Backend/pkg"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	PersonalDataJSON = "json"
+	PersonalDataZIP  = "zip"
+)
+
+// deletionBatchSize bounds the requests carried out by one run of
+// ProcessDeletions; the rest wait for the next run.
+const deletionBatchSize = 100
+
+// PersonalDataSource is what one table holds about a user. A table that
+// stores personal data joins the export and the erasure of an account by
+// registering a source with PrivacyUseCase.Register.
+type PersonalDataSource struct {
+	// Export returns the rows of the user, encoded as JSON. nil leaves the
+	// table out of the export.
+	Export func(ctx context.Context, userID int) (any, error)
+	// Erase deletes or anonymizes the rows of the user in tx when a
+	// deletion request is carried out. nil keeps them.
+	Erase func(ctx context.Context, tx *sql.Tx, userID int) error
+}
+
+type namedDataSource struct {
+	name string
+	PersonalDataSource
+}
+
+// PrivacyUseCase lets users download their data and have their account
+// erased. The audit log is append-only and keeps the entries about the user;
+// that is why the users row is anonymized rather than deleted.
+type PrivacyUseCase struct {
+	DB                        *sql.DB
+	Log                       *logrus.Logger
+	validate                  *validator.Validate
+	UserRepository            domain.UserRepository
+	DeletionRequestRepository domain.DeletionRequestRepository
+	Hasher                    password.Hasher
+	Audit                     *AuditRecorder
+	GracePeriod               time.Duration
+	sources                   []namedDataSource
+}
+
+func NewPrivacyUseCase(db *sql.DB, log *logrus.Logger, validate *validator.Validate, userRepository domain.UserRepository, deletionRequestRepository domain.DeletionRequestRepository, hasher password.Hasher, audit *AuditRecorder, gracePeriod time.Duration) *PrivacyUseCase {
+	return &PrivacyUseCase{
+		DB:                        db,
+		Log:                       log,
+		validate:                  validate,
+		UserRepository:            userRepository,
+		DeletionRequestRepository: deletionRequestRepository,
+		Hasher:                    hasher,
+		Audit:                     audit,
+		GracePeriod:               gracePeriod,
+	}
+}
+
+// Register adds a source whose export appears under name. Sources are
+// exported in the order they are registered and erased in reverse, so the
+// users row, registered first, is anonymized after everything that refers
+// to it. Register them before the server starts.
+func (c *PrivacyUseCase) Register(name string, source PersonalDataSource) {
+	c.sources = append(c.sources, namedDataSource{name: name, PersonalDataSource: source})
+}
+
+type personalDataSection struct {
+	name string
+	data json.RawMessage
+}
+
+// personalDataSections encodes as an object that keeps the registration
+// order.
+type personalDataSections []personalDataSection
+
+func (s personalDataSections) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, section := range s {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		name, err := json.Marshal(section.name)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(name)
+		b.WriteByte(':')
+		b.Write(section.data)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+type personalDataManifest struct {
+	UserID     int      `json:"user_id"`
+	ExportedAt string   `json:"exported_at"`
+	Sections   []string `json:"sections"`
+}
+
+// Export bundles what every source holds about the user: a JSON object with
+// a key per source under "data", or with format zip a manifest.json and a
+// <source>.json per source. A user's rows are few, so unlike the list
+// exports everything is read before the response starts and a failing
+// source is still reported as an error.
+func (c *PrivacyUseCase) Export(ctx context.Context, userID int, format string) (*dto.ExportFile, error) {
+	ctx, span := tracer.Start(ctx, "PrivacyUseCase.Export")
+	defer span.End()
+
+	if format == "" {
+		format = PersonalDataJSON
+	}
+	if format != PersonalDataJSON && format != PersonalDataZIP {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "format must be json or zip")
+	}
+
+	now := time.Now()
+	manifest := personalDataManifest{UserID: userID, ExportedAt: exportTime(now.Unix()), Sections: []string{}}
+	var sections personalDataSections
+	for _, source := range c.sources {
+		if source.Export == nil {
+			continue
+		}
+		data, err := source.Export(ctx, userID)
+		if err != nil {
+			pkg.Logger(ctx, c.Log).Errorf("Failed to export %s: %v", source.name, err)
+			return nil, fiber.ErrInternalServerError
+		}
+		encoded, err := json.Marshal(data)
+		if err != nil {
+			pkg.Logger(ctx, c.Log).Errorf("Failed to encode %s: %v", source.name, err)
+			return nil, fiber.ErrInternalServerError
+		}
+		sections = append(sections, personalDataSection{name: source.name, data: encoded})
+		manifest.Sections = append(manifest.Sections, source.name)
+	}
+
+	tx, err := c.DB.BeginTx(ctx, nil)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed to begin transaction: %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	defer tx.Rollback()
+
+	if err := c.Audit.Record(ctx, tx, entity.AuditActionExport, entity.AuditEntityUser, userID, nil, map[string]any{"format": format, "sections": manifest.Sections}); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	pkg.Logger(ctx, c.Log).WithField("format", format).Info("Personal data exported")
+
+	file := &dto.ExportFile{
+		Filename: fmt.Sprintf("personal-data-%d-%s.%s", userID, now.Format("20060102-150405"), format),
+	}
+	if format == PersonalDataZIP {
+		file.ContentType = "application/zip"
+		file.Write = func(w io.Writer) error { return writePersonalDataZIP(w, manifest, sections) }
+	} else {
+		file.ContentType = fiber.MIMEApplicationJSONCharsetUTF8
+		file.Write = func(w io.Writer) error {
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(struct {
+				personalDataManifest
+				Data personalDataSections `json:"data"`
+			}{manifest, sections})
+		}
+	}
+	return file, nil
+}
+
+func writePersonalDataZIP(w io.Writer, manifest personalDataManifest, sections personalDataSections) error {
+	archive := zip.NewWriter(w)
+	encodedManifest, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeZIPFile(archive, "manifest.json", encodedManifest); err != nil {
+		return err
+	}
+	for _, section := range sections {
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, section.data, "", "  "); err != nil {
+			return err
+		}
+		if err := writeZIPFile(archive, section.name+".json", indented.Bytes()); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+func writeZIPFile(archive *zip.Writer, name string, content []byte) error {
+	file, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(append(content, '\n'))
+	return err
+}
+
+// RequestDeletion schedules the account for erasure once the password is
+// confirmed. Until GracePeriod has passed the account works as before and
+// the request can be cancelled.
+func (c *PrivacyUseCase) RequestDeletion(ctx context.Context, userID int, request *dto.DeleteAccountRequest) (*dto.DeletionRequestResponse, error) {
+	ctx, span := tracer.Start(ctx, "PrivacyUseCase.RequestDeletion")
+	defer span.End()
+
+	if validationErrors := customErrors.UserValidationError(c.validate.Struct(request)); len(validationErrors) > 0 {
+		pkg.Logger(ctx, c.Log).Warnf("Validation failed: %+v", validationErrors)
+		return nil, fiber.NewError(fiber.StatusBadRequest, pkg.FormatValidationErrors(validationErrors))
+	}
+
+	user, err := c.UserRepository.FindByID(ctx, userID)
+	if err != nil {
+		if stdErrors.Is(err, sql.ErrNoRows) {
+			return nil, fiber.ErrUnauthorized
+		}
+		pkg.Logger(ctx, c.Log).Errorf("Failed to load user: %v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	if ok, err := c.Hasher.Verify(request.Password, user.Password); !ok {
+		if err != nil {
+			pkg.Logger(ctx, c.Log).Errorf("Failed to verify password hash: %v", err)
+		}
+		return nil, fiber.NewError(fiber.StatusBadRequest, pkg.FormatValidationErrors(map[string]string{"Password": "Password is incorrect"}))
+	}
+
+	if _, err := c.DeletionRequestRepository.FindByUserID(ctx, userID); err == nil {
+		return nil, fiber.NewError(fiber.StatusConflict, "deletion already requested")
+	} else if !stdErrors.Is(err, sql.ErrNoRows) {
+		pkg.Logger(ctx, c.Log).Errorf("Failed to load deletion request: %v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	now := time.Now()
+	deletion := &entity.DeletionRequest{
+		UserID:      userID,
+		RequestedAt: now.Unix(),
+		ScheduledAt: now.Add(c.GracePeriod).Unix(),
+	}
+	response := &dto.DeletionRequestResponse{RequestedAt: deletion.RequestedAt, ScheduledAt: deletion.ScheduledAt}
+
+	tx, err := c.DB.BeginTx(ctx, nil)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed to begin transaction: %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	defer tx.Rollback()
+
+	if err := c.DeletionRequestRepository.WithTx(tx).Create(ctx, deletion); err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			return nil, fiber.NewError(fiber.StatusConflict, "deletion already requested")
+		}
+		pkg.Logger(ctx, c.Log).Errorf("Failed to create deletion request: %v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	if err := c.Audit.Record(ctx, tx, entity.AuditActionRequestDeletion, entity.AuditEntityUser, userID, nil, response); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	pkg.Logger(ctx, c.Log).WithField("scheduled_at", deletion.ScheduledAt).Info("Account deletion requested")
+	return response, nil
+}
+
+func (c *PrivacyUseCase) GetDeletion(ctx context.Context, userID int) (*dto.DeletionRequestResponse, error) {
+	ctx, span := tracer.Start(ctx, "PrivacyUseCase.GetDeletion")
+	defer span.End()
+
+	deletion, err := c.DeletionRequestRepository.FindByUserID(ctx, userID)
+	if err != nil {
+		if stdErrors.Is(err, sql.ErrNoRows) {
+			return nil, fiber.NewError(fiber.StatusNotFound, "no deletion requested")
+		}
+		pkg.Logger(ctx, c.Log).Errorf("Failed to load deletion request: %v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	return &dto.DeletionRequestResponse{RequestedAt: deletion.RequestedAt, ScheduledAt: deletion.ScheduledAt}, nil
+}
+
+func (c *PrivacyUseCase) CancelDeletion(ctx context.Context, userID int) error {
+	ctx, span := tracer.Start(ctx, "PrivacyUseCase.CancelDeletion")
+	defer span.End()
+
+	tx, err := c.DB.BeginTx(ctx, nil)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed to begin transaction: %+v", err)
+		return fiber.ErrInternalServerError
+	}
+	defer tx.Rollback()
+
+	cancelled, err := c.DeletionRequestRepository.WithTx(tx).Cancel(ctx, userID)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Errorf("Failed to cancel deletion request: %v", err)
+		return fiber.ErrInternalServerError
+	}
+	if !cancelled {
+		return fiber.NewError(fiber.StatusNotFound, "no deletion requested")
+	}
+	if err := c.Audit.Record(ctx, tx, entity.AuditActionCancelDeletion, entity.AuditEntityUser, userID, nil, nil); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed commit transaction : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	pkg.Logger(ctx, c.Log).Info("Account deletion cancelled")
+	return nil
+}
+
+// ProcessDeletions carries out the due deletion requests. A request that
+// fails is logged and retried on the next run.
+func (c *PrivacyUseCase) ProcessDeletions(ctx context.Context) error {
+	now := time.Now().Unix()
+	requests, err := c.DeletionRequestRepository.ListDue(ctx, now, deletionBatchSize)
+	if err != nil {
+		return err
+	}
+
+	erased := 0
+	for _, request := range requests {
+		done, err := c.erase(ctx, request.UserID, now)
+		if err != nil {
+			pkg.Logger(ctx, c.Log).WithField("user_id", request.UserID).Errorf("Failed to erase account: %v", err)
+			continue
+		}
+		if done {
+			erased++
+		}
+	}
+	if erased > 0 {
+		pkg.Logger(ctx, c.Log).WithField("accounts", erased).Info("Erased accounts")
+	}
+	return nil
+}
+
+// erase runs every Erase in one transaction, so an account is never left
+// half erased. It reports false when the request was cancelled meanwhile.
+func (c *PrivacyUseCase) erase(ctx context.Context, userID int, now int64) (bool, error) {
+	ctx, span := tracer.Start(ctx, "PrivacyUseCase.erase")
+	defer span.End()
+
+	tx, err := c.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	// completing first locks the request against a concurrent cancel
+	completed, err := c.DeletionRequestRepository.WithTx(tx).Complete(ctx, userID, now)
+	if err != nil || !completed {
+		return false, err
+	}
+
+	erased := []string{}
+	for i := len(c.sources) - 1; i >= 0; i-- {
+		source := c.sources[i]
+		if source.Erase == nil {
+			continue
+		}
+		if err := source.Erase(ctx, tx, userID); err != nil {
+			return false, fmt.Errorf("%s: %w", source.name, err)
+		}
+		erased = append(erased, source.name)
+	}
+	if err := c.Audit.Record(ctx, tx, entity.AuditActionAnonymize, entity.AuditEntityUser, userID, nil, map[string]any{"erased": erased}); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"sistem-06-Backend/internal/domain/entity"
+	domain "sistem-06-Backend/internal/domain/ports"
+	"sistem-06-Backend/pkg"
+
+	"github.com/sirupsen/logrus"
+)
+
+type RateLimitUseCase struct {
+	Log                 *logrus.Logger
+	RateLimitRepository domain.RateLimitRepository
+}
+
+func NewRateLimitUseCase(log *logrus.Logger, rateLimitRepository domain.RateLimitRepository) *RateLimitUseCase {
+	return &RateLimitUseCase{
+		Log:                 log,
+		RateLimitRepository: rateLimitRepository,
+	}
+}
+
+// Hit counts a request against bucket in a fixed window of the given length.
+func (c *RateLimitUseCase) Hit(ctx context.Context, bucket string, window time.Duration) (*entity.RateLimitCounter, error) {
+	now := time.Now()
+	return c.RateLimitRepository.Hit(ctx, bucket, now.Unix(), now.Add(window).Unix())
+}
+
+// PurgeExpired removes counters whose window has ended.
+func (c *RateLimitUseCase) PurgeExpired(ctx context.Context) error {
+	deleted, err := c.RateLimitRepository.DeleteExpired(ctx, time.Now().Unix())
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		pkg.Logger(ctx, c.Log).Debugf("Purged %d expired rate limit counters", deleted)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	domain "sistem-06-Backend/internal/domain/ports"
+	"sistem-06-Backend/pkg"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RetentionUseCase removes soft deleted users, addresses and roles for good
+// once they have been deleted for longer than Retention.
+type RetentionUseCase struct {
+	Log               *logrus.Logger
+	UserRepository    domain.UserRepository
+	AddressRepository domain.AddressRepository
+	RolesRepository   domain.RolesRepository
+	Retention         time.Duration
+}
+
+func NewRetentionUseCase(log *logrus.Logger, userRepository domain.UserRepository, addressRepository domain.AddressRepository, rolesRepository domain.RolesRepository, retention time.Duration) *RetentionUseCase {
+	return &RetentionUseCase{
+		Log:               log,
+		UserRepository:    userRepository,
+		AddressRepository: addressRepository,
+		RolesRepository:   rolesRepository,
+		Retention:         retention,
+	}
+}
+
+func (c *RetentionUseCase) PurgeDeleted(ctx context.Context) error {
+	deletedBefore := time.Now().Add(-c.Retention).Unix()
+
+	users, err := c.UserRepository.PurgeDeleted(ctx, deletedBefore)
+	if err != nil {
+		return err
+	}
+	addresses, err := c.AddressRepository.PurgeDeleted(ctx, deletedBefore)
+	if err != nil {
+		return err
+	}
+	roles, err := c.RolesRepository.PurgeDeleted(ctx, deletedBefore)
+	if err != nil {
+		return err
+	}
+
+	if users+addresses+roles > 0 {
+		pkg.Logger(ctx, c.Log).WithFields(logrus.Fields{
+			"users":     users,
+			"addresses": addresses,
+			"roles":     roles,
+		}).Info("Purged soft deleted rows")
+	}
+	return nil
+}
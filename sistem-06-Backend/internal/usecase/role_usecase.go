@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"sistem-06-Backend/internal/delivery/http/converter"
+	"sistem-06-Backend/internal/domain/entity"
+	domain "sistem-06-Backend/internal/domain/ports"
+	"sistem-06-Backend/internal/dto"
+	"sistem-06-Backend/pkg"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type RoleUseCase struct {
+	DB              *sql.DB
+	Log             *logrus.Logger
+	RolesRepository domain.RolesRepository
+	Audit           *AuditRecorder
+}
+
+func NewRoleUseCase(db *sql.DB, log *logrus.Logger, rolesRepository domain.RolesRepository, audit *AuditRecorder) *RoleUseCase {
+	return &RoleUseCase{
+		DB:              db,
+		Log:             log,
+		RolesRepository: rolesRepository,
+		Audit:           audit,
+	}
+}
+
+func (c *RoleUseCase) Get(ctx context.Context, roleID int) (*dto.RoleResponse, error) {
+	ctx, span := tracer.Start(ctx, "RoleUseCase.Get")
+	defer span.End()
+
+	role, err := c.RolesRepository.FindByID(ctx, roleID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fiber.NewError(fiber.StatusNotFound, "role not found")
+		}
+		pkg.Logger(ctx, c.Log).Errorf("Failed to load role: %v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	return converter.RoleToResponse(role), nil
+}
+
+// Delete soft deletes the role if it is still at version. Its members keep
+// the assignment but lose the role's permissions until it is restored.
+func (c *RoleUseCase) Delete(ctx context.Context, roleID int, version int) error {
+	ctx, span := tracer.Start(ctx, "RoleUseCase.Delete")
+	defer span.End()
+
+	err := c.change(ctx, roleID, entity.AuditActionDelete, func(repository domain.RolesRepository) error {
+		return repository.SoftDelete(ctx, roleID, version, time.Now().Unix())
+	})
+	if err != nil {
+		return err
+	}
+	pkg.Logger(ctx, c.Log).WithField("role_id", roleID).Info("Role deleted")
+	return nil
+}
+
+func (c *RoleUseCase) Restore(ctx context.Context, roleID int) error {
+	ctx, span := tracer.Start(ctx, "RoleUseCase.Restore")
+	defer span.End()
+
+	err := c.change(ctx, roleID, entity.AuditActionRestore, func(repository domain.RolesRepository) error {
+		restored, err := repository.Restore(ctx, roleID)
+		if err == nil && !restored {
+			return sql.ErrNoRows
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	pkg.Logger(ctx, c.Log).WithField("role_id", roleID).Info("Role restored")
+	return nil
+}
+
+// change applies a delete or restore in a transaction together with its
+// audit entry. apply returns sql.ErrNoRows when there is no role to change.
+func (c *RoleUseCase) change(ctx context.Context, roleID int, action string, apply func(repository domain.RolesRepository) error) error {
+	tx, err := c.DB.BeginTx(ctx, nil)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed to begin transaction: %+v", err)
+		return fiber.ErrInternalServerError
+	}
+	defer tx.Rollback()
+
+	if err := apply(c.RolesRepository.WithTx(tx)); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return fiber.NewError(fiber.StatusNotFound, "role not found")
+		case errors.Is(err, entity.ErrVersionConflict):
+			tx.Rollback()
+			current, err := c.Get(ctx, roleID)
+			if err != nil {
+				return err
+			}
+			return &pkg.VersionConflict{Version: current.Version, Current: current}
+		}
+		pkg.Logger(ctx, c.Log).Errorf("Failed to %s role: %v", action, err)
+		return fiber.ErrInternalServerError
+	}
+	if err := c.Audit.Record(ctx, tx, action, entity.AuditEntityRole, roleID, nil, nil); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed commit transaction : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+	return nil
+}
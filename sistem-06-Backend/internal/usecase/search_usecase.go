@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+
+	"sistem-06-Backend/internal/delivery/http/converter"
+	"sistem-06-Backend/internal/domain/entity"
+	domain "sistem-06-Backend/internal/domain/ports"
+	"sistem-06-Backend/internal/dto"
+	customErrors "sistem-06-Backend/internal/pkg/errors"
+	"sistem-06-Backend/internal/pkg/mask"
+	"sistem-06-Backend/pkg"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type SearchUseCase struct {
+	Log              *logrus.Logger
+	Validate         *validator.Validate
+	UserRepository   domain.UserRepository
+	SearchRepository domain.SearchRepository
+}
+
+func NewSearchUseCase(log *logrus.Logger, validate *validator.Validate, userRepository domain.UserRepository, searchRepository domain.SearchRepository) *SearchUseCase {
+	return &SearchUseCase{
+		Log:              log,
+		Validate:         validate,
+		UserRepository:   userRepository,
+		SearchRepository: searchRepository,
+	}
+}
+
+// Search looks through users and addresses at once and merges the hits by
+// rank; emails are only matched as a whole, see dto.SearchRequest. Viewers
+// with users.manage find every user, anyone else only themselves. Addresses
+// belong to nobody, so they are only searched for viewers with
+// addresses.manage. A token also needs the matching scope, and the results
+// are masked the same way, see mask.Apply.
+func (c *SearchUseCase) Search(ctx context.Context, viewerID int, token *entity.PersonalAccessToken, request *dto.SearchRequest) ([]*dto.SearchResult, error) {
+	ctx, span := tracer.Start(ctx, "SearchUseCase.Search")
+	defer span.End()
+
+	if validationErrors := customErrors.UserValidationError(c.Validate.Struct(request)); len(validationErrors) > 0 {
+		return nil, fiber.NewError(fiber.StatusBadRequest, pkg.FormatValidationErrors(validationErrors))
+	}
+
+	viewer, err := c.UserRepository.FindWithRoles(ctx, viewerID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fiber.ErrUnauthorized
+		}
+		pkg.Logger(ctx, c.Log).Errorf("Failed to load roles: %v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	allowed := func(permission entity.Permissions) bool {
+		return viewer.HasPermission(permission) && (token == nil || token.HasScope(permission))
+	}
+	masked := maskViewer(viewerID, allowed)
+
+	query := entity.SearchQuery{Text: request.Query, Limit: request.Limit}
+	var results []*dto.SearchResult
+
+	if request.Type == "" || request.Type == entity.SearchTypeUser {
+		if !allowed(entity.PermissionManageUsers) {
+			query.UserID = viewerID
+		}
+		users, err := c.SearchRepository.Users(ctx, query)
+		if err != nil {
+			pkg.Logger(ctx, c.Log).Errorf("Failed to search users: %v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+		for _, match := range users {
+			user := converter.UserToResponse(match.User)
+			mask.Apply(user, masked)
+			results = append(results, &dto.SearchResult{
+				Type:  entity.SearchTypeUser,
+				ID:    user.ID,
+				Label: fmt.Sprintf("%s <%s>", user.Name, user.Email),
+				Rank:  match.Rank,
+				Data:  user,
+			})
+		}
+	}
+
+	if (request.Type == "" || request.Type == entity.SearchTypeAddress) && allowed(entity.PermissionManageAddresses) {
+		addresses, err := c.SearchRepository.Addresses(ctx, query)
+		if err != nil {
+			pkg.Logger(ctx, c.Log).Errorf("Failed to search addresses: %v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+		for _, match := range addresses {
+			address := converter.AddressToResponse(match.Address)
+			mask.Apply(address, masked)
+			results = append(results, &dto.SearchResult{
+				Type:  entity.SearchTypeAddress,
+				ID:    address.ID,
+				Label: fmt.Sprintf("%s RT %s/RW %s, %s", address.Jalan, address.RT, address.RW, address.Kota),
+				Rank:  match.Rank,
+				Data:  address,
+			})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Rank > results[j].Rank })
+	if len(results) > request.Limit {
+		results = results[:request.Limit]
+	}
+	return results, nil
+}
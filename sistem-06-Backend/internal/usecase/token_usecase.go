@@ -0,0 +1,221 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"sistem-06-Backend/internal/delivery/http/converter"
+	"sistem-06-Backend/internal/domain/entity"
+	domain "sistem-06-Backend/internal/domain/ports"
+	"sistem-06-Backend/internal/dto"
+	customErrors "sistem-06-Backend/internal/pkg/errors"
+	"sistem-06-Backend/pkg"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// tokenTouchInterval limits how often last_used_at is written, so a busy
+// script does not cause an UPDATE per request.
+const tokenTouchInterval = time.Minute
+
+type TokenUseCase struct {
+	DB              *sql.DB
+	Log             *logrus.Logger
+	Validate        *validator.Validate
+	UserRepository  domain.UserRepository
+	TokenRepository domain.TokenRepository
+	Audit           *AuditRecorder
+	DefaultLifetime time.Duration
+	MaxLifetime     time.Duration
+}
+
+func NewTokenUseCase(db *sql.DB, log *logrus.Logger, validate *validator.Validate, userRepository domain.UserRepository, tokenRepository domain.TokenRepository, audit *AuditRecorder, defaultLifetime time.Duration, maxLifetime time.Duration) *TokenUseCase {
+	return &TokenUseCase{
+		DB:              db,
+		Log:             log,
+		Validate:        validate,
+		UserRepository:  userRepository,
+		TokenRepository: tokenRepository,
+		Audit:           audit,
+		DefaultLifetime: defaultLifetime,
+		MaxLifetime:     maxLifetime,
+	}
+}
+
+// Create issues a token for userID. Scopes are limited to permissions the
+// user holds now; the plain token is returned once and only its hash kept.
+func (c *TokenUseCase) Create(ctx context.Context, userID int, request *dto.CreateTokenRequest) (*dto.CreateTokenResponse, error) {
+	ctx, span := tracer.Start(ctx, "TokenUseCase.Create")
+	defer span.End()
+
+	if err := c.Validate.Struct(request); err != nil {
+		validationErrors := customErrors.UserValidationError(err)
+		pkg.Logger(ctx, c.Log).Warnf("Validation failed: %+v", validationErrors)
+		return nil, fiber.NewError(fiber.StatusBadRequest, pkg.FormatValidationErrors(validationErrors))
+	}
+
+	lifetime := c.DefaultLifetime
+	if request.ExpiresInDays > 0 {
+		lifetime = time.Duration(request.ExpiresInDays) * 24 * time.Hour
+	}
+	if lifetime > c.MaxLifetime {
+		return nil, fiber.NewError(fiber.StatusBadRequest, pkg.FormatValidationErrors(map[string]string{
+			"ExpiresInDays": fmt.Sprintf("ExpiresInDays must be at most %d", int(c.MaxLifetime/(24*time.Hour))),
+		}))
+	}
+
+	user, err := c.UserRepository.FindWithRoles(ctx, userID)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Errorf("Failed to load roles: %v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	scopes := make([]entity.Permissions, 0, len(request.Scopes))
+	seen := make(map[entity.Permissions]bool, len(request.Scopes))
+	for _, name := range request.Scopes {
+		scope := entity.Permissions(name)
+		if !user.HasPermission(scope) {
+			return nil, fiber.NewError(fiber.StatusBadRequest, pkg.FormatValidationErrors(map[string]string{
+				"Scopes": fmt.Sprintf("Scopes contains %s, which you do not have", name),
+			}))
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+
+	now := time.Now()
+	plain := pkg.GenerateToken()
+	token := &entity.PersonalAccessToken{
+		UserID:    userID,
+		Name:      request.Name,
+		TokenHash: pkg.HashToken(plain),
+		Scopes:    scopes,
+		ExpiresAt: now.Add(lifetime).Unix(),
+		CreatedAt: now.Unix(),
+	}
+
+	tx, err := c.DB.BeginTx(ctx, nil)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed to begin transaction: %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	defer tx.Rollback()
+
+	if err := c.TokenRepository.WithTx(tx).CreateToken(ctx, token); err != nil {
+		pkg.Logger(ctx, c.Log).Errorf("Failed to create token: %v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	if err := c.Audit.Record(ctx, tx, entity.AuditActionCreate, entity.AuditEntityPersonalAccessToken, token.ID, nil, converter.TokenToResponse(token)); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	pkg.Logger(ctx, c.Log).WithField("token_id", token.ID).Info("Personal access token created")
+	return &dto.CreateTokenResponse{
+		TokenResponse: *converter.TokenToResponse(token),
+		Token:         plain,
+	}, nil
+}
+
+func (c *TokenUseCase) List(ctx context.Context, userID int) ([]*dto.TokenResponse, error) {
+	ctx, span := tracer.Start(ctx, "TokenUseCase.List")
+	defer span.End()
+
+	tokens, err := c.TokenRepository.ListTokensByUserID(ctx, userID)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Errorf("Failed to list tokens: %v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	responses := make([]*dto.TokenResponse, len(tokens))
+	for i, token := range tokens {
+		responses[i] = converter.TokenToResponse(token)
+	}
+	return responses, nil
+}
+
+func (c *TokenUseCase) Revoke(ctx context.Context, userID int, tokenID int) error {
+	ctx, span := tracer.Start(ctx, "TokenUseCase.Revoke")
+	defer span.End()
+
+	tx, err := c.DB.BeginTx(ctx, nil)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed to begin transaction: %+v", err)
+		return fiber.ErrInternalServerError
+	}
+	defer tx.Rollback()
+
+	revokedAt := time.Now().Unix()
+	revoked, err := c.TokenRepository.WithTx(tx).RevokeToken(ctx, tokenID, userID, revokedAt)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Errorf("Failed to revoke token: %v", err)
+		return fiber.ErrInternalServerError
+	}
+	if !revoked {
+		return fiber.NewError(fiber.StatusNotFound, "token not found")
+	}
+	if err := c.Audit.Record(ctx, tx, entity.AuditActionRevoke, entity.AuditEntityPersonalAccessToken, tokenID,
+		nil, map[string]int64{"revoked_at": revokedAt}); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed commit transaction : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	pkg.Logger(ctx, c.Log).WithField("token_id", tokenID).Info("Personal access token revoked")
+	return nil
+}
+
+// Authenticate resolves a bearer token to its owner. Unknown, expired and
+// revoked tokens all give fiber.ErrUnauthorized. Revoking the owner's
+// sessions, e.g. on a password change, revokes the tokens created before it
+// as well.
+func (c *TokenUseCase) Authenticate(ctx context.Context, plain string) (*entity.PersonalAccessToken, *entity.User, error) {
+	ctx, span := tracer.Start(ctx, "TokenUseCase.Authenticate")
+	defer span.End()
+
+	token, err := c.TokenRepository.FindTokenByHash(ctx, pkg.HashToken(plain))
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			pkg.Logger(ctx, c.Log).Errorf("Failed to find token: %v", err)
+			return nil, nil, fiber.ErrInternalServerError
+		}
+		return nil, nil, fiber.ErrUnauthorized
+	}
+
+	now := time.Now().Unix()
+	if !token.Active(now) {
+		pkg.Logger(ctx, c.Log).WithField("token_id", token.ID).Warn("Expired or revoked token used")
+		return nil, nil, fiber.ErrUnauthorized
+	}
+
+	user, err := c.UserRepository.FindByID(ctx, token.UserID)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed to find token owner: %v", err)
+		return nil, nil, fiber.ErrUnauthorized
+	}
+	if token.CreatedAt < user.SessionsRevokedAt {
+		pkg.Logger(ctx, c.Log).WithField("token_id", token.ID).Warn("Token created before the owner revoked their sessions used")
+		return nil, nil, fiber.ErrUnauthorized
+	}
+
+	if now-token.LastUsedAt >= int64(tokenTouchInterval/time.Second) {
+		if err := c.TokenRepository.TouchToken(ctx, token.ID, now); err != nil {
+			pkg.Logger(ctx, c.Log).Warnf("Failed to update token last use: %v", err)
+		} else {
+			token.LastUsedAt = now
+		}
+	}
+	return token, user, nil
+}
@@ -0,0 +1,7 @@
+package usecase
+
+import "go.opentelemetry.io/otel"
+
+// tracer opens one span per usecase method. It resolves the global provider
+// lazily, so it follows whatever config.NewTracing installed.
+var tracer = otel.Tracer("sistem-06-Backend/internal/usecase")
@@ -0,0 +1,337 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"sistem-06-Backend/internal/domain/entity"
+	domain "sistem-06-Backend/internal/domain/ports"
+	"sistem-06-Backend/internal/dto"
+	customErrors "sistem-06-Backend/internal/pkg/errors"
+	"sistem-06-Backend/internal/pkg/password"
+	"sistem-06-Backend/internal/pkg/totp"
+	"sistem-06-Backend/pkg"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+const recoveryCodeCount = 10
+
+type TwoFactorUseCase struct {
+	DB                  *sql.DB
+	Log                 *logrus.Logger
+	Validate            *validator.Validate
+	UserRepository      domain.UserRepository
+	TwoFactorRepository domain.TwoFactorRepository
+	Hasher              password.Hasher
+	// Lockout counts wrong passwords and codes given to Enable, Disable and
+	// RegenerateRecoveryCodes against the login lockout
+	Lockout *AuthUseCase
+	Audit   *AuditRecorder
+	Issuer  string
+}
+
+func NewTwoFactorUseCase(db *sql.DB, log *logrus.Logger, validate *validator.Validate, userRepository domain.UserRepository, twoFactorRepository domain.TwoFactorRepository, hasher password.Hasher, lockout *AuthUseCase, audit *AuditRecorder, issuer string) *TwoFactorUseCase {
+	return &TwoFactorUseCase{
+		DB:                  db,
+		Log:                 log,
+		Validate:            validate,
+		UserRepository:      userRepository,
+		TwoFactorRepository: twoFactorRepository,
+		Hasher:              hasher,
+		Lockout:             lockout,
+		Audit:               audit,
+		Issuer:              issuer,
+	}
+}
+
+// Enroll creates a new pending secret. It only becomes active once Enable
+// confirms a code generated from it.
+func (c *TwoFactorUseCase) Enroll(ctx context.Context, userID int) (*dto.TwoFactorEnrollResponse, error) {
+	ctx, span := tracer.Start(ctx, "TwoFactorUseCase.Enroll")
+	defer span.End()
+
+	user, err := c.UserRepository.FindByID(ctx, userID)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Errorf("Failed to find user: %v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Errorf("Failed to generate totp secret: %v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	saved, err := c.TwoFactorRepository.SavePending(ctx, &entity.TwoFactor{
+		UserID:    userID,
+		Secret:    secret,
+		CreatedAt: time.Now().Unix(),
+	})
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Errorf("Failed to save totp secret: %v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	if !saved {
+		return nil, fiber.NewError(fiber.StatusConflict, "two-factor authentication is already enabled")
+	}
+
+	return &dto.TwoFactorEnrollResponse{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(c.Issuer, user.Email, secret),
+	}, nil
+}
+
+// Enable confirms the pending secret with a current code and returns the
+// recovery codes. They are shown only this once; only their hashes are kept.
+// Wrong codes count against the login lockout, like those given to Disable.
+func (c *TwoFactorUseCase) Enable(ctx context.Context, userID int, request *dto.TwoFactorCodeRequest) (*dto.RecoveryCodesResponse, error) {
+	ctx, span := tracer.Start(ctx, "TwoFactorUseCase.Enable")
+	defer span.End()
+
+	if err := c.validateRequest(ctx, request); err != nil {
+		return nil, err
+	}
+
+	twoFactor, err := c.findTwoFactor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor.Enabled {
+		return nil, fiber.NewError(fiber.StatusConflict, "two-factor authentication is already enabled")
+	}
+
+	now := time.Now()
+	if err := c.confirmCode(ctx, userID, twoFactor, request, now); err != nil {
+		return nil, err
+	}
+
+	tx, err := c.DB.BeginTx(ctx, nil)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed to begin transaction: %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	defer tx.Rollback()
+
+	if enabled, err := c.TwoFactorRepository.WithTx(tx).Enable(ctx, userID, now.Unix()); err != nil {
+		pkg.Logger(ctx, c.Log).Errorf("Failed to enable two-factor authentication: %v", err)
+		return nil, fiber.ErrInternalServerError
+	} else if !enabled {
+		return nil, fiber.NewError(fiber.StatusConflict, "two-factor authentication is already enabled")
+	}
+
+	codes, err := c.replaceRecoveryCodes(ctx, tx, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Audit.Record(ctx, tx, entity.AuditActionEnable, entity.AuditEntityTwoFactor, userID, nil, nil); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	pkg.Logger(ctx, c.Log).Info("Two-factor authentication enabled")
+	return codes, nil
+}
+
+// Disable removes 2FA after checking the password and a code. Members of a
+// role that requires 2FA cannot disable it. Wrong answers count against the
+// login lockout, which answers with a LoginThrottledError.
+func (c *TwoFactorUseCase) Disable(ctx context.Context, userID int, request *dto.TwoFactorDisableRequest) error {
+	ctx, span := tracer.Start(ctx, "TwoFactorUseCase.Disable")
+	defer span.End()
+
+	if err := c.validateRequest(ctx, request); err != nil {
+		return err
+	}
+
+	user, err := c.UserRepository.FindWithRoles(ctx, userID)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Errorf("Failed to load roles: %v", err)
+		return fiber.ErrInternalServerError
+	}
+	if user.RequiresTwoFactor() {
+		return fiber.NewError(fiber.StatusForbidden, "two-factor authentication is required for your role")
+	}
+
+	twoFactor, err := c.findTwoFactor(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !twoFactor.Enabled {
+		return fiber.NewError(fiber.StatusBadRequest, "two-factor authentication is not enabled")
+	}
+
+	if retryAfter := c.Lockout.ReauthenticationBlockedFor(ctx, user, request.IPAddress); retryAfter > 0 {
+		return &LoginThrottledError{RetryAfter: retryAfter}
+	}
+	if ok, err := c.Hasher.Verify(request.Password, user.Password); !ok {
+		if err != nil {
+			pkg.Logger(ctx, c.Log).Errorf("Failed to verify password hash: %v", err)
+		}
+		c.Lockout.RecordReauthenticationFailure(ctx, user, request.IPAddress)
+		return fiber.NewError(fiber.StatusUnauthorized, "invalid password or code")
+	}
+	if ok, err := verifySecondFactor(ctx, c.TwoFactorRepository, twoFactor, request.Code, time.Now()); err != nil {
+		pkg.Logger(ctx, c.Log).Errorf("Failed to verify second factor: %v", err)
+		return fiber.ErrInternalServerError
+	} else if !ok {
+		c.Lockout.RecordReauthenticationFailure(ctx, user, request.IPAddress)
+		return fiber.NewError(fiber.StatusUnauthorized, "invalid password or code")
+	}
+
+	tx, err := c.DB.BeginTx(ctx, nil)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed to begin transaction: %+v", err)
+		return fiber.ErrInternalServerError
+	}
+	defer tx.Rollback()
+
+	if err := c.TwoFactorRepository.WithTx(tx).Delete(ctx, userID); err != nil {
+		pkg.Logger(ctx, c.Log).Errorf("Failed to disable two-factor authentication: %v", err)
+		return fiber.ErrInternalServerError
+	}
+	if err := c.Audit.Record(ctx, tx, entity.AuditActionDisable, entity.AuditEntityTwoFactor, userID, nil, nil); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed commit transaction : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	pkg.Logger(ctx, c.Log).Info("Two-factor authentication disabled")
+	return nil
+}
+
+// RegenerateRecoveryCodes invalidates every previous recovery code. It takes
+// a TOTP code, not a recovery code, as confirmation; wrong ones count against
+// the login lockout.
+func (c *TwoFactorUseCase) RegenerateRecoveryCodes(ctx context.Context, userID int, request *dto.TwoFactorCodeRequest) (*dto.RecoveryCodesResponse, error) {
+	ctx, span := tracer.Start(ctx, "TwoFactorUseCase.RegenerateRecoveryCodes")
+	defer span.End()
+
+	if err := c.validateRequest(ctx, request); err != nil {
+		return nil, err
+	}
+
+	twoFactor, err := c.findTwoFactor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !twoFactor.Enabled {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "two-factor authentication is not enabled")
+	}
+
+	now := time.Now()
+	if err := c.confirmCode(ctx, userID, twoFactor, request, now); err != nil {
+		return nil, err
+	}
+
+	tx, err := c.DB.BeginTx(ctx, nil)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed to begin transaction: %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	defer tx.Rollback()
+
+	codes, err := c.replaceRecoveryCodes(ctx, tx, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Audit.Record(ctx, tx, entity.AuditActionRegenerateRecoveryCodes, entity.AuditEntityTwoFactor, userID, nil, nil); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	return codes, nil
+}
+
+func (c *TwoFactorUseCase) validateRequest(ctx context.Context, request any) error {
+	if err := c.Validate.Struct(request); err != nil {
+		validationErrors := customErrors.UserValidationError(err)
+		pkg.Logger(ctx, c.Log).Warnf("Validation failed: %+v", validationErrors)
+		return fiber.NewError(fiber.StatusBadRequest, pkg.FormatValidationErrors(validationErrors))
+	}
+	return nil
+}
+
+// confirmCode checks a TOTP code of a signed-in user. While the account is
+// locked it answers with a LoginThrottledError without looking at the code.
+func (c *TwoFactorUseCase) confirmCode(ctx context.Context, userID int, twoFactor *entity.TwoFactor, request *dto.TwoFactorCodeRequest, now time.Time) error {
+	user, err := c.UserRepository.FindByID(ctx, userID)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Errorf("Failed to find user: %v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if retryAfter := c.Lockout.ReauthenticationBlockedFor(ctx, user, request.IPAddress); retryAfter > 0 {
+		return &LoginThrottledError{RetryAfter: retryAfter}
+	}
+	if ok, err := verifyTOTP(ctx, c.TwoFactorRepository, twoFactor, request.Code, now); err != nil {
+		pkg.Logger(ctx, c.Log).Errorf("Failed to verify totp code: %v", err)
+		return fiber.ErrInternalServerError
+	} else if !ok {
+		c.Lockout.RecordReauthenticationFailure(ctx, user, request.IPAddress)
+		return fiber.NewError(fiber.StatusBadRequest, "invalid code")
+	}
+	return nil
+}
+
+func (c *TwoFactorUseCase) findTwoFactor(ctx context.Context, userID int) (*entity.TwoFactor, error) {
+	twoFactor, err := c.TwoFactorRepository.Find(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fiber.NewError(fiber.StatusBadRequest, "two-factor enrollment has not been started")
+		}
+		pkg.Logger(ctx, c.Log).Errorf("Failed to find two-factor enrollment: %v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	return twoFactor, nil
+}
+
+func (c *TwoFactorUseCase) replaceRecoveryCodes(ctx context.Context, tx *sql.Tx, userID int, now time.Time) (*dto.RecoveryCodesResponse, error) {
+	codes, err := totp.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Errorf("Failed to generate recovery codes: %v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hashes[i] = totp.HashRecoveryCode(code)
+	}
+	if err := c.TwoFactorRepository.WithTx(tx).ReplaceRecoveryCodes(ctx, userID, hashes, now.Unix()); err != nil {
+		pkg.Logger(ctx, c.Log).Errorf("Failed to store recovery codes: %v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// verifyTOTP accepts a code once: the matched time step is stored and
+// earlier or equal steps are rejected afterwards.
+func verifyTOTP(ctx context.Context, repository domain.TwoFactorRepository, twoFactor *entity.TwoFactor, code string, now time.Time) (bool, error) {
+	step, ok := totp.Validate(twoFactor.Secret, strings.TrimSpace(code), now, 1)
+	if !ok || step <= twoFactor.LastUsedStep {
+		return false, nil
+	}
+	return repository.MarkStepUsed(ctx, twoFactor.UserID, step)
+}
+
+// verifySecondFactor accepts either a TOTP code or an unused recovery code.
+func verifySecondFactor(ctx context.Context, repository domain.TwoFactorRepository, twoFactor *entity.TwoFactor, code string, now time.Time) (bool, error) {
+	code = strings.TrimSpace(code)
+	if totp.IsCode(code) {
+		return verifyTOTP(ctx, repository, twoFactor, code, now)
+	}
+	return repository.UseRecoveryCode(ctx, twoFactor.UserID, totp.HashRecoveryCode(code), now.Unix())
+}
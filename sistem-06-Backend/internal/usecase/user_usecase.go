@@ -3,38 +3,57 @@ package usecase
 import (
 	"context"
 	"database/sql"
+	stdErrors "errors"
 	"strings"
+	"time"
 
-	"sistem-06-Backend/internal/domain"
-	"sistem-06-Backend/internal/repository"
+	"sistem-06-Backend/internal/delivery/http/converter"
+	"sistem-06-Backend/internal/domain/entity"
+	domain "sistem-06-Backend/internal/domain/ports"
+	"sistem-06-Backend/internal/dto"
+	"sistem-06-Backend/internal/infrastructure/metrics"
+	"sistem-06-Backend/internal/pkg/errors"
+	"sistem-06-Backend/internal/pkg/listquery"
+	"sistem-06-Backend/internal/pkg/password"
+	"sistem-06-Backend/pkg"
 
-	"backend-sistem06.com/utils"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/sirupsen/logrus"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type UserUseCase struct {
 	DB             *sql.DB
 	Log            *logrus.Logger
 	validate       *validator.Validate
-	UserRepository *domain.UserRepository
+	UserRepository domain.UserRepository
+	PasswordPolicy *password.Policy
+	Hasher         password.Hasher
+	Audit          *AuditRecorder
+	Metrics        *metrics.Metrics
 }
 
-func NewUserUseCase(db *sql.DB, log *logrus.Logger, validate *validator.Validate, userRepository repository.UserRepositoryInterface) *UserUseCase {
+func NewUserUseCase(db *sql.DB, log *logrus.Logger, validate *validator.Validate, userRepository domain.UserRepository, passwordPolicy *password.Policy, hasher password.Hasher, audit *AuditRecorder, metrics *metrics.Metrics) *UserUseCase {
 	return &UserUseCase{
 		DB:             db,
 		Log:            log,
 		validate:       validate,
 		UserRepository: userRepository,
+		PasswordPolicy: passwordPolicy,
+		Hasher:         hasher,
+		Audit:          audit,
+		Metrics:        metrics,
 	}
 }
 
-func (c *UserUseCase) Create(ctx context.Context, request *model.RegisterUserRequest) (*model.UserResponse, error) {
+func (c *UserUseCase) Create(ctx context.Context, request *dto.RegisterUserRequest) (*dto.UserResponse, error) {
+	ctx, span := tracer.Start(ctx, "UserUseCase.Create")
+	defer span.End()
+
 	tx, err := c.DB.BeginTx(ctx, nil)
 	if err != nil {
-		c.Log.Warnf("Failed to begin transaction: %+v", err)
+		pkg.Logger(ctx, c.Log).Warnf("Failed to begin transaction: %+v", err)
+		c.Metrics.Registration("error")
 
 		if err == context.Canceled || err == context.DeadlineExceeded {
 			return nil, fiber.NewError(fiber.StatusRequestTimeout, "request timeout or canceled")
@@ -44,36 +63,191 @@ func (c *UserUseCase) Create(ctx context.Context, request *model.RegisterUserReq
 	}
 	defer tx.Rollback()
 
-	if err := c.validate.Struct(request); err != nil {
-		validationErrors := utils.ValidationError(err)
-		c.Log.Warnf("Validation failed: %+v", validationErrors)
+	validationErrors := errors.UserValidationError(c.validate.Struct(request))
+	validationErrors = checkPassword(c.PasswordPolicy, validationErrors, request.Password, request.Email, request.Name)
+	if len(validationErrors) > 0 {
+		pkg.Logger(ctx, c.Log).Warnf("Validation failed: %+v", validationErrors)
+		c.Metrics.Registration("invalid")
 
 		// Return response error yang bisa dibaca frontend
-		return nil, fiber.NewError(fiber.StatusBadRequest, utils.FormatValidationErrors(validationErrors))
+		return nil, fiber.NewError(fiber.StatusBadRequest, pkg.FormatValidationErrors(validationErrors))
 	}
 
-	password, err := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.DefaultCost)
+	// the unique index on email_hash misses accounts whose blind index is
+	// not keyed yet, until encryption rotate has run; FindByEmail also
+	// matches those by their email in the clear
+	if _, err := c.UserRepository.WithTx(tx).FindByEmail(ctx, request.Email); err == nil {
+		c.Metrics.Registration("conflict")
+		return nil, fiber.NewError(fiber.StatusConflict, "email or name already exist")
+	} else if !stdErrors.Is(err, sql.ErrNoRows) {
+		pkg.Logger(ctx, c.Log).Warnf("Failed to look up email: %+v", err)
+		c.Metrics.Registration("error")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	password, err := c.Hasher.Hash(request.Password)
 	if err != nil {
-		c.Log.Warnf("failed to generate bcrypt hash: %+v", err)
+		pkg.Logger(ctx, c.Log).Warnf("failed to generate password hash: %+v", err)
+		c.Metrics.Registration("error")
 		return nil, fiber.ErrInternalServerError
 	}
 
-	user := &entity.UserEntity{
-		Name:     request.Name,
-		Email:    request.Email,
-		Password: string(password),
+	now := time.Now().Unix()
+	user := &entity.User{
+		Name:      request.Name,
+		Email:     request.Email,
+		Password:  password,
+		CreatedAt: now,
+		UpdatedAt: now,
 	}
 
-	if err := c.UserRepository.CreateUser(tx, user); err != nil {
+	if err := c.UserRepository.WithTx(tx).CreateUser(ctx, user); err != nil {
 		if strings.Contains(err.Error(), "duplicate key") {
+			c.Metrics.Registration("conflict")
 			return nil, fiber.NewError(fiber.StatusConflict, "email or name already exist")
 		}
-		c.Log.Warnf("Database insert error: %+v", err)
+		pkg.Logger(ctx, c.Log).Warnf("Database insert error: %+v", err)
+		c.Metrics.Registration("error")
 		return nil, fiber.ErrInternalServerError
 	}
+	if err := c.Audit.Record(ctx, tx, entity.AuditActionCreate, entity.AuditEntityUser, user.ID, nil, converter.UserToResponse(user)); err != nil {
+		c.Metrics.Registration("error")
+		return nil, err
+	}
 	if err := tx.Commit(); err != nil {
-		c.Log.Warnf("Failed commit transaction : %+v", err)
+		pkg.Logger(ctx, c.Log).Warnf("Failed commit transaction : %+v", err)
+		c.Metrics.Registration("error")
 		return nil, fiber.ErrInternalServerError
 	}
+
+	c.Metrics.Registration("success")
 	return converter.UserToResponse(user), nil
 }
+
+func (c *UserUseCase) Get(ctx context.Context, userID int) (*dto.UserResponse, error) {
+	ctx, span := tracer.Start(ctx, "UserUseCase.Get")
+	defer span.End()
+
+	user, err := c.UserRepository.FindWithRoles(ctx, userID)
+	if err != nil {
+		if stdErrors.Is(err, sql.ErrNoRows) {
+			return nil, fiber.NewError(fiber.StatusNotFound, "user not found")
+		}
+		pkg.Logger(ctx, c.Log).Errorf("Failed to load user: %v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	response := converter.UserWithRolesToResponse(user)
+	response.CreatedAt = user.CreatedAt
+	response.UpdatedAt = user.UpdatedAt
+	return response, nil
+}
+
+// List pages through live users with the filter, sort and page parameters
+// of query, see listquery.
+func (c *UserUseCase) List(ctx context.Context, query map[string]string) (*pkg.PageResponse[*dto.UserResponse], error) {
+	ctx, span := tracer.Start(ctx, "UserUseCase.List")
+	defer span.End()
+
+	request, err := listquery.Parse(query)
+	if err != nil {
+		return nil, listError(ctx, c.Log, "users", err)
+	}
+	users, paging, err := c.UserRepository.List(ctx, request)
+	if err != nil {
+		return nil, listError(ctx, c.Log, "users", err)
+	}
+
+	responses := make([]*dto.UserResponse, len(users))
+	for i, user := range users {
+		responses[i] = converter.UserToResponse(user)
+	}
+	return &pkg.PageResponse[*dto.UserResponse]{Data: responses, PageMetadata: paging}, nil
+}
+
+// Delete soft deletes the user if it is still at version. Every lookup skips
+// deleted users, so their sessions and tokens stop working right away.
+func (c *UserUseCase) Delete(ctx context.Context, userID int, version int) error {
+	ctx, span := tracer.Start(ctx, "UserUseCase.Delete")
+	defer span.End()
+
+	tx, err := c.DB.BeginTx(ctx, nil)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed to begin transaction: %+v", err)
+		return fiber.ErrInternalServerError
+	}
+	defer tx.Rollback()
+
+	if err := c.UserRepository.WithTx(tx).SoftDelete(ctx, userID, version, time.Now().Unix()); err != nil {
+		switch {
+		case stdErrors.Is(err, sql.ErrNoRows):
+			return fiber.NewError(fiber.StatusNotFound, "user not found")
+		case stdErrors.Is(err, entity.ErrVersionConflict):
+			tx.Rollback()
+			return c.versionConflict(ctx, userID)
+		}
+		pkg.Logger(ctx, c.Log).Errorf("Failed to delete user: %v", err)
+		return fiber.ErrInternalServerError
+	}
+	if err := c.Audit.Record(ctx, tx, entity.AuditActionDelete, entity.AuditEntityUser, userID, nil, nil); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed commit transaction : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	pkg.Logger(ctx, c.Log).WithField("target_user_id", userID).Info("User deleted")
+	return nil
+}
+
+// Restore brings back a deleted user. It fails with a conflict when a live
+// user has taken the email in the meantime.
+func (c *UserUseCase) Restore(ctx context.Context, userID int) (*dto.UserResponse, error) {
+	ctx, span := tracer.Start(ctx, "UserUseCase.Restore")
+	defer span.End()
+
+	tx, err := c.DB.BeginTx(ctx, nil)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed to begin transaction: %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	defer tx.Rollback()
+
+	userRepository := c.UserRepository.WithTx(tx)
+	restored, err := userRepository.Restore(ctx, userID, time.Now().Unix())
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			return nil, fiber.NewError(fiber.StatusConflict, "email already used by another user")
+		}
+		pkg.Logger(ctx, c.Log).Errorf("Failed to restore user: %v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	if !restored {
+		return nil, fiber.NewError(fiber.StatusNotFound, "deleted user not found")
+	}
+	user, err := userRepository.FindByID(ctx, userID)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Errorf("Failed to load restored user: %v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	if err := c.Audit.Record(ctx, tx, entity.AuditActionRestore, entity.AuditEntityUser, userID, nil, nil); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	pkg.Logger(ctx, c.Log).WithField("target_user_id", userID).Info("User restored")
+	return converter.UserToResponse(user), nil
+}
+
+// versionConflict reports the user as it is now.
+func (c *UserUseCase) versionConflict(ctx context.Context, userID int) error {
+	current, err := c.Get(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return &pkg.VersionConflict{Version: current.Version, Current: current}
+}
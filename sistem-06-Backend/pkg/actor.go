@@ -0,0 +1,33 @@
+package pkg
+
+import "context"
+
+type actorKey struct{}
+type clientIPKey struct{}
+
+// WithActor stores the id of the authenticated user making the request.
+func WithActor(ctx context.Context, userID int) context.Context {
+	return context.WithValue(ctx, actorKey{}, userID)
+}
+
+// Actor returns the user stored by WithActor, or 0 for anonymous requests
+// and background jobs.
+func Actor(ctx context.Context) int {
+	if ctx == nil {
+		return 0
+	}
+	userID, _ := ctx.Value(actorKey{}).(int)
+	return userID
+}
+
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, ip)
+}
+
+func ClientIP(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	ip, _ := ctx.Value(clientIPKey{}).(string)
+	return ip
+}
@@ -0,0 +1,37 @@
+package pkg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ETag formats a row version as a strong entity tag.
+func ETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// ParseETag reads the version back from an If-Match header. Weak tags, lists
+// and "*" are rejected since a write must name the exact version it read.
+func ParseETag(header string) (int, bool) {
+	header = strings.TrimSpace(header)
+	if len(header) < 3 || header[0] != '"' || header[len(header)-1] != '"' {
+		return 0, false
+	}
+	version, err := strconv.Atoi(header[1 : len(header)-1])
+	if err != nil || version < 1 {
+		return 0, false
+	}
+	return version, true
+}
+
+// VersionConflict is returned when a write was based on a stale version.
+// Current is the representation the client should retry against.
+type VersionConflict struct {
+	Version int
+	Current any
+}
+
+func (e *VersionConflict) Error() string {
+	return fmt.Sprintf("resource was modified, current version is %d", e.Version)
+}
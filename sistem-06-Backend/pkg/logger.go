@@ -0,0 +1,40 @@
+package pkg
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+type loggerKey struct{}
+type requestIDKey struct{}
+
+// WithLogger stores a request scoped logrus entry in ctx.
+func WithLogger(ctx context.Context, entry *logrus.Entry) context.Context {
+	return context.WithValue(ctx, loggerKey{}, entry)
+}
+
+// Logger returns the request scoped entry stored in ctx, or an entry of
+// fallback when the context does not carry one (background jobs, tests).
+// The entry is bound to ctx so hooks can read the active trace span.
+func Logger(ctx context.Context, fallback *logrus.Logger) *logrus.Entry {
+	if ctx == nil {
+		return logrus.NewEntry(fallback)
+	}
+	if entry, ok := ctx.Value(loggerKey{}).(*logrus.Entry); ok {
+		return entry.WithContext(ctx)
+	}
+	return logrus.NewEntry(fallback).WithContext(ctx)
+}
+
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
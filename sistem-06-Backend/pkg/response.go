@@ -16,4 +16,6 @@ type PageMetadata struct {
 	Size      int   `json:"size"`
 	TotalItem int64 `json:"total_item"`
 	TotalPage int64 `json:"total_page"`
+	// set on keyset pages that have a successor
+	NextCursor string `json:"next_cursor,omitempty"`
 }
@@ -1,6 +1,7 @@
 package pkg
 
 import (
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -25,19 +26,95 @@ func (s *SessionHandler) SetUserSession(ctx *fiber.Ctx, userID int, email string
 	if err != nil {
 		return err
 	}
-	s.Log.Infof("Setting session for user %d with email %s", userID, email)
+	Logger(ctx.UserContext(), s.Log).Infof("Setting session for user %d", userID)
+
+	// A new id on every login, so an id planted before login (session
+	// fixation) is useless afterwards. The data, e.g. the CSRF token, is kept.
+	// A fresh session got its id in this request, so nobody else knows it.
+	if !sess.Fresh() {
+		if err := sess.Regenerate(); err != nil {
+			Logger(ctx.UserContext(), s.Log).Errorf("Failed to regenerate session id: %v", err)
+			return err
+		}
+		// The store still resolves this request to the old, deleted id, so
+		// a later Get, e.g. SetTwoFactorEnrollmentRequired, would start an
+		// empty session and overwrite the new cookie.
+		ctx.Request().Header.SetCookie(strings.TrimPrefix(s.store.KeyLookup, "cookie:"), sess.ID())
+	}
 
 	sess.Set("user_id", userID)
 	sess.Set("email", email)
 	sess.Set("authenticated", true)
 	sess.Set("created_at", time.Now().Unix())
+	sess.Delete("pending_2fa_user_id")
+	sess.Delete("pending_2fa_expires_at")
 	err = sess.Save()
 	if err != nil {
-		s.Log.Errorf("Failed saving session: %v", err)
+		Logger(ctx.UserContext(), s.Log).Errorf("Failed saving session: %v", err)
 	}
 	return err
 }
 
+// SetPendingTwoFactor remembers a user whose password was correct while the
+// second factor is outstanding. The session stays unauthenticated.
+func (s *SessionHandler) SetPendingTwoFactor(ctx *fiber.Ctx, userID int, ttl time.Duration) (int64, error) {
+	sess, err := s.store.Get(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	expiresAt := time.Now().Add(ttl).Unix()
+	sess.Set("pending_2fa_user_id", userID)
+	sess.Set("pending_2fa_expires_at", expiresAt)
+	sess.Set("authenticated", false)
+	if err := sess.Save(); err != nil {
+		Logger(ctx.UserContext(), s.Log).Errorf("Failed saving session: %v", err)
+		return 0, err
+	}
+	return expiresAt, nil
+}
+
+// GetPendingTwoFactor returns the user waiting for the second factor, or
+// fiber.ErrUnauthorized when there is none or it expired.
+func (s *SessionHandler) GetPendingTwoFactor(ctx *fiber.Ctx) (int, error) {
+	sess, err := s.store.Get(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	userID, ok := sess.Get("pending_2fa_user_id").(int)
+	expiresAt, hasExpiry := sess.Get("pending_2fa_expires_at").(int64)
+	if !ok || !hasExpiry || time.Now().Unix() > expiresAt {
+		return 0, fiber.ErrUnauthorized
+	}
+	return userID, nil
+}
+
+// SetTwoFactorEnrollmentRequired flags a session whose role requires 2FA
+// the user has not set up yet; RequiredAuth then only admits enrollment.
+func (s *SessionHandler) SetTwoFactorEnrollmentRequired(ctx *fiber.Ctx, required bool) error {
+	sess, err := s.store.Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	sess.Set("two_factor_enrollment_required", required)
+	if err := sess.Save(); err != nil {
+		Logger(ctx.UserContext(), s.Log).Errorf("Failed saving session: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (s *SessionHandler) TwoFactorEnrollmentRequired(ctx *fiber.Ctx) bool {
+	sess, err := s.store.Get(ctx)
+	if err != nil {
+		return false
+	}
+	required, ok := sess.Get("two_factor_enrollment_required").(bool)
+	return ok && required
+}
+
 func (s *SessionHandler) GetUserID(ctx *fiber.Ctx) (int, error) {
 	sess, err := s.store.Get(ctx)
 	if err != nil {
@@ -51,6 +128,17 @@ func (s *SessionHandler) GetUserID(ctx *fiber.Ctx) (int, error) {
 	return id.(int), nil
 }
 
+// GetCreatedAt returns when the user logged in to this session, as unix
+// seconds. Sessions from before the value was stored report 0.
+func (s *SessionHandler) GetCreatedAt(ctx *fiber.Ctx) int64 {
+	sess, err := s.store.Get(ctx)
+	if err != nil {
+		return 0
+	}
+	createdAt, _ := sess.Get("created_at").(int64)
+	return createdAt
+}
+
 func (s *SessionHandler) GetUserEmail(ctx *fiber.Ctx) (string, error) {
 	sess, err := s.store.Get(ctx)
 	if err != nil {
@@ -62,7 +150,7 @@ func (s *SessionHandler) GetUserEmail(ctx *fiber.Ctx) (string, error) {
 	}
 	emailStr, ok := email.(string)
 	if !ok {
-		s.Log.Errorf("Invalid email type: %T", email)
+		Logger(ctx.UserContext(), s.Log).Errorf("Invalid email type: %T", email)
 		return "", fiber.NewError(fiber.StatusInternalServerError, "invalid session data")
 	}
 
@@ -112,11 +200,11 @@ func (s *SessionHandler) DestroySession(ctx *fiber.Ctx) error {
 	}
 
 	if err := sess.Destroy(); err != nil {
-		s.Log.Errorf("Failed to destroy session: %v", err)
+		Logger(ctx.UserContext(), s.Log).Errorf("Failed to destroy session: %v", err)
 		return err
 	}
 
-	s.Log.Info("Session destroyed successfully")
+	Logger(ctx.UserContext(), s.Log).Info("Session destroyed successfully")
 	return nil
 }
 
@@ -128,7 +216,7 @@ func (s *SessionHandler) RefreshSession(ctx *fiber.Ctx) error {
 
 	// Just calling Save() will refresh the expiration
 	if err := sess.Save(); err != nil {
-		s.Log.Errorf("Failed to refresh session: %v", err)
+		Logger(ctx.UserContext(), s.Log).Errorf("Failed to refresh session: %v", err)
 		return err
 	}
 
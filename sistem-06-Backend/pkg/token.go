@@ -0,0 +1,22 @@
+package pkg
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// GenerateToken returns 32 random bytes as 64 hex characters.
+func GenerateToken() string {
+	b := make([]byte, 32)
+	// crypto/rand.Read never returns an error
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// HashToken is how bearer tokens are stored and looked up. A plain sha256 is
+// enough because the tokens are random, not chosen by users.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
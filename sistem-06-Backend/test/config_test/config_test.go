@@ -0,0 +1,247 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"sistem-06-Backend/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setRequiredEnv(t *testing.T) {
+	t.Setenv("SISTEM06_DATABASE_USERNAME", "sistem06")
+	t.Setenv("SISTEM06_DATABASE_NAME", "sistem06")
+}
+
+func TestNewConfigDefaults(t *testing.T) {
+	setRequiredEnv(t)
+
+	v, err := config.NewViper()
+	require.NoError(t, err)
+
+	cfg, err := config.NewConfig(v)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3000, cfg.Web.Port)
+	assert.Equal(t, "prefer", cfg.Database.SSLMode)
+	assert.Equal(t, "sessions", cfg.Session.Table)
+	assert.Equal(t, int32(10), cfg.Database.Pool.Max)
+}
+
+func TestNewConfigEnvOverrides(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("SISTEM06_DATABASE_PASSWORD", "from-env")
+	t.Setenv("SISTEM06_DATABASE_POOL_MAX", "25")
+	t.Setenv("SISTEM06_WEB_PREFORK", "true")
+
+	v, err := config.NewViper()
+	require.NoError(t, err)
+
+	cfg, err := config.NewConfig(v)
+	require.NoError(t, err)
+
+	assert.Equal(t, "from-env", cfg.Database.Password)
+	assert.Equal(t, int32(25), cfg.Database.Pool.Max)
+	assert.True(t, cfg.Web.Prefork)
+}
+
+func TestNewConfigSecretFile(t *testing.T) {
+	setRequiredEnv(t)
+
+	secret := filepath.Join(t.TempDir(), "db_password")
+	require.NoError(t, os.WriteFile(secret, []byte("s3cret\n"), 0o600))
+	t.Setenv("SISTEM06_DATABASE_PASSWORD", "ignored")
+	t.Setenv("SISTEM06_DATABASE_PASSWORD_FILE", secret)
+
+	v, err := config.NewViper()
+	require.NoError(t, err)
+
+	cfg, err := config.NewConfig(v)
+	require.NoError(t, err)
+
+	assert.Equal(t, "s3cret", cfg.Database.Password)
+}
+
+func TestNewConfigMissingSecretFile(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("SISTEM06_DATABASE_PASSWORD_FILE", filepath.Join(t.TempDir(), "missing"))
+
+	_, err := config.NewViper()
+	assert.Error(t, err)
+}
+
+func TestValidateReportsEveryInvalidKey(t *testing.T) {
+	t.Setenv("SISTEM06_WEB_PORT", "0")
+	t.Setenv("SISTEM06_DATABASE_SSLMODE", "sometimes")
+	t.Setenv("SISTEM06_DATABASE_POOL_MAX", "0")
+
+	v, err := config.NewViper()
+	require.NoError(t, err)
+
+	_, err = config.NewConfig(v)
+	require.Error(t, err)
+
+	var validationErrors config.ValidationErrors
+	require.ErrorAs(t, err, &validationErrors)
+
+	errorText := err.Error()
+	for _, key := range []string{
+		"web.port",
+		"database.username",
+		"database.name",
+		"database.sslmode",
+		"database.pool.max",
+	} {
+		assert.Contains(t, errorText, key)
+	}
+	assert.Len(t, validationErrors, 6)
+}
+
+func TestCheckConfigRejectsUnknownKeys(t *testing.T) {
+	setRequiredEnv(t)
+
+	v, err := config.NewViper()
+	require.NoError(t, err)
+
+	_, err = config.CheckConfig(v)
+	require.NoError(t, err, "the defaults only use known keys")
+
+	v.Set("databse.host", "db.internal")
+	_, err = config.NewConfig(v)
+	assert.NoError(t, err)
+	_, err = config.CheckConfig(v)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "databse")
+}
+
+func TestValidateDatabaseURLSkipsDiscreteFields(t *testing.T) {
+	t.Setenv("SISTEM06_DATABASE_URL", "postgres://app:pw@db:5432/sistem06?sslmode=require")
+
+	v, err := config.NewViper()
+	require.NoError(t, err)
+
+	cfg, err := config.NewConfig(v)
+	require.NoError(t, err)
+	assert.Equal(t, "postgres://app:pw@db:5432/sistem06?sslmode=require", cfg.Database.URL)
+}
+
+func TestValidateTracingExporter(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("SISTEM06_TRACING_EXPORTER", "otlp")
+	t.Setenv("SISTEM06_TRACING_SAMPLE_RATIO", "1.5")
+
+	v, err := config.NewViper()
+	require.NoError(t, err)
+
+	_, err = config.NewConfig(v)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "tracing.endpoint")
+	assert.Contains(t, err.Error(), "tracing.sample_ratio")
+}
+
+func TestNewConfigDefaultRateLimitRules(t *testing.T) {
+	setRequiredEnv(t)
+
+	v, err := config.NewViper()
+	require.NoError(t, err)
+
+	cfg, err := config.NewConfig(v)
+	require.NoError(t, err)
+
+	rules := config.NewRateLimitRules(cfg)
+	require.Len(t, rules, 3)
+	assert.Equal(t, "login", rules[0].Name)
+	assert.Equal(t, "POST", rules[0].Method)
+	assert.Equal(t, "/api/v1/login*", rules[0].Path)
+	assert.Equal(t, time.Minute, rules[0].Window)
+	assert.Equal(t, "ip_user", rules[2].Key)
+}
+
+func TestValidateRateLimitRules(t *testing.T) {
+	cfg := validConfig(t)
+	cfg.RateLimit = config.RateLimitConfig{
+		Enabled:              true,
+		PurgeIntervalMinutes: 5,
+		Rules: []config.RateLimitRuleConfig{
+			{Name: "login", Method: "POST", Path: "/api/v1/login", Limit: 5, WindowSeconds: 60, Key: "ip"},
+			{Name: "login", Method: "post", Path: "api", Limit: 0, WindowSeconds: 0, Key: "cookie"},
+		},
+	}
+
+	err := cfg.Validate()
+	require.Error(t, err)
+	var validationErrors config.ValidationErrors
+	require.ErrorAs(t, err, &validationErrors)
+	assert.Len(t, validationErrors, 6)
+	assert.Contains(t, err.Error(), "rate_limit.rules[1].key")
+}
+
+func validConfig(t *testing.T) *config.Config {
+	setRequiredEnv(t)
+	v, err := config.NewViper()
+	require.NoError(t, err)
+	cfg, err := config.NewConfig(v)
+	require.NoError(t, err)
+	return cfg
+}
+
+func TestValidateSessionCookie(t *testing.T) {
+	cfg := validConfig(t)
+	assert.Equal(t, "session_id", cfg.Session.Cookie.CookieName(cfg.Session.Cookie.Name))
+
+	cfg.Session.Cookie.HostPrefix = true
+	cfg.Session.Cookie.Secure = false
+	cfg.Session.Cookie.Domain = "example.com"
+	cfg.Session.Cookie.SameSite = "Sometimes"
+
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "session.cookie.same_site")
+	assert.Contains(t, err.Error(), "session.cookie.host_prefix: requires session.cookie.secure")
+	assert.Contains(t, err.Error(), "session.cookie.host_prefix: requires an empty session.cookie.domain")
+	assert.Equal(t, "__Host-session_id", cfg.Session.Cookie.CookieName(cfg.Session.Cookie.Name))
+}
+
+func TestValidateEncryptionKeys(t *testing.T) {
+	cfg := validConfig(t)
+	cipher, err := config.NewFieldCipher(cfg)
+	require.NoError(t, err)
+	assert.Nil(t, cipher, "no keys means no encryption")
+
+	cfg.Encryption = config.EncryptionConfig{
+		PrimaryKey: "k2",
+		Keys:       map[string]string{"k1": "c2hvcnQ="},
+		IndexKey:   "not base64!",
+	}
+	err = cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `encryption.primary_key: must name one of encryption.keys, got "k2"`)
+	assert.Contains(t, err.Error(), "encryption.keys.k1: must be 32 bytes, got 5")
+	assert.Contains(t, err.Error(), "encryption.index_key: must be base64")
+
+	cfg.Encryption = config.EncryptionConfig{KeyFile: "/run/secrets/keys.json", PrimaryKey: "k1"}
+	err = cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "encryption.key_file: cannot be combined with inline keys")
+}
+
+func TestNewFieldCipherReadsKeyFile(t *testing.T) {
+	key := "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+	path := filepath.Join(t.TempDir(), "keys.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"primary_key":"k1","keys":{"k1":"`+key+`"},"index_key":"`+key+`"}`), 0o600))
+
+	cfg := validConfig(t)
+	cfg.Encryption.KeyFile = path
+	require.NoError(t, cfg.Validate())
+	cipher, err := config.NewFieldCipher(cfg)
+	require.NoError(t, err)
+	require.NotNil(t, cipher)
+
+	encrypted, err := cipher.Encrypt("users.email", "budi@example.com")
+	require.NoError(t, err)
+	assert.Contains(t, encrypted, "enc:v1:k1:")
+}
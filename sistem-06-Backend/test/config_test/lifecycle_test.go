@@ -0,0 +1,89 @@
+package config_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"sistem-06-Backend/internal/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLifecycle() *config.Lifecycle {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	return config.NewLifecycle(log)
+}
+
+func TestLifecycleRunsHooksInReverseOrder(t *testing.T) {
+	lifecycle := newTestLifecycle()
+
+	var order []string
+	for _, name := range []string{"database", "session storage", "worker pool"} {
+		lifecycle.OnShutdown(name, func(ctx context.Context) error {
+			order = append(order, name)
+			return nil
+		})
+	}
+
+	err := lifecycle.Shutdown(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"worker pool", "session storage", "database"}, order)
+}
+
+func TestLifecycleRunsEveryHookAndJoinsErrors(t *testing.T) {
+	lifecycle := newTestLifecycle()
+
+	called := 0
+	lifecycle.OnShutdown("first", func(ctx context.Context) error {
+		called++
+		return nil
+	})
+	lifecycle.OnShutdown("second", func(ctx context.Context) error {
+		called++
+		return errors.New("boom")
+	})
+
+	err := lifecycle.Shutdown(context.Background())
+
+	assert.Equal(t, 2, called)
+	assert.ErrorContains(t, err, "second: boom")
+}
+
+func TestLifecycleStopsWorkersBeforeHooks(t *testing.T) {
+	lifecycle := newTestLifecycle()
+
+	workerStopped := false
+	lifecycle.Go("ticker", func(ctx context.Context) {
+		<-ctx.Done()
+		workerStopped = true
+	})
+
+	var stoppedBeforeHook bool
+	lifecycle.OnShutdown("database", func(ctx context.Context) error {
+		stoppedBeforeHook = workerStopped
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	assert.NoError(t, lifecycle.Shutdown(ctx))
+	assert.True(t, stoppedBeforeHook)
+}
+
+func TestLifecycleReadiness(t *testing.T) {
+	lifecycle := newTestLifecycle()
+	assert.False(t, lifecycle.IsReady())
+
+	lifecycle.MarkReady()
+	assert.True(t, lifecycle.IsReady())
+
+	assert.NoError(t, lifecycle.Shutdown(context.Background()))
+	assert.False(t, lifecycle.IsReady())
+}
@@ -6,14 +6,14 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
-	"backend-sistem06.com/internal/entity"
-	"backend-sistem06.com/internal/model/converter"
+	"sistem-06-Backend/internal/delivery/http/converter"
+	"sistem-06-Backend/internal/domain/entity"
 )
 
 func TestUserToResponse(t *testing.T) {
 	t.Run("should convert user entity to response correctly", func(t *testing.T) {
 		now := time.Now().Unix()
-		user := &entity.UserEntity{
+		user := &entity.User{
 			ID:        1,
 			Name:      "John Doe",
 			Email:     "john@example.com",
@@ -32,7 +32,7 @@ func TestUserToResponse(t *testing.T) {
 	})
 
 	t.Run("should not include email in response", func(t *testing.T) {
-		user := &entity.UserEntity{
+		user := &entity.User{
 			ID:        1,
 			Name:      "John Doe",
 			Email:     "john@example.com",
@@ -52,7 +52,7 @@ func TestUserToResponse(t *testing.T) {
 	})
 
 	t.Run("should not include password in response", func(t *testing.T) {
-		user := &entity.UserEntity{
+		user := &entity.User{
 			ID:        1,
 			Name:      "John Doe",
 			Email:     "john@example.com",
@@ -70,7 +70,7 @@ func TestUserToResponse(t *testing.T) {
 	})
 
 	t.Run("should handle zero values correctly", func(t *testing.T) {
-		user := &entity.UserEntity{
+		user := &entity.User{
 			ID:        0,
 			Name:      "",
 			Email:     "",
@@ -127,7 +127,7 @@ func TestUserToResponse_WithDifferentTimestamps(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			user := &entity.UserEntity{
+			user := &entity.User{
 				ID:        1,
 				Name:      "Test User",
 				Email:     "test@example.com",
@@ -0,0 +1,117 @@
+package fieldcrypt_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"sistem-06-Backend/internal/pkg/fieldcrypt"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func key(b byte) []byte {
+	return bytes.Repeat([]byte{b}, fieldcrypt.KeySize)
+}
+
+func TestEncryptRoundTrip(t *testing.T) {
+	cipher, err := fieldcrypt.New("k1", map[string][]byte{"k1": key(1)}, key(9))
+	require.NoError(t, err)
+
+	first, err := cipher.Encrypt("users.email", "budi@example.com")
+	require.NoError(t, err)
+	second, err := cipher.Encrypt("users.email", "budi@example.com")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(first, "enc:v1:k1:"))
+	assert.True(t, fieldcrypt.Encrypted(first))
+	assert.NotEqual(t, first, second, "every value gets its own data key and nonce")
+	assert.NotContains(t, first, "budi")
+
+	plain, err := cipher.Decrypt("users.email", first)
+	require.NoError(t, err)
+	assert.Equal(t, "budi@example.com", plain)
+
+	// the column is authenticated
+	_, err = cipher.Decrypt("users.nik", first)
+	assert.Error(t, err)
+}
+
+func TestDecryptPassesPlainValuesThrough(t *testing.T) {
+	cipher, err := fieldcrypt.New("k1", map[string][]byte{"k1": key(1)}, key(9))
+	require.NoError(t, err)
+
+	plain, err := cipher.Decrypt("users.email", "legacy@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "legacy@example.com", plain)
+
+	_, err = cipher.Decrypt("users.email", "enc:v1:k1:broken")
+	assert.ErrorIs(t, err, fieldcrypt.ErrMalformed)
+}
+
+func TestRotateRewrapsUnderPrimaryKey(t *testing.T) {
+	old, err := fieldcrypt.New("k1", map[string][]byte{"k1": key(1)}, key(9))
+	require.NoError(t, err)
+	encrypted, err := old.Encrypt("users.email", "budi@example.com")
+	require.NoError(t, err)
+
+	rotated, err := fieldcrypt.New("k2", map[string][]byte{"k1": key(1), "k2": key(2)}, key(9))
+	require.NoError(t, err)
+	value, changed, err := rotated.Rotate("users.email", encrypted)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.True(t, strings.HasPrefix(value, "enc:v1:k2:"))
+	// only the data key is re-wrapped
+	assert.Equal(t, encrypted[strings.LastIndex(encrypted, ":"):], value[strings.LastIndex(value, ":"):])
+
+	_, changed, err = rotated.Rotate("users.email", value)
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	// once k1 is dropped only the rotated value can be read
+	current, err := fieldcrypt.New("k2", map[string][]byte{"k2": key(2)}, key(9))
+	require.NoError(t, err)
+	plain, err := current.Decrypt("users.email", value)
+	require.NoError(t, err)
+	assert.Equal(t, "budi@example.com", plain)
+	_, err = current.Decrypt("users.email", encrypted)
+	assert.ErrorIs(t, err, fieldcrypt.ErrUnknownKey)
+
+	value, changed, err = current.Rotate("users.email", "legacy@example.com")
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.True(t, fieldcrypt.Encrypted(value))
+}
+
+func TestBlindIndex(t *testing.T) {
+	cipher, err := fieldcrypt.New("k1", map[string][]byte{"k1": key(1)}, key(9))
+	require.NoError(t, err)
+	other, err := fieldcrypt.New("k1", map[string][]byte{"k1": key(1)}, key(8))
+	require.NoError(t, err)
+
+	index := cipher.BlindIndex("users.email", "budi@example.com")
+	assert.Len(t, index, 64)
+	assert.Equal(t, index, cipher.BlindIndex("users.email", "budi@example.com"))
+	assert.NotEqual(t, index, cipher.BlindIndex("users.nik", "budi@example.com"))
+	assert.NotEqual(t, index, other.BlindIndex("users.email", "budi@example.com"))
+}
+
+func TestNilCipherStoresInTheClear(t *testing.T) {
+	var cipher *fieldcrypt.Cipher
+
+	value, err := cipher.Encrypt("users.email", "budi@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "budi@example.com", value)
+	assert.Len(t, cipher.BlindIndex("users.email", value), 64)
+}
+
+func TestNewRejectsBadKeys(t *testing.T) {
+	_, err := fieldcrypt.New("k2", map[string][]byte{"k1": key(1)}, key(9))
+	assert.Error(t, err)
+	_, err = fieldcrypt.New("k1", map[string][]byte{"k1": key(1)[:16]}, key(9))
+	assert.Error(t, err)
+	_, err = fieldcrypt.New("k:1", map[string][]byte{"k:1": key(1)}, key(9))
+	assert.Error(t, err)
+	_, err = fieldcrypt.New("k1", map[string][]byte{"k1": key(1)}, nil)
+	assert.Error(t, err)
+}
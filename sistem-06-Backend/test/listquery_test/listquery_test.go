@@ -0,0 +1,166 @@
+package listquery_test
+
+import (
+	"testing"
+
+	"sistem-06-Backend/internal/pkg/listquery"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type item struct {
+	ID        int
+	Kota      string
+	CreatedAt int64
+}
+
+var schema = &listquery.Schema[item]{
+	Table:   "address",
+	Columns: "id, kota, created_at",
+	Where:   "deleted_at = 0",
+	Fields: map[string]listquery.Field[item]{
+		"id":         {Column: "id", Type: listquery.Integer, Sortable: true, Value: func(i item) any { return i.ID }},
+		"kota":       {Column: "kota", Type: listquery.Text, Sortable: true, Value: func(i item) any { return i.Kota }},
+		"created_at": {Column: "created_at", Type: listquery.Integer, Sortable: true, Value: func(i item) any { return i.CreatedAt }},
+	},
+	Key:         "id",
+	DefaultSort: []listquery.Sort{{Field: "id"}},
+	DefaultSize: 2,
+	MaxSize:     10,
+	CountTotal:  true,
+}
+
+func compile(t *testing.T, query map[string]string) *listquery.Plan {
+	request, err := listquery.Parse(query)
+	require.NoError(t, err)
+	plan, err := schema.Compile(request)
+	require.NoError(t, err)
+	return plan
+}
+
+func TestCompileOffsetPage(t *testing.T) {
+	plan := compile(t, map[string]string{
+		"filter[kota][like]":      "ban_",
+		"filter[created_at][gte]": "100",
+		"filter[id][in]":          "1, 2,3",
+		"sort":                    "-kota",
+		"page[size]":              "5",
+		"page[number]":            "3",
+		"unrelated":               "ignored",
+	})
+
+	assert.Equal(t,
+		"SELECT id, kota, created_at FROM address WHERE deleted_at = 0 AND created_at >= $1 AND id IN ($2, $3, $4) AND kota ILIKE $5 ORDER BY kota DESC, id ASC LIMIT 6 OFFSET 10",
+		plan.List.SQL)
+	assert.Equal(t, []any{int64(100), int64(1), int64(2), int64(3), `%ban\_%`}, plan.List.Args)
+	require.NotNil(t, plan.Count)
+	assert.Equal(t, "SELECT count(*) FROM address WHERE deleted_at = 0 AND created_at >= $1 AND id IN ($2, $3, $4) AND kota ILIKE $5", plan.Count.SQL)
+
+	items, paging := schema.Page(plan, []item{{ID: 1}, {ID: 2}}, 12)
+	assert.Len(t, items, 2)
+	assert.Equal(t, 3, paging.Page)
+	assert.EqualValues(t, 12, paging.TotalItem)
+	assert.EqualValues(t, 3, paging.TotalPage)
+	assert.Empty(t, paging.NextCursor)
+}
+
+func TestKeysetCursorContinuesAfterLastRow(t *testing.T) {
+	plan := compile(t, map[string]string{"sort": "-created_at", "page[cursor]": "", "page[total]": "false"})
+	assert.Nil(t, plan.Count)
+	assert.Equal(t, "SELECT id, kota, created_at FROM address WHERE deleted_at = 0 ORDER BY created_at DESC, id ASC LIMIT 3", plan.List.SQL)
+
+	items, paging := schema.Page(plan, []item{{ID: 9, CreatedAt: 300}, {ID: 4, CreatedAt: 200}, {ID: 5, CreatedAt: 200}}, 0)
+	require.Len(t, items, 2)
+	assert.EqualValues(t, -1, paging.TotalItem)
+	require.NotEmpty(t, paging.NextCursor)
+
+	next := compile(t, map[string]string{"sort": "-created_at", "page[cursor]": paging.NextCursor})
+	assert.Equal(t,
+		"SELECT id, kota, created_at FROM address WHERE deleted_at = 0 AND ((created_at < $1) OR (created_at = $1 AND id > $2)) ORDER BY created_at DESC, id ASC LIMIT 3",
+		next.List.SQL)
+	assert.Equal(t, []any{int64(200), int64(4)}, next.List.Args)
+
+	request, err := listquery.Parse(map[string]string{"sort": "kota", "page[cursor]": paging.NextCursor})
+	require.NoError(t, err)
+	_, err = schema.Compile(request)
+	var invalid *listquery.Error
+	assert.ErrorAs(t, err, &invalid, "a cursor only fits the sort it was issued for")
+}
+
+func TestRejectsFieldsOutsideTheWhitelist(t *testing.T) {
+	for _, query := range []map[string]string{
+		{"filter[password]": "x"},
+		{"sort": "deleted_at"},
+		{"filter[id]": "1 OR 1=1"},
+		{"filter[id][like]": "1"},
+		{"page[size]": "11"},
+		{"page[cursor]": "not-a-cursor"},
+	} {
+		request, err := listquery.Parse(query)
+		require.NoError(t, err)
+		_, err = schema.Compile(request)
+		var invalid *listquery.Error
+		assert.ErrorAs(t, err, &invalid, "%v", query)
+	}
+
+	for _, query := range []map[string]string{
+		{"filter[kota][regex]": "x"},
+		{"page[size]": "0"},
+		{"page[number]": "2", "page[cursor]": ""},
+	} {
+		_, err := listquery.Parse(query)
+		var invalid *listquery.Error
+		assert.ErrorAs(t, err, &invalid, "%v", query)
+	}
+}
+
+func TestCompileAllIgnoresPaging(t *testing.T) {
+	request, err := listquery.Parse(map[string]string{"filter[kota]": "Bandung", "page[size]": "500", "page[number]": "4"})
+	require.NoError(t, err)
+
+	query, err := schema.CompileAll(request)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT id, kota, created_at FROM address WHERE deleted_at = 0 AND kota = $1 ORDER BY id ASC", query.SQL)
+	assert.Equal(t, []any{"Bandung"}, query.Args)
+
+	request, err = listquery.Parse(map[string]string{"sort": "password"})
+	require.NoError(t, err)
+	_, err = schema.CompileAll(request)
+	var listErr *listquery.Error
+	assert.ErrorAs(t, err, &listErr)
+}
+
+func TestHashedFieldsOnlyMatchExactly(t *testing.T) {
+	hashed := &listquery.Schema[item]{
+		Table:   "users",
+		Columns: "id",
+		Fields: map[string]listquery.Field[item]{
+			"id":    {Column: "id", Type: listquery.Integer, Sortable: true, Value: func(i item) any { return i.ID }},
+			"email": {Column: "email_hash", Type: listquery.Text, Hash: func(value string) string { return "h(" + value + ")" }},
+		},
+		Key:         "id",
+		DefaultSort: []listquery.Sort{{Field: "id"}},
+		DefaultSize: 10,
+		MaxSize:     10,
+	}
+
+	request, err := listquery.Parse(map[string]string{"filter[email][in]": "a@example.com,b@example.com"})
+	require.NoError(t, err)
+	query, err := hashed.CompileAll(request)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE email_hash IN ($1, $2) ORDER BY id ASC", query.SQL)
+	assert.Equal(t, []any{"h(a@example.com)", "h(b@example.com)"}, query.Args)
+
+	for _, query := range []map[string]string{
+		{"filter[email][like]": "example"},
+		{"filter[email][gt]": "a"},
+		{"sort": "email"},
+	} {
+		request, err := listquery.Parse(query)
+		require.NoError(t, err)
+		_, err = hashed.CompileAll(request)
+		var invalid *listquery.Error
+		assert.ErrorAs(t, err, &invalid, "%v", query)
+	}
+}
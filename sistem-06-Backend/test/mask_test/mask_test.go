@@ -0,0 +1,52 @@
+package mask_test
+
+import (
+	"testing"
+
+	"sistem-06-Backend/internal/dto"
+	"sistem-06-Backend/internal/pkg/mask"
+	"sistem-06-Backend/pkg"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func allow(permissions ...string) func(string) bool {
+	return func(permission string) bool {
+		for _, p := range permissions {
+			if p == permission {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func users() []*dto.UserResponse {
+	return []*dto.UserResponse{
+		{ID: 1, Name: "budi", Email: "budi@example.com", PendingEmail: "budi@new.example.com"},
+		{ID: 2, Name: "siti", Email: "siti@example.com"},
+	}
+}
+
+func TestApplyMasksForResidents(t *testing.T) {
+	response := pkg.WebResponse[[]*dto.UserResponse]{Data: users()}
+	mask.Apply(&response, mask.Viewer{ID: 2, Allowed: allow()})
+
+	assert.Equal(t, "b***@example.com", response.Data[0].Email)
+	assert.Empty(t, response.Data[0].PendingEmail)
+	assert.Equal(t, "budi", response.Data[0].Name)
+	assert.Equal(t, "siti@example.com", response.Data[1].Email, "the viewer's own record is left alone")
+}
+
+func TestApplyShowsEverythingToOfficers(t *testing.T) {
+	response := users()
+	mask.Apply(response, mask.Viewer{ID: 9, Allowed: allow("users.manage")})
+	assert.Equal(t, users(), response)
+}
+
+func TestApplyMasksAddressesWithoutManage(t *testing.T) {
+	address := &dto.AddressEntity{ID: 1, Jalan: "Jl. Melati 5", RT: "001", RW: "002", Kota: "Bandung"}
+	mask.Apply(address, mask.Viewer{ID: 2, Allowed: allow("users.manage")})
+	assert.Equal(t, "J***", address.Jalan)
+	assert.Equal(t, "Bandung", address.Kota)
+}
@@ -0,0 +1,214 @@
+package middleware_test
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"iter"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"sistem-06-Backend/internal/delivery/http/middleware"
+	"sistem-06-Backend/internal/domain/entity"
+	domain "sistem-06-Backend/internal/domain/ports"
+	"sistem-06-Backend/internal/pkg/listquery"
+	"sistem-06-Backend/internal/usecase"
+	"sistem-06-Backend/pkg"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/session"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticUserRepository struct {
+	user *entity.User
+}
+
+func (r *staticUserRepository) WithTx(tx *sql.Tx) domain.UserRepository { return r }
+
+func (r *staticUserRepository) CreateUser(ctx context.Context, user *entity.User) error { return nil }
+
+func (r *staticUserRepository) CountById(ctx context.Context, id int) (int, error) { return 0, nil }
+
+func (r *staticUserRepository) CountByName(ctx context.Context, name string) (int, error) {
+	return 0, nil
+}
+
+func (r *staticUserRepository) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
+	return r.FindByID(ctx, 0)
+}
+
+func (r *staticUserRepository) FindByID(ctx context.Context, id int) (*entity.User, error) {
+	if r.user.ID != id {
+		return nil, sql.ErrNoRows
+	}
+	return r.user, nil
+}
+
+func (r *staticUserRepository) FindWithRoles(ctx context.Context, id int) (*entity.User, error) {
+	return r.FindByID(ctx, id)
+}
+
+func (r *staticUserRepository) List(ctx context.Context, request *listquery.Request) ([]*entity.User, pkg.PageMetadata, error) {
+	return []*entity.User{r.user}, pkg.PageMetadata{}, nil
+}
+
+func (r *staticUserRepository) Export(ctx context.Context, request *listquery.Request) (iter.Seq2[*entity.User, error], error) {
+	return func(yield func(*entity.User, error) bool) { yield(r.user, nil) }, nil
+}
+
+func (r *staticUserRepository) UpdateName(ctx context.Context, id int, version int, name string, updatedAt int64) error {
+	return nil
+}
+
+func (r *staticUserRepository) UpdateEmail(ctx context.Context, id int, email string, updatedAt int64) error {
+	return nil
+}
+
+func (r *staticUserRepository) UpdatePassword(ctx context.Context, id int, password string, updatedAt int64) error {
+	return nil
+}
+
+func (r *staticUserRepository) RehashPassword(ctx context.Context, id int, password string) error {
+	return nil
+}
+
+func (r *staticUserRepository) RevokeSessions(ctx context.Context, id int, revokedAt int64) error {
+	return nil
+}
+
+func (r *staticUserRepository) SoftDelete(ctx context.Context, id int, version int, deletedAt int64) error {
+	return sql.ErrNoRows
+}
+
+func (r *staticUserRepository) Restore(ctx context.Context, id int, restoredAt int64) (bool, error) {
+	return false, nil
+}
+
+func (r *staticUserRepository) PurgeDeleted(ctx context.Context, deletedBefore int64) (int64, error) {
+	return 0, nil
+}
+
+func (r *staticUserRepository) Anonymize(ctx context.Context, id int, placeholder string, anonymizedAt int64) (bool, error) {
+	return false, nil
+}
+
+type staticTokenRepository struct {
+	token *entity.PersonalAccessToken
+}
+
+func (r *staticTokenRepository) WithTx(tx *sql.Tx) domain.TokenRepository { return r }
+
+func (r *staticTokenRepository) CreateToken(ctx context.Context, token *entity.PersonalAccessToken) error {
+	return nil
+}
+
+func (r *staticTokenRepository) FindTokenByHash(ctx context.Context, hash string) (*entity.PersonalAccessToken, error) {
+	if r.token.TokenHash != hash {
+		return nil, sql.ErrNoRows
+	}
+	copied := *r.token
+	return &copied, nil
+}
+
+func (r *staticTokenRepository) ListTokensByUserID(ctx context.Context, userID int) ([]*entity.PersonalAccessToken, error) {
+	return nil, nil
+}
+
+func (r *staticTokenRepository) TouchToken(ctx context.Context, id int, usedAt int64) error {
+	return nil
+}
+
+func (r *staticTokenRepository) RevokeToken(ctx context.Context, id int, userID int, revokedAt int64) (bool, error) {
+	return false, nil
+}
+
+func (r *staticTokenRepository) DeleteTokensByUserID(ctx context.Context, userID int) error {
+	return nil
+}
+
+func setupBearerApp(t *testing.T, scopes ...entity.Permissions) (*fiber.App, string) {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	users := &staticUserRepository{user: &entity.User{ID: 7, Email: "bendahara@example.com", Roles: []entity.Role{
+		{ID: 1, Name: "admin", Permission: []entity.Permissions{entity.PermissionManageUsers}},
+	}}}
+	plain := pkg.GenerateToken()
+	tokens := &staticTokenRepository{token: &entity.PersonalAccessToken{
+		ID:        3,
+		UserID:    7,
+		TokenHash: pkg.HashToken(plain),
+		Scopes:    scopes,
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+	}}
+
+	authUseCase := &usecase.AuthUseCase{Log: log, UserRepository: users}
+	tokenUseCase := usecase.NewTokenUseCase(nil, log, nil, users, tokens, nil, time.Hour, time.Hour)
+	authMiddleware := middleware.NewAuthMiddleware(pkg.NewSessionHandler(session.New(), log), authUseCase, tokenUseCase, log)
+
+	app := fiber.New()
+	api := app.Group("/api/v1", authMiddleware.RequiredAuth())
+	api.Get("/me", func(c *fiber.Ctx) error {
+		return c.SendString(c.Locals("email").(string))
+	})
+	api.Post("/users/:id/unlock", authMiddleware.RequirePermission(entity.PermissionManageUsers), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+	api.Get("/exports/users", authMiddleware.RequireAnyPermission(entity.PermissionManageUsers, entity.PermissionExportData), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+	api.Get("/exports/addresses", authMiddleware.RequireAnyPermission(entity.PermissionManageAddresses, entity.PermissionExportData), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+	api.Get("/tokens", authMiddleware.RequireSession(), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+	return app, plain
+}
+
+func bearerRequest(t *testing.T, app *fiber.App, method string, path string, token string) int {
+	req := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	res, err := app.Test(req)
+	require.NoError(t, err)
+	return res.StatusCode
+}
+
+func TestBearerTokenAuthenticates(t *testing.T) {
+	app, token := setupBearerApp(t)
+
+	assert.Equal(t, fiber.StatusOK, bearerRequest(t, app, "GET", "/api/v1/me", token))
+	assert.Equal(t, fiber.StatusUnauthorized, bearerRequest(t, app, "GET", "/api/v1/me", pkg.GenerateToken()))
+	assert.Equal(t, fiber.StatusUnauthorized, bearerRequest(t, app, "GET", "/api/v1/me", ""))
+}
+
+func TestBearerTokenNeedsScopeForPermission(t *testing.T) {
+	app, token := setupBearerApp(t)
+	assert.Equal(t, fiber.StatusForbidden, bearerRequest(t, app, "POST", "/api/v1/users/2/unlock", token))
+
+	app, token = setupBearerApp(t, entity.PermissionManageUsers)
+	assert.Equal(t, fiber.StatusNoContent, bearerRequest(t, app, "POST", "/api/v1/users/2/unlock", token))
+}
+
+func TestRequireAnyPermission(t *testing.T) {
+	// the user has users.manage but neither addresses.manage nor data.export
+	app, token := setupBearerApp(t, entity.PermissionManageUsers)
+	assert.Equal(t, fiber.StatusNoContent, bearerRequest(t, app, "GET", "/api/v1/exports/users", token))
+	assert.Equal(t, fiber.StatusForbidden, bearerRequest(t, app, "GET", "/api/v1/exports/addresses", token))
+
+	// a scope the user's roles do not grant opens nothing
+	app, token = setupBearerApp(t, entity.PermissionExportData)
+	assert.Equal(t, fiber.StatusForbidden, bearerRequest(t, app, "GET", "/api/v1/exports/users", token))
+	assert.Equal(t, fiber.StatusForbidden, bearerRequest(t, app, "GET", "/api/v1/exports/addresses", token))
+}
+
+func TestBearerTokenCannotManageTokens(t *testing.T) {
+	app, token := setupBearerApp(t, entity.PermissionManageUsers)
+	assert.Equal(t, fiber.StatusForbidden, bearerRequest(t, app, "GET", "/api/v1/tokens", token))
+}
@@ -0,0 +1,137 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	httpdelivery "sistem-06-Backend/internal/delivery/http"
+	"sistem-06-Backend/internal/delivery/http/middleware"
+	"sistem-06-Backend/pkg"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/csrf"
+	"github.com/gofiber/fiber/v2/middleware/session"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupCSRFApp() *fiber.App {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	store := session.New()
+	sessionHandler := pkg.NewSessionHandler(store, log)
+	csrfMiddleware := middleware.NewCSRFMiddleware(csrf.Config{
+		KeyLookup:  "header:X-CSRF-Token",
+		CookieName: "csrf_token",
+		Session:    store,
+	}, true, log)
+
+	app := fiber.New()
+	app.Use("/api/v1", csrfMiddleware.Protect())
+	app.Get("/api/v1/csrf", httpdelivery.NewAuthController(nil, log, sessionHandler).CSRFToken)
+	app.Post("/api/v1/login", func(c *fiber.Ctx) error {
+		if err := sessionHandler.SetUserSession(c, 1, "warga@example.com"); err != nil {
+			return err
+		}
+		// a second write in the same request, as AuthController.Login does
+		if err := sessionHandler.SetTwoFactorEnrollmentRequired(c, true); err != nil {
+			return err
+		}
+		return c.SendStatus(fiber.StatusOK)
+	})
+	app.Get("/api/v1/me", func(c *fiber.Ctx) error {
+		userID, err := sessionHandler.GetUserID(c)
+		if err != nil {
+			return err
+		}
+		return c.JSON(fiber.Map{"user_id": userID, "enroll": sessionHandler.TwoFactorEnrollmentRequired(c)})
+	})
+	return app
+}
+
+func fetchCSRFToken(t *testing.T, app *fiber.App) (string, []*http.Cookie) {
+	res, err := app.Test(httptest.NewRequest("GET", "/api/v1/csrf", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, res.StatusCode)
+
+	var body pkg.WebResponse[map[string]string]
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+	require.NotEmpty(t, body.Data["token"])
+	return body.Data["token"], res.Cookies()
+}
+
+func cookieValue(cookies []*http.Cookie, name string) string {
+	for _, cookie := range cookies {
+		if cookie.Name == name {
+			return cookie.Value
+		}
+	}
+	return ""
+}
+
+func TestCSRFRejectsPostWithoutToken(t *testing.T) {
+	app := setupCSRFApp()
+
+	res, err := app.Test(httptest.NewRequest("POST", "/api/v1/login", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusForbidden, res.StatusCode)
+}
+
+func TestCSRFAcceptsTokenAndLoginRegeneratesSession(t *testing.T) {
+	app := setupCSRFApp()
+	token, cookies := fetchCSRFToken(t, app)
+	assert.Equal(t, token, cookieValue(cookies, "csrf_token"))
+
+	req := httptest.NewRequest("POST", "/api/v1/login", nil)
+	req.Header.Set("X-CSRF-Token", token)
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	res, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, res.StatusCode)
+
+	before := cookieValue(cookies, "session_id")
+	after := cookieValue(res.Cookies(), "session_id")
+	require.NotEmpty(t, before)
+	require.NotEmpty(t, after)
+	assert.NotEqual(t, before, after, "login must issue a new session id")
+
+	req = httptest.NewRequest("GET", "/api/v1/me", nil)
+	req.AddCookie(&http.Cookie{Name: "session_id", Value: after})
+	res, err = app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, res.StatusCode, "the new session holds the login")
+	var me map[string]any
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&me))
+	assert.Equal(t, float64(1), me["user_id"])
+	assert.Equal(t, true, me["enroll"])
+}
+
+func TestCSRFRejectsTokenFromAnotherSession(t *testing.T) {
+	app := setupCSRFApp()
+	token, cookies := fetchCSRFToken(t, app)
+
+	// the attacker's own token, replayed without the matching session
+	req := httptest.NewRequest("POST", "/api/v1/login", nil)
+	req.Header.Set("X-CSRF-Token", token)
+	req.AddCookie(&http.Cookie{Name: "csrf_token", Value: cookieValue(cookies, "csrf_token")})
+	res, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusForbidden, res.StatusCode)
+}
+
+func TestCSRFSkipsBearerRequests(t *testing.T) {
+	app := setupCSRFApp()
+
+	req := httptest.NewRequest("POST", "/api/v1/login", nil)
+	req.Header.Set("Authorization", "Bearer "+pkg.GenerateToken())
+	res, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, res.StatusCode)
+}
@@ -0,0 +1,94 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"sistem-06-Backend/internal/delivery/http/middleware"
+	"sistem-06-Backend/pkg"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupLoggingApp() (*fiber.App, *test.Hook) {
+	log, hook := test.NewNullLogger()
+	log.SetLevel(logrus.InfoLevel)
+
+	logging := middleware.NewLoggingMiddleware(log)
+
+	app := fiber.New()
+	app.Use(logging.RequestID(), logging.AccessLog())
+	app.Get("/users/:id", func(c *fiber.Ctx) error {
+		pkg.Logger(c.UserContext(), log).Info("inside handler")
+		return c.SendString(pkg.RequestID(c.UserContext()))
+	})
+	app.Get("/fail", func(c *fiber.Ctx) error {
+		return fiber.ErrBadRequest
+	})
+	return app, hook
+}
+
+func TestRequestIDIsGenerated(t *testing.T) {
+	app, hook := setupLoggingApp()
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/users/7", nil))
+	require.NoError(t, err)
+
+	requestID := resp.Header.Get(middleware.RequestIDHeader)
+	assert.Len(t, requestID, 36)
+
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, requestID, string(body))
+
+	handlerEntry := hook.AllEntries()[0]
+	assert.Equal(t, "inside handler", handlerEntry.Message)
+	assert.Equal(t, requestID, handlerEntry.Data["request_id"])
+	assert.Equal(t, "GET", handlerEntry.Data["method"])
+}
+
+func TestRequestIDIsPropagated(t *testing.T) {
+	app, _ := setupLoggingApp()
+
+	req := httptest.NewRequest("GET", "/users/7", nil)
+	req.Header.Set(middleware.RequestIDHeader, "upstream-abc.123")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, "upstream-abc.123", resp.Header.Get(middleware.RequestIDHeader))
+}
+
+func TestRequestIDRejectsUnsafeValues(t *testing.T) {
+	app, _ := setupLoggingApp()
+
+	req := httptest.NewRequest("GET", "/users/7", nil)
+	req.Header.Set(middleware.RequestIDHeader, "bad id\twith spaces")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.NotEqual(t, "bad id\twith spaces", resp.Header.Get(middleware.RequestIDHeader))
+}
+
+func TestAccessLog(t *testing.T) {
+	app, hook := setupLoggingApp()
+
+	_, err := app.Test(httptest.NewRequest("GET", "/users/7", nil))
+	require.NoError(t, err)
+
+	entry := hook.LastEntry()
+	assert.Equal(t, logrus.InfoLevel, entry.Level)
+	assert.Equal(t, "/users/:id", entry.Data["route"])
+	assert.Equal(t, 200, entry.Data["status"])
+	assert.Contains(t, entry.Data, "latency_ms")
+
+	_, err = app.Test(httptest.NewRequest("GET", "/fail", nil))
+	require.NoError(t, err)
+
+	entry = hook.LastEntry()
+	assert.Equal(t, logrus.WarnLevel, entry.Level)
+	assert.Equal(t, 400, entry.Data["status"])
+}
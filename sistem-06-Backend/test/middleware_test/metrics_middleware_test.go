@@ -0,0 +1,105 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"sistem-06-Backend/internal/delivery/http/middleware"
+	"sistem-06-Backend/internal/infrastructure/metrics"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupMetricsApp(token string, allowlist []string) (*fiber.App, *metrics.Metrics) {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	m := metrics.NewMetrics(nil)
+	metricsMiddleware := middleware.NewMetricsMiddleware(m, token, allowlist, log)
+
+	app := fiber.New()
+	app.Use(metricsMiddleware.Instrument())
+	app.Get("/users/:id", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	app.Get("/metrics", metricsMiddleware.Protect(), func(c *fiber.Ctx) error {
+		return c.SendString("metrics")
+	})
+	return app, m
+}
+
+func TestMetricsInstrumentUsesRoutePattern(t *testing.T) {
+	app, m := setupMetricsApp("", nil)
+
+	for _, path := range []string{"/users/1", "/users/2", "/nope"} {
+		_, err := app.Test(httptest.NewRequest("GET", path, nil))
+		require.NoError(t, err)
+	}
+
+	count, err := testutil.GatherAndCount(m.Registry, "sistem06_http_request_duration_seconds")
+	require.NoError(t, err)
+	// one series for /users/:id and one for unmatched paths
+	assert.Equal(t, 2, count)
+}
+
+func TestMetricsProtect(t *testing.T) {
+	tests := []struct {
+		name       string
+		token      string
+		allowlist  []string
+		authHeader string
+		wantStatus int
+	}{
+		{
+			name:       "allowlisted network",
+			allowlist:  []string{"0.0.0.0/0"},
+			wantStatus: fiber.StatusOK,
+		},
+		{
+			name:       "not allowlisted",
+			allowlist:  []string{"10.0.0.0/8"},
+			wantStatus: fiber.StatusForbidden,
+		},
+		{
+			name:       "valid token",
+			token:      "scrape-secret",
+			authHeader: "Bearer scrape-secret",
+			wantStatus: fiber.StatusOK,
+		},
+		{
+			name:       "wrong token",
+			token:      "scrape-secret",
+			authHeader: "Bearer guess",
+			wantStatus: fiber.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, _ := setupMetricsApp(tt.token, tt.allowlist)
+
+			req := httptest.NewRequest("GET", "/metrics", nil)
+			if tt.authHeader != "" {
+				req.Header.Set("Authorization", tt.authHeader)
+			}
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestMetricsNilIsNoop(t *testing.T) {
+	var m *metrics.Metrics
+
+	assert.NotPanics(t, func() {
+		m.LoginAttempt("success")
+		m.Registration("conflict")
+	})
+}
@@ -0,0 +1,179 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"sistem-06-Backend/internal/delivery/http/middleware"
+	"sistem-06-Backend/internal/domain/entity"
+	"sistem-06-Backend/internal/usecase"
+	"sistem-06-Backend/pkg"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/session"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryRateLimitRepository struct {
+	mu       sync.Mutex
+	counters map[string]*entity.RateLimitCounter
+	err      error
+}
+
+func (r *memoryRateLimitRepository) Hit(ctx context.Context, bucket string, now int64, resetAt int64) (*entity.RateLimitCounter, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	counter, ok := r.counters[bucket]
+	if !ok || counter.ResetAt <= now {
+		counter = &entity.RateLimitCounter{Bucket: bucket, ResetAt: resetAt}
+		r.counters[bucket] = counter
+	}
+	counter.Hits++
+	copied := *counter
+	return &copied, nil
+}
+
+func (r *memoryRateLimitRepository) DeleteExpired(ctx context.Context, now int64) (int64, error) {
+	return 0, nil
+}
+
+func setupRateLimitApp(repo *memoryRateLimitRepository) *fiber.App {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	sessionHandler := pkg.NewSessionHandler(session.New(), log)
+	rules := []middleware.RateLimitRule{
+		{Name: "login", Method: "POST", Path: "/api/v1/login", Limit: 2, Window: time.Minute, Key: middleware.RateLimitKeyIP},
+		{Name: "api", Path: "/api/v1/*", Limit: 100, Window: time.Minute, Key: middleware.RateLimitKeyIPUser},
+	}
+	rateLimitMiddleware := middleware.NewRateLimitMiddleware(usecase.NewRateLimitUseCase(log, repo), sessionHandler, rules, nil, log)
+
+	app := fiber.New()
+	app.Use(rateLimitMiddleware.Limit())
+	app.Post("/api/v1/login", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	app.Get("/api/v1/addresses", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	app.Get("/healthz", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	return app
+}
+
+func TestRateLimitRejectsAfterLimit(t *testing.T) {
+	repo := &memoryRateLimitRepository{counters: map[string]*entity.RateLimitCounter{}}
+	app := setupRateLimitApp(repo)
+
+	for i, wantRemaining := range []string{"1", "0"} {
+		resp, err := app.Test(httptest.NewRequest("POST", "/api/v1/login", nil))
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode, "request %d", i)
+		assert.Equal(t, "2", resp.Header.Get("RateLimit-Limit"))
+		assert.Equal(t, wantRemaining, resp.Header.Get("RateLimit-Remaining"))
+		assert.Equal(t, "2;w=60", resp.Header.Get("RateLimit-Policy"))
+	}
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/api/v1/login", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "0", resp.Header.Get("RateLimit-Remaining"))
+	assert.NotEmpty(t, resp.Header.Get("Retry-After"))
+
+	// other rules keep their own counters
+	resp, err = app.Test(httptest.NewRequest("GET", "/api/v1/addresses", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, "99", resp.Header.Get("RateLimit-Remaining"))
+}
+
+func TestRateLimitMatchesPathVariantsOfTheRoute(t *testing.T) {
+	repo := &memoryRateLimitRepository{counters: map[string]*entity.RateLimitCounter{}}
+	app := setupRateLimitApp(repo)
+
+	// the router sends all of these to the login handler, so they share its
+	// counter
+	for _, path := range []string{"/api/v1/login", "/API/V1/LOGIN", "/api/v1/login/"} {
+		resp, err := app.Test(httptest.NewRequest("POST", path, nil))
+		require.NoError(t, err)
+		assert.Equal(t, "2", resp.Header.Get("RateLimit-Limit"), path)
+	}
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/Api/V1/Login/", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusTooManyRequests, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/API/V1/Addresses/", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, "100", resp.Header.Get("RateLimit-Limit"))
+}
+
+func TestRateLimitSkipsUnmatchedRoutes(t *testing.T) {
+	repo := &memoryRateLimitRepository{counters: map[string]*entity.RateLimitCounter{}}
+	app := setupRateLimitApp(repo)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/healthz", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get("RateLimit-Limit"))
+	assert.Empty(t, repo.counters)
+}
+
+func TestRateLimitFailsOpen(t *testing.T) {
+	repo := &memoryRateLimitRepository{err: errors.New("connection refused")}
+	app := setupRateLimitApp(repo)
+
+	for i := 0; i < 3; i++ {
+		resp, err := app.Test(httptest.NewRequest("POST", "/api/v1/login", nil))
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	}
+}
+
+func TestRateLimitKeysBearerTokensByUser(t *testing.T) {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	users := &staticUserRepository{user: &entity.User{ID: 7, Email: "bendahara@example.com"}}
+	plain := pkg.GenerateToken()
+	tokens := &staticTokenRepository{token: &entity.PersonalAccessToken{
+		ID:        3,
+		UserID:    7,
+		TokenHash: pkg.HashToken(plain),
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+	}}
+	sessionHandler := pkg.NewSessionHandler(session.New(), log)
+	tokenUseCase := usecase.NewTokenUseCase(nil, log, nil, users, tokens, nil, time.Hour, time.Hour)
+	authMiddleware := middleware.NewAuthMiddleware(sessionHandler, &usecase.AuthUseCase{Log: log, UserRepository: users}, tokenUseCase, log)
+
+	repo := &memoryRateLimitRepository{counters: map[string]*entity.RateLimitCounter{}}
+	rules := []middleware.RateLimitRule{
+		{Name: "api", Path: "/api/v1/*", Limit: 100, Window: time.Minute, Key: middleware.RateLimitKeyUser},
+	}
+	rateLimitMiddleware := middleware.NewRateLimitMiddleware(usecase.NewRateLimitUseCase(log, repo), sessionHandler, rules, nil, log)
+
+	app := fiber.New()
+	app.Use(authMiddleware.ResolveToken(), rateLimitMiddleware.Limit())
+	app.Get("/api/v1/me", authMiddleware.RequiredAuth(), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	assert.Equal(t, fiber.StatusOK, bearerRequest(t, app, "GET", "/api/v1/me", plain))
+	assert.Contains(t, repo.counters, "api:user=7")
+
+	// a bad token is still counted, by IP, before it is rejected
+	assert.Equal(t, fiber.StatusUnauthorized, bearerRequest(t, app, "GET", "/api/v1/me", pkg.GenerateToken()))
+	assert.Len(t, repo.counters, 2)
+}
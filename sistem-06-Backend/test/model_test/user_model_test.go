@@ -7,14 +7,15 @@ import (
 	"github.com/go-playground/validator/v10"
 	"github.com/stretchr/testify/assert"
 
-	"backend-sistem06.com/internal/model"
+	"sistem-06-Backend/internal/dto"
+	"sistem-06-Backend/internal/pkg/password"
 )
 
 func TestRegisterUserRequest_Validation(t *testing.T) {
 	validate := validator.New()
 
 	t.Run("should pass validation with valid data", func(t *testing.T) {
-		request := &model.RegisterUserRequest{
+		request := &dto.RegisterUserRequest{
 			Name:     "John Doe",
 			Email:    "john@example.com",
 			Password: "password123",
@@ -25,7 +26,7 @@ func TestRegisterUserRequest_Validation(t *testing.T) {
 	})
 
 	t.Run("should fail when name is empty", func(t *testing.T) {
-		request := &model.RegisterUserRequest{
+		request := &dto.RegisterUserRequest{
 			Name:     "",
 			Email:    "john@example.com",
 			Password: "password123",
@@ -41,7 +42,7 @@ func TestRegisterUserRequest_Validation(t *testing.T) {
 
 	t.Run("should fail when name exceeds max length", func(t *testing.T) {
 		longName := string(make([]byte, 101)) // 101 characters
-		request := &model.RegisterUserRequest{
+		request := &dto.RegisterUserRequest{
 			Name:     longName,
 			Email:    "john@example.com",
 			Password: "password123",
@@ -57,7 +58,7 @@ func TestRegisterUserRequest_Validation(t *testing.T) {
 
 	t.Run("should pass when name is exactly 100 characters", func(t *testing.T) {
 		exactName := strings.Repeat("a", 100)
-		request := &model.RegisterUserRequest{
+		request := &dto.RegisterUserRequest{
 			Name:     exactName,
 			Email:    "john@example.com",
 			Password: "password123",
@@ -68,7 +69,7 @@ func TestRegisterUserRequest_Validation(t *testing.T) {
 	})
 
 	t.Run("should fail when email is empty", func(t *testing.T) {
-		request := &model.RegisterUserRequest{
+		request := &dto.RegisterUserRequest{
 			Name:     "John Doe",
 			Email:    "",
 			Password: "password123",
@@ -92,7 +93,7 @@ func TestRegisterUserRequest_Validation(t *testing.T) {
 		}
 
 		for _, invalidEmail := range invalidEmails {
-			request := &model.RegisterUserRequest{
+			request := &dto.RegisterUserRequest{
 				Name:     "John Doe",
 				Email:    invalidEmail,
 				Password: "password123",
@@ -112,7 +113,7 @@ func TestRegisterUserRequest_Validation(t *testing.T) {
 		}
 
 		for _, validEmail := range validEmails {
-			request := &model.RegisterUserRequest{
+			request := &dto.RegisterUserRequest{
 				Name:     "John Doe",
 				Email:    validEmail,
 				Password: "password123",
@@ -124,7 +125,7 @@ func TestRegisterUserRequest_Validation(t *testing.T) {
 	})
 
 	t.Run("should fail when password is empty", func(t *testing.T) {
-		request := &model.RegisterUserRequest{
+		request := &dto.RegisterUserRequest{
 			Name:     "John Doe",
 			Email:    "john@example.com",
 			Password: "",
@@ -138,23 +139,22 @@ func TestRegisterUserRequest_Validation(t *testing.T) {
 		assert.Equal(t, "required", validationErrors[0].Tag())
 	})
 
-	t.Run("should fail when password is too short", func(t *testing.T) {
-		request := &model.RegisterUserRequest{
+	t.Run("should leave password length to the password policy", func(t *testing.T) {
+		request := &dto.RegisterUserRequest{
 			Name:     "John Doe",
 			Email:    "john@example.com",
 			Password: "pass", // Less than 8 characters
 		}
 
 		err := validate.Struct(request)
-		assert.Error(t, err)
+		assert.NoError(t, err)
 
-		validationErrors := err.(validator.ValidationErrors)
-		assert.Equal(t, "Password", validationErrors[0].Field())
-		assert.Equal(t, "min", validationErrors[0].Tag())
+		policy := &password.Policy{MinLength: 8, MaxLength: 72}
+		assert.Equal(t, []string{"must be at least 8 characters"}, policy.Check(request.Password))
 	})
 
 	t.Run("should pass when password is exactly 8 characters", func(t *testing.T) {
-		request := &model.RegisterUserRequest{
+		request := &dto.RegisterUserRequest{
 			Name:     "John Doe",
 			Email:    "john@example.com",
 			Password: "password", // Exactly 8 characters
@@ -165,7 +165,7 @@ func TestRegisterUserRequest_Validation(t *testing.T) {
 	})
 
 	t.Run("should fail with multiple validation errors", func(t *testing.T) {
-		request := &model.RegisterUserRequest{
+		request := &dto.RegisterUserRequest{
 			Name:     "",
 			Email:    "invalid",
 			Password: "short",
@@ -183,7 +183,7 @@ func TestLoginUserRequest_Validation(t *testing.T) {
 	validate := validator.New()
 
 	t.Run("should pass validation with valid data", func(t *testing.T) {
-		request := &model.LoginUserRequest{
+		request := &dto.UserLoginRequest{
 			Email:    "john@example.com",
 			Password: "password123",
 		}
@@ -193,7 +193,7 @@ func TestLoginUserRequest_Validation(t *testing.T) {
 	})
 
 	t.Run("should fail when email is empty", func(t *testing.T) {
-		request := &model.LoginUserRequest{
+		request := &dto.UserLoginRequest{
 			Email:    "",
 			Password: "password123",
 		}
@@ -203,7 +203,7 @@ func TestLoginUserRequest_Validation(t *testing.T) {
 	})
 
 	t.Run("should fail when email format is invalid", func(t *testing.T) {
-		request := &model.LoginUserRequest{
+		request := &dto.UserLoginRequest{
 			Email:    "invalid-email",
 			Password: "password123",
 		}
@@ -213,7 +213,7 @@ func TestLoginUserRequest_Validation(t *testing.T) {
 	})
 
 	t.Run("should fail when password is too short", func(t *testing.T) {
-		request := &model.LoginUserRequest{
+		request := &dto.UserLoginRequest{
 			Email:    "john@example.com",
 			Password: "short",
 		}
@@ -223,8 +223,8 @@ func TestLoginUserRequest_Validation(t *testing.T) {
 	})
 
 	t.Run("should fail when password exceeds max length", func(t *testing.T) {
-		longPassword := string(make([]byte, 101))
-		request := &model.LoginUserRequest{
+		longPassword := string(make([]byte, password.MaxLength+1))
+		request := &dto.UserLoginRequest{
 			Email:    "john@example.com",
 			Password: longPassword,
 		}
@@ -235,7 +235,7 @@ func TestLoginUserRequest_Validation(t *testing.T) {
 
 	t.Run("should pass when password is exactly 100 characters", func(t *testing.T) {
 		exactPassword := string(make([]byte, 100))
-		request := &model.LoginUserRequest{
+		request := &dto.UserLoginRequest{
 			Email:    "john@example.com",
 			Password: exactPassword,
 		}
@@ -249,7 +249,7 @@ func TestVerifyUserRequest_Validation(t *testing.T) {
 	validate := validator.New()
 
 	t.Run("should pass validation with valid token", func(t *testing.T) {
-		request := &model.VerifyUserRequest{
+		request := &dto.VerifyUserRequest{
 			Token: "valid_token_here",
 		}
 
@@ -258,7 +258,7 @@ func TestVerifyUserRequest_Validation(t *testing.T) {
 	})
 
 	t.Run("should fail when token is empty", func(t *testing.T) {
-		request := &model.VerifyUserRequest{
+		request := &dto.VerifyUserRequest{
 			Token: "",
 		}
 
@@ -272,7 +272,7 @@ func TestVerifyUserRequest_Validation(t *testing.T) {
 
 	t.Run("should fail when token exceeds max length", func(t *testing.T) {
 		longToken := string(make([]byte, 101))
-		request := &model.VerifyUserRequest{
+		request := &dto.VerifyUserRequest{
 			Token: longToken,
 		}
 
@@ -286,7 +286,7 @@ func TestVerifyUserRequest_Validation(t *testing.T) {
 
 	t.Run("should pass when token is exactly 100 characters", func(t *testing.T) {
 		exactToken := string(make([]byte, 100))
-		request := &model.VerifyUserRequest{
+		request := &dto.VerifyUserRequest{
 			Token: exactToken,
 		}
 
@@ -298,7 +298,7 @@ func TestVerifyUserRequest_Validation(t *testing.T) {
 // Test JSON serialization
 func TestUserResponse_JSONTags(t *testing.T) {
 	t.Run("should omit empty fields in JSON", func(t *testing.T) {
-		response := &model.UserResponse{
+		response := &dto.UserResponse{
 			ID:   0,
 			Name: "",
 		}
@@ -312,7 +312,7 @@ func TestUserResponse_JSONTags(t *testing.T) {
 	})
 
 	t.Run("should include non-empty fields", func(t *testing.T) {
-		response := &model.UserResponse{
+		response := &dto.UserResponse{
 			ID:        1,
 			Name:      "John Doe",
 			CreatedAt: 1234567890,
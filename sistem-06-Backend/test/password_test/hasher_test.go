@@ -0,0 +1,84 @@
+package password_test
+
+import (
+	"strings"
+	"testing"
+
+	"sistem-06-Backend/internal/pkg/password"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// small parameters keep the tests fast
+func testArgon2id() *password.Argon2id {
+	return &password.Argon2id{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+}
+
+func TestArgon2idHash(t *testing.T) {
+	hasher := testArgon2id()
+
+	encoded, err := hasher.Hash("rahasia-sekali")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(encoded, "$argon2id$v=19$m=64,t=1,p=1$"))
+	assert.True(t, hasher.Identify(encoded))
+	assert.False(t, hasher.NeedsRehash(encoded))
+
+	ok, err := hasher.Verify("rahasia-sekali", encoded)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = hasher.Verify("salah", encoded)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	other, err := hasher.Hash("rahasia-sekali")
+	require.NoError(t, err)
+	assert.NotEqual(t, encoded, other, "every hash gets its own salt")
+
+	stronger := testArgon2id()
+	stronger.Iterations = 2
+	assert.True(t, stronger.NeedsRehash(encoded))
+	ok, err = stronger.Verify("rahasia-sekali", encoded)
+	require.NoError(t, err)
+	assert.True(t, ok, "stored parameters are used for verifying")
+
+	_, err = hasher.Verify("rahasia-sekali", "$argon2id$v=19$m=64$broken")
+	assert.Error(t, err)
+}
+
+func TestBcryptNeedsRehashOnCostChange(t *testing.T) {
+	hasher := &password.Bcrypt{Cost: bcrypt.MinCost}
+
+	encoded, err := hasher.Hash("rahasia-sekali")
+	require.NoError(t, err)
+	assert.True(t, hasher.Identify(encoded))
+	assert.False(t, hasher.NeedsRehash(encoded))
+	assert.True(t, (&password.Bcrypt{Cost: bcrypt.MinCost + 1}).NeedsRehash(encoded))
+
+	ok, err := hasher.Verify("salah", encoded)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestHasherVerifiesLegacyAlgorithm(t *testing.T) {
+	bcryptHasher := &password.Bcrypt{Cost: bcrypt.MinCost}
+	legacy, err := bcryptHasher.Hash("rahasia-sekali")
+	require.NoError(t, err)
+
+	hasher := password.NewHasher(testArgon2id(), bcryptHasher)
+
+	ok, err := hasher.Verify("rahasia-sekali", legacy)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, hasher.NeedsRehash(legacy))
+
+	encoded, err := hasher.Hash("rahasia-sekali")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(encoded, "$argon2id$"))
+	assert.False(t, hasher.NeedsRehash(encoded))
+
+	_, err = hasher.Verify("rahasia-sekali", "plain-text")
+	assert.ErrorIs(t, err, password.ErrUnknownHash)
+}
@@ -0,0 +1,91 @@
+package password_test
+
+import (
+	"strings"
+	"testing"
+
+	"sistem-06-Backend/internal/pkg/password"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func bundledPolicy(t *testing.T) *password.Policy {
+	list, err := password.LoadBreachedListFile("")
+	require.NoError(t, err)
+	return &password.Policy{
+		MinLength:           8,
+		MaxLength:           72,
+		MinCharacterClasses: 2,
+		RejectPersonalInfo:  true,
+		Breached:            list,
+	}
+}
+
+func TestBundledBreachedList(t *testing.T) {
+	list, err := password.LoadBreachedListFile("")
+	require.NoError(t, err)
+
+	assert.Greater(t, list.Len(), 100)
+	assert.True(t, list.Contains("password123"))
+	assert.True(t, list.Contains("bismillah"))
+	assert.False(t, list.Contains("kopi-tubruk-Senja-42"))
+}
+
+func TestLoadBreachedListAcceptsCounts(t *testing.T) {
+	// SHA-1 of "hunter2" with a count as in the HIBP downloads, then a bare hash
+	list, err := password.LoadBreachedList(strings.NewReader("# comment\nf3bbbd66a63d4bf1747940578ec3d0103530e21d:17\n\nB3C6E5EF8A7A0C8A3F4B9C3D1E0F2A1B4C5D6E7F\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, list.Len())
+	assert.True(t, list.Contains("hunter2"))
+
+	_, err = password.LoadBreachedList(strings.NewReader("not-a-hash\n"))
+	assert.Error(t, err)
+}
+
+func TestPolicyCheck(t *testing.T) {
+	policy := bundledPolicy(t)
+
+	tests := []struct {
+		name     string
+		password string
+		personal []string
+		want     string
+	}{
+		{"too short", "Ab1!", nil, "at least 8 characters"},
+		{"single class", "kopitubruksenja", nil, "at least 2 of"},
+		{"contains email", "Budi.Santoso2024", []string{"budi.santoso@example.com"}, "email or name"},
+		{"contains name word", "xx-Santoso-99", []string{"x@example.com", "Budi Santoso"}, "email or name"},
+		{"breached", "Password123", nil, "data breach"},
+		{"too long", strings.Repeat("a1", 40), nil, "at most 72"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := policy.Check(tt.password, tt.personal...)
+			require.NotEmpty(t, violations)
+			assert.Contains(t, strings.Join(violations, "|"), tt.want)
+		})
+	}
+
+	assert.Empty(t, policy.Check("kopi-tubruk-Senja-42", "budi@example.com", "Budi Santoso"))
+}
+
+func TestPolicyMaxBytes(t *testing.T) {
+	policy := &password.Policy{MinLength: 8, MaxLength: 72, MaxBytes: password.BcryptMaxBytes}
+
+	// 40 characters but 80 bytes, more than bcrypt accepts
+	long := strings.Repeat("é", 40)
+	assert.Equal(t, []string{"must be at most 72 bytes"}, policy.Check(long))
+	assert.Empty(t, policy.Check(strings.Repeat("é", 36)))
+
+	_, err := (&password.Bcrypt{Cost: 4}).Hash(long)
+	assert.Error(t, err, "the policy has to catch this before the hasher")
+}
+
+func TestPolicyRequiredClasses(t *testing.T) {
+	policy := &password.Policy{MinLength: 1, RequireUppercase: true, RequireDigit: true, RequireSymbol: true}
+
+	violations := policy.Check("lowercase")
+	assert.Equal(t, []string{"must contain an uppercase letter", "must contain a digit", "must contain a symbol"}, violations)
+	assert.Empty(t, policy.Check("Lower-case1"))
+}
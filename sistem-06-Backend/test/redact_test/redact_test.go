@@ -0,0 +1,68 @@
+package redact_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"sistem-06-Backend/internal/pkg/redact"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var defaultFields = []string{"email", "password", "nik", "phone"}
+
+func TestRedactsKeyedValuesAndKnownFormats(t *testing.T) {
+	redactor, err := redact.New(defaultFields, nil)
+	require.NoError(t, err)
+
+	tests := map[string]string{
+		"Setting session for user 7 with email budi@example.com": "Setting session for user 7 with email [REDACTED]",
+		"request &{Name:budi Email:x Password:hunter2}":          "request &{Name:budi Email:[REDACTED] Password:[REDACTED]}",
+		`{"new_password":"hunter2","name":"budi"}`:               `{"new_password":"[REDACTED]","name":"budi"}`,
+		"warga 3201234567890123 di 081234567890":                 "warga [REDACTED] di [REDACTED]",
+		"call +6281234567890 or nik=3201":                        "call [REDACTED] or nik=[REDACTED]",
+		"user 7 updated address 42":                              "user 7 updated address 42",
+	}
+	for input, expected := range tests {
+		assert.Equal(t, expected, redactor.String(input), input)
+	}
+}
+
+func TestExtraPatterns(t *testing.T) {
+	redactor, err := redact.New(nil, []string{`tok_[a-z0-9]+`})
+	require.NoError(t, err)
+	assert.Equal(t, "bearer [REDACTED], budi@example.com", redactor.String("bearer tok_abc123, budi@example.com"))
+
+	_, err = redact.New(nil, []string{"("})
+	assert.Error(t, err)
+}
+
+func TestHookRedactsMessageAndFields(t *testing.T) {
+	redactor, err := redact.New(defaultFields, nil)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	log := logrus.New()
+	log.SetOutput(&out)
+	log.SetFormatter(&logrus.JSONFormatter{})
+	log.AddHook(redact.NewHook(redactor))
+
+	log.WithFields(logrus.Fields{
+		"user_id":      7,
+		"new_password": "hunter2",
+		"target":       "siti@example.com",
+		"error":        errors.New("duplicate key for budi@example.com"),
+	}).Infof("Notifying %s", "budi@example.com")
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &entry))
+	assert.Equal(t, "Notifying [REDACTED]", entry["msg"])
+	assert.Equal(t, float64(7), entry["user_id"])
+	assert.Equal(t, "[REDACTED]", entry["new_password"])
+	assert.Equal(t, "[REDACTED]", entry["target"])
+	assert.Equal(t, "duplicate key for [REDACTED]", entry["error"])
+}
@@ -0,0 +1,48 @@
+package repository_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"regexp"
+	"testing"
+
+	"sistem-06-Backend/internal/domain/entity"
+	"sistem-06-Backend/internal/infrastructure/database/repository"
+	"sistem-06-Backend/internal/infrastructure/database/sqlc"
+	"sistem-06-Backend/internal/pkg/fieldcrypt"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmailChangeRepositoryEncryptsNewEmail(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	cipher, err := fieldcrypt.New("k1", map[string][]byte{"k1": bytes.Repeat([]byte{1}, 32)}, bytes.Repeat([]byte{9}, 32))
+	require.NoError(t, err)
+	changes := repository.NewEmailChangeRepository(sqlc.New(db), log, cipher)
+
+	newEmail := &captured{}
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO email_changes")).
+		WithArgs(int32(7), newEmail, "token-hash", int64(2), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, changes.Save(context.Background(), &entity.EmailChange{
+		UserID: 7, NewEmail: "budi@example.com", TokenHash: "token-hash", ExpiresAt: 2, CreatedAt: 1,
+	}))
+	assert.NotContains(t, newEmail.value, "budi")
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM email_changes")).
+		WithArgs("token-hash").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "new_email", "token_hash", "expires_at", "created_at"}).
+			AddRow(7, newEmail.value, "token-hash", 2, 1))
+	change, err := changes.FindByTokenHash(context.Background(), "token-hash")
+	require.NoError(t, err)
+	assert.Equal(t, "budi@example.com", change.NewEmail)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,53 @@
+package repository_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"regexp"
+	"testing"
+
+	"sistem-06-Backend/internal/domain/entity"
+	"sistem-06-Backend/internal/infrastructure/database/repository"
+	"sistem-06-Backend/internal/infrastructure/database/sqlc"
+	"sistem-06-Backend/internal/pkg/fieldcrypt"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchTermsOnlyKeepsWords(t *testing.T) {
+	assert.Equal(t, "budi:* | gg:* | melati:* | rt:* | 03:*", repository.SearchTerms("Budi, Gg. Melati RT 03"))
+	assert.Equal(t, "a:* | b:*", repository.SearchTerms("a' & !b:* | ("))
+	assert.Equal(t, "", repository.SearchTerms("&|!"))
+}
+
+func TestSearchUsersMatchesEmailsOnlyAsAWhole(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	cipher, err := fieldcrypt.New("k1", map[string][]byte{"k1": bytes.Repeat([]byte{1}, 32)}, bytes.Repeat([]byte{9}, 32))
+	require.NoError(t, err)
+	search := repository.NewSearchRepository(sqlc.New(db), log, cipher)
+
+	// the whole address is looked up by its blind index
+	mock.ExpectQuery(regexp.QuoteMeta("FROM users")).
+		WithArgs(sqlmock.AnyArg(), " budi@example.com ", cipher.BlindIndex("users.email", "budi@example.com"), sqlmock.AnyArg(), int32(20)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "created_at", "updated_at", "version", "rank"}))
+	_, err = search.Users(context.Background(), entity.SearchQuery{Text: " budi@example.com ", Limit: 20})
+	require.NoError(t, err)
+
+	// a part of it only has an index of its own, which no stored email has
+	mock.ExpectQuery(regexp.QuoteMeta("FROM users")).
+		WithArgs(sqlmock.AnyArg(), "budi@", cipher.BlindIndex("users.email", "budi@"), sqlmock.AnyArg(), int32(20)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "created_at", "updated_at", "version", "rank"}))
+	_, err = search.Users(context.Background(), entity.SearchQuery{Text: "budi@", Limit: 20})
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
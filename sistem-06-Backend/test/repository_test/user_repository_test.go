@@ -0,0 +1,128 @@
+package repository_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"io"
+	"regexp"
+	"testing"
+
+	"sistem-06-Backend/internal/domain/entity"
+	"sistem-06-Backend/internal/infrastructure/database/repository"
+	"sistem-06-Backend/internal/infrastructure/database/sqlc"
+	"sistem-06-Backend/internal/pkg/fieldcrypt"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captured matches any string argument and remembers it.
+type captured struct {
+	value string
+}
+
+func (c *captured) Match(v driver.Value) bool {
+	c.value, _ = v.(string)
+	return fieldcrypt.Encrypted(c.value)
+}
+
+func newEncryptedUserRepository(t *testing.T) (*repository.UserRepositoryImpl, *fieldcrypt.Cipher, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	cipher, err := fieldcrypt.New("k1", map[string][]byte{"k1": bytes.Repeat([]byte{1}, 32)}, bytes.Repeat([]byte{9}, 32))
+	require.NoError(t, err)
+	return repository.NewUserRepository(sqlc.New(db), log, cipher), cipher, mock
+}
+
+var userColumns = []string{"id", "name", "email", "password", "created_at", "updated_at", "sessions_revoked_at", "deleted_at", "version"}
+
+func TestUserRepositoryEncryptsEmails(t *testing.T) {
+	users, cipher, mock := newEncryptedUserRepository(t)
+	hash := cipher.BlindIndex("users.email", "budi@example.com")
+
+	email := &captured{}
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO users")).
+		WithArgs("budi", email, sql.NullString{String: hash, Valid: true}, "hashed", int64(1), int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+
+	user := &entity.User{Name: "budi", Email: "budi@example.com", Password: "hashed", CreatedAt: 1, UpdatedAt: 1}
+	require.NoError(t, users.CreateUser(context.Background(), user))
+	assert.Equal(t, 7, user.ID)
+	assert.NotContains(t, email.value, "budi")
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM users")).
+		WithArgs(hash, "budi@example.com").
+		WillReturnRows(sqlmock.NewRows(userColumns).AddRow(7, "budi", email.value, "hashed", 1, 1, 0, 0, 1))
+
+	found, err := users.FindByEmail(context.Background(), "budi@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "budi@example.com", found.Email)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepositoryRotatesEmails(t *testing.T) {
+	users, cipher, mock := newEncryptedUserRepository(t)
+	hash := sql.NullString{String: cipher.BlindIndex("users.email", "budi@example.com"), Valid: true}
+	current, err := cipher.Encrypt("users.email", "siti@example.com")
+	require.NoError(t, err)
+	currentHash := sql.NullString{String: cipher.BlindIndex("users.email", "siti@example.com"), Valid: true}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, email, email_hash")).
+		WithArgs(int32(0), int32(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "email_hash"}).
+			AddRow(1, "budi@example.com", nil).
+			AddRow(2, current, currentHash))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE users")).
+		WithArgs(&captured{}, hash, int32(1), "budi@example.com").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, email, email_hash")).
+		WithArgs(int32(2), int32(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "email_hash"}))
+
+	rotated, err := users.RotateEmails(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, 1, rotated, "the current row is left alone")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepositoryRotateWithoutKeysFillsHashes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	users := repository.NewUserRepository(sqlc.New(db), log, nil)
+
+	// the same hash the encrypt_user_email migration computes in SQL
+	sum := sha256.Sum256([]byte("users.email\x00siti@example.com"))
+	migrated := sql.NullString{String: hex.EncodeToString(sum[:]), Valid: true}
+	hash := sql.NullString{String: (*fieldcrypt.Cipher)(nil).BlindIndex("users.email", "budi@example.com"), Valid: true}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, email, email_hash")).
+		WithArgs(int32(0), int32(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "email_hash"}).
+			AddRow(1, "budi@example.com", nil).
+			AddRow(2, "siti@example.com", migrated))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE users")).
+		WithArgs("budi@example.com", hash, int32(1), "budi@example.com").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, email, email_hash")).
+		WithArgs(int32(2), int32(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "email_hash"}))
+
+	rotated, err := users.RotateEmails(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, 1, rotated, "the migrated hash is already right")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
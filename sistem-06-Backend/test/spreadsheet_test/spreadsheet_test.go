@@ -0,0 +1,146 @@
+package spreadsheet_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"sistem-06-Backend/internal/pkg/spreadsheet"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildXLSX zips the parts of a minimal workbook with a single sheet.
+func buildXLSX(t *testing.T, sharedStrings string, sheet string) []byte {
+	parts := map[string]string{
+		"xl/workbook.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Alamat" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/sharedStrings" Target="sharedStrings.xml"/>
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+</Relationships>`,
+		"xl/sharedStrings.xml":     sharedStrings,
+		"xl/worksheets/sheet1.xml": sheet,
+	}
+
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+	for name, content := range parts {
+		writer, err := archive.Create(name)
+		require.NoError(t, err)
+		_, err = writer.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, archive.Close())
+	return buffer.Bytes()
+}
+
+func TestReadXLSX(t *testing.T) {
+	content := buildXLSX(t,
+		`<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<si><t>jalan</t></si><si><t>rt</t></si><si><r><t>Gg. </t></r><r><t>Melati</t></r></si></sst>`,
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c></row>
+<row r="2"></row>
+<row r="3"><c r="A3" t="s"><v>2</v></c><c r="C3"><v>42</v></c></row>
+<row r="4"><c r="B4" t="inlineStr"><is><t>003</t></is></c></row>
+</sheetData></worksheet>`)
+
+	rows, err := spreadsheet.Read(spreadsheet.XLSX, bytes.NewReader(content), int64(len(content)))
+	require.NoError(t, err)
+	require.Len(t, rows, 3, "the empty row is skipped")
+	assert.Equal(t, spreadsheet.Row{Number: 1, Cells: []string{"jalan", "rt"}}, rows[0])
+	assert.Equal(t, spreadsheet.Row{Number: 3, Cells: []string{"Gg. Melati", "", "42"}}, rows[1])
+	assert.Equal(t, spreadsheet.Row{Number: 4, Cells: []string{"", "003"}}, rows[2])
+}
+
+func TestReadXLSXRejectsBadSharedString(t *testing.T) {
+	content := buildXLSX(t,
+		`<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"></sst>`,
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+<row r="1"><c r="A1" t="s"><v>5</v></c></row></sheetData></worksheet>`)
+
+	_, err := spreadsheet.ReadXLSX(bytes.NewReader(content), int64(len(content)))
+	assert.Error(t, err)
+
+	_, err = spreadsheet.ReadXLSX(strings.NewReader("jalan,rt"), 8)
+	assert.Error(t, err)
+}
+
+func TestReadCSV(t *testing.T) {
+	rows, err := spreadsheet.ReadCSV(strings.NewReader("\xEF\xBB\xBFjalan;rt;kota\n\"Jl. Mawar; No. 1\";003;Bandung\n\n;;\nGg. Melati;004\n"))
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, []string{"jalan", "rt", "kota"}, rows[0].Cells, "the byte order mark is dropped")
+	assert.Equal(t, spreadsheet.Row{Number: 2, Cells: []string{"Jl. Mawar; No. 1", "003", "Bandung"}}, rows[1])
+	assert.Equal(t, spreadsheet.Row{Number: 5, Cells: []string{"Gg. Melati", "004"}}, rows[2])
+
+	rows, err = spreadsheet.ReadCSV(strings.NewReader("name,email\nbudi,budi@example.com"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"budi", "budi@example.com"}, rows[1].Cells)
+}
+
+func TestFormatOf(t *testing.T) {
+	format, err := spreadsheet.FormatOf("Alamat.XLSX")
+	require.NoError(t, err)
+	assert.Equal(t, spreadsheet.XLSX, format)
+
+	_, err = spreadsheet.FormatOf("alamat.xls")
+	assert.ErrorIs(t, err, spreadsheet.ErrUnsupportedFormat)
+}
+
+func TestCSVWriterEscapesFormulas(t *testing.T) {
+	var buffer bytes.Buffer
+	writer, err := spreadsheet.NewWriter(spreadsheet.CSV, &buffer, "Users")
+	require.NoError(t, err)
+	require.NoError(t, writer.Write([]string{"name", "email"}))
+	require.NoError(t, writer.Write([]string{"=HYPERLINK(\"http://x\")", "budi@example.com"}))
+	require.NoError(t, writer.Close())
+
+	assert.Equal(t, "\xEF\xBB\xBFname,email\n\"'=HYPERLINK(\"\"http://x\"\")\",budi@example.com\n", buffer.String())
+}
+
+func TestXLSXWriterRoundTrip(t *testing.T) {
+	var buffer bytes.Buffer
+	writer, err := spreadsheet.NewWriter(spreadsheet.XLSX, &buffer, "Alamat: RW 05")
+	require.NoError(t, err)
+	require.NoError(t, writer.Write([]string{"jalan", "rt"}))
+	require.NoError(t, writer.Write([]string{" Gg. Melati <1> & 2", "003"}))
+	require.NoError(t, writer.Close())
+
+	rows, err := spreadsheet.ReadXLSX(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	require.NoError(t, err)
+	assert.Equal(t, []spreadsheet.Row{
+		{Number: 1, Cells: []string{"jalan", "rt"}},
+		{Number: 2, Cells: []string{" Gg. Melati <1> & 2", "003"}},
+	}, rows)
+}
+
+func TestPDFWriterPaginates(t *testing.T) {
+	var buffer bytes.Buffer
+	writer, err := spreadsheet.NewWriter(spreadsheet.PDF, &buffer, "Warga RW 05")
+	require.NoError(t, err)
+	require.NoError(t, writer.Write([]string{"Name", "Email"}))
+	for range 60 {
+		require.NoError(t, writer.Write([]string{"Siti (Bu RT) Rahayu — Café", strings.Repeat("x", 200)}))
+	}
+	require.NoError(t, writer.Close())
+
+	pdf := buffer.String()
+	assert.True(t, strings.HasPrefix(pdf, "%PDF-1.4\n"))
+	assert.True(t, strings.HasSuffix(pdf, "%%EOF\n"))
+	assert.Contains(t, pdf, "/Count 2 >>")
+	assert.Contains(t, pdf, `(Siti \(Bu RT\) Rahayu `+"\x97 Caf\xe9) Tj")
+	assert.Contains(t, pdf, "\x85) Tj", "long cells are cut with an ellipsis")
+
+	// startxref has to point at the cross-reference table
+	var xref int
+	_, err = fmt.Sscanf(pdf[strings.LastIndex(pdf, "startxref\n"):], "startxref\n%d", &xref)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(pdf[xref:], "xref\n"))
+}
@@ -0,0 +1,81 @@
+package totp_test
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	"sistem-06-Backend/internal/pkg/totp"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RFC 6238 appendix B secret, truncated to 6 digits
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestCodeMatchesRFCVectors(t *testing.T) {
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+	for unix, want := range vectors {
+		code, err := totp.Code(rfcSecret, totp.Step(time.Unix(unix, 0)))
+		require.NoError(t, err)
+		assert.Equal(t, want, code, "time %d", unix)
+	}
+}
+
+func TestValidateAllowsSkew(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	previous, err := totp.Code(rfcSecret, totp.Step(now)-1)
+	require.NoError(t, err)
+
+	step, ok := totp.Validate(rfcSecret, previous, now, 1)
+	assert.True(t, ok)
+	assert.Equal(t, totp.Step(now)-1, step)
+
+	_, ok = totp.Validate(rfcSecret, previous, now, 0)
+	assert.False(t, ok)
+
+	_, ok = totp.Validate(rfcSecret, "12345a", now, 1)
+	assert.False(t, ok)
+}
+
+func TestGenerateSecretRoundTrips(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	code, err := totp.Code(secret, totp.Step(time.Now()))
+	require.NoError(t, err)
+	_, ok := totp.Validate(secret, code, time.Now(), 1)
+	assert.True(t, ok)
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri, err := url.Parse(totp.ProvisioningURI("Sistem06", "budi@example.com", "JBSWY3DPEHPK3PXP"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/Sistem06:budi@example.com", uri.Path)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", uri.Query().Get("secret"))
+	assert.Equal(t, "Sistem06", uri.Query().Get("issuer"))
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := totp.GenerateRecoveryCodes(10)
+	require.NoError(t, err)
+	require.Len(t, codes, 10)
+	assert.Regexp(t, `^[A-Z2-7]{4}-[A-Z2-7]{4}$`, codes[0])
+	assert.NotEqual(t, codes[0], codes[1])
+
+	assert.Equal(t, totp.HashRecoveryCode(codes[0]), totp.HashRecoveryCode(" "+codes[0][:4]+codes[0][5:]+" "))
+	assert.NotEqual(t, totp.HashRecoveryCode(codes[0]), totp.HashRecoveryCode(codes[1]))
+}
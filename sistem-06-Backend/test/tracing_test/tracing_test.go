@@ -0,0 +1,161 @@
+package tracing_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	controller "sistem-06-Backend/internal/delivery/http"
+	"sistem-06-Backend/internal/delivery/http/middleware"
+	"sistem-06-Backend/internal/infrastructure/tracing"
+	"sistem-06-Backend/internal/usecase"
+	"sistem-06-Backend/pkg"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/storage/memory"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	exporter     = tracetest.NewInMemoryExporter()
+	providerOnce sync.Once
+)
+
+// setupExporter installs one global provider for the whole package, since
+// the otel global delegate can only be replaced once.
+func setupExporter(t *testing.T) *tracetest.InMemoryExporter {
+	providerOnce.Do(func() {
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+			propagation.TraceContext{},
+			propagation.Baggage{},
+		))
+	})
+	exporter.Reset()
+	return exporter
+}
+
+type healthyRepository struct{}
+
+func (healthyRepository) Ping(ctx context.Context) error { return nil }
+
+func (healthyRepository) MigrationVersion(ctx context.Context) (int64, bool, error) {
+	return 1, false, nil
+}
+
+func setupApp() *fiber.App {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	healthUseCase := usecase.NewHealthUseCase(&sql.DB{}, log, memory.New(), healthyRepository{}, 1, func() bool { return true })
+	healthController := controller.NewHealthController(healthUseCase, log)
+
+	app := fiber.New()
+	app.Use(middleware.NewTracingMiddleware().Trace())
+	app.Get("/readyz", healthController.Readiness)
+	return app
+}
+
+func findSpan(t *testing.T, spans tracetest.SpanStubs, name string) tracetest.SpanStub {
+	for _, span := range spans {
+		if span.Name == name {
+			return span
+		}
+	}
+	t.Fatalf("span %q not recorded", name)
+	return tracetest.SpanStub{}
+}
+
+func TestTraceNestsUseCaseUnderServerSpan(t *testing.T) {
+	exporter := setupExporter(t)
+	app := setupApp()
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/readyz", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	spans := exporter.GetSpans()
+	server := findSpan(t, spans, "GET /readyz")
+	readiness := findSpan(t, spans, "HealthUseCase.Readiness")
+
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind)
+	assert.False(t, server.Parent.IsValid())
+	assert.Equal(t, server.SpanContext.SpanID(), readiness.Parent.SpanID())
+	assert.Equal(t, server.SpanContext.TraceID(), readiness.SpanContext.TraceID())
+	assert.NotEmpty(t, resp.Header.Get("traceparent"))
+}
+
+func TestTraceContinuesIncomingTraceparent(t *testing.T) {
+	exporter := setupExporter(t)
+	app := setupApp()
+
+	req := httptest.NewRequest("GET", "/readyz", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	_, err := app.Test(req)
+	require.NoError(t, err)
+
+	server := findSpan(t, exporter.GetSpans(), "GET /readyz")
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent.SpanID().String())
+	assert.True(t, server.Parent.IsRemote())
+}
+
+func TestQueryTracerNamesSqlcQueries(t *testing.T) {
+	exporter := setupExporter(t)
+	queryTracer := tracing.NewQueryTracer()
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "UserUseCase.Create")
+	queryCtx := queryTracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{
+		SQL: "-- name: FindUserByEmail :one\nSELECT id FROM users WHERE email = $1",
+	})
+	queryTracer.TraceQueryEnd(queryCtx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("SELECT 1")})
+
+	queryCtx = queryTracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "SELECT 1"})
+	queryTracer.TraceQueryEnd(queryCtx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("SELECT 1")})
+	parent.End()
+
+	spans := exporter.GetSpans()
+	query := findSpan(t, spans, "sqlc.FindUserByEmail")
+	assert.Equal(t, trace.SpanKindClient, query.SpanKind)
+	assert.Equal(t, parent.SpanContext().SpanID(), query.Parent.SpanID())
+	findSpan(t, spans, "db.query")
+}
+
+func TestLogHookAddsTraceID(t *testing.T) {
+	setupExporter(t)
+
+	var buf bytes.Buffer
+	log := logrus.New()
+	log.SetOutput(&buf)
+	log.SetFormatter(&logrus.JSONFormatter{})
+	log.AddHook(tracing.NewLogHook())
+
+	ctx, span := otel.Tracer("test").Start(context.Background(), "request")
+	pkg.Logger(ctx, log).Info("inside span")
+	span.End()
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, span.SpanContext().TraceID().String(), entry["trace_id"])
+	assert.Equal(t, span.SpanContext().SpanID().String(), entry["span_id"])
+
+	buf.Reset()
+	pkg.Logger(context.Background(), log).Info("no span")
+	entry = map[string]any{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.NotContains(t, entry, "trace_id")
+}
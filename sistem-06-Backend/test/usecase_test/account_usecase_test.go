@@ -0,0 +1,203 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"testing"
+	"time"
+
+	"sistem-06-Backend/internal/domain/entity"
+	domain "sistem-06-Backend/internal/domain/ports"
+	"sistem-06-Backend/internal/dto"
+	"sistem-06-Backend/internal/pkg/password"
+	"sistem-06-Backend/internal/usecase"
+	"sistem-06-Backend/pkg"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type memoryEmailChangeRepository struct {
+	changes map[int]*entity.EmailChange
+}
+
+func (r *memoryEmailChangeRepository) WithTx(tx *sql.Tx) domain.EmailChangeRepository { return r }
+
+func (r *memoryEmailChangeRepository) Save(ctx context.Context, change *entity.EmailChange) error {
+	copied := *change
+	r.changes[change.UserID] = &copied
+	return nil
+}
+
+func (r *memoryEmailChangeRepository) FindByUserID(ctx context.Context, userID int) (*entity.EmailChange, error) {
+	if change, ok := r.changes[userID]; ok {
+		return change, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *memoryEmailChangeRepository) FindByTokenHash(ctx context.Context, hash string) (*entity.EmailChange, error) {
+	for _, change := range r.changes {
+		if change.TokenHash == hash {
+			return change, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *memoryEmailChangeRepository) Delete(ctx context.Context, userID int) error {
+	delete(r.changes, userID)
+	return nil
+}
+
+// tokenNotifier keeps the last email change token so tests can confirm it.
+type tokenNotifier struct {
+	recordingNotifier
+	token string
+}
+
+func (n *tokenNotifier) EmailChangeRequested(ctx context.Context, user *entity.User, newEmail string, token string) error {
+	n.token = token
+	return nil
+}
+
+func newAccountUseCase(t *testing.T) (*usecase.AccountUseCase, *lockoutUserRepository, *tokenNotifier, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("Lama-rahasia-42"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	users := &lockoutUserRepository{users: map[string]*entity.User{
+		"budi@example.com": {ID: 7, Name: "budi", Email: "budi@example.com", Password: string(hash), Version: 1},
+		"siti@example.com": {ID: 8, Name: "siti", Email: "siti@example.com"},
+	}}
+	changes := &memoryEmailChangeRepository{changes: map[int]*entity.EmailChange{}}
+	notifier := &tokenNotifier{}
+	policy := &password.Policy{MinLength: 8, MaxLength: 72, MinCharacterClasses: 2, RejectPersonalInfo: true}
+
+	uc := usecase.NewAccountUseCase(db, log, validator.New(), users, changes, policy, testHasher, notifier, nil, time.Hour)
+	return uc, users, notifier, mock
+}
+
+func ptr(s string) *string { return &s }
+
+func TestAccountEmailChangeNeedsVerification(t *testing.T) {
+	uc, users, notifier, mock := newAccountUseCase(t)
+	ctx := context.Background()
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	response, err := uc.Update(ctx, 7, 1, &dto.UpdateAccountRequest{Name: ptr("budi santoso"), Email: ptr("budi.baru@example.com")})
+	require.NoError(t, err)
+	assert.Equal(t, "budi santoso", response.Name)
+	assert.Equal(t, 2, response.Version)
+	assert.Equal(t, "budi@example.com", response.Email)
+	assert.Equal(t, "budi.baru@example.com", response.PendingEmail)
+	require.NotEmpty(t, notifier.token)
+
+	_, err = uc.VerifyEmail(ctx, 8, &dto.VerifyUserRequest{Token: notifier.token})
+	assertStatus(t, err, fiber.StatusBadRequest)
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	response, err = uc.VerifyEmail(ctx, 7, &dto.VerifyUserRequest{Token: notifier.token})
+	require.NoError(t, err)
+	assert.Equal(t, "budi.baru@example.com", response.Email)
+	assert.Empty(t, response.PendingEmail)
+	assert.Contains(t, users.users, "budi.baru@example.com")
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	_, err = uc.VerifyEmail(ctx, 7, &dto.VerifyUserRequest{Token: notifier.token})
+	assertStatus(t, err, fiber.StatusBadRequest)
+}
+
+func TestAccountEmailChangeRejectsTakenEmail(t *testing.T) {
+	uc, _, _, mock := newAccountUseCase(t)
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	_, err := uc.Update(context.Background(), 7, 1, &dto.UpdateAccountRequest{Email: ptr("siti@example.com")})
+	assertStatus(t, err, fiber.StatusConflict)
+
+	_, err = uc.Update(context.Background(), 7, 1, &dto.UpdateAccountRequest{Email: ptr("not-an-email")})
+	assertStatus(t, err, fiber.StatusBadRequest)
+}
+
+func TestAccountEmailVerificationRejectsEmailTakenMeanwhile(t *testing.T) {
+	uc, users, notifier, mock := newAccountUseCase(t)
+	ctx := context.Background()
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	_, err := uc.Update(ctx, 7, 1, &dto.UpdateAccountRequest{Email: ptr("budi.baru@example.com")})
+	require.NoError(t, err)
+
+	// registered before budi confirmed the change
+	users.users["budi.baru@example.com"] = &entity.User{ID: 9, Name: "budi baru", Email: "budi.baru@example.com"}
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	_, err = uc.VerifyEmail(ctx, 7, &dto.VerifyUserRequest{Token: notifier.token})
+	assertStatus(t, err, fiber.StatusConflict)
+	assert.Equal(t, 7, users.users["budi@example.com"].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAccountUpdateRejectsStaleVersion(t *testing.T) {
+	uc, users, _, mock := newAccountUseCase(t)
+	ctx := context.Background()
+
+	// someone else renamed the account after version 1 was read
+	users.users["budi@example.com"].Version = 2
+	_, err := uc.Update(ctx, 7, 1, &dto.UpdateAccountRequest{Name: ptr("budi santoso")})
+
+	var conflict *pkg.VersionConflict
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, 2, conflict.Version)
+	current := conflict.Current.(*dto.UserResponse)
+	assert.Equal(t, "budi", current.Name)
+	assert.Equal(t, 2, current.Version)
+	assert.NoError(t, mock.ExpectationsWereMet(), "a stale version is rejected before any write")
+}
+
+func TestAccountChangePassword(t *testing.T) {
+	uc, users, _, mock := newAccountUseCase(t)
+	ctx := context.Background()
+
+	_, err := uc.ChangePassword(ctx, 7, &dto.ChangePasswordRequest{CurrentPassword: "salah", Password: "Baru-rahasia-42"})
+	assertStatus(t, err, fiber.StatusBadRequest)
+	assert.Contains(t, err.Error(), `"CurrentPassword"`)
+
+	_, err = uc.ChangePassword(ctx, 7, &dto.ChangePasswordRequest{CurrentPassword: "Lama-rahasia-42", Password: "Lama-rahasia-42"})
+	assertStatus(t, err, fiber.StatusBadRequest)
+
+	_, err = uc.ChangePassword(ctx, 7, &dto.ChangePasswordRequest{CurrentPassword: "Lama-rahasia-42", Password: "short"})
+	assertStatus(t, err, fiber.StatusBadRequest)
+	assert.Contains(t, err.Error(), `"Password"`)
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	response, err := uc.ChangePassword(ctx, 7, &dto.ChangePasswordRequest{
+		CurrentPassword:     "Lama-rahasia-42",
+		Password:            "Baru-rahasia-42",
+		RevokeOtherSessions: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 7, response.ID)
+
+	user := users.users["budi@example.com"]
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(user.Password), []byte("Baru-rahasia-42")))
+	assert.NotZero(t, user.SessionsRevokedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,181 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"sort"
+	"sync"
+	"testing"
+
+	"sistem-06-Backend/internal/domain/entity"
+	domain "sistem-06-Backend/internal/domain/ports"
+	"sistem-06-Backend/internal/dto"
+	"sistem-06-Backend/internal/pkg/fieldcrypt"
+	"sistem-06-Backend/internal/usecase"
+	"sistem-06-Backend/pkg"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryAuditRepository chains entries the same way the postgres repository
+// does.
+type memoryAuditRepository struct {
+	mu      sync.Mutex
+	entries []*entity.AuditEntry
+}
+
+func (r *memoryAuditRepository) WithTx(tx *sql.Tx) domain.AuditRepository { return r }
+
+func (r *memoryAuditRepository) Append(ctx context.Context, entry *entity.AuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry.PrevHash = entity.AuditGenesisHash
+	if len(r.entries) > 0 {
+		entry.PrevHash = r.entries[len(r.entries)-1].Hash
+	}
+	entry.Hash = entry.ComputeHash()
+	entry.ID = int64(len(r.entries) + 1)
+	copied := *entry
+	r.entries = append(r.entries, &copied)
+	return nil
+}
+
+func (r *memoryAuditRepository) matching(filter entity.AuditFilter) []*entity.AuditEntry {
+	var result []*entity.AuditEntry
+	for _, entry := range r.entries {
+		if (filter.ActorID == 0 || entry.ActorID == filter.ActorID) &&
+			(filter.Action == "" || entry.Action == filter.Action) &&
+			(filter.EntityType == "" || entry.EntityType == filter.EntityType) &&
+			(filter.EntityID == "" || entry.EntityID == filter.EntityID) {
+			result = append(result, entry)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID > result[j].ID })
+	return result
+}
+
+func (r *memoryAuditRepository) Search(ctx context.Context, filter entity.AuditFilter) ([]*entity.AuditEntry, error) {
+	result := r.matching(filter)
+	if filter.Offset >= len(result) {
+		return nil, nil
+	}
+	result = result[filter.Offset:]
+	if len(result) > filter.Limit {
+		result = result[:filter.Limit]
+	}
+	return result, nil
+}
+
+func (r *memoryAuditRepository) Count(ctx context.Context, filter entity.AuditFilter) (int64, error) {
+	return int64(len(r.matching(filter))), nil
+}
+
+func (r *memoryAuditRepository) ListAfter(ctx context.Context, afterID int64, limit int) ([]*entity.AuditEntry, error) {
+	var result []*entity.AuditEntry
+	for _, entry := range r.entries {
+		if entry.ID > afterID && len(result) < limit {
+			result = append(result, entry)
+		}
+	}
+	return result, nil
+}
+
+func newAuditRecorder() (*usecase.AuditRecorder, *usecase.AuditUseCase, *memoryAuditRepository) {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	repo := &memoryAuditRepository{}
+	return usecase.NewAuditRecorder(repo, log, nil), usecase.NewAuditUseCase(log, validator.New(), repo), repo
+}
+
+func TestAuditRecordStoresDiffAndRequestDetails(t *testing.T) {
+	recorder, _, repo := newAuditRecorder()
+
+	ctx := pkg.WithActor(context.Background(), 7)
+	ctx = pkg.WithClientIP(ctx, "203.0.113.9")
+	ctx = pkg.WithRequestID(ctx, "req-1")
+
+	before := map[string]any{"name": "budi", "email": "budi@example.com"}
+	after := map[string]any{"name": "budi santoso", "email": "budi@example.com"}
+	require.NoError(t, recorder.Record(ctx, nil, entity.AuditActionUpdate, entity.AuditEntityUser, 7, before, after))
+
+	require.Len(t, repo.entries, 1)
+	entry := repo.entries[0]
+	assert.Equal(t, 7, entry.ActorID)
+	assert.Equal(t, "203.0.113.9", entry.IP)
+	assert.Equal(t, "req-1", entry.RequestID)
+	assert.Equal(t, "7", entry.EntityID)
+	assert.Equal(t, entity.AuditGenesisHash, entry.PrevHash)
+
+	var diff map[string]map[string]any
+	require.NoError(t, json.Unmarshal([]byte(entry.Diff), &diff))
+	assert.Equal(t, map[string]map[string]any{"name": {"old": "budi", "new": "budi santoso"}}, diff)
+
+	var nilRecorder *usecase.AuditRecorder
+	assert.NoError(t, nilRecorder.Record(ctx, nil, entity.AuditActionDelete, entity.AuditEntityUser, 7, after, nil))
+}
+
+func TestAuditRecordStoresEmailsAsBlindIndex(t *testing.T) {
+	recorder, _, repo := newAuditRecorder()
+
+	before := map[string]any{"email": "budi@example.com"}
+	after := map[string]any{"email": "budi.santoso@example.com"}
+	require.NoError(t, recorder.Record(context.Background(), nil, entity.AuditActionUpdate, entity.AuditEntityUser, 7, before, after))
+	require.NoError(t, recorder.Record(context.Background(), nil, entity.AuditActionRequestEmailChange, entity.AuditEntityUser, 7, nil, map[string]any{"pending_email": "siti@example.com"}))
+
+	require.Len(t, repo.entries, 2)
+	for _, entry := range repo.entries {
+		assert.NotContains(t, entry.Diff, "@example.com")
+	}
+	var diff map[string]map[string]any
+	require.NoError(t, json.Unmarshal([]byte(repo.entries[0].Diff), &diff))
+	assert.Equal(t, (*fieldcrypt.Cipher)(nil).BlindIndex("users.email", "budi@example.com"), diff["email"]["old"])
+	assert.Equal(t, (*fieldcrypt.Cipher)(nil).BlindIndex("users.email", "budi.santoso@example.com"), diff["email"]["new"])
+}
+
+func TestAuditVerifyDetectsTampering(t *testing.T) {
+	recorder, uc, repo := newAuditRecorder()
+	ctx := context.Background()
+
+	for i := 1; i <= 3; i++ {
+		require.NoError(t, recorder.Record(ctx, nil, entity.AuditActionCreate, entity.AuditEntityAddress, i, nil, map[string]any{"id": i}))
+	}
+
+	response, err := uc.Verify(ctx)
+	require.NoError(t, err)
+	assert.True(t, response.Valid)
+	assert.EqualValues(t, 3, response.Checked)
+	assert.Equal(t, repo.entries[2].Hash, response.LastHash)
+
+	repo.entries[1].Diff = `{"id":{"old":null,"new":99}}`
+	response, err = uc.Verify(ctx)
+	require.NoError(t, err)
+	assert.False(t, response.Valid)
+	assert.EqualValues(t, 2, response.BrokenAt)
+	assert.EqualValues(t, 1, response.Checked)
+}
+
+func TestAuditSearchPages(t *testing.T) {
+	recorder, uc, _ := newAuditRecorder()
+	ctx := pkg.WithActor(context.Background(), 7)
+
+	for i := 1; i <= 3; i++ {
+		require.NoError(t, recorder.Record(ctx, nil, entity.AuditActionCreate, entity.AuditEntityAddress, i, nil, map[string]any{"id": i}))
+	}
+
+	responses, paging, err := uc.Search(ctx, &dto.SearchAuditLogRequest{ActorID: 7, Page: 1, Size: 2})
+	require.NoError(t, err)
+	require.Len(t, responses, 2)
+	assert.Equal(t, "3", responses[0].EntityID)
+	assert.EqualValues(t, 3, paging.TotalItem)
+	assert.EqualValues(t, 2, paging.TotalPage)
+
+	_, _, err = uc.Search(ctx, &dto.SearchAuditLogRequest{Page: 0, Size: 2})
+	assertStatus(t, err, fiber.StatusBadRequest)
+}
@@ -0,0 +1,441 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"iter"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"sistem-06-Backend/internal/domain/entity"
+	domain "sistem-06-Backend/internal/domain/ports"
+	"sistem-06-Backend/internal/dto"
+	"sistem-06-Backend/internal/pkg/fieldcrypt"
+	"sistem-06-Backend/internal/pkg/listquery"
+	"sistem-06-Backend/internal/pkg/password"
+	"sistem-06-Backend/internal/usecase"
+	"sistem-06-Backend/pkg"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type lockoutUserRepository struct {
+	users map[string]*entity.User
+}
+
+func (r *lockoutUserRepository) WithTx(tx *sql.Tx) domain.UserRepository { return r }
+
+func (r *lockoutUserRepository) CreateUser(ctx context.Context, user *entity.User) error { return nil }
+
+func (r *lockoutUserRepository) CountById(ctx context.Context, id int) (int, error) { return 0, nil }
+
+func (r *lockoutUserRepository) CountByName(ctx context.Context, name string) (int, error) {
+	return 0, nil
+}
+
+func (r *lockoutUserRepository) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
+	if user, ok := r.users[email]; ok && user.DeletedAt == 0 {
+		return user, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *lockoutUserRepository) FindByID(ctx context.Context, id int) (*entity.User, error) {
+	for _, user := range r.users {
+		if user.ID == id && user.DeletedAt == 0 {
+			return user, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *lockoutUserRepository) FindWithRoles(ctx context.Context, id int) (*entity.User, error) {
+	return r.FindByID(ctx, id)
+}
+
+// List ignores filters and pages; listquery has its own tests.
+func (r *lockoutUserRepository) List(ctx context.Context, request *listquery.Request) ([]*entity.User, pkg.PageMetadata, error) {
+	var users []*entity.User
+	for _, user := range r.users {
+		if user.DeletedAt == 0 {
+			users = append(users, user)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	return users, pkg.PageMetadata{Page: 1, Size: len(users), TotalItem: int64(len(users)), TotalPage: 1}, nil
+}
+
+// Export yields what List returns; roles are whatever the users carry.
+func (r *lockoutUserRepository) Export(ctx context.Context, request *listquery.Request) (iter.Seq2[*entity.User, error], error) {
+	users, _, err := r.List(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return func(yield func(*entity.User, error) bool) {
+		for _, user := range users {
+			if !yield(user, nil) {
+				return
+			}
+		}
+	}, nil
+}
+
+func (r *lockoutUserRepository) UpdateName(ctx context.Context, id int, version int, name string, updatedAt int64) error {
+	return r.updateVersion(id, version, func(user *entity.User) { user.Name = name })
+}
+
+func (r *lockoutUserRepository) UpdateEmail(ctx context.Context, id int, email string, updatedAt int64) error {
+	for key, user := range r.users {
+		if user.ID == id {
+			delete(r.users, key)
+			user.Email = email
+			r.users[email] = user
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func (r *lockoutUserRepository) UpdatePassword(ctx context.Context, id int, password string, updatedAt int64) error {
+	return r.update(id, func(user *entity.User) { user.Password = password })
+}
+
+func (r *lockoutUserRepository) RehashPassword(ctx context.Context, id int, password string) error {
+	user, err := r.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	user.Password = password
+	return nil
+}
+
+func (r *lockoutUserRepository) RevokeSessions(ctx context.Context, id int, revokedAt int64) error {
+	user, err := r.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	user.SessionsRevokedAt = revokedAt
+	return nil
+}
+
+func (r *lockoutUserRepository) SoftDelete(ctx context.Context, id int, version int, deletedAt int64) error {
+	return r.updateVersion(id, version, func(user *entity.User) { user.DeletedAt = deletedAt })
+}
+
+func (r *lockoutUserRepository) Restore(ctx context.Context, id int, restoredAt int64) (bool, error) {
+	for _, user := range r.users {
+		if user.ID == id && user.DeletedAt > 0 {
+			user.DeletedAt = 0
+			user.Version++
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *lockoutUserRepository) PurgeDeleted(ctx context.Context, deletedBefore int64) (int64, error) {
+	var purged int64
+	for key, user := range r.users {
+		if user.DeletedAt > 0 && user.DeletedAt < deletedBefore {
+			delete(r.users, key)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (r *lockoutUserRepository) Anonymize(ctx context.Context, id int, placeholder string, anonymizedAt int64) (bool, error) {
+	for key, user := range r.users {
+		if user.ID == id && key != placeholder {
+			delete(r.users, key)
+			user.Name, user.Email, user.Password = placeholder, placeholder, ""
+			user.DeletedAt, user.SessionsRevokedAt = anonymizedAt, anonymizedAt
+			user.Version++
+			r.users[placeholder] = user
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *lockoutUserRepository) update(id int, apply func(user *entity.User)) error {
+	user, err := r.FindByID(context.Background(), id)
+	if err != nil {
+		return err
+	}
+	apply(user)
+	user.Version++
+	return nil
+}
+
+// updateVersion is update guarded by the version the caller read.
+func (r *lockoutUserRepository) updateVersion(id int, version int, apply func(user *entity.User)) error {
+	user, err := r.FindByID(context.Background(), id)
+	if err != nil {
+		return err
+	}
+	if user.Version != version {
+		return entity.ErrVersionConflict
+	}
+	return r.update(id, apply)
+}
+
+type loginAttemptKey struct {
+	scope      entity.LoginScope
+	identifier string
+}
+
+// memoryLoginAttemptRepository mirrors the semantics of the postgres queries.
+type memoryLoginAttemptRepository struct {
+	mu       sync.Mutex
+	attempts map[loginAttemptKey]*entity.LoginAttempt
+}
+
+func newMemoryLoginAttemptRepository() *memoryLoginAttemptRepository {
+	return &memoryLoginAttemptRepository{attempts: map[loginAttemptKey]*entity.LoginAttempt{}}
+}
+
+func (r *memoryLoginAttemptRepository) WithTx(tx *sql.Tx) domain.LoginAttemptRepository { return r }
+
+func (r *memoryLoginAttemptRepository) Find(ctx context.Context, scope entity.LoginScope, identifier string) (*entity.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	attempt, ok := r.attempts[loginAttemptKey{scope, identifier}]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *attempt
+	return &copied, nil
+}
+
+func (r *memoryLoginAttemptRepository) RecordFailure(ctx context.Context, scope entity.LoginScope, identifier string, now int64, windowStart int64) (*entity.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := loginAttemptKey{scope, identifier}
+	attempt, ok := r.attempts[key]
+	if !ok {
+		attempt = &entity.LoginAttempt{Scope: scope, Identifier: identifier}
+		r.attempts[key] = attempt
+	}
+	if attempt.LastFailedAt < windowStart {
+		attempt.Failures = 0
+	}
+	attempt.Failures++
+	attempt.LastFailedAt = now
+	copied := *attempt
+	return &copied, nil
+}
+
+func (r *memoryLoginAttemptRepository) Lock(ctx context.Context, scope entity.LoginScope, identifier string, until int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if attempt, ok := r.attempts[loginAttemptKey{scope, identifier}]; ok && until > attempt.LockedUntil {
+		attempt.LockedUntil = until
+	}
+	return nil
+}
+
+func (r *memoryLoginAttemptRepository) Reset(ctx context.Context, scope entity.LoginScope, identifier string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.attempts, loginAttemptKey{scope, identifier})
+	return nil
+}
+
+func (r *memoryLoginAttemptRepository) DeleteExpired(ctx context.Context, lastFailedBefore int64, now int64) (int64, error) {
+	return 0, nil
+}
+
+type recordingNotifier struct {
+	locked chan *entity.User
+}
+
+func (n *recordingNotifier) AccountLocked(ctx context.Context, user *entity.User, until time.Time) error {
+	n.locked <- user
+	return nil
+}
+
+func (n *recordingNotifier) EmailChangeRequested(ctx context.Context, user *entity.User, newEmail string, token string) error {
+	return nil
+}
+
+func (n *recordingNotifier) PasswordChanged(ctx context.Context, user *entity.User) error {
+	return nil
+}
+
+// testHasher matches the bcrypt.MinCost hashes the tests store, so logins
+// do not rehash unless a test wants them to.
+var testHasher = password.NewHasher(&password.Bcrypt{Cost: bcrypt.MinCost})
+
+var testLoginPolicy = usecase.LoginPolicy{
+	FreeAttempts:     2,
+	BaseDelay:        0,
+	MaxDelay:         0,
+	AccountThreshold: 3,
+	IPThreshold:      100,
+	Window:           15 * time.Minute,
+	LockoutDuration:  15 * time.Minute,
+}
+
+func newLockoutAuthUseCase(t *testing.T) (*usecase.AuthUseCase, *memoryLoginAttemptRepository, *recordingNotifier) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("correct-password"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	users := &lockoutUserRepository{users: map[string]*entity.User{
+		"budi@example.com": {ID: 7, Name: "budi", Email: "budi@example.com", Password: string(hash)},
+	}}
+	attempts := newMemoryLoginAttemptRepository()
+	notifier := &recordingNotifier{locked: make(chan *entity.User, 4)}
+
+	uc := usecase.NewAuthUseCase(&sql.DB{}, log, validator.New(), users, attempts, newMemoryTwoFactorRepository(), notifier, testHasher, nil, testLoginPolicy, nil, nil)
+	return uc, attempts, notifier
+}
+
+// accountKey is the identifier the failures of email are counted under
+// without encryption keys.
+func accountKey(email string) string {
+	var cipher *fieldcrypt.Cipher
+	return cipher.BlindIndex("login_attempts.identifier", email)
+}
+
+func login(uc *usecase.AuthUseCase, email string, password string) error {
+	_, err := uc.Login(context.Background(), &dto.UserLoginRequest{
+		Email:     email,
+		Password:  password,
+		IPAddress: "203.0.113.9",
+	})
+	return err
+}
+
+func TestLoginPolicyDelay(t *testing.T) {
+	policy := usecase.LoginPolicy{
+		FreeAttempts:     3,
+		BaseDelay:        time.Second,
+		MaxDelay:         5 * time.Second,
+		AccountThreshold: 10,
+		IPThreshold:      50,
+		LockoutDuration:  15 * time.Minute,
+	}
+
+	expected := map[int]time.Duration{
+		1:  0,
+		3:  0,
+		4:  time.Second,
+		5:  2 * time.Second,
+		6:  4 * time.Second,
+		7:  5 * time.Second,
+		9:  5 * time.Second,
+		10: 15 * time.Minute,
+	}
+	for failures, delay := range expected {
+		assert.Equal(t, delay, policy.Delay(entity.LoginScopeAccount, failures), "failures=%d", failures)
+	}
+	assert.Equal(t, 5*time.Second, policy.Delay(entity.LoginScopeIP, 10))
+	assert.Equal(t, 15*time.Minute, policy.Delay(entity.LoginScopeIP, 50))
+}
+
+func TestLoginUnknownEmailLooksLikeWrongPassword(t *testing.T) {
+	uc, attempts, _ := newLockoutAuthUseCase(t)
+
+	wrongPassword := login(uc, "budi@example.com", "wrong-password")
+	unknownEmail := login(uc, "nobody@example.com", "wrong-password")
+
+	assert.Equal(t, fiber.ErrUnauthorized, wrongPassword)
+	assert.Equal(t, wrongPassword, unknownEmail)
+
+	for _, email := range []string{"budi@example.com", "nobody@example.com"} {
+		attempt, err := attempts.Find(context.Background(), entity.LoginScopeAccount, accountKey(email))
+		require.NoError(t, err)
+		assert.Equal(t, 1, attempt.Failures)
+
+		// the counter is kept under a blind index, not the address
+		_, err = attempts.Find(context.Background(), entity.LoginScopeAccount, email)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	}
+	ipAttempt, err := attempts.Find(context.Background(), entity.LoginScopeIP, "203.0.113.9")
+	require.NoError(t, err)
+	assert.Equal(t, 2, ipAttempt.Failures)
+}
+
+func TestLoginLocksAccountAndNotifies(t *testing.T) {
+	uc, _, notifier := newLockoutAuthUseCase(t)
+
+	// differently cased spellings share one counter
+	for i := 0; i < testLoginPolicy.AccountThreshold-1; i++ {
+		assert.Equal(t, fiber.ErrUnauthorized, login(uc, "Budi@Example.com", "wrong-password"))
+	}
+	assert.Equal(t, fiber.ErrUnauthorized, login(uc, "budi@example.com", "wrong-password"))
+
+	select {
+	case user := <-notifier.locked:
+		assert.Equal(t, 7, user.ID)
+	case <-time.After(time.Second):
+		t.Fatal("lockout notice not sent")
+	}
+
+	// even the correct password is refused while locked
+	err := login(uc, "budi@example.com", "correct-password")
+	var throttled *usecase.LoginThrottledError
+	require.True(t, errors.As(err, &throttled))
+	assert.Greater(t, throttled.RetryAfter, 14*time.Minute)
+}
+
+func TestLoginLocksUnknownEmailWithoutNotice(t *testing.T) {
+	uc, _, notifier := newLockoutAuthUseCase(t)
+
+	for i := 0; i < testLoginPolicy.AccountThreshold; i++ {
+		assert.Equal(t, fiber.ErrUnauthorized, login(uc, "nobody@example.com", "wrong-password"))
+	}
+
+	var throttled *usecase.LoginThrottledError
+	assert.True(t, errors.As(login(uc, "nobody@example.com", "wrong-password"), &throttled))
+	assert.Empty(t, notifier.locked)
+}
+
+func TestLoginSuccessResetsAccountCounter(t *testing.T) {
+	uc, attempts, _ := newLockoutAuthUseCase(t)
+
+	assert.Equal(t, fiber.ErrUnauthorized, login(uc, "budi@example.com", "wrong-password"))
+	require.NoError(t, login(uc, "budi@example.com", "correct-password"))
+
+	_, err := attempts.Find(context.Background(), entity.LoginScopeAccount, accountKey("budi@example.com"))
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	ipAttempt, err := attempts.Find(context.Background(), entity.LoginScopeIP, "203.0.113.9")
+	require.NoError(t, err)
+	assert.Equal(t, 1, ipAttempt.Failures)
+}
+
+func TestUnlockLiftsLockout(t *testing.T) {
+	uc, _, _ := newLockoutAuthUseCase(t)
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	uc.DB = db
+
+	for i := 0; i < testLoginPolicy.AccountThreshold; i++ {
+		_ = login(uc, "budi@example.com", "wrong-password")
+	}
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	require.NoError(t, uc.Unlock(context.Background(), 7))
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.NoError(t, login(uc, "budi@example.com", "correct-password"))
+
+	var fiberErr *fiber.Error
+	require.True(t, errors.As(uc.Unlock(context.Background(), 99), &fiberErr))
+	assert.Equal(t, fiber.StatusNotFound, fiberErr.Code)
+}
@@ -0,0 +1,152 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"iter"
+	"strings"
+	"testing"
+
+	"sistem-06-Backend/internal/domain/entity"
+	domain "sistem-06-Backend/internal/domain/ports"
+	"sistem-06-Backend/internal/pkg/listquery"
+	"sistem-06-Backend/internal/usecase"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type exportAddressRepository struct {
+	domain.AddressRepository
+	addresses []*entity.Address
+}
+
+func (r *exportAddressRepository) Export(ctx context.Context, request *listquery.Request) (iter.Seq2[*entity.Address, error], error) {
+	return func(yield func(*entity.Address, error) bool) {
+		for _, address := range r.addresses {
+			if !yield(address, nil) {
+				return
+			}
+		}
+	}, nil
+}
+
+func newExportUseCase(t *testing.T) (*usecase.ExportUseCase, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	admin := entity.Role{ID: 1, Name: "admin", Permission: []entity.Permissions{entity.PermissionManageUsers, entity.PermissionManageAddresses}}
+	clerk := entity.Role{ID: 2, Name: "clerk", Permission: []entity.Permissions{entity.PermissionExportData}}
+	users := &lockoutUserRepository{users: map[string]*entity.User{
+		"admin@example.com":        {ID: 1, Name: "admin", Email: "admin@example.com", Roles: []entity.Role{admin}},
+		"clerk@example.com":        {ID: 2, Name: "clerk", Email: "clerk@example.com", Roles: []entity.Role{clerk}},
+		"budi.santoso@example.com": {ID: 3, Name: "budi", Email: "budi.santoso@example.com"},
+	}}
+	addresses := &exportAddressRepository{addresses: []*entity.Address{
+		{ID: 1, Jalan: "Jl. Merdeka 1", RT: "001", RW: "002", Kota: "Bandung", PostalCode: "40111"},
+	}}
+	return usecase.NewExportUseCase(db, log, users, addresses, nil), mock
+}
+
+func exportBody(t *testing.T, write func(io.Writer) error) string {
+	var body bytes.Buffer
+	require.NoError(t, write(&body))
+	return body.String()
+}
+
+func TestExportUsersShowsEmailsToUserManagers(t *testing.T) {
+	uc, mock := newExportUseCase(t)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	file, err := uc.Users(context.Background(), 1, nil, map[string]string{})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(file.Filename, "users-"))
+	assert.True(t, strings.HasSuffix(file.Filename, ".csv"))
+
+	body := exportBody(t, file.Write)
+	assert.Contains(t, body, "ID,Name,Email,Roles,Created At")
+	assert.Contains(t, body, "budi.santoso@example.com")
+	assert.Contains(t, body, "admin@example.com,admin")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExportUsersMasksEmailsWithExportOnly(t *testing.T) {
+	uc, mock := newExportUseCase(t)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	file, err := uc.Users(context.Background(), 2, nil, map[string]string{})
+	require.NoError(t, err)
+
+	body := exportBody(t, file.Write)
+	assert.Contains(t, body, "b***@example.com")
+	assert.NotContains(t, body, "budi.santoso@example.com")
+}
+
+func TestExportUsersChecksPermissions(t *testing.T) {
+	uc, _ := newExportUseCase(t)
+
+	_, err := uc.Users(context.Background(), 3, nil, map[string]string{})
+	assert.Equal(t, fiber.ErrForbidden, err)
+
+	// the token of an admin without the scope cannot export either
+	token := &entity.PersonalAccessToken{Scopes: []entity.Permissions{entity.PermissionViewAuditLog}}
+	_, err = uc.Users(context.Background(), 1, token, map[string]string{})
+	assert.Equal(t, fiber.ErrForbidden, err)
+}
+
+func TestExportRejectsBadQueries(t *testing.T) {
+	uc, _ := newExportUseCase(t)
+
+	for name, query := range map[string]map[string]string{
+		"format":   {"format": "docx"},
+		"operator": {"filter[name][near]": "budi"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := uc.Users(context.Background(), 1, nil, query)
+			var fiberErr *fiber.Error
+			require.ErrorAs(t, err, &fiberErr)
+			assert.Equal(t, fiber.StatusBadRequest, fiberErr.Code)
+		})
+	}
+}
+
+func TestExportAddressesAsPDF(t *testing.T) {
+	uc, mock := newExportUseCase(t)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	file, err := uc.Addresses(context.Background(), 1, nil, map[string]string{"format": "pdf"})
+	require.NoError(t, err)
+	assert.Equal(t, "application/pdf", file.ContentType)
+
+	body := exportBody(t, file.Write)
+	assert.True(t, strings.HasPrefix(body, "%PDF-1.4"))
+	assert.Contains(t, body, "(Jl. Merdeka 1)")
+
+	_, err = uc.Addresses(context.Background(), 3, nil, map[string]string{})
+	assert.Equal(t, fiber.ErrForbidden, err)
+}
+
+func TestExportAddressesMasksStreetsWithExportOnly(t *testing.T) {
+	uc, mock := newExportUseCase(t)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	file, err := uc.Addresses(context.Background(), 2, nil, map[string]string{})
+	require.NoError(t, err)
+
+	body := exportBody(t, file.Write)
+	assert.Contains(t, body, "1,J***,001,002,Bandung,40111")
+	assert.NotContains(t, body, "Merdeka")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,94 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"testing"
+
+	"sistem-06-Backend/internal/usecase"
+
+	"github.com/gofiber/storage/memory"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockHealthRepository struct {
+	PingErr      error
+	Version      int64
+	Dirty        bool
+	MigrationErr error
+}
+
+func (m *MockHealthRepository) Ping(ctx context.Context) error {
+	return m.PingErr
+}
+
+func (m *MockHealthRepository) MigrationVersion(ctx context.Context) (int64, bool, error) {
+	return m.Version, m.Dirty, m.MigrationErr
+}
+
+func newHealthUseCase(repo *MockHealthRepository, ready bool) *usecase.HealthUseCase {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	return usecase.NewHealthUseCase(&sql.DB{}, log, memory.New(), repo, 20251129105125, func() bool { return ready })
+}
+
+func TestHealthReadiness(t *testing.T) {
+	tests := []struct {
+		name        string
+		repo        *MockHealthRepository
+		ready       bool
+		wantReady   bool
+		failedCheck string
+	}{
+		{
+			name:      "all dependencies healthy",
+			repo:      &MockHealthRepository{Version: 20251129105125},
+			ready:     true,
+			wantReady: true,
+		},
+		{
+			name:        "shutting down",
+			repo:        &MockHealthRepository{Version: 20251129105125},
+			ready:       false,
+			failedCheck: "lifecycle",
+		},
+		{
+			name:        "database unreachable",
+			repo:        &MockHealthRepository{PingErr: errors.New("connection refused"), Version: 20251129105125},
+			ready:       true,
+			failedCheck: "database",
+		},
+		{
+			name:        "schema behind",
+			repo:        &MockHealthRepository{Version: 20251129105120},
+			ready:       true,
+			failedCheck: "migrations",
+		},
+		{
+			name:        "dirty migration",
+			repo:        &MockHealthRepository{Version: 20251129105125, Dirty: true},
+			ready:       true,
+			failedCheck: "migrations",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			healthUseCase := newHealthUseCase(tt.repo, tt.ready)
+
+			response, ready := healthUseCase.Readiness(context.Background())
+
+			assert.Equal(t, tt.wantReady, ready)
+			if tt.wantReady {
+				assert.Equal(t, "ok", response.Status)
+				return
+			}
+			assert.Equal(t, "fail", response.Status)
+			assert.Equal(t, "fail", response.Checks[tt.failedCheck].Status)
+			assert.NotEmpty(t, response.Checks[tt.failedCheck].Detail)
+		})
+	}
+}
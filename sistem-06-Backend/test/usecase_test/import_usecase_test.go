@@ -0,0 +1,275 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"testing"
+
+	"sistem-06-Backend/internal/domain/entity"
+	domain "sistem-06-Backend/internal/domain/ports"
+	"sistem-06-Backend/internal/dto"
+	"sistem-06-Backend/internal/pkg/password"
+	"sistem-06-Backend/internal/pkg/validation"
+	"sistem-06-Backend/internal/usecase"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryImportJobRepository struct {
+	jobs map[string]entity.ImportJob
+	// statuses written by UpdateProgress, in order
+	progress []string
+}
+
+func (r *memoryImportJobRepository) Create(ctx context.Context, job *entity.ImportJob) error {
+	r.jobs[job.ID] = *job
+	return nil
+}
+
+func (r *memoryImportJobRepository) FindByID(ctx context.Context, id string) (*entity.ImportJob, error) {
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &job, nil
+}
+
+func (r *memoryImportJobRepository) UpdateProgress(ctx context.Context, job *entity.ImportJob) error {
+	r.progress = append(r.progress, job.Status)
+	r.jobs[job.ID] = *job
+	return nil
+}
+
+func (r *memoryImportJobRepository) Finish(ctx context.Context, job *entity.ImportJob) error {
+	r.jobs[job.ID] = *job
+	return nil
+}
+
+type createdAddressRepository struct {
+	domain.AddressRepository
+	created []*entity.Address
+}
+
+func (r *createdAddressRepository) WithTx(tx *sql.Tx) domain.AddressRepository { return r }
+
+func (r *createdAddressRepository) CreateAddress(ctx context.Context, address *entity.Address) error {
+	address.ID = len(r.created) + 1
+	r.created = append(r.created, address)
+	return nil
+}
+
+// createdUserRepository keeps the users an import creates on top of the
+// existing ones of lockoutUserRepository.
+type createdUserRepository struct {
+	*lockoutUserRepository
+	created []*entity.User
+}
+
+func (r *createdUserRepository) WithTx(tx *sql.Tx) domain.UserRepository { return r }
+
+func (r *createdUserRepository) CreateUser(ctx context.Context, user *entity.User) error {
+	user.ID = 100 + len(r.created)
+	r.created = append(r.created, user)
+	return nil
+}
+
+type namedRolesRepository struct {
+	domain.RolesRepository
+	roles    map[string]*entity.Role
+	assigned map[int][]int
+}
+
+func (r *namedRolesRepository) WithTx(tx *sql.Tx) domain.RolesRepository { return r }
+
+func (r *namedRolesRepository) FindByName(ctx context.Context, name string) (*entity.Role, error) {
+	if role, ok := r.roles[name]; ok {
+		return role, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *namedRolesRepository) AssignRoleToUser(ctx context.Context, userId int, rolesId int) error {
+	r.assigned[userId] = append(r.assigned[userId], rolesId)
+	return nil
+}
+
+type importFixture struct {
+	uc        *usecase.ImportUseCase
+	jobs      *memoryImportJobRepository
+	addresses *createdAddressRepository
+	users     *createdUserRepository
+	roles     *namedRolesRepository
+	mock      sqlmock.Sqlmock
+}
+
+func newImportFixture(t *testing.T) *importFixture {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	validate := validator.New()
+	validate.RegisterValidation("RT_RW", validation.CustomRtRwCodeValidation)
+	validate.RegisterValidation("postal_code", validation.CustomPostalCodeValidation)
+
+	f := &importFixture{
+		jobs:      &memoryImportJobRepository{jobs: map[string]entity.ImportJob{}},
+		addresses: &createdAddressRepository{},
+		users: &createdUserRepository{lockoutUserRepository: &lockoutUserRepository{users: map[string]*entity.User{
+			"siti@example.com": {ID: 8, Name: "siti", Email: "siti@example.com"},
+		}}},
+		roles: &namedRolesRepository{
+			roles:    map[string]*entity.Role{"officer": {ID: 2, Name: "officer"}, "auditor": {ID: 3, Name: "auditor"}},
+			assigned: map[int][]int{},
+		},
+		mock: mock,
+	}
+	policy := &password.Policy{MinLength: 8, MaxLength: 72, MinCharacterClasses: 2}
+	// jobs run before Start returns, so tests can look at the outcome
+	run := func(name string, fn func(ctx context.Context)) { fn(context.Background()) }
+	f.uc = usecase.NewImportUseCase(db, log, validate, f.users, f.addresses, f.roles, f.jobs, policy, testHasher, nil, run, 10, 1<<20)
+	return f
+}
+
+func (f *importFixture) job(t *testing.T, userID int, id string) *dto.ImportJobResponse {
+	job, err := f.uc.Get(context.Background(), userID, id)
+	require.NoError(t, err)
+	return job
+}
+
+func TestImportAddressesDryRunReportsRowErrors(t *testing.T) {
+	f := newImportFixture(t)
+
+	file := "Jalan;RT;RW;Kota;Kode Pos\n" +
+		"Gg. Melati;003;005;Bandung;40111\n" +
+		"Jl. Mawar;3;005;;40111\n"
+	response, err := f.uc.Start(context.Background(), 1, entity.ImportKindAddress, &dto.ImportRequest{
+		Filename: "alamat.csv",
+		Content:  []byte(file),
+		DryRun:   true,
+		Mapping:  map[string]string{"Kode Pos": "postal_code"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, entity.ImportStatusPending, response.Status)
+	assert.Equal(t, 2, response.TotalRows)
+
+	job := f.job(t, 1, response.ID)
+	assert.Equal(t, entity.ImportStatusFailed, job.Status)
+	assert.Equal(t, 2, job.ProcessedRows)
+	assert.Zero(t, job.ImportedRows)
+	require.Len(t, job.Errors, 2)
+	assert.Equal(t, 3, job.Errors[0].Row)
+	assert.Equal(t, "rt", job.Errors[0].Field)
+	assert.Equal(t, dto.ImportRowError{Row: 3, Field: "kota", Message: "Kota is required"}, job.Errors[1])
+	assert.Equal(t, []string{entity.ImportStatusRunning}, f.jobs.progress)
+	assert.Empty(t, f.addresses.created)
+	assert.NoError(t, f.mock.ExpectationsWereMet(), "a dry run writes nothing")
+
+	_, err = f.uc.Get(context.Background(), 2, response.ID)
+	assertStatus(t, err, fiber.StatusNotFound)
+}
+
+func TestImportAddressesCommitsAllRows(t *testing.T) {
+	f := newImportFixture(t)
+
+	f.mock.ExpectBegin()
+	f.mock.ExpectCommit()
+	response, err := f.uc.Start(context.Background(), 1, entity.ImportKindAddress, &dto.ImportRequest{
+		Filename: "alamat.csv",
+		Content:  []byte("jalan,rt,rw,kota,postal_code,catatan\nGg. Melati,003,005,Bandung,40111,x\nJl. Mawar,004,005,Bandung,40112,\n"),
+	})
+	require.NoError(t, err)
+
+	job := f.job(t, 1, response.ID)
+	assert.Equal(t, entity.ImportStatusSucceeded, job.Status)
+	assert.Equal(t, 2, job.ImportedRows)
+	assert.Empty(t, job.Errors)
+	require.Len(t, f.addresses.created, 2)
+	assert.Equal(t, "Jl. Mawar", f.addresses.created[1].Jalan)
+	assert.NoError(t, f.mock.ExpectationsWereMet())
+}
+
+func TestImportRejectsBadFiles(t *testing.T) {
+	f := newImportFixture(t)
+	ctx := context.Background()
+
+	_, err := f.uc.Start(ctx, 1, entity.ImportKindAddress, &dto.ImportRequest{Filename: "alamat.xls", Content: []byte("x")})
+	assertStatus(t, err, fiber.StatusBadRequest)
+
+	_, err = f.uc.Start(ctx, 1, entity.ImportKindAddress, &dto.ImportRequest{Filename: "alamat.csv", Content: []byte("jalan,rt,rw\nGg. Melati,003,005\n")})
+	assertStatus(t, err, fiber.StatusBadRequest)
+	assert.Contains(t, err.Error(), "missing columns: kota, postal_code")
+
+	_, err = f.uc.Start(ctx, 1, entity.ImportKindUser, &dto.ImportRequest{
+		Filename: "users.csv",
+		Content:  []byte("name,email,password\nbudi,budi@example.com,Rahasia-42\n"),
+		Mapping:  map[string]string{"name": "nama"},
+	})
+	assertStatus(t, err, fiber.StatusBadRequest)
+
+	var file []byte
+	file = append(file, "name,email,password\n"...)
+	for range 11 {
+		file = append(file, "budi,budi@example.com,Rahasia-42\n"...)
+	}
+	_, err = f.uc.Start(ctx, 1, entity.ImportKindUser, &dto.ImportRequest{Filename: "users.csv", Content: file})
+	assertStatus(t, err, fiber.StatusBadRequest)
+	assert.Empty(t, f.jobs.jobs)
+}
+
+func TestImportUsersAssignsRoles(t *testing.T) {
+	f := newImportFixture(t)
+
+	f.mock.ExpectBegin()
+	f.mock.ExpectCommit()
+	response, err := f.uc.Start(context.Background(), 1, entity.ImportKindUser, &dto.ImportRequest{
+		Filename: "users.csv",
+		Content:  []byte("Name,Email,Password,Roles\nbudi,budi@example.com,Rahasia-42,officer; auditor\nani,ani@example.com,Rahasia-43,\n"),
+	})
+	require.NoError(t, err)
+
+	job := f.job(t, 1, response.ID)
+	assert.Equal(t, entity.ImportStatusSucceeded, job.Status, job.Errors)
+	assert.Equal(t, 2, job.ImportedRows)
+	require.Len(t, f.users.created, 2)
+	budi := f.users.created[0]
+	match, err := testHasher.Verify("Rahasia-42", budi.Password)
+	require.NoError(t, err)
+	assert.True(t, match)
+	assert.Equal(t, []int{2, 3}, f.roles.assigned[budi.ID])
+	assert.Empty(t, f.roles.assigned[f.users.created[1].ID])
+	assert.NoError(t, f.mock.ExpectationsWereMet())
+}
+
+func TestImportUsersRollsBackOnAnyRowError(t *testing.T) {
+	f := newImportFixture(t)
+
+	file := "name,email,password,roles\n" +
+		"budi,budi@example.com,Rahasia-42,officer\n" +
+		"budi2,BUDI@example.com,Rahasia-42,\n" +
+		"siti2,siti@example.com,Rahasia-42,\n" +
+		"ani,ani@example.com,pendek,kepala\n"
+	response, err := f.uc.Start(context.Background(), 1, entity.ImportKindUser, &dto.ImportRequest{Filename: "users.csv", Content: []byte(file)})
+	require.NoError(t, err)
+
+	job := f.job(t, 1, response.ID)
+	assert.Equal(t, entity.ImportStatusFailed, job.Status)
+	assert.Zero(t, job.ImportedRows)
+	assert.Equal(t, 4, job.ProcessedRows)
+	require.Len(t, job.Errors, 4)
+	assert.Equal(t, dto.ImportRowError{Row: 3, Field: "email", Message: "Email is already used in row 2"}, job.Errors[0])
+	assert.Equal(t, dto.ImportRowError{Row: 4, Field: "email", Message: "Email is already used"}, job.Errors[1])
+	assert.Equal(t, 5, job.Errors[2].Row)
+	assert.Equal(t, "password", job.Errors[2].Field)
+	assert.Equal(t, dto.ImportRowError{Row: 5, Field: "roles", Message: "Roles has unknown roles: kepala"}, job.Errors[3])
+	assert.Empty(t, f.users.created)
+	assert.NoError(t, f.mock.ExpectationsWereMet(), "nothing is written when a row fails")
+}
@@ -0,0 +1,46 @@
+package usecase_test
+
+import (
+	"database/sql"
+	"io"
+	"strings"
+	"testing"
+
+	"sistem-06-Backend/internal/domain/entity"
+	"sistem-06-Backend/internal/pkg/password"
+	"sistem-06-Backend/internal/usecase"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestLoginRehashesOutdatedPassword(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("correct-password"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	user := &entity.User{ID: 7, Name: "budi", Email: "budi@example.com", Password: string(hash), UpdatedAt: 1700000000, Version: 3}
+	users := &lockoutUserRepository{users: map[string]*entity.User{"budi@example.com": user}}
+	hasher := password.NewHasher(
+		&password.Argon2id{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+		&password.Bcrypt{Cost: bcrypt.MinCost},
+	)
+	uc := usecase.NewAuthUseCase(&sql.DB{}, log, validator.New(), users, newMemoryLoginAttemptRepository(), newMemoryTwoFactorRepository(), &recordingNotifier{}, hasher, nil, testLoginPolicy, nil, nil)
+
+	assert.Error(t, login(uc, "budi@example.com", "wrong-password"))
+	assert.Equal(t, string(hash), user.Password, "a failed login keeps the hash")
+
+	require.NoError(t, login(uc, "budi@example.com", "correct-password"))
+	assert.True(t, strings.HasPrefix(user.Password, "$argon2id$"))
+	assert.Equal(t, int64(1700000000), user.UpdatedAt)
+	assert.Equal(t, 3, user.Version, "a rehash does not change the ETag")
+
+	upgraded := user.Password
+	require.NoError(t, login(uc, "budi@example.com", "correct-password"))
+	assert.Equal(t, upgraded, user.Password, "an up to date hash is kept")
+}
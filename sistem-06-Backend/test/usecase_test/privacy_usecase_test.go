@@ -0,0 +1,228 @@
+package usecase_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"sistem-06-Backend/internal/domain/entity"
+	domain "sistem-06-Backend/internal/domain/ports"
+	"sistem-06-Backend/internal/dto"
+	"sistem-06-Backend/internal/usecase"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type memoryDeletionRequestRepository struct {
+	requests map[int]*entity.DeletionRequest
+}
+
+func (r *memoryDeletionRequestRepository) WithTx(tx *sql.Tx) domain.DeletionRequestRepository {
+	return r
+}
+
+func (r *memoryDeletionRequestRepository) Create(ctx context.Context, request *entity.DeletionRequest) error {
+	copied := *request
+	r.requests[request.UserID] = &copied
+	return nil
+}
+
+func (r *memoryDeletionRequestRepository) FindByUserID(ctx context.Context, userID int) (*entity.DeletionRequest, error) {
+	if request, ok := r.requests[userID]; ok {
+		return request, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *memoryDeletionRequestRepository) Cancel(ctx context.Context, userID int) (bool, error) {
+	request, ok := r.requests[userID]
+	if !ok || !request.Pending() {
+		return false, nil
+	}
+	delete(r.requests, userID)
+	return true, nil
+}
+
+func (r *memoryDeletionRequestRepository) ListDue(ctx context.Context, now int64, limit int) ([]*entity.DeletionRequest, error) {
+	var due []*entity.DeletionRequest
+	for _, request := range r.requests {
+		if request.Pending() && request.ScheduledAt <= now && len(due) < limit {
+			due = append(due, request)
+		}
+	}
+	return due, nil
+}
+
+func (r *memoryDeletionRequestRepository) Complete(ctx context.Context, userID int, completedAt int64) (bool, error) {
+	request, ok := r.requests[userID]
+	if !ok || !request.Pending() {
+		return false, nil
+	}
+	request.CompletedAt = completedAt
+	return true, nil
+}
+
+type privacyFixture struct {
+	uc        *usecase.PrivacyUseCase
+	users     *lockoutUserRepository
+	deletions *memoryDeletionRequestRepository
+	mock      sqlmock.Sqlmock
+	// names of the sources in the order they were erased
+	erased []string
+}
+
+func newPrivacyUseCase(t *testing.T, grace time.Duration) *privacyFixture {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("Lama-rahasia-42"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	f := &privacyFixture{
+		users: &lockoutUserRepository{users: map[string]*entity.User{
+			"budi@example.com": {ID: 7, Name: "budi", Email: "budi@example.com", Password: string(hash), CreatedAt: 100, Version: 1},
+		}},
+		deletions: &memoryDeletionRequestRepository{requests: map[int]*entity.DeletionRequest{}},
+		mock:      mock,
+	}
+	f.uc = usecase.NewPrivacyUseCase(db, log, validator.New(), f.users, f.deletions, testHasher, nil, grace)
+
+	user := usecase.UserDataSource(f.users)
+	erase := user.Erase
+	user.Erase = func(ctx context.Context, tx *sql.Tx, userID int) error {
+		f.erased = append(f.erased, "user")
+		return erase(ctx, tx, userID)
+	}
+	f.uc.Register("user", user)
+	f.uc.Register("notes", usecase.PersonalDataSource{
+		Export: func(ctx context.Context, userID int) (any, error) {
+			return []string{"catatan warga"}, nil
+		},
+		Erase: func(ctx context.Context, tx *sql.Tx, userID int) error {
+			f.erased = append(f.erased, "notes")
+			return nil
+		},
+	})
+	// export only, kept on erasure
+	f.uc.Register("addresses", usecase.PersonalDataSource{
+		Export: func(ctx context.Context, userID int) (any, error) {
+			return []*dto.AddressEntity{{ID: 1, Jalan: "Jl. Melati 5"}}, nil
+		},
+	})
+	return f
+}
+
+func TestPersonalDataExportBundlesRegisteredSources(t *testing.T) {
+	f := newPrivacyUseCase(t, time.Hour)
+
+	f.mock.ExpectBegin()
+	f.mock.ExpectCommit()
+	file, err := f.uc.Export(context.Background(), 7, "")
+	require.NoError(t, err)
+	assert.Equal(t, fiber.MIMEApplicationJSONCharsetUTF8, file.ContentType)
+
+	var out bytes.Buffer
+	require.NoError(t, file.Write(&out))
+	var exported struct {
+		UserID   int                        `json:"user_id"`
+		Sections []string                   `json:"sections"`
+		Data     map[string]json.RawMessage `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &exported))
+	assert.Equal(t, 7, exported.UserID)
+	assert.Equal(t, []string{"user", "notes", "addresses"}, exported.Sections)
+	var user dto.PersonalUserData
+	require.NoError(t, json.Unmarshal(exported.Data["user"], &user))
+	assert.Equal(t, "budi@example.com", user.Email)
+	assert.NotContains(t, out.String(), "$2a$", "the password hash is not exported")
+	assert.Less(t, bytes.Index(out.Bytes(), []byte(`"user":`)), bytes.Index(out.Bytes(), []byte(`"notes":`)))
+
+	f.mock.ExpectBegin()
+	f.mock.ExpectCommit()
+	file, err = f.uc.Export(context.Background(), 7, "zip")
+	require.NoError(t, err)
+	out.Reset()
+	require.NoError(t, file.Write(&out))
+	archive, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range archive.File {
+		names = append(names, entry.Name)
+	}
+	assert.Equal(t, []string{"manifest.json", "user.json", "notes.json", "addresses.json"}, names)
+	assert.NoError(t, f.mock.ExpectationsWereMet())
+
+	_, err = f.uc.Export(context.Background(), 7, "csv")
+	assertStatus(t, err, fiber.StatusBadRequest)
+}
+
+func TestDeletionRequestNeedsPasswordAndIsCancellable(t *testing.T) {
+	f := newPrivacyUseCase(t, time.Hour)
+	ctx := context.Background()
+
+	_, err := f.uc.RequestDeletion(ctx, 7, &dto.DeleteAccountRequest{Password: "salah-sandi"})
+	assertStatus(t, err, fiber.StatusBadRequest)
+
+	f.mock.ExpectBegin()
+	f.mock.ExpectCommit()
+	response, err := f.uc.RequestDeletion(ctx, 7, &dto.DeleteAccountRequest{Password: "Lama-rahasia-42"})
+	require.NoError(t, err)
+	assert.Equal(t, response.RequestedAt+3600, response.ScheduledAt)
+
+	_, err = f.uc.RequestDeletion(ctx, 7, &dto.DeleteAccountRequest{Password: "Lama-rahasia-42"})
+	assertStatus(t, err, fiber.StatusConflict)
+
+	// not due yet
+	require.NoError(t, f.uc.ProcessDeletions(ctx))
+	assert.Empty(t, f.erased)
+
+	f.mock.ExpectBegin()
+	f.mock.ExpectCommit()
+	require.NoError(t, f.uc.CancelDeletion(ctx, 7))
+	_, err = f.uc.GetDeletion(ctx, 7)
+	assertStatus(t, err, fiber.StatusNotFound)
+	assert.NoError(t, f.mock.ExpectationsWereMet())
+}
+
+func TestProcessDeletionsAnonymizesTheUserLast(t *testing.T) {
+	f := newPrivacyUseCase(t, 0)
+	ctx := context.Background()
+
+	f.mock.ExpectBegin()
+	f.mock.ExpectCommit()
+	_, err := f.uc.RequestDeletion(ctx, 7, &dto.DeleteAccountRequest{Password: "Lama-rahasia-42"})
+	require.NoError(t, err)
+
+	f.mock.ExpectBegin()
+	f.mock.ExpectCommit()
+	require.NoError(t, f.uc.ProcessDeletions(ctx))
+	assert.Equal(t, []string{"notes", "user"}, f.erased)
+
+	user := f.users.users["deleted-user-7"]
+	require.NotNil(t, user, "the row is kept under a placeholder")
+	assert.Equal(t, 7, user.ID)
+	assert.Empty(t, user.Password)
+	assert.NotZero(t, user.DeletedAt)
+	assert.NotContains(t, f.users.users, "budi@example.com")
+	assert.False(t, f.deletions.requests[7].Pending())
+
+	// a completed request is not carried out again
+	require.NoError(t, f.uc.ProcessDeletions(ctx))
+	assert.Len(t, f.erased, 2)
+	assert.NoError(t, f.mock.ExpectationsWereMet())
+}
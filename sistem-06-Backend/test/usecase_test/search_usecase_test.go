@@ -0,0 +1,99 @@
+package usecase_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"sistem-06-Backend/internal/domain/entity"
+	"sistem-06-Backend/internal/dto"
+	"sistem-06-Backend/internal/usecase"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSearchRepository returns fixed matches and remembers the queries
+// it was asked.
+type recordingSearchRepository struct {
+	userQueries    []entity.SearchQuery
+	addressQueries []entity.SearchQuery
+}
+
+func (r *recordingSearchRepository) Users(ctx context.Context, query entity.SearchQuery) ([]entity.UserMatch, error) {
+	r.userQueries = append(r.userQueries, query)
+	return []entity.UserMatch{{User: &entity.User{ID: 7, Name: "Budi", Email: "budi@example.com"}, Rank: 0.4}}, nil
+}
+
+func (r *recordingSearchRepository) Addresses(ctx context.Context, query entity.SearchQuery) ([]entity.AddressMatch, error) {
+	r.addressQueries = append(r.addressQueries, query)
+	return []entity.AddressMatch{
+		{Address: &entity.Address{ID: 3, Jalan: "Gg. Melati", RT: "03", RW: "05", Kota: "Bandung"}, Rank: 0.9},
+		{Address: &entity.Address{ID: 4, Jalan: "Jl. Mawar", RT: "04", RW: "05", Kota: "Bandung"}, Rank: 0.1},
+	}, nil
+}
+
+func newSearchUseCase() (*usecase.SearchUseCase, *recordingSearchRepository) {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	officer := entity.Role{ID: 1, Name: "officer", Permission: []entity.Permissions{entity.PermissionManageUsers, entity.PermissionManageAddresses}}
+	users := &lockoutUserRepository{users: map[string]*entity.User{
+		"budi@example.com":  {ID: 7, Name: "Budi", Email: "budi@example.com"},
+		"admin@example.com": {ID: 1, Name: "admin", Email: "admin@example.com", Roles: []entity.Role{officer}},
+	}}
+	search := &recordingSearchRepository{}
+	return usecase.NewSearchUseCase(log, validator.New(), users, search), search
+}
+
+func TestSearchMergesResultsByRank(t *testing.T) {
+	uc, search := newSearchUseCase()
+
+	results, err := uc.Search(context.Background(), 1, nil, &dto.SearchRequest{Query: "Budi, Gg. Melati RT 03", Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, entity.SearchTypeAddress, results[0].Type)
+	assert.Equal(t, "Gg. Melati RT 03/RW 05, Bandung", results[0].Label)
+	assert.Equal(t, entity.SearchTypeUser, results[1].Type)
+	assert.Equal(t, 0, search.userQueries[0].UserID, "officers search every user")
+}
+
+func TestSearchLimitsOrdinaryUsersToThemselves(t *testing.T) {
+	uc, search := newSearchUseCase()
+
+	results, err := uc.Search(context.Background(), 7, nil, &dto.SearchRequest{Query: "budi", Limit: 20})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, 7, search.userQueries[0].UserID)
+	assert.Empty(t, search.addressQueries)
+
+	// a token without the scopes searches like an ordinary user
+	token := &entity.PersonalAccessToken{Scopes: []entity.Permissions{entity.PermissionViewAuditLog}}
+	_, err = uc.Search(context.Background(), 1, token, &dto.SearchRequest{Query: "budi", Limit: 20})
+	require.NoError(t, err)
+	assert.Equal(t, 1, search.userQueries[1].UserID)
+	assert.Empty(t, search.addressQueries)
+
+	_, err = uc.Search(context.Background(), 7, nil, &dto.SearchRequest{Query: "budi", Type: "role", Limit: 20})
+	assertStatus(t, err, fiber.StatusBadRequest)
+}
+
+func TestSearchMasksEmailsWithoutUserManagement(t *testing.T) {
+	uc, _ := newSearchUseCase()
+
+	token := &entity.PersonalAccessToken{Scopes: []entity.Permissions{entity.PermissionManageAddresses}}
+	results, err := uc.Search(context.Background(), 1, token, &dto.SearchRequest{Query: "budi", Type: entity.SearchTypeUser, Limit: 20})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "Budi <b***@example.com>", results[0].Label)
+	assert.Equal(t, "b***@example.com", results[0].Data.(*dto.UserResponse).Email)
+
+	// users see their own email
+	results, err = uc.Search(context.Background(), 7, nil, &dto.SearchRequest{Query: "budi", Limit: 20})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "Budi <budi@example.com>", results[0].Label)
+}
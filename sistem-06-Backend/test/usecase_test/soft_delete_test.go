@@ -0,0 +1,113 @@
+package usecase_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"sistem-06-Backend/internal/domain/entity"
+	domain "sistem-06-Backend/internal/domain/ports"
+	"sistem-06-Backend/internal/dto"
+	"sistem-06-Backend/internal/usecase"
+	"sistem-06-Backend/pkg"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// purgeAddressRepository and purgeRolesRepository only record the cutoff the
+// purge job passes them.
+type purgeAddressRepository struct {
+	domain.AddressRepository
+	deletedBefore int64
+}
+
+func (r *purgeAddressRepository) PurgeDeleted(ctx context.Context, deletedBefore int64) (int64, error) {
+	r.deletedBefore = deletedBefore
+	return 0, nil
+}
+
+type purgeRolesRepository struct {
+	domain.RolesRepository
+	deletedBefore int64
+}
+
+func (r *purgeRolesRepository) PurgeDeleted(ctx context.Context, deletedBefore int64) (int64, error) {
+	r.deletedBefore = deletedBefore
+	return 0, nil
+}
+
+func TestUserDeleteAndRestore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	users := &lockoutUserRepository{users: map[string]*entity.User{
+		"budi@example.com": {ID: 7, Name: "budi", Email: "budi@example.com", Version: 3},
+	}}
+	uc := usecase.NewUserUseCase(db, log, validator.New(), users, nil, testHasher, nil, nil)
+	ctx := context.Background()
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	err = uc.Delete(ctx, 7, 2)
+	var conflict *pkg.VersionConflict
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, 3, conflict.Current.(*dto.UserResponse).Version)
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	require.NoError(t, uc.Delete(ctx, 7, 3))
+	_, err = users.FindByEmail(ctx, "budi@example.com")
+	assert.Error(t, err, "deleted users are hidden from lookups")
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	assertStatus(t, uc.Delete(ctx, 7, 4), fiber.StatusNotFound)
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	response, err := uc.Restore(ctx, 7)
+	require.NoError(t, err)
+	assert.Equal(t, "budi@example.com", response.Email)
+	assert.Equal(t, 5, response.Version)
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	_, err = uc.Restore(ctx, 7)
+	assertStatus(t, err, fiber.StatusNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRetentionPurgesOnlyExpiredRows(t *testing.T) {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	now := time.Now()
+	users := &lockoutUserRepository{users: map[string]*entity.User{
+		"lama@example.com":  {ID: 1, Email: "lama@example.com", DeletedAt: now.Add(-40 * 24 * time.Hour).Unix()},
+		"baru@example.com":  {ID: 2, Email: "baru@example.com", DeletedAt: now.Add(-time.Hour).Unix()},
+		"aktif@example.com": {ID: 3, Email: "aktif@example.com"},
+	}}
+	addresses := &purgeAddressRepository{}
+	roles := &purgeRolesRepository{}
+
+	uc := usecase.NewRetentionUseCase(log, users, addresses, roles, 30*24*time.Hour)
+	require.NoError(t, uc.PurgeDeleted(context.Background()))
+
+	assert.NotContains(t, users.users, "lama@example.com")
+	assert.Contains(t, users.users, "baru@example.com")
+	assert.Contains(t, users.users, "aktif@example.com")
+
+	cutoff := now.Add(-30 * 24 * time.Hour).Unix()
+	assert.InDelta(t, cutoff, addresses.deletedBefore, 5)
+	assert.InDelta(t, cutoff, roles.deletedBefore, 5)
+}
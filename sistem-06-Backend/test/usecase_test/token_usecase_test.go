@@ -0,0 +1,238 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"sistem-06-Backend/internal/domain/entity"
+	domain "sistem-06-Backend/internal/domain/ports"
+	"sistem-06-Backend/internal/dto"
+	"sistem-06-Backend/internal/usecase"
+	"sistem-06-Backend/pkg"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryTokenRepository struct {
+	mu     sync.Mutex
+	nextID int
+	tokens map[int]*entity.PersonalAccessToken
+}
+
+func newMemoryTokenRepository() *memoryTokenRepository {
+	return &memoryTokenRepository{tokens: map[int]*entity.PersonalAccessToken{}}
+}
+
+func (r *memoryTokenRepository) WithTx(tx *sql.Tx) domain.TokenRepository { return r }
+
+func (r *memoryTokenRepository) CreateToken(ctx context.Context, token *entity.PersonalAccessToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	token.ID = r.nextID
+	copied := *token
+	r.tokens[token.ID] = &copied
+	return nil
+}
+
+func (r *memoryTokenRepository) FindTokenByHash(ctx context.Context, hash string) (*entity.PersonalAccessToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, token := range r.tokens {
+		if token.TokenHash == hash {
+			copied := *token
+			return &copied, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *memoryTokenRepository) ListTokensByUserID(ctx context.Context, userID int) ([]*entity.PersonalAccessToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var tokens []*entity.PersonalAccessToken
+	for id := 1; id <= r.nextID; id++ {
+		if token, ok := r.tokens[id]; ok && token.UserID == userID && token.RevokedAt == 0 {
+			copied := *token
+			tokens = append(tokens, &copied)
+		}
+	}
+	return tokens, nil
+}
+
+func (r *memoryTokenRepository) TouchToken(ctx context.Context, id int, usedAt int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokens[id].LastUsedAt = usedAt
+	return nil
+}
+
+func (r *memoryTokenRepository) RevokeToken(ctx context.Context, id int, userID int, revokedAt int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	token, ok := r.tokens[id]
+	if !ok || token.UserID != userID || token.RevokedAt != 0 {
+		return false, nil
+	}
+	token.RevokedAt = revokedAt
+	return true, nil
+}
+
+func (r *memoryTokenRepository) DeleteTokensByUserID(ctx context.Context, userID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, token := range r.tokens {
+		if token.UserID == userID {
+			delete(r.tokens, id)
+		}
+	}
+	return nil
+}
+
+func newTokenUseCase(t *testing.T) (*usecase.TokenUseCase, *memoryTokenRepository, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	users := &lockoutUserRepository{users: map[string]*entity.User{
+		"bendahara@example.com": {ID: 7, Name: "bendahara", Email: "bendahara@example.com", Roles: []entity.Role{
+			{ID: 1, Name: "admin", Permission: []entity.Permissions{entity.PermissionManageUsers}},
+		}},
+		"warga@example.com": {ID: 8, Name: "warga", Email: "warga@example.com"},
+	}}
+	tokens := newMemoryTokenRepository()
+	return usecase.NewTokenUseCase(db, log, validator.New(), users, tokens, nil, 30*24*time.Hour, 90*24*time.Hour), tokens, mock
+}
+
+func assertStatus(t *testing.T, err error, status int) {
+	t.Helper()
+	var fiberErr *fiber.Error
+	require.True(t, errors.As(err, &fiberErr), "expected a fiber error, got %v", err)
+	assert.Equal(t, status, fiberErr.Code)
+}
+
+func TestCreateTokenStoresOnlyHash(t *testing.T) {
+	uc, tokens, mock := newTokenUseCase(t)
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	response, err := uc.Create(context.Background(), 7, &dto.CreateTokenRequest{
+		Name:   "laporan kas",
+		Scopes: []string{"users.manage", "users.manage"},
+	})
+	require.NoError(t, err)
+	assert.Len(t, response.Token, 64)
+	assert.Equal(t, []string{"users.manage"}, response.Scopes)
+	assert.InDelta(t, time.Now().Add(30*24*time.Hour).Unix(), response.ExpiresAt, 5)
+
+	stored := tokens.tokens[response.ID]
+	assert.Equal(t, pkg.HashToken(response.Token), stored.TokenHash)
+	assert.NotEqual(t, response.Token, stored.TokenHash)
+}
+
+func TestCreateTokenRejectsScopeUserLacks(t *testing.T) {
+	uc, _, _ := newTokenUseCase(t)
+
+	_, err := uc.Create(context.Background(), 8, &dto.CreateTokenRequest{
+		Name:   "script",
+		Scopes: []string{"users.manage"},
+	})
+	assertStatus(t, err, fiber.StatusBadRequest)
+	assert.Contains(t, err.Error(), "Scopes")
+}
+
+func TestCreateTokenRejectsLifetimeAboveMax(t *testing.T) {
+	uc, _, _ := newTokenUseCase(t)
+
+	_, err := uc.Create(context.Background(), 7, &dto.CreateTokenRequest{Name: "script", ExpiresInDays: 91})
+	assertStatus(t, err, fiber.StatusBadRequest)
+	assert.Contains(t, err.Error(), "ExpiresInDays")
+
+	_, err = uc.Create(context.Background(), 7, &dto.CreateTokenRequest{Name: ""})
+	assertStatus(t, err, fiber.StatusBadRequest)
+}
+
+func TestAuthenticateToken(t *testing.T) {
+	uc, tokens, mock := newTokenUseCase(t)
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	created, err := uc.Create(context.Background(), 7, &dto.CreateTokenRequest{Name: "script"})
+	require.NoError(t, err)
+
+	token, user, err := uc.Authenticate(context.Background(), created.Token)
+	require.NoError(t, err)
+	assert.Equal(t, 7, user.ID)
+	assert.Equal(t, created.ID, token.ID)
+	assert.NotZero(t, tokens.tokens[created.ID].LastUsedAt)
+
+	_, _, err = uc.Authenticate(context.Background(), pkg.GenerateToken())
+	assert.Equal(t, fiber.ErrUnauthorized, err)
+
+	tokens.tokens[created.ID].ExpiresAt = time.Now().Add(-time.Second).Unix()
+	_, _, err = uc.Authenticate(context.Background(), created.Token)
+	assert.Equal(t, fiber.ErrUnauthorized, err)
+}
+
+func TestAuthenticateRejectsTokensCreatedBeforeSessionsRevoked(t *testing.T) {
+	uc, tokens, mock := newTokenUseCase(t)
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	created, err := uc.Create(context.Background(), 7, &dto.CreateTokenRequest{Name: "script"})
+	require.NoError(t, err)
+	tokens.tokens[created.ID].CreatedAt -= 60
+
+	// e.g. a password change with revoke_other_sessions
+	require.NoError(t, uc.UserRepository.RevokeSessions(context.Background(), 7, time.Now().Unix()))
+
+	_, _, err = uc.Authenticate(context.Background(), created.Token)
+	assert.Equal(t, fiber.ErrUnauthorized, err)
+
+	// tokens created afterwards keep working
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	renewed, err := uc.Create(context.Background(), 7, &dto.CreateTokenRequest{Name: "script"})
+	require.NoError(t, err)
+	_, user, err := uc.Authenticate(context.Background(), renewed.Token)
+	require.NoError(t, err)
+	assert.Equal(t, 7, user.ID)
+}
+
+func TestRevokeToken(t *testing.T) {
+	uc, _, mock := newTokenUseCase(t)
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	created, err := uc.Create(context.Background(), 7, &dto.CreateTokenRequest{Name: "script"})
+	require.NoError(t, err)
+
+	// another user cannot revoke it
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	assertStatus(t, uc.Revoke(context.Background(), 8, created.ID), fiber.StatusNotFound)
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	require.NoError(t, uc.Revoke(context.Background(), 7, created.ID))
+	assert.NoError(t, mock.ExpectationsWereMet())
+	_, _, err = uc.Authenticate(context.Background(), created.Token)
+	assert.Equal(t, fiber.ErrUnauthorized, err)
+
+	list, err := uc.List(context.Background(), 7)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
@@ -0,0 +1,351 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"sistem-06-Backend/internal/domain/entity"
+	domain "sistem-06-Backend/internal/domain/ports"
+	"sistem-06-Backend/internal/dto"
+	"sistem-06-Backend/internal/pkg/totp"
+	"sistem-06-Backend/internal/usecase"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type memoryTwoFactorRepository struct {
+	mu            sync.Mutex
+	enrollments   map[int]*entity.TwoFactor
+	recoveryCodes map[int]map[string]bool
+}
+
+func newMemoryTwoFactorRepository() *memoryTwoFactorRepository {
+	return &memoryTwoFactorRepository{
+		enrollments:   map[int]*entity.TwoFactor{},
+		recoveryCodes: map[int]map[string]bool{},
+	}
+}
+
+func (r *memoryTwoFactorRepository) WithTx(tx *sql.Tx) domain.TwoFactorRepository { return r }
+
+func (r *memoryTwoFactorRepository) Find(ctx context.Context, userID int) (*entity.TwoFactor, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	twoFactor, ok := r.enrollments[userID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *twoFactor
+	return &copied, nil
+}
+
+func (r *memoryTwoFactorRepository) SavePending(ctx context.Context, twoFactor *entity.TwoFactor) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.enrollments[twoFactor.UserID]; ok && existing.Enabled {
+		return false, nil
+	}
+	copied := *twoFactor
+	r.enrollments[twoFactor.UserID] = &copied
+	return true, nil
+}
+
+func (r *memoryTwoFactorRepository) Enable(ctx context.Context, userID int, enabledAt int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	twoFactor, ok := r.enrollments[userID]
+	if !ok || twoFactor.Enabled {
+		return false, nil
+	}
+	twoFactor.Enabled = true
+	twoFactor.EnabledAt = enabledAt
+	return true, nil
+}
+
+func (r *memoryTwoFactorRepository) MarkStepUsed(ctx context.Context, userID int, step int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	twoFactor, ok := r.enrollments[userID]
+	if !ok || twoFactor.LastUsedStep >= step {
+		return false, nil
+	}
+	twoFactor.LastUsedStep = step
+	return true, nil
+}
+
+func (r *memoryTwoFactorRepository) Delete(ctx context.Context, userID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.enrollments, userID)
+	delete(r.recoveryCodes, userID)
+	return nil
+}
+
+func (r *memoryTwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID int, hashes []string, createdAt int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	codes := map[string]bool{}
+	for _, hash := range hashes {
+		codes[hash] = false
+	}
+	r.recoveryCodes[userID] = codes
+	return nil
+}
+
+func (r *memoryTwoFactorRepository) UseRecoveryCode(ctx context.Context, userID int, hash string, usedAt int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	used, ok := r.recoveryCodes[userID][hash]
+	if !ok || used {
+		return false, nil
+	}
+	r.recoveryCodes[userID][hash] = true
+	return true, nil
+}
+
+type twoFactorFixture struct {
+	auth      *usecase.AuthUseCase
+	twoFactor *usecase.TwoFactorUseCase
+	repo      *memoryTwoFactorRepository
+	mock      sqlmock.Sqlmock
+}
+
+func newTwoFactorFixture(t *testing.T, roles ...entity.Role) *twoFactorFixture {
+	hash, err := bcrypt.GenerateFromPassword([]byte("correct-password"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	validate := validator.New()
+
+	users := &lockoutUserRepository{users: map[string]*entity.User{
+		"siti@example.com": {ID: 3, Name: "siti", Email: "siti@example.com", Password: string(hash), Roles: roles},
+	}}
+	repo := newMemoryTwoFactorRepository()
+	notifier := &recordingNotifier{locked: make(chan *entity.User, 4)}
+
+	auth := usecase.NewAuthUseCase(db, log, validate, users, newMemoryLoginAttemptRepository(), repo, notifier, testHasher, nil, testLoginPolicy, nil, nil)
+	return &twoFactorFixture{
+		auth:      auth,
+		twoFactor: usecase.NewTwoFactorUseCase(db, log, validate, users, repo, testHasher, auth, nil, "Sistem06"),
+		repo:      repo,
+		mock:      mock,
+	}
+}
+
+// enroll runs the enrollment flow and returns the secret and recovery codes.
+// The confirming code uses the previous time step so the test can still log
+// in with the current one.
+func (f *twoFactorFixture) enroll(t *testing.T) (string, []string) {
+	enrollment, err := f.twoFactor.Enroll(context.Background(), 3)
+	require.NoError(t, err)
+	assert.Contains(t, enrollment.ProvisioningURI, "otpauth://totp/Sistem06:siti@example.com")
+
+	code, err := totp.Code(enrollment.Secret, totp.Step(time.Now())-1)
+	require.NoError(t, err)
+
+	f.mock.ExpectBegin()
+	f.mock.ExpectCommit()
+	recovery, err := f.twoFactor.Enable(context.Background(), 3, &dto.TwoFactorCodeRequest{Code: code})
+	require.NoError(t, err)
+	require.Len(t, recovery.RecoveryCodes, 10)
+	return enrollment.Secret, recovery.RecoveryCodes
+}
+
+func (f *twoFactorFixture) verify(code string) (*dto.UserResponse, error) {
+	return f.auth.VerifyTwoFactor(context.Background(), &dto.TwoFactorLoginRequest{
+		Code:      code,
+		UserID:    3,
+		IPAddress: "203.0.113.9",
+	})
+}
+
+func TestLoginRequiresSecondFactorWhenEnabled(t *testing.T) {
+	f := newTwoFactorFixture(t)
+
+	response, err := f.auth.Login(context.Background(), &dto.UserLoginRequest{Email: "siti@example.com", Password: "correct-password"})
+	require.NoError(t, err)
+	assert.False(t, response.TwoFactorRequired)
+
+	secret, _ := f.enroll(t)
+
+	response, err = f.auth.Login(context.Background(), &dto.UserLoginRequest{Email: "siti@example.com", Password: "correct-password"})
+	require.NoError(t, err)
+	assert.True(t, response.TwoFactorRequired)
+	assert.Equal(t, 3, response.User.ID)
+
+	code, err := totp.Code(secret, totp.Step(time.Now()))
+	require.NoError(t, err)
+	user, err := f.verify(code)
+	require.NoError(t, err)
+	assert.Equal(t, "siti@example.com", user.Email)
+
+	// the same code cannot be replayed
+	_, err = f.verify(code)
+	assert.Equal(t, fiber.ErrUnauthorized, err)
+}
+
+func TestVerifyTwoFactorAcceptsRecoveryCodeOnce(t *testing.T) {
+	f := newTwoFactorFixture(t)
+	_, recoveryCodes := f.enroll(t)
+
+	_, err := f.verify(recoveryCodes[0])
+	require.NoError(t, err)
+
+	_, err = f.verify(recoveryCodes[0])
+	assert.Equal(t, fiber.ErrUnauthorized, err)
+}
+
+func TestVerifyTwoFactorWrongCodesLockAccount(t *testing.T) {
+	f := newTwoFactorFixture(t)
+	f.enroll(t)
+
+	for i := 0; i < testLoginPolicy.AccountThreshold; i++ {
+		_, err := f.verify("000000")
+		assert.Equal(t, fiber.ErrUnauthorized, err)
+	}
+
+	_, err := f.verify("000000")
+	var throttled *usecase.LoginThrottledError
+	assert.True(t, errors.As(err, &throttled))
+}
+
+func TestRolePolicyRequiresEnrollment(t *testing.T) {
+	f := newTwoFactorFixture(t, entity.Role{ID: 1, Name: "bendahara", RequireTwoFactor: true})
+
+	response, err := f.auth.Login(context.Background(), &dto.UserLoginRequest{Email: "siti@example.com", Password: "correct-password"})
+	require.NoError(t, err)
+	assert.False(t, response.TwoFactorRequired)
+	assert.True(t, response.TwoFactorEnrollmentRequired)
+
+	secret, _ := f.enroll(t)
+	code, err := totp.Code(secret, totp.Step(time.Now()))
+	require.NoError(t, err)
+
+	err = f.twoFactor.Disable(context.Background(), 3, &dto.TwoFactorDisableRequest{Password: "correct-password", Code: code})
+	var fiberErr *fiber.Error
+	require.True(t, errors.As(err, &fiberErr))
+	assert.Equal(t, fiber.StatusForbidden, fiberErr.Code)
+}
+
+func TestDisableTwoFactor(t *testing.T) {
+	f := newTwoFactorFixture(t)
+	secret, _ := f.enroll(t)
+	code, err := totp.Code(secret, totp.Step(time.Now()))
+	require.NoError(t, err)
+
+	err = f.twoFactor.Disable(context.Background(), 3, &dto.TwoFactorDisableRequest{Password: "wrong-password", Code: code})
+	var fiberErr *fiber.Error
+	require.True(t, errors.As(err, &fiberErr))
+	assert.Equal(t, fiber.StatusUnauthorized, fiberErr.Code)
+
+	f.mock.ExpectBegin()
+	f.mock.ExpectCommit()
+	require.NoError(t, f.twoFactor.Disable(context.Background(), 3, &dto.TwoFactorDisableRequest{Password: "correct-password", Code: code}))
+
+	_, err = f.repo.Find(context.Background(), 3)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, f.mock.ExpectationsWereMet())
+}
+
+func TestDisableTwoFactorWrongAnswersLockAccount(t *testing.T) {
+	f := newTwoFactorFixture(t)
+	secret, _ := f.enroll(t)
+	code, err := totp.Code(secret, totp.Step(time.Now()))
+	require.NoError(t, err)
+
+	for i := 0; i < testLoginPolicy.AccountThreshold; i++ {
+		err := f.twoFactor.Disable(context.Background(), 3, &dto.TwoFactorDisableRequest{Password: "wrong-password", Code: code, IPAddress: "203.0.113.9"})
+		var fiberErr *fiber.Error
+		require.True(t, errors.As(err, &fiberErr))
+		assert.Equal(t, fiber.StatusUnauthorized, fiberErr.Code)
+	}
+
+	// the right answers are refused too while the account is locked
+	err = f.twoFactor.Disable(context.Background(), 3, &dto.TwoFactorDisableRequest{Password: "correct-password", Code: code, IPAddress: "203.0.113.9"})
+	var throttled *usecase.LoginThrottledError
+	require.True(t, errors.As(err, &throttled))
+
+	// and so is a login
+	_, err = f.auth.Login(context.Background(), &dto.UserLoginRequest{Email: "siti@example.com", Password: "correct-password"})
+	assert.True(t, errors.As(err, &throttled))
+
+	_, err = f.repo.Find(context.Background(), 3)
+	assert.NoError(t, err, "2FA is still enabled")
+}
+
+func TestEnableTwoFactorWrongCodesLockAccount(t *testing.T) {
+	f := newTwoFactorFixture(t)
+	enrollment, err := f.twoFactor.Enroll(context.Background(), 3)
+	require.NoError(t, err)
+
+	for i := 0; i < testLoginPolicy.AccountThreshold; i++ {
+		_, err := f.twoFactor.Enable(context.Background(), 3, &dto.TwoFactorCodeRequest{Code: "000000", IPAddress: "203.0.113.9"})
+		var fiberErr *fiber.Error
+		require.True(t, errors.As(err, &fiberErr))
+		assert.Equal(t, fiber.StatusBadRequest, fiberErr.Code)
+	}
+
+	// the right code is refused too while the account is locked
+	code, err := totp.Code(enrollment.Secret, totp.Step(time.Now()))
+	require.NoError(t, err)
+	_, err = f.twoFactor.Enable(context.Background(), 3, &dto.TwoFactorCodeRequest{Code: code, IPAddress: "203.0.113.9"})
+	var throttled *usecase.LoginThrottledError
+	require.True(t, errors.As(err, &throttled))
+
+	twoFactor, err := f.repo.Find(context.Background(), 3)
+	require.NoError(t, err)
+	assert.False(t, twoFactor.Enabled)
+}
+
+func TestRegenerateRecoveryCodesWrongCodesLockAccount(t *testing.T) {
+	f := newTwoFactorFixture(t)
+	secret, recoveryCodes := f.enroll(t)
+
+	for i := 0; i < testLoginPolicy.AccountThreshold; i++ {
+		_, err := f.twoFactor.RegenerateRecoveryCodes(context.Background(), 3, &dto.TwoFactorCodeRequest{Code: "000000", IPAddress: "203.0.113.9"})
+		var fiberErr *fiber.Error
+		require.True(t, errors.As(err, &fiberErr))
+		assert.Equal(t, fiber.StatusBadRequest, fiberErr.Code)
+	}
+
+	code, err := totp.Code(secret, totp.Step(time.Now()))
+	require.NoError(t, err)
+	_, err = f.twoFactor.RegenerateRecoveryCodes(context.Background(), 3, &dto.TwoFactorCodeRequest{Code: code, IPAddress: "203.0.113.9"})
+	var throttled *usecase.LoginThrottledError
+	require.True(t, errors.As(err, &throttled))
+
+	// and so is a login
+	_, err = f.auth.Login(context.Background(), &dto.UserLoginRequest{Email: "siti@example.com", Password: "correct-password"})
+	assert.True(t, errors.As(err, &throttled))
+
+	// the old recovery codes were not replaced
+	used, err := f.repo.UseRecoveryCode(context.Background(), 3, totp.HashRecoveryCode(recoveryCodes[0]), time.Now().Unix())
+	require.NoError(t, err)
+	assert.True(t, used)
+}
+
+func TestEnrollRejectsWhenAlreadyEnabled(t *testing.T) {
+	f := newTwoFactorFixture(t)
+	f.enroll(t)
+
+	_, err := f.twoFactor.Enroll(context.Background(), 3)
+	var fiberErr *fiber.Error
+	require.True(t, errors.As(err, &fiberErr))
+	assert.Equal(t, fiber.StatusConflict, fiberErr.Code)
+}
@@ -0,0 +1,36 @@
+package usecase_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"sistem-06-Backend/internal/domain/entity"
+	"sistem-06-Backend/internal/usecase"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserListReturnsPage(t *testing.T) {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	users := &lockoutUserRepository{users: map[string]*entity.User{
+		"budi@example.com": {ID: 7, Name: "budi", Email: "budi@example.com", Password: "hash"},
+		"siti@example.com": {ID: 8, Name: "siti", Email: "siti@example.com", DeletedAt: 1},
+	}}
+	uc := usecase.NewUserUseCase(nil, log, validator.New(), users, nil, testHasher, nil, nil)
+
+	response, err := uc.List(context.Background(), map[string]string{"sort": "-created_at"})
+	require.NoError(t, err)
+	require.Len(t, response.Data, 1)
+	assert.Equal(t, "budi@example.com", response.Data[0].Email)
+	assert.EqualValues(t, 1, response.PageMetadata.TotalItem)
+
+	_, err = uc.List(context.Background(), map[string]string{"page[size]": "banyak"})
+	assertStatus(t, err, fiber.StatusBadRequest)
+}
@@ -0,0 +1,57 @@
+package usecase_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"sistem-06-Backend/internal/domain/entity"
+	"sistem-06-Backend/internal/dto"
+	"sistem-06-Backend/internal/pkg/password"
+	"sistem-06-Backend/internal/usecase"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterRejectsPasswordPolicyViolations(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	list, err := password.LoadBreachedListFile("")
+	require.NoError(t, err)
+	policy := &password.Policy{MinLength: 8, MaxLength: 72, MinCharacterClasses: 2, RejectPersonalInfo: true, Breached: list}
+
+	users := &lockoutUserRepository{users: map[string]*entity.User{}}
+	uc := usecase.NewUserUseCase(db, log, validator.New(), users, policy, testHasher, nil, nil)
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	_, err = uc.Create(context.Background(), &dto.RegisterUserRequest{
+		Name:     "Budi Santoso",
+		Email:    "not-an-email",
+		Password: "budisantoso1",
+	})
+	assertStatus(t, err, fiber.StatusBadRequest)
+	assert.Contains(t, err.Error(), `"Email"`)
+	assert.Contains(t, err.Error(), `"Password": "Password must not contain your email or name"`)
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	_, err = uc.Create(context.Background(), &dto.RegisterUserRequest{
+		Name:     "Siti",
+		Email:    "siti@example.com",
+		Password: "password123",
+	})
+	assertStatus(t, err, fiber.StatusBadRequest)
+	assert.Contains(t, err.Error(), "data breach")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
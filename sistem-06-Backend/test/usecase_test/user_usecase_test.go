@@ -7,16 +7,18 @@ import (
 	"io"
 	"testing"
 
+	"sistem-06-Backend/internal/dto"
+	"sistem-06-Backend/internal/infrastructure/database/repository"
+	"sistem-06-Backend/internal/infrastructure/database/sqlc"
+	"sistem-06-Backend/internal/pkg/password"
+	"sistem-06-Backend/internal/usecase"
+
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/crypto/bcrypt"
-
-	"backend-sistem06.com/internal/model"
-	"backend-sistem06.com/internal/repository"
-	"backend-sistem06.com/internal/usecase"
 )
 
 func setupUserUseCase(t *testing.T) (*usecase.UserUseCase, sqlmock.Sqlmock, func()) {
@@ -29,9 +31,9 @@ func setupUserUseCase(t *testing.T) (*usecase.UserUseCase, sqlmock.Sqlmock, func
 	log.SetOutput(io.Discard) // Suppress log output in tests
 
 	validate := validator.New()
-	userRepo := repository.NewUserRepository(db, log)
+	userRepo := repository.NewUserRepository(sqlc.New(db), log, nil)
 
-	uc := usecase.NewUserUseCase(db, log, validate, userRepo)
+	uc := usecase.NewUserUseCase(db, log, validate, userRepo, &password.Policy{MinLength: 8, MaxLength: 72}, testHasher, nil, nil)
 
 	cleanup := func() {
 		db.Close()
@@ -40,12 +42,18 @@ func setupUserUseCase(t *testing.T) (*usecase.UserUseCase, sqlmock.Sqlmock, func
 	return uc, mock, cleanup
 }
 
+// expectEmailFree expects the lookup of the email ahead of the insert to
+// find nobody.
+func expectEmailFree(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(`FROM users`).WillReturnError(sql.ErrNoRows)
+}
+
 func TestUserUseCase_Create(t *testing.T) {
 	t.Run("should successfully create user", func(t *testing.T) {
 		uc, mock, cleanup := setupUserUseCase(t)
 		defer cleanup()
 
-		request := &model.RegisterUserRequest{
+		request := &dto.RegisterUserRequest{
 			Name:     "John Doe",
 			Email:    "john@example.com",
 			Password: "password123",
@@ -55,8 +63,9 @@ func TestUserUseCase_Create(t *testing.T) {
 		mock.ExpectBegin()
 
 		// Expect INSERT query with RETURNING id
-		mock.ExpectQuery(`INSERT INTO users \(name, email, password, created_at, updated_at\)`).
-			WithArgs(request.Name, request.Email, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		expectEmailFree(mock)
+		mock.ExpectQuery(`INSERT INTO users \(name, email, email_hash, password, created_at, updated_at\)`).
+			WithArgs(request.Name, request.Email, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
 			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 
 		// Expect commit
@@ -81,7 +90,7 @@ func TestUserUseCase_Create(t *testing.T) {
 
 		mock.ExpectBegin().WillReturnError(sql.ErrConnDone)
 
-		request := &model.RegisterUserRequest{
+		request := &dto.RegisterUserRequest{
 			Name:     "John Doe",
 			Email:    "john@example.com",
 			Password: "password123",
@@ -102,7 +111,7 @@ func TestUserUseCase_Create(t *testing.T) {
 		mock.ExpectBegin()
 		mock.ExpectRollback()
 
-		request := &model.RegisterUserRequest{
+		request := &dto.RegisterUserRequest{
 			Name:     "John Doe",
 			Email:    "invalid-email",
 			Password: "password123",
@@ -127,7 +136,7 @@ func TestUserUseCase_Create(t *testing.T) {
 		mock.ExpectBegin()
 		mock.ExpectRollback()
 
-		request := &model.RegisterUserRequest{
+		request := &dto.RegisterUserRequest{
 			Name:     "",
 			Email:    "john@example.com",
 			Password: "password123",
@@ -150,7 +159,7 @@ func TestUserUseCase_Create(t *testing.T) {
 		defer cleanup()
 
 		plainPassword := "password123"
-		request := &model.RegisterUserRequest{
+		request := &dto.RegisterUserRequest{
 			Name:     "John Doe",
 			Email:    "john@example.com",
 			Password: plainPassword,
@@ -159,10 +168,11 @@ func TestUserUseCase_Create(t *testing.T) {
 		mock.ExpectBegin()
 
 		// We can't easily capture the hashed password from sqlmock,
-		// but we can verify that the 3rd argument (password) is not the plain password
+		// but we can verify that the 4th argument (password) is not the plain password
 		// by using a custom matcher or just accept AnyArg
+		expectEmailFree(mock)
 		mock.ExpectQuery(`INSERT INTO users`).
-			WithArgs(request.Name, request.Email, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WithArgs(request.Name, request.Email, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
 			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 
 		mock.ExpectCommit()
@@ -183,7 +193,7 @@ func TestUserUseCase_Create(t *testing.T) {
 		uc, mock, cleanup := setupUserUseCase(t)
 		defer cleanup()
 
-		request := &model.RegisterUserRequest{
+		request := &dto.RegisterUserRequest{
 			Name:     "John Doe",
 			Email:    "john@example.com",
 			Password: "password123",
@@ -192,8 +202,9 @@ func TestUserUseCase_Create(t *testing.T) {
 		mock.ExpectBegin()
 
 		// Simulate duplicate key error
+		expectEmailFree(mock)
 		mock.ExpectQuery(`INSERT INTO users`).
-			WithArgs(request.Name, request.Email, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WithArgs(request.Name, request.Email, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
 			WillReturnError(errors.New("pq: duplicate key value violates unique constraint"))
 
 		mock.ExpectRollback()
@@ -210,11 +221,41 @@ func TestUserUseCase_Create(t *testing.T) {
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
+	t.Run("should return conflict error for an email the unique index misses", func(t *testing.T) {
+		uc, mock, cleanup := setupUserUseCase(t)
+		defer cleanup()
+
+		request := &dto.RegisterUserRequest{
+			Name:     "John Doe",
+			Email:    "john@example.com",
+			Password: "password123",
+		}
+
+		mock.ExpectBegin()
+
+		// an account whose email_hash encryption rotate has not keyed yet,
+		// found by its email in the clear
+		mock.ExpectQuery(`FROM users`).
+			WithArgs(sqlmock.AnyArg(), request.Email).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "password", "created_at", "updated_at", "sessions_revoked_at", "deleted_at", "version"}).
+				AddRow(1, "Legacy", request.Email, "hash", 1, 1, 0, 0, 1))
+
+		mock.ExpectRollback()
+
+		result, err := uc.Create(context.Background(), request)
+
+		assert.Nil(t, result)
+		fiberErr, ok := err.(*fiber.Error)
+		assert.True(t, ok)
+		assert.Equal(t, fiber.StatusConflict, fiberErr.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
 	t.Run("should rollback transaction on database insert error", func(t *testing.T) {
 		uc, mock, cleanup := setupUserUseCase(t)
 		defer cleanup()
 
-		request := &model.RegisterUserRequest{
+		request := &dto.RegisterUserRequest{
 			Name:     "John Doe",
 			Email:    "john@example.com",
 			Password: "password123",
@@ -222,8 +263,9 @@ func TestUserUseCase_Create(t *testing.T) {
 
 		mock.ExpectBegin()
 
+		expectEmailFree(mock)
 		mock.ExpectQuery(`INSERT INTO users`).
-			WithArgs(request.Name, request.Email, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WithArgs(request.Name, request.Email, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
 			WillReturnError(errors.New("database connection lost"))
 
 		mock.ExpectRollback()
@@ -240,7 +282,7 @@ func TestUserUseCase_Create(t *testing.T) {
 		uc, mock, cleanup := setupUserUseCase(t)
 		defer cleanup()
 
-		request := &model.RegisterUserRequest{
+		request := &dto.RegisterUserRequest{
 			Name:     "John Doe",
 			Email:    "john@example.com",
 			Password: "password123",
@@ -248,8 +290,9 @@ func TestUserUseCase_Create(t *testing.T) {
 
 		mock.ExpectBegin()
 
+		expectEmailFree(mock)
 		mock.ExpectQuery(`INSERT INTO users`).
-			WithArgs(request.Name, request.Email, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WithArgs(request.Name, request.Email, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
 			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 
 		// Commit fails
@@ -270,7 +313,7 @@ func TestUserUseCase_Create(t *testing.T) {
 		// Don't actually cancel the context - just mock the error
 		mock.ExpectBegin().WillReturnError(context.Canceled)
 
-		request := &model.RegisterUserRequest{
+		request := &dto.RegisterUserRequest{
 			Name:     "John Doe",
 			Email:    "john@example.com",
 			Password: "password123",
@@ -295,7 +338,7 @@ func TestUserUseCase_Create(t *testing.T) {
 
 		mock.ExpectBegin().WillReturnError(context.DeadlineExceeded)
 
-		request := &model.RegisterUserRequest{
+		request := &dto.RegisterUserRequest{
 			Name:     "John Doe",
 			Email:    "john@example.com",
 			Password: "password123",
@@ -316,7 +359,7 @@ func TestUserUseCase_Create(t *testing.T) {
 	t.Run("should verify bcrypt password hashing", func(t *testing.T) {
 		// This is a separate test to actually verify bcrypt works
 		plainPassword := "mySecurePassword123"
-		request := &model.RegisterUserRequest{
+		request := &dto.RegisterUserRequest{
 			Name:     "John Doe",
 			Email:    "john@example.com",
 			Password: plainPassword,
@@ -338,7 +381,7 @@ func TestUserUseCase_Create(t *testing.T) {
 		uc, mock, cleanup := setupUserUseCase(t)
 		defer cleanup()
 
-		request := &model.RegisterUserRequest{
+		request := &dto.RegisterUserRequest{
 			Name:     "John Doe",
 			Email:    "john@example.com",
 			Password: "password123",
@@ -347,8 +390,9 @@ func TestUserUseCase_Create(t *testing.T) {
 		mock.ExpectBegin()
 
 		// The repository should use the transaction
+		expectEmailFree(mock)
 		mock.ExpectQuery(`INSERT INTO users`).
-			WithArgs(request.Name, request.Email, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WithArgs(request.Name, request.Email, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
 			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 
 		mock.ExpectCommit()
@@ -365,13 +409,13 @@ func TestUserUseCase_Create(t *testing.T) {
 func TestUserUseCase_Create_ValidationScenarios(t *testing.T) {
 	testCases := []struct {
 		name        string
-		request     *model.RegisterUserRequest
+		request     *dto.RegisterUserRequest
 		expectError bool
 		errorField  string
 	}{
 		{
 			name: "valid request",
-			request: &model.RegisterUserRequest{
+			request: &dto.RegisterUserRequest{
 				Name:     "John Doe",
 				Email:    "john@example.com",
 				Password: "password123",
@@ -380,7 +424,7 @@ func TestUserUseCase_Create_ValidationScenarios(t *testing.T) {
 		},
 		{
 			name: "empty name",
-			request: &model.RegisterUserRequest{
+			request: &dto.RegisterUserRequest{
 				Name:     "",
 				Email:    "john@example.com",
 				Password: "password123",
@@ -390,7 +434,7 @@ func TestUserUseCase_Create_ValidationScenarios(t *testing.T) {
 		},
 		{
 			name: "invalid email format",
-			request: &model.RegisterUserRequest{
+			request: &dto.RegisterUserRequest{
 				Name:     "John Doe",
 				Email:    "not-an-email",
 				Password: "password123",
@@ -400,7 +444,7 @@ func TestUserUseCase_Create_ValidationScenarios(t *testing.T) {
 		},
 		{
 			name: "empty email",
-			request: &model.RegisterUserRequest{
+			request: &dto.RegisterUserRequest{
 				Name:     "John Doe",
 				Email:    "",
 				Password: "password123",
@@ -410,7 +454,7 @@ func TestUserUseCase_Create_ValidationScenarios(t *testing.T) {
 		},
 		{
 			name: "empty password",
-			request: &model.RegisterUserRequest{
+			request: &dto.RegisterUserRequest{
 				Name:     "John Doe",
 				Email:    "john@example.com",
 				Password: "",
@@ -420,7 +464,7 @@ func TestUserUseCase_Create_ValidationScenarios(t *testing.T) {
 		},
 		{
 			name: "all fields empty",
-			request: &model.RegisterUserRequest{
+			request: &dto.RegisterUserRequest{
 				Name:     "",
 				Email:    "",
 				Password: "",
@@ -437,8 +481,9 @@ func TestUserUseCase_Create_ValidationScenarios(t *testing.T) {
 			mock.ExpectBegin()
 
 			if !tc.expectError {
+				expectEmailFree(mock)
 				mock.ExpectQuery(`INSERT INTO users`).
-					WithArgs(tc.request.Name, tc.request.Email, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WithArgs(tc.request.Name, tc.request.Email, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
 					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 				mock.ExpectCommit()
 			} else {
@@ -479,10 +524,10 @@ func BenchmarkUserUseCase_Create(b *testing.B) {
 	log.SetOutput(io.Discard)
 
 	validate := validator.New()
-	userRepo := repository.NewUserRepository(db, log)
-	uc := usecase.NewUserUseCase(db, log, validate, userRepo)
+	userRepo := repository.NewUserRepository(sqlc.New(db), log, nil)
+	uc := usecase.NewUserUseCase(db, log, validate, userRepo, &password.Policy{MinLength: 8, MaxLength: 72}, testHasher, nil, nil)
 
-	request := &model.RegisterUserRequest{
+	request := &dto.RegisterUserRequest{
 		Name:     "John Doe",
 		Email:    "john@example.com",
 		Password: "password123",
@@ -491,8 +536,9 @@ func BenchmarkUserUseCase_Create(b *testing.B) {
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		mock.ExpectBegin()
+		expectEmailFree(mock)
 		mock.ExpectQuery(`INSERT INTO users`).
-			WithArgs(request.Name, request.Email, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WithArgs(request.Name, request.Email, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
 			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 		mock.ExpectCommit()
 
@@ -4,7 +4,7 @@ import (
 	"reflect"
 	"testing"
 
-	"backend-sistem06.com/utils"
+	"sistem-06-Backend/internal/pkg/validation"
 )
 
 // Helper function to create a mock FieldLevel for testing
@@ -131,7 +131,7 @@ func TestCustomRtRwCodeValidation(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mock := &mockFieldLevel{value: tt.value}
-			res := utils.CustomRtRwCodeValidation(mock)
+			res := validation.CustomRtRwCodeValidation(mock)
 
 			if res != tt.expected {
 				t.Errorf("Validation failed for '%s': expected %v, got %v",
@@ -180,7 +180,7 @@ func TestCustomPostalCodeValidation(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mock := &mockFieldLevel{value: tt.value}
 
-			result := utils.CustomPostalCodeValidation(mock)
+			result := validation.CustomPostalCodeValidation(mock)
 
 			if result != tt.expected {
 				t.Errorf("Postal code validation failed for '%s': expected %v, got %v",
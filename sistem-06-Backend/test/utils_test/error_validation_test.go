@@ -4,8 +4,8 @@ import (
 	"errors"
 	"testing"
 
-	"backend-sistem06.com/utils" // sesuaikan dengan module path Anda
 	"github.com/go-playground/validator/v10"
+	customErrors "sistem-06-Backend/internal/pkg/errors"
 )
 
 // Test struct untuk simulasi validation
@@ -19,7 +19,7 @@ func TestValidationError(t *testing.T) {
 	validate := validator.New()
 
 	t.Run("should return nil when error is nil", func(t *testing.T) {
-		result := utils.ValidationError(nil)
+		result := customErrors.UserValidationError(nil)
 
 		if result != nil {
 			t.Error("expected nil, got non-nil result")
@@ -30,7 +30,7 @@ func TestValidationError(t *testing.T) {
 		user := TestUser{Email: "", Username: ""}
 		err := validate.Struct(user)
 
-		errors := utils.ValidationError(err)
+		errors := customErrors.UserValidationError(err)
 
 		if errors == nil {
 			t.Fatal("expected errors map, got nil")
@@ -49,7 +49,7 @@ func TestValidationError(t *testing.T) {
 		user := TestUser{Email: "invalid-email", Username: "john"}
 		err := validate.Struct(user)
 
-		errors := utils.ValidationError(err)
+		errors := customErrors.UserValidationError(err)
 
 		if errors == nil {
 			t.Fatal("expected errors map, got nil")
@@ -70,7 +70,7 @@ func TestValidationError(t *testing.T) {
 		user := TestUser{Email: "test@test.com", Username: "ab", Age: 20}
 		err := validate.Struct(user)
 
-		errors := utils.ValidationError(err)
+		errors := customErrors.UserValidationError(err)
 
 		if errors == nil {
 			t.Fatal("expected errors map, got nil")
@@ -95,7 +95,7 @@ func TestValidationError(t *testing.T) {
 		}
 		err := validate.Struct(user)
 
-		errors := utils.ValidationError(err)
+		errors := customErrors.UserValidationError(err)
 
 		if errors == nil {
 			t.Fatal("expected errors map, got nil")
@@ -121,7 +121,7 @@ func TestValidationError(t *testing.T) {
 		custom := CustomStruct{Field: "invalid@#$"}
 		err := validate.Struct(custom)
 
-		errors := utils.ValidationError(err)
+		errors := customErrors.UserValidationError(err)
 
 		if errors == nil {
 			t.Fatal("expected errors map, got nil")
@@ -142,7 +142,7 @@ func TestValidationError(t *testing.T) {
 		user := TestUser{Email: "invalid", Username: "ab"}
 		err := validate.Struct(user)
 
-		errors := utils.ValidationError(err)
+		errors := customErrors.UserValidationError(err)
 
 		if errors == nil {
 			t.Fatal("expected errors map, got nil")
@@ -157,7 +157,7 @@ func TestValidationError(t *testing.T) {
 		// Simulasi error biasa (bukan validator.ValidationErrors)
 		err := errors.New("some generic error")
 
-		result := utils.ValidationError(err)
+		result := customErrors.UserValidationError(err)
 
 		if result != nil {
 			t.Error("expected nil for non-validator error")
@@ -171,7 +171,7 @@ func TestFormatValidationErrors(t *testing.T) {
 			"Email": "Email is required",
 		}
 
-		result := utils.FormatValidationErrors(errs)
+		result := customErrors.UserFormatValidationErrors(errs)
 
 		expected := `{"Email": "Email is required"}`
 		if result != expected {
@@ -185,7 +185,7 @@ func TestFormatValidationErrors(t *testing.T) {
 			"Username": "Username must be at least 3 characters",
 		}
 
-		result := utils.FormatValidationErrors(errs)
+		result := customErrors.UserFormatValidationErrors(errs)
 
 		// Map iteration order is not guaranteed, so check both fields exist
 		if len(result) == 0 {
@@ -216,7 +216,7 @@ func TestFormatValidationErrors(t *testing.T) {
 	t.Run("should handle empty errors map", func(t *testing.T) {
 		errs := map[string]string{}
 
-		result := utils.FormatValidationErrors(errs)
+		result := customErrors.UserFormatValidationErrors(errs)
 
 		expected := "{}"
 		if result != expected {
@@ -229,7 +229,7 @@ func TestFormatValidationErrors(t *testing.T) {
 			"Field": "Error message",
 		}
 
-		result := utils.FormatValidationErrors(errs)
+		result := customErrors.UserFormatValidationErrors(errs)
 
 		// Check starts with { and ends with }
 		if result[0] != '{' || result[len(result)-1] != '}' {
@@ -252,13 +252,13 @@ func TestValidationErrorIntegration(t *testing.T) {
 		err := validate.Struct(user)
 
 		// Get validation errors
-		errors := utils.ValidationError(err)
+		errors := customErrors.UserValidationError(err)
 		if errors == nil {
 			t.Fatal("expected errors, got nil")
 		}
 
 		// Format errors
-		formatted := utils.FormatValidationErrors(errors)
+		formatted := customErrors.UserFormatValidationErrors(errors)
 
 		// Should produce valid JSON-like string
 		if formatted[0] != '{' || formatted[len(formatted)-1] != '}' {
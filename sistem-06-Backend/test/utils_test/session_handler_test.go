@@ -4,7 +4,6 @@ import (
 	"testing"
 	"time"
 
-	"backend-sistem06.com/utils"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/session"
 	"github.com/gofiber/storage/memory"
@@ -12,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/valyala/fasthttp"
+	"sistem-06-Backend/pkg"
 )
 
 // Helper function to create test app with session
@@ -40,7 +40,7 @@ func createTestContext(app *fiber.App) *fiber.Ctx {
 func TestNewSessionHandler(t *testing.T) {
 	_, store, log := setupTestApp()
 
-	handler := utils.NewSessionHandler(store, log)
+	handler := pkg.NewSessionHandler(store, log)
 
 	assert.NotNil(t, handler)
 	assert.Equal(t, log, handler.Log)
@@ -48,7 +48,7 @@ func TestNewSessionHandler(t *testing.T) {
 
 func TestSetUserSession(t *testing.T) {
 	app, store, log := setupTestApp()
-	handler := utils.NewSessionHandler(store, log)
+	handler := pkg.NewSessionHandler(store, log)
 
 	tests := []struct {
 		name    string
@@ -97,7 +97,7 @@ func TestSetUserSession(t *testing.T) {
 
 func TestGetUserID(t *testing.T) {
 	app, store, log := setupTestApp()
-	handler := utils.NewSessionHandler(store, log)
+	handler := pkg.NewSessionHandler(store, log)
 
 	t.Run("Get user ID from existing session", func(t *testing.T) {
 		ctx := createTestContext(app)
@@ -130,7 +130,7 @@ func TestGetUserID(t *testing.T) {
 
 func TestGetUserEmail(t *testing.T) {
 	app, store, log := setupTestApp()
-	handler := utils.NewSessionHandler(store, log)
+	handler := pkg.NewSessionHandler(store, log)
 
 	t.Run("Get email from existing session", func(t *testing.T) {
 		ctx := createTestContext(app)
@@ -177,7 +177,7 @@ func TestGetUserEmail(t *testing.T) {
 
 func TestGetUserSession(t *testing.T) {
 	app, store, log := setupTestApp()
-	handler := utils.NewSessionHandler(store, log)
+	handler := pkg.NewSessionHandler(store, log)
 
 	t.Run("Get full session data", func(t *testing.T) {
 		ctx := createTestContext(app)
@@ -229,7 +229,7 @@ func TestGetUserSession(t *testing.T) {
 
 func TestIsAuthenticated(t *testing.T) {
 	app, store, log := setupTestApp()
-	handler := utils.NewSessionHandler(store, log)
+	handler := pkg.NewSessionHandler(store, log)
 
 	t.Run("User is authenticated", func(t *testing.T) {
 		ctx := createTestContext(app)
@@ -287,7 +287,7 @@ func TestIsAuthenticated(t *testing.T) {
 
 func TestDestroySession(t *testing.T) {
 	app, store, log := setupTestApp()
-	handler := utils.NewSessionHandler(store, log)
+	handler := pkg.NewSessionHandler(store, log)
 
 	t.Run("Destroy existing session", func(t *testing.T) {
 		ctx := createTestContext(app)
@@ -320,7 +320,7 @@ func TestDestroySession(t *testing.T) {
 
 func TestRefreshSession(t *testing.T) {
 	app, store, log := setupTestApp()
-	handler := utils.NewSessionHandler(store, log)
+	handler := pkg.NewSessionHandler(store, log)
 
 	t.Run("Refresh existing session", func(t *testing.T) {
 		ctx := createTestContext(app)
@@ -362,7 +362,7 @@ func TestRefreshSession(t *testing.T) {
 
 func TestGetUserRole(t *testing.T) {
 	app, store, log := setupTestApp()
-	handler := utils.NewSessionHandler(store, log)
+	handler := pkg.NewSessionHandler(store, log)
 
 	t.Run("Get role from session", func(t *testing.T) {
 		ctx := createTestContext(app)
@@ -412,7 +412,7 @@ func TestGetUserRole(t *testing.T) {
 // Integration test
 func TestSessionHandlerIntegration(t *testing.T) {
 	app, store, log := setupTestApp()
-	handler := utils.NewSessionHandler(store, log)
+	handler := pkg.NewSessionHandler(store, log)
 
 	ctx := createTestContext(app)
 	defer app.ReleaseCtx(ctx)
@@ -466,7 +466,7 @@ func TestSessionHandlerIntegration(t *testing.T) {
 // Benchmark tests
 func BenchmarkSetUserSession(b *testing.B) {
 	app, store, log := setupTestApp()
-	handler := utils.NewSessionHandler(store, log)
+	handler := pkg.NewSessionHandler(store, log)
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -478,7 +478,7 @@ func BenchmarkSetUserSession(b *testing.B) {
 
 func BenchmarkIsAuthenticated(b *testing.B) {
 	app, store, log := setupTestApp()
-	handler := utils.NewSessionHandler(store, log)
+	handler := pkg.NewSessionHandler(store, log)
 	ctx := createTestContext(app)
 	defer app.ReleaseCtx(ctx)
 
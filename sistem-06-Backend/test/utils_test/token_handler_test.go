@@ -3,12 +3,12 @@ package utils_test
 import (
 	"testing"
 
-	"backend-sistem06.com/utils"
+	"sistem-06-Backend/pkg"
 )
 
 func TestGenerateToken(t *testing.T) {
 	t.Run("should generate token with correct length", func(t *testing.T) {
-		token := utils.GenerateToken()
+		token := pkg.GenerateToken()
 
 		// 32 bytes = 64 hex characters
 		expectedLength := 64
@@ -18,8 +18,8 @@ func TestGenerateToken(t *testing.T) {
 	})
 
 	t.Run("should generate different tokens on multiple calls", func(t *testing.T) {
-		token1 := utils.GenerateToken()
-		token2 := utils.GenerateToken()
+		token1 := pkg.GenerateToken()
+		token2 := pkg.GenerateToken()
 
 		if token1 == token2 {
 			t.Error("expected different tokens, got identical tokens")
@@ -27,7 +27,7 @@ func TestGenerateToken(t *testing.T) {
 	})
 
 	t.Run("should generate valid hex string", func(t *testing.T) {
-		token := utils.GenerateToken()
+		token := pkg.GenerateToken()
 
 		// Check if all characters are valid hex (0-9, a-f)
 		for _, char := range token {
@@ -38,7 +38,7 @@ func TestGenerateToken(t *testing.T) {
 	})
 
 	t.Run("should never generate empty token", func(t *testing.T) {
-		token := utils.GenerateToken()
+		token := pkg.GenerateToken()
 
 		if token == "" {
 			t.Error("expected non-empty token, got empty string")
@@ -48,6 +48,6 @@ func TestGenerateToken(t *testing.T) {
 
 func BenchmarkGenerateToken(b *testing.B) {
 	for i := 0; i < b.N; i++ {
-		utils.GenerateToken()
+		pkg.GenerateToken()
 	}
 }